@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/mergestate"
+	"github.com/gittower/git-flow-next/internal/update"
+	"github.com/spf13/cobra"
+)
+
+// autoUpdateCmd represents the autoupdate command
+var autoUpdateCmd = &cobra.Command{
+	Use:   "autoupdate",
+	Short: "Update all auto-updating base branches from their parents",
+	Long: `Update all base branches that have autoUpdate enabled from their parent branches.
+Each branch is updated using its configured downstream strategy, parents first,
+so that e.g. develop is kept current with main after a hotfix has landed.`,
+	Example: `  git flow autoupdate
+  git flow autoupdate --dry-run`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		AutoUpdateCommand(dryRun)
+	},
+}
+
+// AutoUpdateCommand is the implementation of the autoupdate command
+func AutoUpdateCommand(dryRun bool) {
+	if err := autoUpdate(dryRun); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(int(exitCode))
+	}
+}
+
+// autoUpdate performs the actual auto-update logic and returns any errors
+func autoUpdate(dryRun bool) error {
+	// Validate that git-flow is initialized
+	initialized, err := config.IsInitialized()
+	if err != nil {
+		return &errors.GitError{Operation: "check if git-flow is initialized", Err: err}
+	}
+	if !initialized {
+		return &errors.NotInitializedError{}
+	}
+
+	// An update resolved after a conflict, as the conflict message asks, leaves its state behind
+	if !dryRun {
+		if err := clearStaleUpdateState(); err != nil {
+			return err
+		}
+	}
+
+	// Get configuration
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return &errors.GitError{Operation: "load configuration", Err: err}
+	}
+
+	branches := getAutoUpdateBranches(cfg)
+	if len(branches) == 0 {
+		fmt.Println("No branches are configured for auto-update")
+		return nil
+	}
+
+	// Remember where we started so we can return there afterwards
+	originalBranch, err := git.GetCurrentBranch()
+	if err != nil {
+		return &errors.GitError{Operation: "get current branch", Err: err}
+	}
+
+	updated := 0
+	for _, branchName := range branches {
+		branchConfig := cfg.Branches[branchName]
+		parentBranch := branchConfig.Parent
+
+		if err := git.BranchExists(branchName); err != nil {
+			fmt.Printf("Skipping '%s': branch does not exist\n", branchName)
+			continue
+		}
+		if err := git.BranchExists(parentBranch); err != nil {
+			fmt.Printf("Skipping '%s': parent branch '%s' does not exist\n", branchName, parentBranch)
+			continue
+		}
+
+		// Check whether the parent has anything new for this branch
+		behind, err := git.CountCommits(branchName, parentBranch)
+		if err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("compare '%s' with '%s'", branchName, parentBranch), Err: err}
+		}
+		if behind == 0 {
+			fmt.Printf("'%s' is already up to date with '%s'\n", branchName, parentBranch)
+			continue
+		}
+
+		strategy := branchConfig.DownstreamStrategy
+		if strategy == "" || strategy == string(config.MergeStrategyNone) {
+			strategy = strategyMerge
+		}
+
+		if dryRun {
+			fmt.Printf("Would update '%s' from '%s' using %s strategy (%d new commits)\n", branchName, parentBranch, strategy, behind)
+			continue
+		}
+
+		state := &mergestate.MergeState{
+			Action:         "update",
+			BranchName:     branchName,
+			ParentBranch:   parentBranch,
+			MergeStrategy:  strategy,
+			CurrentStep:    "merge",
+			FullBranchName: branchName,
+		}
+		if err := update.UpdateBranchFromParent(branchName, parentBranch, strategy, true, state); err != nil {
+			if _, ok := err.(*errors.UnresolvedConflictsError); ok {
+				fmt.Printf("Conflicts detected while updating '%s' from '%s'. Resolve them and run 'git flow autoupdate' again\n", branchName, parentBranch)
+			}
+			return err
+		}
+		updated++
+	}
+
+	// Return to the original branch
+	if !dryRun && originalBranch != "" {
+		if err := git.Checkout(originalBranch); err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("checkout original branch '%s'", originalBranch), Err: err}
+		}
+	}
+
+	if !dryRun {
+		fmt.Printf("Auto-updated %d of %d branches\n", updated, len(branches))
+	}
+	return nil
+}
+
+// getAutoUpdateBranches returns all base branches with autoUpdate enabled,
+// ordered so that parents are updated before their children
+func getAutoUpdateBranches(cfg *config.Config) []string {
+	branches := []string{}
+	for branchName, branch := range cfg.Branches {
+		if branch.Type == string(config.BranchTypeBase) && branch.AutoUpdate && branch.Parent != "" {
+			branches = append(branches, branchName)
+		}
+	}
+	sortBranchesByDepth(cfg, branches)
+	return branches
+}
+
+// sortBranchesByDepth sorts branch names by their depth in the branch hierarchy, then by name
+func sortBranchesByDepth(cfg *config.Config, branches []string) {
+	sort.Slice(branches, func(i, j int) bool {
+		depthI := getBranchDepth(cfg, branches[i])
+		depthJ := getBranchDepth(cfg, branches[j])
+		if depthI != depthJ {
+			return depthI < depthJ
+		}
+		return branches[i] < branches[j]
+	})
+}
+
+// getBranchDepth returns the number of parents above a branch in the configured hierarchy
+func getBranchDepth(cfg *config.Config, branchName string) int {
+	depth := 0
+	visited := map[string]bool{branchName: true}
+	current := cfg.Branches[branchName].Parent
+	for current != "" && !visited[current] {
+		visited[current] = true
+		depth++
+		current = cfg.Branches[current].Parent
+	}
+	return depth
+}
+
+func init() {
+	rootCmd.AddCommand(autoUpdateCmd)
+
+	autoUpdateCmd.Flags().Bool("dry-run", false, "Show which branches would be updated without changing anything")
+}
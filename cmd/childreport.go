@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/mergestate"
+)
+
+// Outcomes of a child base branch update, as reported by --children-strategy-report
+const (
+	childOutcomeUpToDate    = "already up to date"
+	childOutcomeFastForward = "fast-forwarded"
+	childOutcomeMerge       = "created a merge commit"
+	childOutcomeSquash      = "created a squash commit"
+	childOutcomeRebase      = "rebased"
+	childOutcomeUpdated     = "updated"
+)
+
+// printChildrenStrategyReport prints, per child base branch, the strategy it was updated with and
+// what the update did to it. The outcome is derived from where the branch was before the finish.
+func printChildrenStrategyReport(state *mergestate.MergeState) {
+	if len(state.ChildBranches) == 0 {
+		return
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return
+	}
+
+	width := 0
+	for _, branch := range state.ChildBranches {
+		width = max(width, len(branch))
+	}
+
+	fmt.Println("Child base branch updates:")
+	for _, branch := range state.ChildBranches {
+		branchConfig := cfg.Branches[branch]
+		if recorded, ok := state.ChildStrategies[branch]; ok {
+			branchConfig.DownstreamStrategy = recorded
+		}
+		strategy := childUpdateStrategy(branchConfig)
+		fmt.Printf("  %-*s  %-6s  %s\n", width, branch, strategy, childUpdateOutcome(state, branch, strategy))
+	}
+}
+
+// childUpdateOutcome describes what updating the child branch with the strategy did
+func childUpdateOutcome(state *mergestate.MergeState, branch string, strategy string) string {
+	if slices.Contains(state.UnchangedBranches, branch) {
+		return childOutcomeUpToDate
+	}
+	before, ok := state.ChildBranchHeads[branch]
+	if !ok {
+		return childOutcomeUpdated
+	}
+	after, err := git.ResolveCommit(branch)
+	if err != nil {
+		return childOutcomeUpdated
+	}
+	if after == before {
+		return childOutcomeUpToDate
+	}
+
+	// The branch now is the target and only gained its commits
+	if target, err := git.ResolveCommit(state.ParentBranch); err == nil && after == target {
+		if contained, err := git.IsAncestor(before, after); err == nil && contained {
+			return childOutcomeFastForward
+		}
+	}
+
+	switch strategy {
+	case strategyRebase:
+		return childOutcomeRebase
+	case strategySquash:
+		return childOutcomeSquash
+	}
+	if parents, err := git.CommitParents(after); err == nil && len(parents) > 1 {
+		return childOutcomeMerge
+	}
+	return childOutcomeUpdated
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"os"
+)
+
+// ANSI color codes used to highlight output
+const (
+	colorRed    = "31"
+	colorGreen  = "32"
+	colorYellow = "33"
+)
+
+// colorOutput reports whether output is highlighted with ANSI colors. It is set once per
+// process by initColor, before the command runs.
+var colorOutput bool
+
+// initColor decides whether output is colored. --no-color and a non-empty NO_COLOR turn color
+// off, a non-empty CLICOLOR_FORCE other than "0" turns it on, and otherwise output is colored
+// only when stdout is a terminal that is not "dumb".
+func initColor(noColor bool) {
+	colorOutput = shouldUseColor(noColor)
+}
+
+// shouldUseColor applies the rules described at initColor
+func shouldUseColor(noColor bool) bool {
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if force := os.Getenv("CLICOLOR_FORCE"); force != "" && force != "0" {
+		return true
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// colorize wraps text in the given ANSI color, if output is colored
+func colorize(color string, text string) string {
+	if !colorOutput {
+		return text
+	}
+	return "\033[" + color + "m" + text + "\033[0m"
+}
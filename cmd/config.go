@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and change the git-flow configuration",
+	Long:  `Inspect the git-flow configuration as git-flow resolves it, with defaults applied, and change branch settings safely.`,
+}
+
+// configBranchCmd represents the config branch command
+var configBranchCmd = &cobra.Command{
+	Use:   "branch <name>",
+	Short: "Show the resolved configuration of a branch type or base branch",
+	Long: `Show the resolved configuration of a branch type (e.g. feature) or base branch (e.g. develop).
+The values are the ones commands like finish use, including defaults for settings that are not configured.`,
+	Example: `  git flow config branch feature
+  git flow config branch develop --json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		ConfigBranchCommand(args[0], jsonOutput)
+	},
+}
+
+// configListCmd represents the config list command
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show the resolved configuration of all branches",
+	Long: `Show the resolved configuration of all base branches and branch types as a tree, with each branch
+listed below its parent and followed by its settings.`,
+	Example: `  git flow config list`,
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ConfigListCommand()
+	},
+}
+
+// BranchConfigView is the resolved configuration of a branch, as printed by config branch
+type BranchConfigView struct {
+	Name               string `json:"name"`               // Branch type or base branch name
+	Type               string `json:"type"`               // "base" or "topic"
+	Parent             string `json:"parent"`             // Parent branch
+	StartPoint         string `json:"startPoint"`         // Branch topic branches are started from
+	UpstreamStrategy   string `json:"upstreamStrategy"`   // Strategy for merging into the parent
+	DownstreamStrategy string `json:"downstreamStrategy"` // Strategy for updating from the parent
+	Prefix             string `json:"prefix"`             // Branch name prefix of topic branches
+	Tag                bool   `json:"tag"`                // Whether finish creates a tag
+	TagPrefix          string `json:"tagPrefix"`          // Prefix of created tags
+	AutoUpdate         bool   `json:"autoUpdate"`         // Whether the branch is updated from its parent automatically
+	Protected          bool   `json:"protected"`          // Whether merging into the branch requires approval
+}
+
+// ConfigBranchCommand is the implementation of the config branch command
+func ConfigBranchCommand(name string, jsonOutput bool) {
+	if err := configBranch(name, jsonOutput); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(int(exitCode))
+	}
+}
+
+// configBranch prints the resolved configuration of the branch and returns any errors
+func configBranch(name string, jsonOutput bool) error {
+	// Validate that git-flow is initialized
+	initialized, err := config.IsInitialized()
+	if err != nil {
+		return &errors.GitError{Operation: "check if git-flow is initialized", Err: err}
+	}
+	if !initialized {
+		return &errors.NotInitializedError{}
+	}
+
+	// Get configuration
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return &errors.GitError{Operation: "load configuration", Err: err}
+	}
+
+	branchConfig, ok := cfg.Branches[name]
+	if !ok {
+		return &errors.InvalidBranchTypeError{BranchType: name}
+	}
+
+	view := BranchConfigView{
+		Name:               name,
+		Type:               branchConfig.Type,
+		Parent:             branchConfig.Parent,
+		StartPoint:         branchConfig.StartPoint,
+		UpstreamStrategy:   branchConfig.UpstreamStrategy,
+		DownstreamStrategy: branchConfig.DownstreamStrategy,
+		Prefix:             branchConfig.Prefix,
+		Tag:                branchConfig.Tag,
+		TagPrefix:          branchConfig.TagPrefix,
+		AutoUpdate:         branchConfig.AutoUpdate,
+		Protected:          branchConfig.Protected,
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(view, "", "  ")
+		if err != nil {
+			return &errors.GitError{Operation: "encode branch configuration", Err: err}
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	for _, prop := range branchProperties {
+		fmt.Printf("%s: %s\n", prop.Name, prop.Value(branchConfig))
+	}
+	return nil
+}
+
+// ConfigListCommand is the implementation of the config list command
+func ConfigListCommand() {
+	if err := configList(); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(int(exitCode))
+	}
+}
+
+// configList prints the resolved configuration of all branches as a tree and returns any errors
+func configList() error {
+	cfg, err := loadInitializedConfig()
+	if err != nil {
+		return err
+	}
+
+	// Children are listed below their parent, base branches before branch types, each sorted by name
+	children := map[string][]string{}
+	var names []string
+	for name, branchConfig := range cfg.Branches {
+		if branchConfig.Type != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		typeI, typeJ := cfg.Branches[names[i]].Type, cfg.Branches[names[j]].Type
+		if typeI != typeJ {
+			return typeI == string(config.BranchTypeBase)
+		}
+		return names[i] < names[j]
+	})
+	var roots []string
+	for _, name := range names {
+		parent := cfg.Branches[name].Parent
+		if parent == "" || cfg.Branches[parent].Type == "" || parent == name {
+			roots = append(roots, name)
+		} else {
+			children[parent] = append(children[parent], name)
+		}
+	}
+
+	fmt.Printf("remote: %s\n", cfg.Remote)
+	printed := map[string]bool{}
+	var printTree func(name string, depth int)
+	printTree = func(name string, depth int) {
+		if printed[name] {
+			return
+		}
+		printed[name] = true
+		indent := strings.Repeat("  ", depth)
+		fmt.Printf("%s%s (%s)\n", indent, name, cfg.Branches[name].Type)
+		// The type and parent are shown by the tree itself
+		for _, prop := range branchProperties {
+			if prop.Name != "type" && prop.Name != "parent" {
+				fmt.Printf("%s  %s: %s\n", indent, prop.Name, prop.Value(cfg.Branches[name]))
+			}
+		}
+		for _, child := range children[name] {
+			printTree(child, depth+1)
+		}
+	}
+	for _, name := range roots {
+		printTree(name, 0)
+	}
+	// Branches in a parent cycle have no root above them
+	for _, name := range names {
+		printTree(name, 0)
+	}
+	return nil
+}
+
+func init() {
+	configBranchCmd.Flags().Bool("json", false, "Print the configuration as JSON")
+	configCmd.AddCommand(configBranchCmd)
+	configCmd.AddCommand(configListCmd)
+	rootCmd.AddCommand(configCmd)
+}
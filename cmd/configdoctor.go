@@ -0,0 +1,314 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// configDoctorCmd represents the config doctor command
+var configDoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the git-flow configuration for problems",
+	Long: `Check the git-flow configuration for problems such as an outdated version, missing base branches, empty
+merge strategies, overlapping prefixes, prefixes without a trailing slash and settings left behind by deleted branches.
+With --fix, the problems that can be repaired safely are fixed, each after confirmation. Problems that need
+a decision, such as a missing main branch, a parent cycle or overlapping prefixes, are only reported.`,
+	Example: `  git flow config doctor
+  git flow config doctor --fix
+  git flow config doctor --fix --yes`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fix, _ := cmd.Flags().GetBool("fix")
+		yes, _ := cmd.Flags().GetBool("yes")
+		ConfigDoctorCommand(fix, yes)
+	},
+}
+
+// configProblem is a problem found in the configuration
+type configProblem struct {
+	Description    string       // What is wrong
+	FixDescription string       // What the fix does, empty if the problem can't be fixed safely
+	Fix            func() error // Repairs the problem, nil if the problem can't be fixed safely
+}
+
+// ConfigDoctorCommand is the implementation of the config doctor command
+func ConfigDoctorCommand(fix bool, yes bool) {
+	if err := configDoctor(fix, yes); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(int(exitCode))
+	}
+}
+
+// configDoctor reports the problems in the configuration, fixes them if requested and returns any errors
+func configDoctor(fix bool, yes bool) error {
+	// Validate that git-flow is initialized
+	initialized, err := config.IsInitialized()
+	if err != nil {
+		return &errors.GitError{Operation: "check if git-flow is initialized", Err: err}
+	}
+	if !initialized {
+		return &errors.NotInitializedError{}
+	}
+
+	// Get configuration
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return &errors.GitError{Operation: "load configuration", Err: err}
+	}
+
+	problems, err := findConfigProblems(cfg)
+	if err != nil {
+		return err
+	}
+	if len(problems) == 0 {
+		fmt.Println("No problems found in the git-flow configuration")
+		return nil
+	}
+
+	// Fixes are confirmed one by one, which needs a terminal unless --yes answers for all of them
+	if fix && !yes && !stdinIsTerminal() {
+		for _, problem := range problems {
+			if problem.Fix != nil {
+				return &errors.InvalidOptionError{Option: "--fix", Reason: "fixes have to be confirmed, but there is no terminal; run with --yes to apply them without asking"}
+			}
+		}
+	}
+
+	remaining, fixable := 0, 0
+	for _, problem := range problems {
+		if problem.Fix == nil {
+			fmt.Printf("Problem: %s\n", problem.Description)
+			remaining++
+			continue
+		}
+		if !fix {
+			fmt.Printf("Problem: %s (fix: %s)\n", problem.Description, problem.FixDescription)
+			remaining++
+			fixable++
+			continue
+		}
+		if !yes {
+			confirmed, answered := askConfirmation(fmt.Sprintf("Problem: %s. Fix: %s?", problem.Description, problem.FixDescription))
+			if !answered {
+				return &errors.InvalidOptionError{Option: "--fix", Reason: "no answer could be read; run with --yes to apply the fixes without asking"}
+			}
+			if !confirmed {
+				fmt.Printf("Skipped: %s\n", problem.Description)
+				remaining++
+				fixable++
+				continue
+			}
+		}
+		if err := problem.Fix(); err != nil {
+			return &errors.GitError{Operation: problem.FixDescription, Err: err}
+		}
+		fmt.Printf("Fixed: %s (%s)\n", problem.Description, problem.FixDescription)
+	}
+
+	if remaining > 0 {
+		return &errors.ConfigProblemsError{Count: remaining, Fixable: fixable}
+	}
+	return nil
+}
+
+// findConfigProblems checks the version of the configuration, the base branches, the parent chains, the
+// strategies and prefixes of the configured branches and the settings of topic branches, and returns the
+// problems in that order
+func findConfigProblems(cfg *config.Config) ([]configProblem, error) {
+	// Settings of topic branches, such as their base, are loaded as branches without a type
+	var names []string
+	for name, branchConfig := range cfg.Branches {
+		if branchConfig.Type != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var problems []configProblem
+
+	// Configurations written by an older git-flow are upgraded to the current schema
+	if compareVersions(cfg.Version, config.SchemaVersion) < 0 {
+		problems = append(problems, setConfigProblem(
+			fmt.Sprintf("configuration has version %s, older than %s", cfg.Version, config.SchemaVersion),
+			"gitflow.version", config.SchemaVersion))
+	}
+
+	// Base branches have to exist; a missing one is created from its parent
+	for _, name := range names {
+		branchConfig := cfg.Branches[name]
+		if branchConfig.Type != string(config.BranchTypeBase) || git.BranchExists(name) == nil {
+			continue
+		}
+		if branchConfig.Parent == "" {
+			problems = append(problems, configProblem{Description: fmt.Sprintf("base branch '%s' does not exist", name)})
+			continue
+		}
+		if git.BranchExists(branchConfig.Parent) != nil {
+			problems = append(problems, configProblem{Description: fmt.Sprintf("base branch '%s' does not exist, and neither does its parent '%s'", name, branchConfig.Parent)})
+			continue
+		}
+		parent := branchConfig.Parent
+		problems = append(problems, configProblem{
+			Description:    fmt.Sprintf("base branch '%s' does not exist", name),
+			FixDescription: fmt.Sprintf("create it from '%s'", parent),
+			Fix: func() error {
+				commit, err := git.ResolveCommit(parent)
+				if err != nil {
+					return err
+				}
+				return git.CreateBranchRef(name, commit)
+			},
+		})
+	}
+
+	// Parents and start points have to be configured and parent chains have to end
+	for _, name := range config.FindOrphanedBranches(cfg) {
+		if cfg.Branches[name].Type != "" {
+			problems = append(problems, configProblem{Description: fmt.Sprintf("'%s' refers to a parent or start point that is not configured", name)})
+		}
+	}
+	reportedCycles := map[string]bool{}
+	for _, name := range names {
+		cycle := findParentCycle(name, cfg)
+		if len(cycle) == 0 {
+			continue
+		}
+		members := append([]string{}, cycle[1:]...)
+		sort.Strings(members)
+		if key := strings.Join(members, " "); !reportedCycles[key] {
+			reportedCycles[key] = true
+			problems = append(problems, configProblem{Description: fmt.Sprintf("parent chain forms a cycle: %s", strings.Join(cycle, " -> "))})
+		}
+	}
+
+	// Strategies default to the ones of the default configuration
+	for _, name := range names {
+		branchConfig := cfg.Branches[name]
+		upstream, downstream := defaultStrategies(name, branchConfig)
+		if branchConfig.UpstreamStrategy == "" {
+			problems = append(problems, setConfigProblem(
+				fmt.Sprintf("upstream strategy of '%s' is empty", name),
+				fmt.Sprintf("gitflow.branch.%s.upstreamStrategy", name), upstream))
+		}
+		if branchConfig.DownstreamStrategy == "" {
+			problems = append(problems, setConfigProblem(
+				fmt.Sprintf("downstream strategy of '%s' is empty", name),
+				fmt.Sprintf("gitflow.branch.%s.downstreamStrategy", name), downstream))
+		}
+	}
+
+	// Branches of topic types whose prefixes overlap cannot be told apart by their name
+	for _, conflict := range config.FindPrefixConflicts(cfg) {
+		problems = append(problems, configProblem{Description: fmt.Sprintf("prefixes of branch types '%s' ('%s') and '%s' ('%s') overlap",
+			conflict.TypeA, conflict.PrefixA, conflict.TypeB, conflict.PrefixB)})
+	}
+
+	// Prefixes end with exactly one slash
+	for _, name := range names {
+		branchConfig := cfg.Branches[name]
+		trimmed := strings.TrimRight(branchConfig.Prefix, "/")
+		if branchConfig.Type != string(config.BranchTypeTopic) || trimmed == "" || trimmed+"/" == branchConfig.Prefix {
+			continue
+		}
+		problems = append(problems, setConfigProblem(
+			fmt.Sprintf("prefix '%s' of '%s' does not end with a single '/'", branchConfig.Prefix, name),
+			fmt.Sprintf("gitflow.branch.%s.prefix", name), trimmed+"/"))
+	}
+
+	// Settings of topic branches are removed with the branch, but deleting it with git leaves them behind
+	settings, err := git.GetAllConfig(`^gitflow\.branch\.`)
+	if err != nil {
+		return nil, &errors.GitError{Operation: "read branch settings", Err: err}
+	}
+	stale := map[string]bool{}
+	for key := range settings {
+		section := strings.TrimPrefix(key, "gitflow.branch.")
+		dot := strings.LastIndex(section, ".")
+		if dot < 0 {
+			continue
+		}
+		branch := section[:dot]
+		if cfg.Branches[strings.ToLower(branch)].Type != "" || git.BranchExists(branch) == nil {
+			continue
+		}
+		stale[branch] = true
+	}
+	var staleBranches []string
+	for branch := range stale {
+		staleBranches = append(staleBranches, branch)
+	}
+	sort.Strings(staleBranches)
+	for _, branch := range staleBranches {
+		section := "gitflow.branch." + branch
+		problems = append(problems, configProblem{
+			Description:    fmt.Sprintf("settings of deleted branch '%s' are left in %s.*", branch, section),
+			FixDescription: "remove them",
+			Fix: func() error {
+				return git.RemoveConfigSection(section)
+			},
+		})
+	}
+
+	return problems, nil
+}
+
+// setConfigProblem returns a problem that is fixed by setting key to value
+func setConfigProblem(description string, key string, value string) configProblem {
+	return configProblem{
+		Description:    description,
+		FixDescription: fmt.Sprintf("set %s to '%s'", key, value),
+		Fix: func() error {
+			return git.SetConfig(key, value)
+		},
+	}
+}
+
+// defaultStrategies returns the upstream and downstream strategy a branch gets when none is configured:
+// the ones of the branch in the default configuration, or the ones of feature and develop otherwise
+func defaultStrategies(name string, branchConfig config.BranchConfig) (string, string) {
+	if defaults, ok := config.DefaultConfig().Branches[name]; ok && defaults.Type == branchConfig.Type {
+		return defaults.UpstreamStrategy, defaults.DownstreamStrategy
+	}
+	switch {
+	case branchConfig.Type == string(config.BranchTypeTopic):
+		return string(config.MergeStrategyMerge), string(config.MergeStrategyRebase)
+	case branchConfig.Parent == "":
+		return string(config.MergeStrategyNone), string(config.MergeStrategyNone)
+	default:
+		return string(config.MergeStrategyMerge), string(config.MergeStrategyMerge)
+	}
+}
+
+// findParentCycle returns the parent chain of the branch up to and including the first branch that
+// repeats, starting at that branch (e.g. develop -> main -> develop), or nil if the chain ends
+func findParentCycle(branch string, cfg *config.Config) []string {
+	visited := []string{}
+	for current := branch; current != ""; current = cfg.Branches[current].Parent {
+		for i, seen := range visited {
+			if seen == current {
+				return append(visited[i:], current)
+			}
+		}
+		visited = append(visited, current)
+	}
+	return nil
+}
+
+func init() {
+	configDoctorCmd.Flags().Bool("fix", false, "Repair the problems that can be fixed safely")
+	configDoctorCmd.Flags().Bool("yes", false, "Apply the fixes without asking for confirmation")
+	configCmd.AddCommand(configDoctorCmd)
+}
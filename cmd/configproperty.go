@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// configGetCmd represents the config get command
+var configGetCmd = &cobra.Command{
+	Use:   "get <branch> <property>",
+	Short: "Print a setting of a branch type or base branch",
+	Long: `Print the resolved value of a setting of a branch type (e.g. feature) or base branch (e.g. develop).
+The properties are the ones listed by 'git flow config branch'.`,
+	Example: `  git flow config get feature upstreamstrategy
+  git flow config get develop autoupdate`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		ConfigGetCommand(args[0], args[1])
+	},
+}
+
+// configSetCmd represents the config set command
+var configSetCmd = &cobra.Command{
+	Use:   "set <branch> <property> <value>",
+	Short: "Change a setting of a branch type or base branch",
+	Long: `Change a setting of a branch type (e.g. feature) or base branch (e.g. develop) after checking that
+the property exists and the value is valid for it, e.g. that a strategy is merge, rebase, squash or none
+and that a parent is a configured base branch.`,
+	Example: `  git flow config set feature upstreamstrategy squash
+  git flow config set develop autoupdate false`,
+	Args: cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		ConfigSetCommand(args[0], args[1], args[2])
+	},
+}
+
+// branchProperty is a setting of a branch that config branch prints and config get and set accept
+type branchProperty struct {
+	Name  string                                                                // Name as printed and accepted, in lower case
+	Key   string                                                                // Last part of the git config key gitflow.branch.<branch>.<key>
+	Value func(branchConfig config.BranchConfig) string                         // Resolved value of the setting
+	Check func(branch string, value string, cfg *config.Config) (string, error) // Validates a new value and returns it as stored, nil if any value is valid
+}
+
+// branchProperties are the settings of a branch, in the order config branch prints them
+var branchProperties = []branchProperty{
+	{"type", "type", func(b config.BranchConfig) string { return b.Type }, checkBranchType},
+	{"parent", "parent", func(b config.BranchConfig) string { return b.Parent }, checkParent},
+	{"startpoint", "startPoint", func(b config.BranchConfig) string { return b.StartPoint }, checkBaseBranch},
+	{"upstreamstrategy", "upstreamStrategy", func(b config.BranchConfig) string { return b.UpstreamStrategy }, checkStrategy},
+	{"downstreamstrategy", "downstreamStrategy", func(b config.BranchConfig) string { return b.DownstreamStrategy }, checkStrategy},
+	{"prefix", "prefix", func(b config.BranchConfig) string { return b.Prefix }, checkPrefix},
+	{"tag", "tag", func(b config.BranchConfig) string { return strconv.FormatBool(b.Tag) }, checkBool},
+	{"tagprefix", "tagprefix", func(b config.BranchConfig) string { return b.TagPrefix }, nil},
+	{"autoupdate", "autoUpdate", func(b config.BranchConfig) string { return strconv.FormatBool(b.AutoUpdate) }, checkBool},
+	{"protected", "protected", func(b config.BranchConfig) string { return strconv.FormatBool(b.Protected) }, checkBool},
+}
+
+// ConfigGetCommand is the implementation of the config get command
+func ConfigGetCommand(branch string, property string) {
+	if err := configGet(branch, property); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(int(exitCode))
+	}
+}
+
+// configGet prints the resolved value of the property of the branch and returns any errors
+func configGet(branch string, property string) error {
+	cfg, err := loadInitializedConfig()
+	if err != nil {
+		return err
+	}
+	branchConfig, prop, err := findBranchProperty(cfg, branch, property)
+	if err != nil {
+		return err
+	}
+	fmt.Println(prop.Value(branchConfig))
+	return nil
+}
+
+// ConfigSetCommand is the implementation of the config set command
+func ConfigSetCommand(branch string, property string, value string) {
+	if err := configSet(branch, property, value); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(int(exitCode))
+	}
+}
+
+// configSet validates the value and sets the property of the branch, and returns any errors
+func configSet(branch string, property string, value string) error {
+	cfg, err := loadInitializedConfig()
+	if err != nil {
+		return err
+	}
+	_, prop, err := findBranchProperty(cfg, branch, property)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("gitflow.branch.%s.%s", branch, prop.Key)
+	if prop.Check != nil {
+		value, err = prop.Check(branch, value, cfg)
+		if err != nil {
+			return &errors.InvalidOptionError{Option: key, Reason: err.Error()}
+		}
+	}
+
+	if err := git.SetConfig(key, value); err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("set %s", key), Err: err}
+	}
+	fmt.Printf("Set %s to '%s'\n", key, value)
+	return nil
+}
+
+// loadInitializedConfig returns the configuration, or an error if git-flow is not initialized
+func loadInitializedConfig() (*config.Config, error) {
+	initialized, err := config.IsInitialized()
+	if err != nil {
+		return nil, &errors.GitError{Operation: "check if git-flow is initialized", Err: err}
+	}
+	if !initialized {
+		return nil, &errors.NotInitializedError{}
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, &errors.GitError{Operation: "load configuration", Err: err}
+	}
+	return cfg, nil
+}
+
+// findBranchProperty returns the configuration of the branch and the property, or an error if the branch
+// is not a configured branch type or base branch or the property is unknown
+func findBranchProperty(cfg *config.Config, branch string, property string) (config.BranchConfig, branchProperty, error) {
+	// Settings of topic branches, such as their base, are loaded as branches without a type
+	branchConfig, ok := cfg.Branches[branch]
+	if !ok || branchConfig.Type == "" {
+		return config.BranchConfig{}, branchProperty{}, &errors.InvalidBranchTypeError{BranchType: branch}
+	}
+	names := []string{}
+	for _, prop := range branchProperties {
+		if prop.Name == strings.ToLower(property) {
+			return branchConfig, prop, nil
+		}
+		names = append(names, prop.Name)
+	}
+	return config.BranchConfig{}, branchProperty{}, &errors.InvalidOptionError{
+		Option: fmt.Sprintf("gitflow.branch.%s.%s", branch, property),
+		Reason: fmt.Sprintf("unknown property '%s', expected one of %s", property, strings.Join(names, ", ")),
+	}
+}
+
+// checkBranchType accepts the types of branches
+func checkBranchType(branch string, value string, cfg *config.Config) (string, error) {
+	switch lower := strings.ToLower(value); lower {
+	case string(config.BranchTypeBase), string(config.BranchTypeTopic):
+		return lower, nil
+	}
+	return "", fmt.Errorf("unknown type '%s', expected base or topic", value)
+}
+
+// checkParent accepts a configured base branch that doesn't lead back to the branch, or no parent for a base branch
+func checkParent(branch string, value string, cfg *config.Config) (string, error) {
+	if value == "" {
+		if cfg.Branches[branch].Type == string(config.BranchTypeTopic) {
+			return "", fmt.Errorf("a branch type needs a parent")
+		}
+		return value, nil
+	}
+	if _, err := checkBaseBranch(branch, value, cfg); err != nil {
+		return "", err
+	}
+
+	// The parent chain has to end, as finish and update follow it
+	changed := &config.Config{Branches: map[string]config.BranchConfig{}}
+	for name, branchConfig := range cfg.Branches {
+		changed.Branches[name] = branchConfig
+	}
+	branchConfig := changed.Branches[branch]
+	branchConfig.Parent = value
+	changed.Branches[branch] = branchConfig
+	if cycle := findParentCycle(branch, changed); len(cycle) > 0 {
+		return "", fmt.Errorf("parent chain would form a cycle: %s", strings.Join(cycle, " -> "))
+	}
+	return value, nil
+}
+
+// checkBaseBranch accepts a configured base branch, as a start point or parent
+func checkBaseBranch(branch string, value string, cfg *config.Config) (string, error) {
+	if cfg.Branches[value].Type != string(config.BranchTypeBase) {
+		return "", fmt.Errorf("'%s' is not a configured base branch", value)
+	}
+	return value, nil
+}
+
+// checkStrategy accepts the strategies branches are merged and updated with
+func checkStrategy(branch string, value string, cfg *config.Config) (string, error) {
+	switch lower := strings.ToLower(value); lower {
+	case string(config.MergeStrategyMerge), string(config.MergeStrategyRebase), string(config.MergeStrategySquash), string(config.MergeStrategyNone):
+		return lower, nil
+	}
+	return "", fmt.Errorf("unknown strategy '%s', expected merge, rebase, squash or none", value)
+}
+
+// checkPrefix accepts a prefix that ends with a single slash, the way config doctor expects it
+func checkPrefix(branch string, value string, cfg *config.Config) (string, error) {
+	trimmed := strings.TrimRight(value, "/")
+	if trimmed == "" || trimmed+"/" != value {
+		return "", fmt.Errorf("prefix '%s' has to end with a single '/'", value)
+	}
+	return value, nil
+}
+
+// checkBool accepts true and false
+func checkBool(branch string, value string, cfg *config.Config) (string, error) {
+	switch lower := strings.ToLower(value); lower {
+	case "true", "false":
+		return lower, nil
+	}
+	return "", fmt.Errorf("expected true or false, got '%s'", value)
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+}
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"regexp"
 
 	"github.com/gittower/git-flow-next/internal/config"
 	"github.com/gittower/git-flow-next/internal/errors"
@@ -9,7 +11,7 @@ import (
 )
 
 // DeleteCommand handles the deletion of a topic branch
-func DeleteCommand(branchType string, name string, force bool, remote *bool) error {
+func DeleteCommand(branchType string, name string, force bool, yes bool, remote *bool) error {
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -34,6 +36,39 @@ func DeleteCommand(branchType string, name string, force bool, remote *bool) err
 		return &errors.BranchNotFoundError{BranchName: fullBranchName}
 	}
 
+	// Determine if we should delete remote branch
+	deleteRemote := false
+	if remote != nil {
+		// Command line flag takes precedence
+		deleteRemote = *remote
+	} else {
+		// Check config if not specified
+		configKey := fmt.Sprintf("gitflow.branch.%s.deleteRemote", branchType)
+		remoteConfig, err := git.GetConfig(configKey)
+		if err == nil && remoteConfig == "true" {
+			deleteRemote = true
+		}
+	}
+
+	// Get remote name from config
+	remoteName, err := git.GetConfig("gitflow.remote")
+	if err != nil {
+		remoteName = "origin" // Default to origin if not configured
+	}
+
+	// Show what would be lost and ask before deleting, unless skipped
+	if !yes && !force {
+		printDeleteSummary(fullBranchName, branchConfig.Parent, remoteName, deleteRemote)
+		confirmed, answered := askConfirmation(fmt.Sprintf("Delete branch '%s'?", fullBranchName))
+		if !answered {
+			return &errors.InvalidOptionError{Option: "--yes", Reason: "no answer could be read (is the terminal non-interactive?); pass --yes to delete without confirmation"}
+		}
+		if !confirmed {
+			fmt.Println("Delete cancelled")
+			return nil
+		}
+	}
+
 	// Check if we're currently on the branch to be deleted
 	currentBranch, err := git.GetCurrentBranch()
 	if err != nil {
@@ -51,34 +86,15 @@ func DeleteCommand(branchType string, name string, force bool, remote *bool) err
 		}
 	}
 
-	// Determine if we should delete remote branch
-	deleteRemote := false
-	if remote != nil {
-		// Command line flag takes precedence
-		deleteRemote = *remote
-	} else {
-		// Check config if not specified
-		configKey := fmt.Sprintf("gitflow.branch.%s.deleteRemote", branchType)
-		remoteConfig, err := git.GetConfig(configKey)
-		if err == nil && remoteConfig == "true" {
-			deleteRemote = true
-		}
-	}
-
 	// Delete the branch with appropriate flag
 	deleteErr := git.DeleteBranch(fullBranchName, force)
 	if deleteErr != nil {
 		return &errors.GitError{Operation: fmt.Sprintf("delete branch '%s'", fullBranchName), Err: deleteErr}
 	}
+	cleanupBranchConfig(fullBranchName)
 
 	// Delete remote branch if requested
 	if deleteRemote {
-		// Get remote name from config
-		remoteName, err := git.GetConfig("gitflow.remote")
-		if err != nil {
-			remoteName = "origin" // Default to origin if not configured
-		}
-
 		// Delete remote branch
 		if err := git.DeleteRemoteBranch(remoteName, fullBranchName); err != nil {
 			return &errors.GitError{Operation: fmt.Sprintf("delete remote branch '%s'", fullBranchName), Err: err}
@@ -90,3 +106,39 @@ func DeleteCommand(branchType string, name string, force bool, remote *bool) err
 
 	return nil
 }
+
+// printDeleteSummary prints the unmerged commits and the remote backup status of a branch about to be deleted
+func printDeleteSummary(branchName string, parentBranch string, remoteName string, deleteRemote bool) {
+	if parentBranch != "" {
+		if unmerged, err := git.CountCommits(parentBranch, branchName); err == nil {
+			fmt.Printf("Branch '%s' has %d commit(s) not merged into '%s'\n", branchName, unmerged, parentBranch)
+		}
+	}
+
+	remoteBranch := remoteName + "/" + branchName
+	switch {
+	case !git.RemoteBranchExists(remoteName, branchName):
+		fmt.Printf("No remote backup: '%s' does not exist\n", remoteBranch)
+	case deleteRemote:
+		fmt.Printf("Remote branch '%s' will be deleted as well\n", remoteBranch)
+	default:
+		if unpushed, err := git.CountCommits(remoteBranch, branchName); err == nil && unpushed > 0 {
+			fmt.Printf("Remote backup '%s' is missing %d commit(s)\n", remoteBranch, unpushed)
+		} else {
+			fmt.Printf("Remote backup '%s' is kept\n", remoteBranch)
+		}
+	}
+}
+
+// cleanupBranchConfig removes the gitflow.branch.<fullName>.* settings of a deleted branch, such as its
+// base and start commit, so they don't accumulate. The branch is already gone, so failures only warn.
+func cleanupBranchConfig(fullName string) {
+	section := fmt.Sprintf("gitflow.branch.%s", fullName)
+	values, err := git.GetAllConfig("^" + regexp.QuoteMeta(section+"."))
+	if err != nil || len(values) == 0 {
+		return
+	}
+	if err := git.RemoveConfigSection(section); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to remove settings of branch '%s': %v\n", fullName, err)
+	}
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+)
+
+// DiffCommand shows the changes made on a topic branch since its base
+func DiffCommand(branchType string, name string) error {
+	branchName, base, err := resolveBranchBase(branchType, name)
+	if err != nil {
+		return err
+	}
+
+	output, err := git.Diff(base, branchName)
+	if err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("diff branch '%s'", branchName), Err: err}
+	}
+	fmt.Print(output)
+	return nil
+}
+
+// LogCommand shows the commits made on a topic branch since its base
+func LogCommand(branchType string, name string) error {
+	branchName, base, err := resolveBranchBase(branchType, name)
+	if err != nil {
+		return err
+	}
+
+	output, err := git.Log(base, branchName)
+	if err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("log branch '%s'", branchName), Err: err}
+	}
+	fmt.Print(output)
+	return nil
+}
+
+// resolveBranchBase returns the full name of the topic branch and the commit its changes are based on.
+// The start commit recorded by 'start --track-parent-commit-only' is preferred over the live parent branch.
+func resolveBranchBase(branchType string, name string) (string, string, error) {
+	// Load configuration
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return "", "", &errors.GitError{Operation: "load configuration", Err: err}
+	}
+
+	// Get branch configuration
+	branchConfig, ok := cfg.Branches[branchType]
+	if !ok {
+		return "", "", &errors.InvalidBranchTypeError{BranchType: branchType}
+	}
+
+	// Construct full branch name, defaulting to the current branch
+	branchName := branchConfig.Prefix + name
+	if name == "" {
+		currentBranch, err := git.GetCurrentBranch()
+		if err != nil {
+			return "", "", &errors.GitError{Operation: "get current branch", Err: err}
+		}
+		if !strings.HasPrefix(currentBranch, branchConfig.Prefix) {
+			return "", "", &errors.GitError{Operation: "validate current branch", Err: fmt.Errorf("current branch is not a %s branch", branchType)}
+		}
+		branchName = currentBranch
+	}
+
+	// Check if branch exists
+	if err := git.BranchExists(branchName); err != nil {
+		return "", "", &errors.BranchNotFoundError{BranchName: branchName}
+	}
+
+	// Prefer the recorded start commit
+	startCommit, err := git.GetConfig(fmt.Sprintf("gitflow.branch.%s.startcommit", branchName))
+	if err == nil && startCommit != "" {
+		return branchName, startCommit, nil
+	}
+
+	// Fall back to where the branch diverged from its parent
+	base, err := git.MergeBase(branchConfig.Parent, branchName)
+	if err != nil {
+		return "", "", &errors.GitError{Operation: fmt.Sprintf("find base of branch '%s'", branchName), Err: err}
+	}
+	return branchName, base, nil
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// applyEnvFile sets the flags named in the --env-file, if given, that were not set on the command
+// line. Keys are flag names or their config key form, so SIGNINGKEY, EMIT_EVENT and
+// tag-message-from-changelog all work. Values from the file thereby override config but not flags.
+// The webhook secret has no flag and is only read from gitflow.<type>.finish.webhooksecret or
+// $GITFLOW_WEBHOOK_SECRET, so a WEBHOOK_SECRET key is rejected like any other unknown key.
+func applyEnvFile(cmd *cobra.Command) error {
+	path, _ := cmd.Flags().GetString("env-file")
+	if path == "" {
+		return nil
+	}
+
+	entries, err := util.ReadEnvFile(path)
+	if err != nil {
+		return &errors.InvalidOptionError{Option: "--env-file", Reason: err.Error()}
+	}
+
+	for _, entry := range entries {
+		flag := lookupEnvFileFlag(cmd, entry.Key)
+		if flag == nil || flag.Name == "env-file" {
+			return &errors.InvalidOptionError{Option: "--env-file", Reason: fmt.Sprintf("%s:%d: unknown option '%s'", path, entry.Line, entry.Key)}
+		}
+		if flag.Changed {
+			continue
+		}
+		if err := cmd.Flags().Set(flag.Name, entry.Value); err != nil {
+			return &errors.InvalidOptionError{Option: "--env-file", Reason: fmt.Sprintf("%s:%d: invalid value for '%s': %v", path, entry.Line, entry.Key, err)}
+		}
+	}
+	return nil
+}
+
+// lookupEnvFileFlag finds the flag for an env file key, ignoring case, underscores and hyphens
+func lookupEnvFileFlag(cmd *cobra.Command, key string) *pflag.Flag {
+	normalized := strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+	if flag := cmd.Flags().Lookup(normalized); flag != nil {
+		return flag
+	}
+
+	compact := strings.ReplaceAll(normalized, "-", "")
+	var match *pflag.Flag
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if match == nil && strings.ReplaceAll(flag.Name, "-", "") == compact {
+			match = flag
+		}
+	})
+	return match
+}
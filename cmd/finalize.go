@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/mergestate"
+)
+
+// reservedTagSection is the config section recording a tag reserved by finish --reserve-tag
+func reservedTagSection(tagName string) string {
+	return fmt.Sprintf("gitflow.reservedtag.%s", tagName)
+}
+
+// reserveTag creates a lightweight placeholder tag at the target and records the reservation,
+// so that finalize can later replace it with the final annotated tag
+func reserveTag(state *mergestate.MergeState, tagName string, tagTarget string, forceTag bool) error {
+	if err := git.CreateTag(tagName, &git.TagOptions{Target: tagTarget, Force: forceTag, Lightweight: true}); err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("reserve tag '%s'", tagName), Err: err}
+	}
+
+	section := reservedTagSection(tagName)
+	if err := git.SetConfig(section+".type", state.BranchType); err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("record reservation of tag '%s'", tagName), Err: err}
+	}
+	if err := git.SetConfig(section+".branch", state.ParentBranch); err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("record reservation of tag '%s'", tagName), Err: err}
+	}
+	fmt.Printf("Reserved tag '%s'; run 'git flow %s finalize %s' to replace it with the final tag\n", tagName, state.BranchType, tagName)
+
+	// Remember the tag so later steps and hooks can refer to it
+	state.TagName = tagName
+	state.TagCreated = true
+	if err := saveMergeState(state); err != nil {
+		return &errors.GitError{Operation: "save merge state", Err: err}
+	}
+	return nil
+}
+
+// FinalizeCommand is the implementation of the finalize command for topic branches
+func FinalizeCommand(branchType string, name string, commit string, tagOptions *TagOptions) error {
+	// Load configuration
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return &errors.GitError{Operation: "load configuration", Err: err}
+	}
+
+	// Get branch configuration
+	branchConfig, ok := cfg.Branches[branchType]
+	if !ok {
+		return &errors.InvalidBranchTypeError{BranchType: branchType}
+	}
+
+	// Accept the tag name as well as the version without the tag prefix
+	tagName := name
+	branch, err := git.GetConfig(reservedTagSection(tagName) + ".branch")
+	if err != nil && branchConfig.TagPrefix != "" {
+		tagName = branchConfig.TagPrefix + name
+		branch, err = git.GetConfig(reservedTagSection(tagName) + ".branch")
+	}
+	if err != nil {
+		return &errors.TagNotReservedError{TagName: tagName}
+	}
+
+	// The final tag goes to the given commit, or to the current tip of the branch the placeholder was created on
+	target := branch
+	if commit != "" {
+		target = commit
+	}
+	targetCommit, err := git.ResolveCommit(target)
+	if err != nil && commit != "" {
+		return &errors.InvalidOptionError{Option: "--commit", Reason: err.Error()}
+	}
+	if err != nil {
+		return &errors.BranchNotFoundError{BranchName: branch}
+	}
+
+	// Determine tag message, with the same defaults and overrides as finish
+	message := fmt.Sprintf("Tagging version %s", tagName)
+	messageFile := ""
+	if configMessageFile, err := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.messagefile", branchType)); err == nil && configMessageFile != "" {
+		messageFile = configMessageFile
+	}
+	if tagOptions != nil && tagOptions.Message != "" {
+		message = tagOptions.Message
+	}
+	if tagOptions != nil && tagOptions.MessageFile != "" {
+		messageFile = tagOptions.MessageFile
+	}
+	shouldSign, signingKey := getTagSigning(branchType, tagOptions)
+
+	gitTagOptions := &git.TagOptions{
+		Message:     message,
+		MessageFile: messageFile,
+		Sign:        shouldSign,
+		SigningKey:  signingKey,
+		Target:      targetCommit,
+		Force:       true,
+	}
+	if err := git.CreateTag(tagName, gitTagOptions); err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("finalize tag '%s'", tagName), Err: err}
+	}
+	fmt.Printf("Finalized tag '%s' at %s\n", tagName, shortSHA(targetCommit))
+
+	// Verify the signature right away so a broken signing setup surfaces now
+	if shouldSign && shouldVerifyCreatedTag(branchType) {
+		status, err := git.VerifyTag(tagName)
+		if err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("verify tag '%s'", tagName), Err: err}
+		}
+		fmt.Printf("Verified signature of tag '%s': %s\n", tagName, status)
+	}
+
+	// The tag is final now, so the reservation is no longer needed
+	if err := git.RemoveConfigSection(reservedTagSection(tagName)); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to remove the reservation of tag '%s': %v\n", tagName, err)
+	}
+	return nil
+}
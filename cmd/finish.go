@@ -1,23 +1,40 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
 	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/gittower/git-flow-next/internal/config"
 	"github.com/gittower/git-flow-next/internal/errors"
 	"github.com/gittower/git-flow-next/internal/git"
 	"github.com/gittower/git-flow-next/internal/mergestate"
 	"github.com/gittower/git-flow-next/internal/update"
+	"github.com/gittower/git-flow-next/internal/util"
 )
 
 // Step constants
 const (
+	stepRefreshParent  = "refresh_parent"
 	stepMerge          = "merge"
 	stepCreateTag      = "create_tag"
+	stepMergeAlsoInto  = "merge_also_into"
 	stepUpdateChildren = "update_children"
 	stepDeleteBranch   = "delete_branch"
+	stepPush           = "push"
+)
+
+// Changelog defaults used for --tag-message-from-changelog
+const (
+	defaultChangelogFile    = "CHANGELOG.md"
+	defaultChangelogSection = `(?i)^#+\s*\[?unreleased\]?`
 )
 
 // Strategy constants
@@ -35,6 +52,21 @@ type TagOptions struct {
 	Message     string // Custom message for the tag
 	MessageFile string // File containing the message
 	TagName     string // Custom tag name
+
+	MessageFromChangelog bool // Use the unreleased section of the changelog as the tag message
+	RenameIfExists       bool // Append a numeric suffix when the tag already exists (takes precedence over Force and NoReuse)
+	Force                bool // Replace an existing tag
+	ForceRemote          bool // With Force, replace the tag even if it was already pushed to the remote
+	NoReuse              bool // Fail instead of reusing an existing tag at the commit to tag
+
+	Since        string // Baseline ref; the commits since it are listed in the tag message
+	Object       string // Object to tag instead of the tip of the target, e.g. a build artifact tree
+	TargetBranch string // Branch to tag instead of the target, e.g. develop for a hotfix (overrides config)
+	Dereference  *bool  // Whether an annotated tag given as the object is tagged as the object it points to (nil means true)
+
+	Contributors *bool // Whether to list the authors since the last tag in the tag message (nil means use config default)
+
+	Reserve bool // Only create a lightweight placeholder tag, replaced by the final tag with finalize
 }
 
 // BranchRetentionOptions contains options for branch retention when finishing a branch
@@ -43,11 +75,83 @@ type BranchRetentionOptions struct {
 	KeepRemote  *bool // Whether to keep the remote branch (nil means use config default)
 	KeepLocal   *bool // Whether to keep the local branch (nil means use config default)
 	ForceDelete *bool // Whether to force delete the branch (nil means use config default)
+
+	ForceRemoteDelete bool  // Whether to delete the remote branch even if it has commits that were not merged
+	VerifyRemote      *bool // Whether to fetch the remote branch and check it is merged before deleting it (nil means use config default)
+
+	KeepBranchConfig *bool // Whether to keep the gitflow.branch.<name>.* settings of a deleted branch (nil means use config default)
+}
+
+// FinishOptions contains general options controlling how a branch is finished
+type FinishOptions struct {
+	Ours            bool     // Resolve conflicting hunks in favor of the target branch (-X ours)
+	Theirs          bool     // Resolve conflicting hunks in favor of the finished branch (-X theirs)
+	StrategyOptions []string // Further merge strategy options passed to git merge as -X (e.g. find-renames=30%)
+
+	AllowUnrelatedHistories bool   // Merge a branch that shares no common ancestor with the target
+	MergeBaseOverride       string // Commit a squash takes the changes of the branch from, instead of the merge base
+	SquashMessage           string // Message of the squash commit (overrides SquashMessageFile and config)
+	SquashMessageFile       string // File with the message of the squash commit (overrides config)
+
+	SourceRef string // Arbitrary ref (tag, commit) to integrate instead of a topic branch
+
+	MinCommits       *int  // Fewest commits not in the target the branch must have, 0 for no limit (nil means use config default)
+	MaxCommits       *int  // Most commits not in the target the branch may have, 0 for no limit (nil means use config default)
+	CommitCountGuard *bool // Whether to refuse a finish outside the commit count limits (nil means true)
+
+	TargetTrackingBranch bool // Whether to create or fast-forward the target branch from its remote-tracking branch
+
+	TargetRemoteTrackingUpdate bool  // Whether to fetch the remote-tracking branches of the updated base branches after the finish
+	Push                       *bool // Whether to push the target, the updated child base branches and the tag after the finish (nil means use config default)
+	TagPushSafe                *bool // Whether a replaced tag is force-pushed only if the remote still has its previous value (nil means use config default)
+	LocalOnly                  *bool // Whether the finish keeps the remote branch and pushes nothing, so it never contacts the remote (nil means use config default)
+
+	SkipEmptyChildren  bool  // Skip updating child base branches that already contain the parent tip
+	AutoUpdateChildren *bool // Whether to update all child base branches (true), none (false) or only those with autoUpdate enabled (nil)
+	ChildrenParallel   bool  // Check all child base branches concurrently before updating the ones that need it
+	KeepHistoryNote    bool  // Attach a git note with the git-flow metadata to the merge commit
+
+	CommitDate string // Author/committer date for the merge commit and tag (defaults to GIT_AUTHOR_DATE/GIT_COMMITTER_DATE or now)
+
+	EditMergeMessage *bool // Whether to edit the merge or squash commit message in the editor (nil means use config default)
+
+	AlsoInto         []string // Additional branches the branch is merged into after the target, in order
+	AlsoIntoStrategy string   // Strategy used to merge into the additional branches (merge or squash, defaults to merge)
+
+	PreventDeleteRace  bool // Refuse to delete the branch if it received new commits after it was merged
+	Idempotent         bool // Succeed without changes if the branch is gone and its tag is already on the target
+	ReuseExistingMerge bool // Tag the existing merge instead of merging again if the branch is already merged into the target
+
+	PostFinishCommand string // Shell command to run after a successful finish (overrides config)
+	IssueCommand      string // Shell command to comment on the branch's issue after a successful finish (overrides config)
+	EmitEvent         string // Webhook URL to post the finish outcome to (overrides config)
+	OutputFormat      string // Template for the success line (overrides config)
+	ReflogMessage     string // Reflog message for the git operations of the finish (defaults to "git-flow finish <branch>")
+	Confirm           *bool  // Whether to ask for confirmation before acting (nil means use config default)
+	AssumeYes         bool   // Whether to finish a branch without the type's prefix without asking
+	StashUntracked    bool   // Whether to stash untracked files during the finish and restore them afterwards
+	Approve           string // Protected target branch the finish is approved to merge into
+	ListSteps         bool   // Whether to only print the steps the finish would run
+	ValidateOnly      bool   // Whether to only run the checks of the finish and report whether they pass
+	DryRun            bool   // Whether to only describe the finish, like ListSteps
+	JSON              bool   // Whether to describe the finish as a JSON FinishPlan (requires DryRun)
+	ReportTiming      bool   // Whether to print the duration of each finish step at the end
+	PrintTag          bool   // Whether to print a "tag: <name> <sha>" line for the created tag, for scripts
+	RecordMetrics     string // CSV file to append a row with the metrics of the finish to
+	RecordSnapshot    *bool  // Whether to log the commits of the base branches before and after the finish (nil means use config default)
+	ChildrenReport    bool   // Whether to print the strategy and outcome of each child base branch update
+	Verbose           bool   // Whether to print additional output such as hook output
 }
 
 // FinishCommand is the implementation of the finish command for topic branches
-func FinishCommand(branchType string, name string, continueOp bool, abortOp bool, force bool, tagOptions *TagOptions, retentionOptions *BranchRetentionOptions) {
-	if err := executeFinish(branchType, name, continueOp, abortOp, force, tagOptions, retentionOptions); err != nil {
+func FinishCommand(branchType string, name string, continueOp bool, abortOp bool, force bool, tagOptions *TagOptions, retentionOptions *BranchRetentionOptions, finishOptions *FinishOptions) {
+	// Leave a resumable state behind when interrupted
+	stopWatching := watchInterrupts()
+	defer stopWatching()
+
+	_, err := executeFinish(branchType, name, continueOp, abortOp, force, tagOptions, retentionOptions, finishOptions)
+	emitFinishConflictEvent(err, finishOptions)
+	if err := recordFinishConflict(err); err != nil {
 		var exitCode errors.ExitCode
 		if flowErr, ok := err.(errors.Error); ok {
 			exitCode = flowErr.ExitCode()
@@ -59,59 +163,115 @@ func FinishCommand(branchType string, name string, continueOp bool, abortOp bool
 	}
 }
 
-// executeFinish performs the actual branch finishing logic and returns any errors
-func executeFinish(branchType string, name string, continueOp bool, abortOp bool, force bool, tagOptions *TagOptions, retentionOptions *BranchRetentionOptions) error {
+// executeFinish performs the actual branch finishing logic and returns the outcome of a completed finish and any errors
+func executeFinish(branchType string, name string, continueOp bool, abortOp bool, force bool, tagOptions *TagOptions, retentionOptions *BranchRetentionOptions, finishOptions *FinishOptions) (*FinishResult, error) {
+	if finishOptions != nil && finishOptions.ReportTiming {
+		enableStepTiming()
+	}
+
 	// Get configuration early
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		return &errors.GitError{Operation: "load configuration", Err: err}
+		return nil, &errors.GitError{Operation: "load configuration", Err: err}
 	}
 
 	// Get branch configuration
 	branchConfig, ok := cfg.Branches[branchType]
 	if !ok {
-		return &errors.InvalidBranchTypeError{BranchType: branchType}
+		return nil, &errors.InvalidBranchTypeError{BranchType: branchType}
+	}
+
+	// Only describe the finish, without running it
+	if finishOptions != nil && finishOptions.JSON {
+		if !finishOptions.DryRun {
+			return nil, &errors.InvalidOptionError{Option: "--json", Reason: "can only be used together with --dry-run"}
+		}
+		return nil, printFinishPlanJSON(branchType, name, branchConfig, cfg, tagOptions, retentionOptions, finishOptions)
+	}
+	if finishOptions != nil && (finishOptions.ListSteps || finishOptions.DryRun) {
+		return nil, listFinishSteps(branchType, name, branchConfig, cfg, tagOptions, retentionOptions, finishOptions)
+	}
+	if finishOptions != nil && finishOptions.ValidateOnly {
+		return nil, validateFinishOnly(branchType, name, branchConfig, cfg, tagOptions, retentionOptions, finishOptions)
 	}
 
 	// Check if there's a merge in progress
 	if mergestate.IsMergeInProgress() {
 		state, err := mergestate.LoadMergeState()
 		if err != nil {
-			return &errors.GitError{Operation: "load merge state", Err: err}
+			return nil, &errors.GitError{Operation: "load merge state", Err: err}
 		}
 
 		// Get the branch config for the state's branch type
 		stateBranchConfig, ok := cfg.Branches[state.BranchType]
 		if !ok {
-			return &errors.InvalidBranchTypeError{BranchType: state.BranchType}
+			return nil, &errors.InvalidBranchTypeError{BranchType: state.BranchType}
 		}
 
 		if abortOp {
-			return handleAbort(state)
+			return handleAbort(state, finishOptions)
 		}
 
 		if continueOp {
-			return handleContinue(state, stateBranchConfig, tagOptions, retentionOptions)
+			if err := validateContinueState(state); err != nil {
+				return nil, err
+			}
+			if err := setInterruptState(state); err != nil {
+				return nil, &errors.GitError{Operation: "register merge state", Err: err}
+			}
+			return handleContinue(state, stateBranchConfig, tagOptions, retentionOptions, finishOptions)
 		}
 
-		return &errors.MergeInProgressError{BranchName: state.FullBranchName}
+		return nil, &errors.MergeInProgressError{BranchName: state.FullBranchName}
 	}
 
 	// Don't allow continue or abort if no merge is in progress
 	if continueOp || abortOp {
-		return &errors.NoMergeInProgressError{}
+		return nil, &errors.NoMergeInProgressError{}
+	}
+
+	// A rebase or cherry-pick started outside git-flow must be completed first
+	if err := ensureNoGitOperationInProgress("finish"); err != nil {
+		return nil, err
+	}
+
+	// A branch that is never merged back, such as a support branch, is only tagged and pushed
+	if !mergesBack(branchConfig) {
+		return finishWithoutMerge(branchType, name, branchConfig, cfg, tagOptions, retentionOptions, finishOptions)
+	}
+
+	// A source ref is integrated as-is; the name only serves as the short name
+	if finishOptions != nil && finishOptions.SourceRef != "" {
+		return finishBranch(branchType, name, branchConfig, tagOptions, retentionOptions, finishOptions)
 	}
 
 	// Resolve branch name (try with and without prefix)
 	resolvedName, err := resolveBranchName(name, branchConfig)
 	if err != nil {
-		return err
+		// A retried finish that already completed is not an error, if requested
+		if finishOptions != nil && finishOptions.Idempotent {
+			if tagName, targetBranch, finished := finishAlreadyCompleted(branchType, name, branchConfig, tagOptions); finished {
+				fmt.Printf("Branch '%s' already finished: tag '%s' is part of '%s'\n", branchConfig.Prefix+strings.TrimPrefix(name, branchConfig.Prefix), tagName, targetBranch)
+				return nil, nil
+			}
+		}
+		return nil, err
 	}
 	name = resolvedName
 
-	// If the branch exists but doesn't have the expected prefix
+	// Refuse to finish a base branch before asking any questions about it
+	targetBranch, err := getFinishTarget(branchType, name, branchConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureNotBaseBranch(name, targetBranch, cfg); err != nil {
+		return nil, err
+	}
+
+	// If the branch exists but doesn't have the expected prefix, ask unless --force or --assume-yes confirms it
+	assumeYes := finishOptions != nil && finishOptions.AssumeYes
 	if !strings.HasPrefix(name, branchConfig.Prefix) {
-		if !force {
+		if !force && !assumeYes {
 			// Get the short name for tag creation
 			shortName := name
 			if strings.Contains(name, "/") {
@@ -122,7 +282,7 @@ func executeFinish(branchType string, name string, continueOp bool, abortOp bool
 			// Prompt user for confirmation
 			fmt.Printf("Warning: Branch '%s' is not a standard %s branch (missing prefix '%s').\n", name, branchType, branchConfig.Prefix)
 			fmt.Printf("Finishing this branch will:\n")
-			fmt.Printf("1. Merge it into '%s' using the %s strategy\n", branchConfig.Parent, branchConfig.UpstreamStrategy)
+			fmt.Printf("1. Merge it into '%s' using the %s strategy\n", targetBranch, branchConfig.UpstreamStrategy)
 
 			// Adjust tag message based on tag options
 			showTagMessage := branchConfig.Tag
@@ -144,33 +304,29 @@ func executeFinish(branchType string, name string, continueOp bool, abortOp bool
 			}
 
 			fmt.Printf("3. Delete the branch after successful merge\n\n")
-			fmt.Printf("Do you want to continue? [y/N]: ")
-
-			var response string
-			fmt.Scanln(&response)
-			if strings.ToLower(response) != "y" {
-				return fmt.Errorf("operation cancelled by user")
+			if confirmed, _ := askConfirmation("Do you want to continue?"); !confirmed {
+				return nil, &errors.OperationCancelledError{}
 			}
 		}
 	}
 
 	// Regular finish command flow
-	return finishBranch(branchType, name, branchConfig, tagOptions, retentionOptions)
+	return finishBranch(branchType, name, branchConfig, tagOptions, retentionOptions, finishOptions)
 }
 
-func finishBranch(branchType string, name string, branchConfig config.BranchConfig, tagOptions *TagOptions, retentionOptions *BranchRetentionOptions) error {
+func finishBranch(branchType string, name string, branchConfig config.BranchConfig, tagOptions *TagOptions, retentionOptions *BranchRetentionOptions, finishOptions *FinishOptions) (*FinishResult, error) {
 	// Validate that git-flow is initialized
 	initialized, err := config.IsInitialized()
 	if err != nil {
-		return &errors.GitError{Operation: "check if git-flow is initialized", Err: err}
+		return nil, &errors.GitError{Operation: "check if git-flow is initialized", Err: err}
 	}
 	if !initialized {
-		return &errors.NotInitializedError{}
+		return nil, &errors.NotInitializedError{}
 	}
 
 	// Validate inputs
 	if name == "" {
-		return &errors.InvalidBranchNameError{Name: name}
+		return nil, &errors.InvalidBranchNameError{Name: name}
 	}
 
 	// Get the short name by removing the prefix if it exists
@@ -183,30 +339,136 @@ func finishBranch(branchType string, name string, branchConfig config.BranchConf
 		shortName = parts[len(parts)-1]
 	}
 
-	// Check if branch exists
-	if err := git.BranchExists(name); err != nil {
-		return &errors.BranchNotFoundError{BranchName: name}
+	sourceRef := ""
+	if finishOptions != nil {
+		sourceRef = finishOptions.SourceRef
 	}
 
-	// Get target branch (always the parent branch)
-	targetBranch := branchConfig.Parent
+	if sourceRef != "" {
+		// Integrate an arbitrary ref; the given name is only used as the short name
+		if _, err := git.ResolveCommit(sourceRef); err != nil {
+			return nil, &errors.GitError{Operation: fmt.Sprintf("resolve source ref '%s'", sourceRef), Err: err}
+		}
+		if strings.ToLower(branchConfig.UpstreamStrategy) == strategyRebase {
+			return nil, &errors.InvalidOptionError{Option: "--source-ref", Reason: "cannot be used with the rebase strategy"}
+		}
+		shortName = strings.TrimPrefix(name, branchConfig.Prefix)
+		name = sourceRef
+	} else if err := git.BranchExists(name); err != nil {
+		// Check if branch exists
+		return nil, &errors.BranchNotFoundError{BranchName: name}
+	}
 
-	// Check if target branch exists
-	if err := git.BranchExists(targetBranch); err != nil {
-		return &errors.BranchNotFoundError{BranchName: targetBranch}
+	// Get target branch (the parent branch, unless routed elsewhere by name)
+	routedName := name
+	if sourceRef != "" {
+		routedName = branchConfig.Prefix + shortName
+	}
+	targetBranch, err := getFinishTarget(branchType, routedName, branchConfig)
+	if err != nil {
+		return nil, err
 	}
 
 	// Find child base branches that need to be updated
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		return &errors.GitError{Operation: "load configuration", Err: err}
+		return nil, &errors.GitError{Operation: "load configuration", Err: err}
 	}
 
-	childBranches := []string{}
-	for branchName, branch := range cfg.Branches {
-		if branch.Type == string(config.BranchTypeBase) && branch.Parent == targetBranch {
-			fmt.Printf("Found child base branch '%s' to update\n", branchName)
-			childBranches = append(childBranches, branchName)
+	// Check if target branch exists, creating or updating it from its remote-tracking branch if requested
+	if shouldUseTargetTrackingBranch(branchType, finishOptions) {
+		if err := syncTargetFromRemote(targetBranch, cfg.Remote); err != nil {
+			return nil, err
+		}
+	}
+	if err := git.BranchExists(targetBranch); err != nil {
+		return nil, &errors.BranchNotFoundError{BranchName: targetBranch}
+	}
+
+	// Never merge a branch into itself or finish a base branch
+	if err := ensureNotBaseBranch(name, targetBranch, cfg); err != nil {
+		return nil, err
+	}
+
+	// Validate merge-related options against the configured strategy
+	if err := validateFinishOptions(branchConfig, finishOptions); err != nil {
+		return nil, err
+	}
+	if err := checkLocalOnlyOptions(branchType, retentionOptions, finishOptions); err != nil {
+		return nil, err
+	}
+
+	// Every additional target must be a separate, existing branch
+	if finishOptions != nil {
+		if err := validateAlsoInto(name, targetBranch, finishOptions.AlsoInto); err != nil {
+			return nil, err
+		}
+	}
+
+	// Editing the merge message needs someone at a terminal, or an editor that runs on its own
+	if shouldEditMergeMessage(branchType, finishOptions) {
+		if err := ensureEditorAvailable(); err != nil {
+			return nil, err
+		}
+	}
+
+	// The squash base must be part of the history of the branch
+	if finishOptions != nil && finishOptions.MergeBaseOverride != "" {
+		if err := validateMergeBaseOverride(finishOptions.MergeBaseOverride, name); err != nil {
+			return nil, err
+		}
+	}
+
+	// The baseline for the tag message and the object to tag must exist before anything is merged
+	if tagOptions != nil && tagOptions.Since != "" {
+		if _, err := git.ResolveCommit(tagOptions.Since); err != nil {
+			return nil, &errors.InvalidOptionError{Option: "--since", Reason: err.Error()}
+		}
+	}
+	if tagOptions != nil && tagOptions.Object != "" {
+		if _, err := git.ResolveObject(tagOptions.Object); err != nil {
+			return nil, &errors.InvalidOptionError{Option: "--tag-object", Reason: err.Error()}
+		}
+	}
+
+	// Untracked files don't block a finish, but changes to tracked files do
+	hasChanges, err := git.HasTrackedChanges()
+	if err != nil {
+		return nil, &errors.GitError{Operation: "check working tree", Err: err}
+	}
+	if hasChanges {
+		return nil, &errors.UncommittedChangesError{Operation: "finish"}
+	}
+
+	// A branch without commits or with too many of them is likely not the one meant to be finished
+	if err := checkCommitCount(branchType, name, targetBranch, finishOptions); err != nil {
+		return nil, err
+	}
+
+	// Find child base branches that need to be updated
+	childBranches, skippedChildren := autoUpdateChildren(cfg, childBaseBranches(cfg, targetBranch), finishOptions)
+	for _, branchName := range childBranches {
+		fmt.Printf("Found child base branch '%s' to update\n", branchName)
+	}
+	for _, branchName := range skippedChildren {
+		fmt.Printf("Skipping child base branch '%s', which is not updated automatically (use --autoupdate-children to update it)\n", branchName)
+	}
+
+	// Every branch the finish checks out must be free, which it isn't when used by another worktree
+	checkoutBranches := append([]string{targetBranch}, childBranches...)
+	if finishOptions != nil {
+		checkoutBranches = append(checkoutBranches, finishOptions.AlsoInto...)
+	}
+	if sourceRef == "" {
+		checkoutBranches = append(checkoutBranches, name)
+	}
+	for _, branch := range checkoutBranches {
+		worktreePath, err := git.OtherWorktreeForBranch(branch)
+		if err != nil {
+			return nil, &errors.GitError{Operation: "list worktrees", Err: err}
+		}
+		if worktreePath != "" {
+			return nil, &errors.BranchInOtherWorktreeError{BranchName: branch, Path: worktreePath}
 		}
 	}
 
@@ -217,200 +479,1659 @@ func finishBranch(branchType string, name string, branchConfig config.BranchConf
 		BranchName:      shortName,
 		CurrentStep:     stepMerge,
 		ParentBranch:    targetBranch,
-		MergeStrategy:   branchConfig.UpstreamStrategy,
+		MergeStrategy:   strings.ToLower(branchConfig.UpstreamStrategy),
 		FullBranchName:  name,
+		IsSourceRef:     sourceRef != "",
 		ChildBranches:   childBranches,
 		UpdatedBranches: []string{},
 	}
-	if err := mergestate.SaveMergeState(state); err != nil {
-		return &errors.GitError{Operation: "save merge state", Err: err}
+	if finishOptions != nil {
+		state.SkipEmptyChildren = finishOptions.SkipEmptyChildren
+		state.ChildrenParallel = finishOptions.ChildrenParallel
+		state.HistoryNote = finishOptions.KeepHistoryNote
+		state.AlsoInto = finishOptions.AlsoInto
+		state.AlsoIntoStrategy = finishOptions.AlsoIntoStrategy
+		state.PreventDeleteRace = finishOptions.PreventDeleteRace
+		state.ReflogAction = finishOptions.ReflogMessage
+		state.Webhook = finishOptions.EmitEvent
+	}
+	// Whether to push is decided now, so that a finish resumed with a plain --continue still pushes
+	state.LocalOnly = shouldFinishLocally(branchType, finishOptions)
+	state.Push = shouldPushBaseBranches(branchType, finishOptions)
+	if state.Push {
+		state.TagPushSafe = shouldPushTagSafely(branchType, finishOptions)
+	}
+	if err := startFinishMetrics(state, finishOptions); err != nil {
+		return nil, err
+	}
+	if err := startBaseSnapshot(state, finishOptions); err != nil {
+		return nil, err
 	}
 
-	return finish(state, branchConfig, tagOptions, retentionOptions)
-}
-
-// resolveBranchName tries to find the branch name with and without prefix
-func resolveBranchName(name string, branchConfig config.BranchConfig) (string, error) {
-	// Try name as-is first
-	if err := git.BranchExists(name); err == nil {
-		return name, nil
+	// Read the issue now, the branch settings are gone by the time the finish succeeds
+	if issue, err := git.GetConfig(fmt.Sprintf("gitflow.branch.%s.issue", name)); err == nil {
+		state.Issue = issue
 	}
 
-	// If not found as-is, try with prefix
-	if !strings.HasPrefix(name, branchConfig.Prefix) {
-		fullName := branchConfig.Prefix + name
-		if err := git.BranchExists(fullName); err == nil {
-			return fullName, nil
+	// Remember where the child branches and additional targets were, so that an abort can restore them
+	for _, branchName := range append(childBranches, state.AlsoInto...) {
+		if err := recordChildBranchHead(state, branchName); err != nil {
+			return nil, err
+		}
+	}
+	if state.TargetHead, err = git.ResolveCommit(targetBranch); err != nil {
+		return nil, &errors.GitError{Operation: fmt.Sprintf("resolve target branch '%s'", targetBranch), Err: err}
+	}
+	if sourceRef == "" {
+		// A rebase rewrites the finished branch, so it is restored as well
+		if state.BranchHead, err = git.ResolveCommit(name); err != nil {
+			return nil, &errors.GitError{Operation: fmt.Sprintf("resolve branch '%s'", name), Err: err}
 		}
 	}
 
-	return "", &errors.BranchNotFoundError{BranchName: name}
-}
-
-// handleCreateTagStep handles the tag creation step
-func handleCreateTagStep(state *mergestate.MergeState, branchConfig config.BranchConfig, tagOptions *TagOptions, retentionOptions *BranchRetentionOptions) error {
-	// 1. Start with branch configuration default
-	shouldTag := branchConfig.Tag
+	// The tag may go on another branch that receives the finished branch
+	if shouldCreateTag(branchType, branchConfig, tagOptions) {
+		if state.TagTarget, err = getTagBranch(state, tagOptions); err != nil {
+			return nil, err
+		}
+	}
 
-	// 2. Check for branch-specific config override
-	branchSpecificTagConfig, err := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.notag", state.BranchType))
-	if err == nil && branchSpecificTagConfig == "true" {
-		// notag=true means don't create a tag
-		shouldTag = false
+	// Merging into a protected branch needs explicit approval
+	if targetConfig, ok := cfg.Branches[targetBranch]; ok && targetConfig.Protected {
+		approved, err := approveProtectedTarget(name, targetBranch, finishOptions)
+		if err != nil {
+			return nil, err
+		}
+		if !approved {
+			return nil, &errors.OperationCancelledError{}
+		}
 	}
 
-	// 3. Command-line flags override config
-	if tagOptions != nil && tagOptions.ShouldTag != nil {
-		shouldTag = *tagOptions.ShouldTag
+	// Show the plan and ask before changing anything, if requested
+	if shouldConfirmFinish(finishOptions) {
+		printFinishPlan(state, branchConfig, tagOptions, retentionOptions)
+		confirmed, err := confirmFinish()
+		if err != nil {
+			return nil, err
+		}
+		if !confirmed {
+			return nil, &errors.OperationCancelledError{}
+		}
 	}
 
-	if shouldTag {
-		if err := createTagForBranch(state, branchConfig, tagOptions); err != nil {
-			return err
+	// Move untracked files out of the way until the finish is complete, if requested
+	if finishOptions != nil && finishOptions.StashUntracked {
+		hasUntracked, err := git.HasUntrackedFiles()
+		if err != nil {
+			return nil, &errors.GitError{Operation: "check working tree", Err: err}
+		}
+		if hasUntracked {
+			stash, err := git.StashUntracked(fmt.Sprintf("git-flow: untracked files while finishing %s", name))
+			if err != nil {
+				return nil, &errors.GitError{Operation: "stash untracked files", Err: err}
+			}
+			fmt.Println("Stashed untracked files")
+			state.UntrackedStash = stash
 		}
 	}
 
-	// Move to next step
-	state.CurrentStep = stepUpdateChildren
-	if err := mergestate.SaveMergeState(state); err != nil {
-		return &errors.GitError{Operation: "save merge state", Err: err}
+	// Bring the target branch up to date with its own parent first, if configured
+	refreshParent := shouldRefreshParent(branchType, targetBranch, cfg)
+	if refreshParent {
+		state.CurrentStep = stepRefreshParent
 	}
-	return handleContinue(state, branchConfig, tagOptions, retentionOptions)
+	if err := saveMergeState(state); err != nil {
+		return nil, &errors.GitError{Operation: "save merge state", Err: err}
+	}
+
+	if refreshParent {
+		return handleRefreshParentStep(state, branchConfig, tagOptions, retentionOptions, finishOptions)
+	}
+	return finish(state, branchConfig, tagOptions, retentionOptions, finishOptions)
 }
 
-// createTagForBranch creates a tag for the finished branch
-func createTagForBranch(state *mergestate.MergeState, branchConfig config.BranchConfig, tagOptions *TagOptions) error {
-	// Determine tag name
-	// 1. Start with branch name and apply prefix from branch config
-	tagName := state.BranchName
-	if branchConfig.TagPrefix != "" {
-		tagName = branchConfig.TagPrefix + state.BranchName
+// reflogAction returns the label of the reflog entries written by the merges of the finish, so the
+// reflog shows which changes were made by git-flow
+func reflogAction(state *mergestate.MergeState) string {
+	if state.ReflogAction != "" {
+		return state.ReflogAction
 	}
+	return fmt.Sprintf("git-flow finish %s", state.FullBranchName)
+}
 
-	// 2. Command-line custom tag name overrides config
-	if tagOptions != nil && tagOptions.TagName != "" {
-		tagName = tagOptions.TagName
+// shouldUseTargetTrackingBranch reports whether the target branch may be created or updated from its
+// remote-tracking branch, via --target-tracking-branch or gitflow.<type>.finish.createlocaltarget
+func shouldUseTargetTrackingBranch(branchType string, finishOptions *FinishOptions) bool {
+	if finishOptions != nil && finishOptions.TargetTrackingBranch {
+		return true
 	}
+	createConfig, err := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.createlocaltarget", branchType))
+	return err == nil && createConfig == "true"
+}
 
-	// Determine tag message
-	// Default message
-	message := fmt.Sprintf("Tagging version %s", tagName)
+// syncTargetFromRemote creates the local target branch from its remote-tracking branch when only the
+// latter exists, as in CI checkouts that fetched the base branches only, or fast-forwards the local
+// branch when it is behind. A missing remote-tracking branch leaves the target as it is.
+func syncTargetFromRemote(targetBranch string, remote string) error {
+	remoteRef := fmt.Sprintf("%s/%s", remote, targetBranch)
+	if _, err := git.ResolveCommit("refs/remotes/" + remoteRef); err != nil {
+		return nil
+	}
 
-	// Command-line message overrides default
-	if tagOptions != nil && tagOptions.Message != "" {
-		message = tagOptions.Message
+	if err := git.BranchExists(targetBranch); err != nil {
+		if err := git.CreateTrackingBranch(targetBranch, remoteRef); err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("create local branch '%s'", targetBranch), Err: err}
+		}
+		fmt.Printf("Created local branch '%s' from '%s'\n", targetBranch, remoteRef)
+		return nil
 	}
 
-	// Handle message file
-	useMessageFile := false
-	messageFilePath := ""
+	behind, err := git.CountCommits(targetBranch, remoteRef)
+	if err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("compare '%s' with '%s'", targetBranch, remoteRef), Err: err}
+	}
+	if behind == 0 {
+		return nil
+	}
+	fastForward, err := git.IsAncestor(targetBranch, remoteRef)
+	if err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("compare '%s' with '%s'", targetBranch, remoteRef), Err: err}
+	}
+	currentBranch, err := git.GetCurrentBranch()
+	if err != nil {
+		return &errors.GitError{Operation: "get current branch", Err: err}
+	}
+	if !fastForward || currentBranch == targetBranch {
+		fmt.Fprintf(os.Stderr, "Warning: '%s' is behind '%s' and cannot be fast-forwarded here, finishing into the local branch\n", targetBranch, remoteRef)
+		return nil
+	}
+	if err := git.ResetBranch(targetBranch, remoteRef); err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("update local branch '%s'", targetBranch), Err: err}
+	}
+	fmt.Printf("Fast-forwarded '%s' to '%s'\n", targetBranch, remoteRef)
+	return nil
+}
 
-	// 1. Check for branch-specific message file config
-	configMessageFile, err := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.messagefile", state.BranchType))
-	if err == nil && configMessageFile != "" {
-		useMessageFile = true
-		messageFilePath = configMessageFile
+// getFinishTarget selects the branch to merge into. Entries of gitflow.<type>.finish.routing
+// ("<pattern>=<target>", first match wins) route branches matching the pattern to the target;
+// without a match the configured parent is used.
+func getFinishTarget(branchType string, branchName string, branchConfig config.BranchConfig) (string, error) {
+	configKey := fmt.Sprintf("gitflow.%s.finish.routing", branchType)
+	routes, err := git.GetConfigValues(configKey)
+	if err != nil {
+		return "", &errors.GitError{Operation: "read finish routing", Err: err}
 	}
 
-	// 2. Command-line message file overrides config
-	if tagOptions != nil && tagOptions.MessageFile != "" {
-		useMessageFile = true
-		messageFilePath = tagOptions.MessageFile
+	for _, route := range routes {
+		pattern, target, ok := strings.Cut(route, "=")
+		pattern = strings.TrimSpace(pattern)
+		target = strings.TrimSpace(target)
+		if !ok || pattern == "" || target == "" {
+			return "", &errors.InvalidOptionError{Option: configKey, Reason: fmt.Sprintf("'%s' is not of the form <pattern>=<target>", route)}
+		}
+		matched, err := path.Match(pattern, branchName)
+		if err != nil {
+			return "", &errors.InvalidOptionError{Option: configKey, Reason: fmt.Sprintf("invalid pattern '%s'", pattern)}
+		}
+		if matched {
+			return target, nil
+		}
 	}
 
-	// Determine signing options
-	// 1. Start with not signing
-	shouldSign := false
+	return branchConfig.Parent, nil
+}
 
-	// 2. Check branch-specific signing config
-	signConfig, err := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.sign", state.BranchType))
-	if err == nil && signConfig == "true" {
-		shouldSign = true
+// approveProtectedTarget checks the --approve value against the protected target branch,
+// or asks for approval when none was given
+func approveProtectedTarget(branchName string, targetBranch string, finishOptions *FinishOptions) (bool, error) {
+	approval := ""
+	if finishOptions != nil {
+		approval = finishOptions.Approve
 	}
-
-	// 3. Command-line signing flags override config
-	if tagOptions != nil && tagOptions.ShouldSign != nil {
-		shouldSign = *tagOptions.ShouldSign
+	if approval != "" {
+		if approval != targetBranch {
+			return false, &errors.InvalidOptionError{Option: "--approve", Reason: fmt.Sprintf("approval for '%s' does not match the protected target branch '%s'", approval, targetBranch)}
+		}
+		return true, nil
 	}
 
-	// Determine signing key
-	signingKey := ""
+	confirmed, answered := askConfirmation(fmt.Sprintf("Branch '%s' is protected. Merge '%s' into it?", targetBranch, branchName))
+	if !answered {
+		return false, &errors.InvalidOptionError{Option: "--approve", Reason: fmt.Sprintf("'%s' is protected and no approval could be read; pass --approve %s", targetBranch, targetBranch)}
+	}
+	return confirmed, nil
+}
 
-	// 1. Check branch-specific signing key
-	configSigningKey, err := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.signingkey", state.BranchType))
-	if err == nil && configSigningKey != "" {
-		signingKey = configSigningKey
-		shouldSign = true // Specifying a key implies signing
+// shouldConfirmFinish determines whether to ask for confirmation before finishing
+func shouldConfirmFinish(finishOptions *FinishOptions) bool {
+	// 1. Check config
+	confirm := false
+	confirmConfig, err := git.GetConfig("gitflow.finish.confirm")
+	if err == nil && confirmConfig == "true" {
+		confirm = true
 	}
 
-	// 2. Command-line signing key overrides config
-	if tagOptions != nil && tagOptions.SigningKey != "" {
-		signingKey = tagOptions.SigningKey
-		shouldSign = true // Specifying a key implies signing
+	// 2. Command-line flags override config
+	if finishOptions != nil && finishOptions.Confirm != nil {
+		confirm = *finishOptions.Confirm
 	}
 
-	// Create the tag using the git module
-	gitTagOptions := &git.TagOptions{
-		Message:     message,
-		MessageFile: messageFilePath,
-		Sign:        shouldSign,
-		SigningKey:  signingKey,
+	return confirm
+}
+
+// printFinishPlan prints what finishing the branch is going to do
+func printFinishPlan(state *mergestate.MergeState, branchConfig config.BranchConfig, tagOptions *TagOptions, retentionOptions *BranchRetentionOptions) {
+	fmt.Println("Finish plan:")
+	fmt.Printf("  Merge '%s' into '%s' using %s strategy\n", state.FullBranchName, state.ParentBranch, strings.ToLower(state.MergeStrategy))
+
+	if shouldCreateTag(state.BranchType, branchConfig, tagOptions) && state.TagTarget != "" {
+		fmt.Printf("  Create tag '%s' on '%s'\n", getTagName(state, branchConfig, tagOptions), state.TagTarget)
+	} else if shouldCreateTag(state.BranchType, branchConfig, tagOptions) {
+		fmt.Printf("  Create tag '%s'\n", getTagName(state, branchConfig, tagOptions))
+	} else {
+		fmt.Println("  Create no tag")
 	}
-	
-	// Use MessageFile if specified, otherwise use Message
-	if useMessageFile {
-		gitTagOptions.Message = "" // Clear message since we're using file
+
+	if len(state.ChildBranches) > 0 {
+		fmt.Printf("  Update child base branches: %s\n", strings.Join(state.ChildBranches, ", "))
 	} else {
-		gitTagOptions.MessageFile = "" // Clear file since we're using message
+		fmt.Println("  Update no child base branches")
 	}
-	
-	if err := git.CreateTag(tagName, gitTagOptions); err != nil {
-		return &errors.GitError{Operation: fmt.Sprintf("create tag '%s'", tagName), Err: err}
+
+	fmt.Printf("  %s\n", describeBranchDeletion(state, retentionOptions))
+}
+
+// describeBranchDeletion describes what the delete step does with the finished branch
+func describeBranchDeletion(state *mergestate.MergeState, retentionOptions *BranchRetentionOptions) string {
+	_, keepRemote, keepLocal, _ := getBranchRetentionSettings(state.BranchType, retentionOptions)
+	keepRemote = keepRemote || state.LocalOnly
+	switch {
+	case state.IsSourceRef || (keepLocal && keepRemote):
+		return fmt.Sprintf("Keep '%s'", state.FullBranchName)
+	case keepRemote:
+		return fmt.Sprintf("Delete local branch '%s'", state.FullBranchName)
+	case keepLocal:
+		return fmt.Sprintf("Delete remote branch '%s' if it exists", state.FullBranchName)
+	default:
+		return fmt.Sprintf("Delete local branch '%s' and its remote branch if it exists", state.FullBranchName)
 	}
-	fmt.Printf("Created tag '%s'\n", tagName)
-	return nil
 }
 
-// handleUpdateChildrenStep handles updating child base branches
-func handleUpdateChildrenStep(state *mergestate.MergeState, branchConfig config.BranchConfig, tagOptions *TagOptions, retentionOptions *BranchRetentionOptions) error {
-	// Find next child branch to update
-	nextBranch := findNextBranchToUpdate(state)
+// planFinishState resolves the branch and target the way a finish would and returns the state
+// the finish would start with, without changing anything
+func planFinishState(branchType string, name string, branchConfig config.BranchConfig, cfg *config.Config, tagOptions *TagOptions, retentionOptions *BranchRetentionOptions, finishOptions *FinishOptions) (*mergestate.MergeState, error) {
+	if err := checkMergeBackOptions(branchType, branchConfig, tagOptions, retentionOptions, finishOptions); err != nil {
+		return nil, err
+	}
+	if err := checkLocalOnlyOptions(branchType, retentionOptions, finishOptions); err != nil {
+		return nil, err
+	}
 
-	// If no more branches to update, move to final step
-	if nextBranch == "" {
-		state.CurrentStep = stepDeleteBranch
-		if err := mergestate.SaveMergeState(state); err != nil {
-			return &errors.GitError{Operation: "save merge state", Err: err}
+	shortName := strings.TrimPrefix(name, branchConfig.Prefix)
+	fullName := branchConfig.Prefix + shortName
+	routedName := fullName
+	isSourceRef := finishOptions != nil && finishOptions.SourceRef != ""
+	if isSourceRef {
+		fullName = finishOptions.SourceRef
+	} else {
+		resolvedName, err := resolveBranchName(name, branchConfig)
+		if err != nil {
+			return nil, err
 		}
-		return handleContinue(state, branchConfig, tagOptions, retentionOptions)
+		fullName = resolvedName
+		routedName = resolvedName
+	}
+
+	// A branch that is not merged back is its own target and updates no children
+	if !mergesBack(branchConfig) {
+		return &mergestate.MergeState{
+			BranchType:     branchType,
+			BranchName:     shortName,
+			ParentBranch:   fullName,
+			MergeStrategy:  string(config.MergeStrategyNone),
+			FullBranchName: fullName,
+			ChildBranches:  []string{},
+		}, nil
 	}
 
-	// Update the next child branch
-	if err := updateChildBranch(nextBranch, state); err != nil {
+	targetBranch, err := getFinishTarget(branchType, routedName, branchConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var alsoInto []string
+	if finishOptions != nil {
+		alsoInto = finishOptions.AlsoInto
+	}
+	childBranches, _ := autoUpdateChildren(cfg, childBaseBranches(cfg, targetBranch), finishOptions)
+
+	return &mergestate.MergeState{
+		BranchType:     branchType,
+		BranchName:     shortName,
+		ParentBranch:   targetBranch,
+		MergeStrategy:  branchConfig.UpstreamStrategy,
+		FullBranchName: fullName,
+		IsSourceRef:    isSourceRef,
+		ChildBranches:  childBranches,
+		AlsoInto:       alsoInto,
+		LocalOnly:      shouldFinishLocally(branchType, finishOptions),
+	}, nil
+}
+
+// listFinishSteps prints the steps a finish of the branch would run, in order, without changing anything
+func listFinishSteps(branchType string, name string, branchConfig config.BranchConfig, cfg *config.Config, tagOptions *TagOptions, retentionOptions *BranchRetentionOptions, finishOptions *FinishOptions) error {
+	state, err := planFinishState(branchType, name, branchConfig, cfg, tagOptions, retentionOptions, finishOptions)
+	if err != nil {
 		return err
 	}
+	fullName := state.FullBranchName
+	targetBranch := state.ParentBranch
 
-	// Mark this branch as updated
-	state.UpdatedBranches = append(state.UpdatedBranches, nextBranch)
-	if err := mergestate.SaveMergeState(state); err != nil {
-		return &errors.GitError{Operation: "save merge state", Err: err}
+	type step struct {
+		name        string
+		description string
 	}
+	steps := []step{}
+	skipped := []step{}
 
-	// Continue with next branch
-	return handleContinue(state, branchConfig, tagOptions, retentionOptions)
-}
+	// A branch that is not merged back is only tagged and pushed
+	mergeBack := mergesBack(branchConfig)
+	if mergeBack && shouldRefreshParent(branchType, targetBranch, cfg) {
+		grandparentBranch := cfg.Branches[targetBranch].Parent
+		steps = append(steps, step{stepRefreshParent, fmt.Sprintf("Update '%s' from '%s'", targetBranch, grandparentBranch)})
+	}
+	if mergeBack {
+		steps = append(steps, step{stepMerge, fmt.Sprintf("Merge '%s' into '%s' using %s strategy", fullName, targetBranch, strings.ToLower(branchConfig.UpstreamStrategy))})
+	} else {
+		skipped = append(skipped, step{stepMerge, "upstream strategy is 'none'"})
+	}
+	if len(state.AlsoInto) > 0 {
+		steps = append(steps, step{stepMergeAlsoInto, fmt.Sprintf("Merge '%s' into %s", fullName, strings.Join(state.AlsoInto, ", "))})
+	}
+
+	shouldTag := shouldCreateTag(branchType, branchConfig, tagOptions)
+	tagStep := step{stepCreateTag, fmt.Sprintf("Create tag '%s'", getTagName(state, branchConfig, tagOptions))}
+	deferTag := shouldTag && tagIncludesSHAs(branchType) && len(state.ChildBranches) > 0
+	if !shouldTag {
+		skipped = append(skipped, step{stepCreateTag, "no tag is created"})
+	} else if !deferTag {
+		steps = append(steps, tagStep)
+	}
+
+	var skippedChildren []string
+	if mergeBack {
+		_, skippedChildren = autoUpdateChildren(cfg, childBaseBranches(cfg, targetBranch), finishOptions)
+	}
+	if len(state.ChildBranches) > 0 {
+		steps = append(steps, step{stepUpdateChildren, fmt.Sprintf("Update child base branches: %s", strings.Join(state.ChildBranches, ", "))})
+	} else if len(skippedChildren) == 0 {
+		skipped = append(skipped, step{stepUpdateChildren, "no child base branches"})
+	}
+	if len(skippedChildren) > 0 {
+		skipped = append(skipped, step{stepUpdateChildren, fmt.Sprintf("not updated automatically: %s", strings.Join(skippedChildren, ", "))})
+	}
+	if deferTag {
+		tagStep.description += ", listing the resulting branch SHAs"
+		steps = append(steps, tagStep)
+	}
+
+	if mergeBack {
+		steps = append(steps, step{stepDeleteBranch, describeBranchDeletion(state, retentionOptions)})
+	} else {
+		skipped = append(skipped, step{stepDeleteBranch, "the branch is kept"})
+	}
+
+	if shouldPushBaseBranches(branchType, finishOptions) {
+		refs := append(append([]string{targetBranch}, state.AlsoInto...), state.ChildBranches...)
+		description := fmt.Sprintf("Push '%s' to '%s'", strings.Join(refs, "', '"), finishRemote())
+		if shouldTag {
+			description += fmt.Sprintf(" with tag '%s'", getTagName(state, branchConfig, tagOptions))
+		}
+		steps = append(steps, step{stepPush, description})
+	}
+
+	fmt.Printf("Finish steps for '%s':\n", fullName)
+	for i, s := range steps {
+		fmt.Printf("  %d. %s: %s\n", i+1, s.name, s.description)
+	}
+	for _, s := range skipped {
+		fmt.Printf("  - %s (skipped: %s)\n", s.name, s.description)
+	}
+	return nil
+}
+
+// childBaseBranches returns the base branches whose parent is the target, which finish keeps up to date.
+// The target itself is never its own child, even if misconfigured as its own parent. The branches are
+// ordered by hierarchy depth, then by name, so that updates and conflicts are reproducible.
+func childBaseBranches(cfg *config.Config, targetBranch string) []string {
+	childBranches := []string{}
+	for branchName, branch := range cfg.Branches {
+		if branchName == targetBranch {
+			continue
+		}
+		if branch.Type == string(config.BranchTypeBase) && branch.Parent == targetBranch {
+			childBranches = append(childBranches, branchName)
+		}
+	}
+	sortBranchesByDepth(cfg, childBranches)
+	return childBranches
+}
+
+// autoUpdateChildren splits the child base branches into the ones the finish updates and the ones it skips.
+// By default only children with autoUpdate enabled are updated; --autoupdate-children updates all of them
+// and --no-autoupdate-children none.
+func autoUpdateChildren(cfg *config.Config, childBranches []string, finishOptions *FinishOptions) ([]string, []string) {
+	if finishOptions != nil && finishOptions.AutoUpdateChildren != nil {
+		if *finishOptions.AutoUpdateChildren {
+			return childBranches, nil
+		}
+		return []string{}, childBranches
+	}
+	updated, skipped := []string{}, []string{}
+	for _, branchName := range childBranches {
+		if cfg.Branches[branchName].AutoUpdate {
+			updated = append(updated, branchName)
+		} else {
+			skipped = append(skipped, branchName)
+		}
+	}
+	return updated, skipped
+}
+
+// confirmFinish asks whether to proceed. It fails instead of waiting when no input can be read.
+func confirmFinish() (bool, error) {
+	confirmed, answered := askConfirmation("Proceed?")
+	if !answered {
+		return false, &errors.InvalidOptionError{Option: "--confirm", Reason: "no answer could be read (is the terminal non-interactive?); run without --confirm or use --no-confirm"}
+	}
+	return confirmed, nil
+}
+
+// confirmationReader is shared by all questions so that buffered answers aren't lost between them.
+// It is replaced when a program using git-flow as a library replaces os.Stdin.
+var confirmationReader = bufio.NewReader(os.Stdin)
+
+// confirmationInput is the file confirmationReader reads from
+var confirmationInput = os.Stdin
+
+// askConfirmation asks a yes/no question on stdin. answered is false when no input could be read.
+func askConfirmation(question string) (confirmed bool, answered bool) {
+	fmt.Printf("%s [y/N]: ", question)
+	if confirmationInput != os.Stdin {
+		confirmationInput = os.Stdin
+		confirmationReader = bufio.NewReader(os.Stdin)
+	}
+	response, err := confirmationReader.ReadString('\n')
+	if err != nil && response == "" {
+		fmt.Println()
+		return false, false
+	}
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes", true
+}
+
+// shouldRefreshParent reports whether gitflow.<type>.finish.refreshparent asks to update the
+// target branch from its own parent before merging, which requires the target to auto-update
+func shouldRefreshParent(branchType string, targetBranch string, cfg *config.Config) bool {
+	refreshConfig, err := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.refreshparent", branchType))
+	if err != nil || refreshConfig != "true" {
+		return false
+	}
+	targetConfig, ok := cfg.Branches[targetBranch]
+	return ok && targetConfig.AutoUpdate && targetConfig.Parent != ""
+}
+
+// handleRefreshParentStep updates the target branch from its parent and then merges the topic branch
+func handleRefreshParentStep(state *mergestate.MergeState, branchConfig config.BranchConfig, tagOptions *TagOptions, retentionOptions *BranchRetentionOptions, finishOptions *FinishOptions) (*FinishResult, error) {
+	startStepTiming(stepRefreshParent)
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, &errors.GitError{Operation: "load configuration", Err: err}
+	}
+	targetConfig := cfg.Branches[state.ParentBranch]
+	grandparentBranch := targetConfig.Parent
+
+	if err := git.BranchExists(grandparentBranch); err != nil {
+		return nil, &errors.BranchNotFoundError{BranchName: grandparentBranch}
+	}
+
+	// Only update if the grandparent has anything new for the target
+	behind, err := git.CountCommits(state.ParentBranch, grandparentBranch)
+	if err != nil {
+		return nil, &errors.GitError{Operation: fmt.Sprintf("compare '%s' with '%s'", state.ParentBranch, grandparentBranch), Err: err}
+	}
+	if behind > 0 {
+		fmt.Printf("Updating '%s' from '%s' before merging...\n", state.ParentBranch, grandparentBranch)
+
+		strategy := targetConfig.DownstreamStrategy
+		if strategy == "" || strategy == string(config.MergeStrategyNone) {
+			strategy = strategyMerge
+		}
+		if err := update.UpdateBranchFromParent(state.ParentBranch, grandparentBranch, strategy, false, nil); err != nil {
+			if _, ok := err.(*errors.UnresolvedConflictsError); ok {
+				printConflicts(state.ParentBranch, grandparentBranch)
+				msg := fmt.Sprintf("Merge conflicts detected while updating '%s' from '%s'. Resolve conflicts and run 'git flow %s finish --continue %s'\n", state.ParentBranch, grandparentBranch, state.BranchType, state.BranchName)
+				msg += fmt.Sprintf("To abort the merge, run 'git flow %s finish --abort %s'", state.BranchType, state.BranchName)
+				fmt.Println(msg)
+			}
+			return nil, err
+		}
+	}
+
+	// Continue with the topic merge
+	state.CurrentStep = stepMerge
+	if err := saveMergeState(state); err != nil {
+		return nil, &errors.GitError{Operation: "save merge state", Err: err}
+	}
+	return finish(state, branchConfig, tagOptions, retentionOptions, finishOptions)
+}
+
+// resolveBranchName tries to find the branch name with and without prefix
+func resolveBranchName(name string, branchConfig config.BranchConfig) (string, error) {
+	// Try name as-is first
+	if err := git.BranchExists(name); err == nil {
+		return name, nil
+	}
+
+	// If not found as-is, try with prefix
+	if !strings.HasPrefix(name, branchConfig.Prefix) {
+		fullName := branchConfig.Prefix + name
+		if err := git.BranchExists(fullName); err == nil {
+			return fullName, nil
+		}
+	}
+
+	return "", &errors.BranchNotFoundError{BranchName: name}
+}
+
+// finishAlreadyCompleted reports whether a missing branch was already finished, judged by the tag the
+// finish creates: it must exist and be part of the target branch. Without a tag there is no such evidence.
+func finishAlreadyCompleted(branchType string, name string, branchConfig config.BranchConfig, tagOptions *TagOptions) (string, string, bool) {
+	if !shouldCreateTag(branchType, branchConfig, tagOptions) {
+		return "", "", false
+	}
+
+	shortName := strings.TrimPrefix(name, branchConfig.Prefix)
+	targetBranch, err := getFinishTarget(branchType, branchConfig.Prefix+shortName, branchConfig)
+	if err != nil {
+		return "", "", false
+	}
+	tagName := getTagName(&mergestate.MergeState{BranchName: shortName}, branchConfig, tagOptions)
+	if !git.TagExists(tagName) {
+		return "", "", false
+	}
+	merged, err := git.IsAncestor("refs/tags/"+tagName, targetBranch)
+	if err != nil || !merged {
+		return "", "", false
+	}
+	return tagName, targetBranch, true
+}
+
+// ensureNoGitOperationInProgress refuses to run the operation while a rebase or cherry-pick is
+// stopped halfway, since stacking operations on top of it can leave the repository in a corrupt state
+func ensureNoGitOperationInProgress(operation string) error {
+	gitOperation, err := git.OperationInProgress()
+	if err != nil {
+		return &errors.GitError{Operation: "check for git operations in progress", Err: err}
+	}
+	if gitOperation != "" {
+		return &errors.GitOperationInProgressError{Operation: operation, GitOperation: gitOperation}
+	}
+	return nil
+}
+
+// ensureNotBaseBranch returns an error if the branch is the finish target or a configured base branch,
+// also under another name such as a symbolic ref to it
+func ensureNotBaseBranch(name string, targetBranch string, cfg *config.Config) error {
+	if name == targetBranch {
+		return &errors.BaseBranchFinishError{BranchName: name, TargetBranch: targetBranch}
+	}
+	if branch, ok := cfg.Branches[name]; ok && branch.Type == string(config.BranchTypeBase) {
+		return &errors.BaseBranchFinishError{BranchName: name, TargetBranch: targetBranch}
+	}
+
+	// Whether the branch exists is checked elsewhere
+	ref, err := git.BranchRef(name)
+	if err != nil || ref == "refs/heads/"+name {
+		return nil
+	}
+	if targetRef, err := git.BranchRef(targetBranch); err == nil && targetRef == ref {
+		return &errors.BaseBranchFinishError{BranchName: name, TargetBranch: targetBranch, RefersTo: targetBranch}
+	}
+	resolved := strings.TrimPrefix(ref, "refs/heads/")
+	if branch, ok := cfg.Branches[resolved]; ok && branch.Type == string(config.BranchTypeBase) {
+		return &errors.BaseBranchFinishError{BranchName: name, TargetBranch: targetBranch, RefersTo: resolved}
+	}
+	return nil
+}
+
+// validateFinishOptions checks that the finish options can be used with the branch's upstream strategy
+func validateFinishOptions(branchConfig config.BranchConfig, finishOptions *FinishOptions) error {
+	if finishOptions == nil {
+		return nil
+	}
+
+	if finishOptions.Ours && finishOptions.Theirs {
+		return &errors.InvalidOptionError{Option: "--ours/--theirs", Reason: "only one of --ours and --theirs can be used"}
+	}
+
+	if finishOptions.Ours || finishOptions.Theirs {
+		strategy := strings.ToLower(branchConfig.UpstreamStrategy)
+		if strategy != strategyMerge && strategy != strategySquash {
+			return &errors.InvalidOptionError{Option: "--ours/--theirs", Reason: fmt.Sprintf("only supported with the merge and squash strategies, not '%s'", strategy)}
+		}
+
+		side := "the target branch"
+		if finishOptions.Theirs {
+			side = "the finished branch"
+		}
+		fmt.Fprintf(os.Stderr, "Warning: conflicting changes will be resolved in favor of %s; changes from the other side may be silently dropped\n", side)
+	}
+
+	if len(finishOptions.StrategyOptions) > 0 {
+		strategy := strings.ToLower(branchConfig.UpstreamStrategy)
+		if strategy != strategyMerge && strategy != strategySquash {
+			return &errors.InvalidOptionError{Option: "--strategy-option", Reason: fmt.Sprintf("only supported with the merge and squash strategies, not '%s'", strategy)}
+		}
+		for _, option := range finishOptions.StrategyOptions {
+			if err := validateStrategyOption(option); err != nil {
+				return err
+			}
+		}
+	}
+
+	if finishOptions.AllowUnrelatedHistories {
+		strategy := strings.ToLower(branchConfig.UpstreamStrategy)
+		if strategy != strategyMerge && strategy != strategySquash {
+			return &errors.InvalidOptionError{Option: "--merge-allow-unrelated-histories", Reason: fmt.Sprintf("only supported with the merge and squash strategies, not '%s'", strategy)}
+		}
+		fmt.Fprintf(os.Stderr, "Warning: histories without a common ancestor will be merged; make sure the branch is meant to be joined with the target, e.g. for a subtree import\n")
+	}
+
+	if finishOptions.MergeBaseOverride != "" {
+		if strategy := strings.ToLower(branchConfig.UpstreamStrategy); strategy != strategySquash {
+			return &errors.InvalidOptionError{Option: "--merge-base-override", Reason: fmt.Sprintf("only supported with the squash strategy, not '%s'", strategy)}
+		}
+		if finishOptions.Ours || finishOptions.Theirs {
+			return &errors.InvalidOptionError{Option: "--merge-base-override", Reason: "cannot be combined with --ours or --theirs"}
+		}
+		if len(finishOptions.StrategyOptions) > 0 {
+			return &errors.InvalidOptionError{Option: "--merge-base-override", Reason: "cannot be combined with --strategy-option"}
+		}
+	}
+
+	if finishOptions.SquashMessage != "" || finishOptions.SquashMessageFile != "" {
+		if strategy := strings.ToLower(branchConfig.UpstreamStrategy); strategy != strategySquash {
+			option := "--squash-message"
+			if finishOptions.SquashMessage == "" {
+				option = "--squash-message-file"
+			}
+			return &errors.InvalidOptionError{Option: option, Reason: fmt.Sprintf("only supported with the squash strategy, not '%s'", strategy)}
+		}
+	}
+
+	switch strings.ToLower(finishOptions.AlsoIntoStrategy) {
+	case "", strategyMerge, strategySquash:
+	default:
+		return &errors.InvalidOptionError{Option: "--also-into-strategy", Reason: fmt.Sprintf("unsupported strategy '%s', expected merge or squash", finishOptions.AlsoIntoStrategy)}
+	}
+
+	if finishOptions.CommitDate != "" && !util.IsValidCommitDate(finishOptions.CommitDate) {
+		return &errors.InvalidOptionError{Option: "--commit-date", Reason: fmt.Sprintf("unsupported date '%s', use e.g. RFC 3339 (2006-01-02T15:04:05Z) or '@<unix timestamp> +0000'", finishOptions.CommitDate)}
+	}
+
+	return nil
+}
+
+// validateAlsoInto checks that the additional targets exist and are neither the branch nor its target
+func validateAlsoInto(branch string, targetBranch string, alsoInto []string) error {
+	for i, target := range alsoInto {
+		if target == branch || target == targetBranch {
+			return &errors.InvalidOptionError{Option: "--also-into", Reason: fmt.Sprintf("'%s' is already part of the finish", target)}
+		}
+		if slices.Contains(alsoInto[:i], target) {
+			return &errors.InvalidOptionError{Option: "--also-into", Reason: fmt.Sprintf("'%s' is given more than once", target)}
+		}
+		if err := git.BranchExists(target); err != nil {
+			return &errors.BranchNotFoundError{BranchName: target}
+		}
+	}
+	return nil
+}
+
+// validateMergeBaseOverride checks that the squash base resolves to a commit the branch contains
+func validateMergeBaseOverride(base string, branch string) error {
+	if _, err := git.ResolveCommit(base); err != nil {
+		return &errors.InvalidOptionError{Option: "--merge-base-override", Reason: err.Error()}
+	}
+	isAncestor, err := git.IsAncestor(base, branch)
+	if err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("compare '%s' with '%s'", base, branch), Err: err}
+	}
+	if !isAncestor {
+		return &errors.InvalidOptionError{Option: "--merge-base-override", Reason: fmt.Sprintf("'%s' is not an ancestor of '%s'", base, branch)}
+	}
+	return nil
+}
+
+// validateStrategyOption checks that option is a merge strategy option of the ort strategy, with a valid value
+func validateStrategyOption(option string) error {
+	name, value, hasValue := strings.Cut(option, "=")
+	switch name {
+	case "ours", "theirs":
+		return &errors.InvalidOptionError{Option: "--strategy-option", Reason: fmt.Sprintf("use --%s instead of --strategy-option %s", name, option)}
+	case "ignore-space-change", "ignore-all-space", "ignore-space-at-eol", "ignore-cr-at-eol",
+		"renormalize", "no-renormalize", "no-renames", "patience", "histogram":
+		if hasValue {
+			return &errors.InvalidOptionError{Option: "--strategy-option", Reason: fmt.Sprintf("'%s' does not take a value", name)}
+		}
+	case "find-renames", "rename-threshold":
+		if !hasValue && name == "find-renames" {
+			return nil
+		}
+		// Git reads a value without % as a decimal fraction (5 is 50%), so only percentages are accepted
+		threshold, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+		if !strings.HasSuffix(value, "%") || err != nil || threshold < 0 || threshold > 100 {
+			return &errors.InvalidOptionError{Option: "--strategy-option", Reason: fmt.Sprintf("invalid similarity threshold '%s' for '%s', expected a percentage like 50%%", value, name)}
+		}
+	case "subtree":
+	case "diff-algorithm":
+		switch value {
+		case "myers", "minimal", "patience", "histogram":
+		default:
+			return &errors.InvalidOptionError{Option: "--strategy-option", Reason: fmt.Sprintf("unsupported diff algorithm '%s', expected myers, minimal, patience or histogram", value)}
+		}
+	default:
+		return &errors.InvalidOptionError{Option: "--strategy-option", Reason: fmt.Sprintf("unsupported merge strategy option '%s'", option)}
+	}
+	return nil
+}
+
+// getMergeOptions converts finish options into git merge options
+func getMergeOptions(finishOptions *FinishOptions) *git.MergeOptions {
+	mergeOptions := &git.MergeOptions{}
+	if finishOptions == nil {
+		return mergeOptions
+	}
+	if finishOptions.Ours {
+		mergeOptions.StrategyOptions = append(mergeOptions.StrategyOptions, "ours")
+	}
+	if finishOptions.Theirs {
+		mergeOptions.StrategyOptions = append(mergeOptions.StrategyOptions, "theirs")
+	}
+	mergeOptions.StrategyOptions = append(mergeOptions.StrategyOptions, finishOptions.StrategyOptions...)
+	mergeOptions.CommitDate = finishOptions.CommitDate
+	mergeOptions.AllowUnrelatedHistories = finishOptions.AllowUnrelatedHistories
+	mergeOptions.SquashBase = finishOptions.MergeBaseOverride
+	return mergeOptions
+}
+
+// shouldEditMergeMessage reports whether the merge or squash commit message is edited in the editor,
+// from gitflow.<type>.finish.editmergemessage and the command-line flags
+func shouldEditMergeMessage(branchType string, finishOptions *FinishOptions) bool {
+	// 1. Check branch-specific config
+	edit := false
+	editConfig, err := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.editmergemessage", branchType))
+	if err == nil && editConfig == "true" {
+		edit = true
+	}
+
+	// 2. Command-line flags override config
+	if finishOptions != nil && finishOptions.EditMergeMessage != nil {
+		edit = *finishOptions.EditMergeMessage
+	}
+
+	return edit
+}
+
+// mergeMessageFile returns the config key and the merge message template file configured for merges of the
+// branch type into the target, from gitflow.<type>.finish.mergemessagefile.<target> or gitflow.<type>.finish.mergemessagefile
+func mergeMessageFile(branchType string, target string) (string, string) {
+	for _, key := range []string{
+		fmt.Sprintf("gitflow.%s.finish.mergemessagefile.%s", branchType, target),
+		fmt.Sprintf("gitflow.%s.finish.mergemessagefile", branchType),
+	} {
+		if file, err := git.GetConfig(key); err == nil && file != "" {
+			return key, file
+		}
+	}
+	return "", ""
+}
+
+// mergeMessageFromFile returns the commit message for merging source into target from the configured
+// template file, with %branch% and %target% replaced. It returns "" if no template is configured.
+func mergeMessageFromFile(branchType string, source string, target string) (string, error) {
+	key, file := mergeMessageFile(branchType, target)
+	if file == "" {
+		return "", nil
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", &errors.InvalidOptionError{Option: key, Reason: fmt.Sprintf("failed to read merge message file: %v", err)}
+	}
+	message := strings.NewReplacer("%branch%", source, "%target%", target).Replace(string(content))
+	return strings.TrimSpace(message), nil
+}
+
+// squashMessage returns the message of the squash commit: --squash-message, the content of
+// --squash-message-file, gitflow.<type>.finish.squashmessage or the merge message file, in that order,
+// with %branch% and %target% replaced. It defaults to "Squashed <type> '<name>' into <target>".
+func squashMessage(state *mergestate.MergeState, finishOptions *FinishOptions) (string, error) {
+	replacer := strings.NewReplacer("%branch%", state.FullBranchName, "%target%", state.ParentBranch)
+	if finishOptions != nil && finishOptions.SquashMessage != "" {
+		return replacer.Replace(finishOptions.SquashMessage), nil
+	}
+	if finishOptions != nil && finishOptions.SquashMessageFile != "" {
+		content, err := os.ReadFile(finishOptions.SquashMessageFile)
+		if err != nil {
+			return "", &errors.InvalidOptionError{Option: "--squash-message-file", Reason: fmt.Sprintf("failed to read squash message file: %v", err)}
+		}
+		message := strings.TrimSpace(replacer.Replace(string(content)))
+		if message == "" {
+			return "", &errors.InvalidOptionError{Option: "--squash-message-file", Reason: fmt.Sprintf("squash message file '%s' is empty", finishOptions.SquashMessageFile)}
+		}
+		return message, nil
+	}
+	if message, err := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.squashmessage", state.BranchType)); err == nil && strings.TrimSpace(message) != "" {
+		return strings.TrimSpace(replacer.Replace(message)), nil
+	}
+	message, err := mergeMessageFromFile(state.BranchType, state.FullBranchName, state.ParentBranch)
+	if err != nil || message != "" {
+		return message, err
+	}
+	return fmt.Sprintf("Squashed %s '%s' into %s", state.BranchType, state.BranchName, state.ParentBranch), nil
+}
+
+// commitStagedSquash commits the changes of a squash that stopped before its commit, e.g. on a conflict,
+// once they are resolved and staged. A squash the user already committed leaves nothing to commit.
+func commitStagedSquash(state *mergestate.MergeState) error {
+	if strings.ToLower(state.MergeStrategy) != strategySquash || git.HasMergeHead() {
+		return nil
+	}
+	staged, err := git.HasStagedChanges()
+	if err != nil {
+		return &errors.GitError{Operation: "check for staged changes", Err: err}
+	}
+	if !staged {
+		return nil
+	}
+	message := state.SquashMessage
+	if message == "" {
+		message = fmt.Sprintf("Squashed commit of branch '%s'", state.FullBranchName)
+	}
+	if err := git.CommitStaged(message, ""); err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("commit the squashed changes of '%s'", state.FullBranchName), Err: err}
+	}
+	fmt.Printf("Committed the squashed changes of '%s' to '%s'\n", state.FullBranchName, state.ParentBranch)
+	return nil
+}
+
+// ensureEditorAvailable returns an error if the editor would wait for input that never comes.
+// An explicit GIT_EDITOR is trusted to run on its own; otherwise stdin has to be a terminal.
+func ensureEditorAvailable() error {
+	if os.Getenv("GIT_EDITOR") != "" || stdinIsTerminal() {
+		return nil
+	}
+	return &errors.InvalidOptionError{Option: "--merge-message-edit", Reason: "no terminal to run the editor in; set GIT_EDITOR or run with --no-merge-message-edit"}
+}
+
+// stdinIsTerminal reports whether stdin is a terminal a question can be answered on
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	// /dev/null is a character device as well, but no terminal
+	devNull, err := os.Stat(os.DevNull)
+	return err != nil || !os.SameFile(info, devNull)
+}
+
+// mergeMessageTemplate returns the message the editor opens with: the default subject of the
+// merge or squash commit, followed by the integrated commits as comments
+func mergeMessageTemplate(state *mergestate.MergeState) (string, error) {
+	subject := state.SquashMessage
+	if subject == "" {
+		subject = fmt.Sprintf("Merge branch '%s' into %s", state.FullBranchName, state.ParentBranch)
+		message, err := mergeMessageFromFile(state.BranchType, state.FullBranchName, state.ParentBranch)
+		if err != nil {
+			return "", err
+		}
+		if message != "" {
+			subject = message
+		}
+	}
+
+	log, err := git.Log(state.ParentBranch, state.FullBranchName)
+	if err != nil {
+		return "", &errors.GitError{Operation: fmt.Sprintf("list commits of '%s'", state.FullBranchName), Err: err}
+	}
+	lines := []string{subject, "", fmt.Sprintf("# Commits integrated from '%s':", state.FullBranchName)}
+	for _, line := range strings.Split(strings.TrimSpace(log), "\n") {
+		if line != "" {
+			lines = append(lines, "#   "+line)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// handleCreateTagStep handles the tag creation step
+func handleCreateTagStep(state *mergestate.MergeState, branchConfig config.BranchConfig, tagOptions *TagOptions, retentionOptions *BranchRetentionOptions, finishOptions *FinishOptions) (*FinishResult, error) {
+	if state.HistoryNote {
+		if err := addHistoryNote(state); err != nil {
+			return nil, err
+		}
+	}
+
+	shouldTag := shouldCreateTag(state.BranchType, branchConfig, tagOptions)
+	tagOnChild := slices.Contains(state.ChildBranches, state.TagTarget)
+	if shouldTag && (tagIncludesSHAs(state.BranchType) || tagOnChild) && findNextBranchToUpdate(state) != "" {
+		// The tag lists the child branch SHAs or goes on a child branch, so create it once the children are updated
+		state.TagAfterChildren = true
+	} else if shouldTag {
+		if err := createTagForBranch(state, branchConfig, tagOptions, finishOptions); err != nil {
+			return nil, err
+		}
+		state.TagAfterChildren = false
+	}
+
+	// Move to next step
+	state.CurrentStep = stepUpdateChildren
+	if err := saveMergeState(state); err != nil {
+		return nil, &errors.GitError{Operation: "save merge state", Err: err}
+	}
+	return handleContinue(state, branchConfig, tagOptions, retentionOptions, finishOptions)
+}
+
+// defaultNotesRef is the notes ref used for history notes unless gitflow.finish.notesref is set
+const defaultNotesRef = "refs/notes/gitflow"
+
+// addHistoryNote records the git-flow metadata of the finish as a note on the merge commit,
+// so the provenance survives the deletion of the branch
+func addHistoryNote(state *mergestate.MergeState) error {
+	notesRef := defaultNotesRef
+	if configRef, err := git.GetConfig("gitflow.finish.notesref"); err == nil && configRef != "" {
+		notesRef = configRef
+	}
+
+	commit, err := git.ResolveCommit(state.ParentBranch)
+	if err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("resolve '%s'", state.ParentBranch), Err: err}
+	}
+
+	note := fmt.Sprintf("git-flow-branch-type: %s\ngit-flow-branch-name: %s\ngit-flow-strategy: %s\n",
+		state.BranchType, state.BranchName, strings.ToLower(state.MergeStrategy))
+	if err := git.AddNote(notesRef, commit, note); err != nil {
+		return &errors.GitError{Operation: "add history note", Err: err}
+	}
+	fmt.Printf("Added history note to %s in '%s'\n", shortSHA(commit), notesRef)
+	return nil
+}
+
+// shouldCreateTag determines whether finishing a branch of the given type creates a tag
+func shouldCreateTag(branchType string, branchConfig config.BranchConfig, tagOptions *TagOptions) bool {
+	// 1. Start with branch configuration default
+	shouldTag := branchConfig.Tag
+
+	// 2. Check for branch-specific config override
+	branchSpecificTagConfig, err := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.notag", branchType))
+	if err == nil && branchSpecificTagConfig == "true" {
+		// notag=true means don't create a tag
+		shouldTag = false
+	}
+
+	// 3. Command-line flags override config; reserving a tag implies tagging
+	if tagOptions != nil && tagOptions.ShouldTag != nil {
+		shouldTag = *tagOptions.ShouldTag
+	} else if tagOptions != nil && tagOptions.Reserve {
+		shouldTag = true
+	}
+
+	return shouldTag
+}
+
+// getTagName determines the tag name for the finished branch, before any renaming of existing tags
+func getTagName(state *mergestate.MergeState, branchConfig config.BranchConfig, tagOptions *TagOptions) string {
+	// 1. Start with branch name and apply prefix from branch config
+	tagName := state.BranchName
+	if branchConfig.TagPrefix != "" {
+		tagName = branchConfig.TagPrefix + state.BranchName
+	}
+
+	// 2. Command-line custom tag name overrides config
+	if tagOptions != nil && tagOptions.TagName != "" {
+		tagName = tagOptions.TagName
+	}
+
+	return tagName
+}
+
+// tagIncludesSHAs reports whether gitflow.<type>.finish.tagincludeshas asks to list the
+// resulting SHAs of the target and the updated child base branches in the tag message
+func tagIncludesSHAs(branchType string) bool {
+	includeConfig, err := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.tagincludeshas", branchType))
+	return err == nil && includeConfig == "true"
+}
+
+// changesSinceSummary lists the commits on the target that are not reachable from the baseline ref
+func changesSinceSummary(since string, target string) (string, error) {
+	log, err := git.Log(since, target)
+	if err != nil {
+		return "", &errors.GitError{Operation: fmt.Sprintf("list commits since '%s'", since), Err: err}
+	}
+	log = strings.TrimSpace(log)
+	if log == "" {
+		return fmt.Sprintf("No changes since %s", since), nil
+	}
+	return fmt.Sprintf("Changes since %s:\n%s", since, log), nil
+}
+
+// tagIncludesContributors reports whether the tag message lists the contributors, from
+// gitflow.<type>.finish.tagcontributors and the command-line flags
+func tagIncludesContributors(branchType string, tagOptions *TagOptions) bool {
+	// 1. Check branch-specific config
+	include := false
+	includeConfig, err := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.tagcontributors", branchType))
+	if err == nil && includeConfig == "true" {
+		include = true
+	}
+
+	// 2. Command-line flags override config
+	if tagOptions != nil && tagOptions.Contributors != nil {
+		include = *tagOptions.Contributors
+	}
+	return include
+}
+
+// contributorsSummary lists the authors of the commits on the target since the baseline ref, with their
+// number of commits. Without a baseline, the last tag on the target other than tagName is used, and
+// without an earlier tag, all authors of the target are listed.
+func contributorsSummary(since string, target string, tagName string) (string, error) {
+	if since == "" {
+		lastTag, err := git.LastTag(target, tagName)
+		if err != nil {
+			return "", &errors.GitError{Operation: fmt.Sprintf("find the last tag on '%s'", target), Err: err}
+		}
+		since = lastTag
+	}
+	revisionRange := target
+	header := "Contributors:"
+	if since != "" {
+		revisionRange = since + ".." + target
+		header = fmt.Sprintf("Contributors since %s:", since)
+	}
+
+	shortlog, err := git.Shortlog(revisionRange)
+	if err != nil {
+		return "", &errors.GitError{Operation: fmt.Sprintf("list contributors of '%s'", revisionRange), Err: err}
+	}
+	lines := []string{header}
+	for _, line := range strings.Split(strings.TrimSpace(shortlog), "\n") {
+		count, author, found := strings.Cut(strings.TrimSpace(line), "\t")
+		if !found {
+			continue
+		}
+		unit := "commits"
+		if count == "1" {
+			unit = "commit"
+		}
+		lines = append(lines, fmt.Sprintf("%s (%s %s)", author, count, unit))
+	}
+	if len(lines) == 1 {
+		lines = append(lines, "none")
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// branchSHAsSummary lists the current SHA of the target and each updated child base branch
+func branchSHAsSummary(state *mergestate.MergeState) (string, error) {
+	lines := []string{"Branches:"}
+	for _, branch := range append([]string{state.ParentBranch}, state.UpdatedBranches...) {
+		sha, err := git.ResolveCommit(branch)
+		if err != nil {
+			return "", &errors.GitError{Operation: fmt.Sprintf("resolve branch '%s'", branch), Err: err}
+		}
+		lines = append(lines, fmt.Sprintf("%s %s", branch, sha))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// getTagBranch returns the branch the tag goes on if it is not the target: --tag-on or
+// gitflow.<type>.finish.tagtarget. That branch has to receive the finished branch during the finish,
+// as an --also-into branch or an updated child base branch, so that the tag contains it.
+func getTagBranch(state *mergestate.MergeState, tagOptions *TagOptions) (string, error) {
+	option := "--tag-on"
+	branch := ""
+	if tagOptions != nil {
+		branch = tagOptions.TargetBranch
+	}
+	if branch == "" {
+		option = fmt.Sprintf("gitflow.%s.finish.tagtarget", state.BranchType)
+		if configValue, err := git.GetConfig(option); err == nil {
+			branch = configValue
+		}
+	}
+	if branch == "" || branch == state.ParentBranch {
+		return "", nil
+	}
+	if !slices.Contains(state.AlsoInto, branch) && !slices.Contains(state.ChildBranches, branch) {
+		return "", &errors.InvalidOptionError{Option: option, Reason: fmt.Sprintf("'%s' does not receive '%s' during the finish; tag '%s', an --also-into branch or a child base branch of it", branch, state.FullBranchName, state.ParentBranch)}
+	}
+	return branch, nil
+}
+
+// tagBranch returns the branch whose tip is tagged
+func tagBranch(state *mergestate.MergeState) string {
+	if state.TagTarget != "" {
+		return state.TagTarget
+	}
+	return state.ParentBranch
+}
+
+// createTagForBranch creates a tag on the target branch, or the configured tag target, for the finished branch
+func createTagForBranch(state *mergestate.MergeState, branchConfig config.BranchConfig, tagOptions *TagOptions, finishOptions *FinishOptions) error {
+	// Determine tag name
+	tagName := getTagName(state, branchConfig, tagOptions)
+
+	// Pick a free name with a numeric suffix if the tag already exists and renaming is enabled
+	renameIfExists := false
+	renameConfig, err := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.renametagifexists", state.BranchType))
+	if err == nil && renameConfig == "true" {
+		renameIfExists = true
+	}
+	if tagOptions != nil && tagOptions.RenameIfExists {
+		renameIfExists = true
+	}
+	if renameIfExists && git.TagExists(tagName) {
+		baseTagName := tagName
+		for suffix := 2; git.TagExists(tagName); suffix++ {
+			tagName = fmt.Sprintf("%s-%d", baseTagName, suffix)
+		}
+		fmt.Printf("Tag '%s' already exists, using '%s' instead\n", baseTagName, tagName)
+	}
+
+	// The tip of the target is tagged, unless --tag-object names another object. The branch is named by
+	// its full ref, so that a tag with the same name can't be tagged instead.
+	tagTarget := "refs/heads/" + tagBranch(state)
+	if tagOptions != nil && tagOptions.Object != "" {
+		tagTarget = tagOptions.Object
+	}
+
+	// Tagging an annotated tag would nest tag objects, so the tag goes on the object it points to
+	if tagOptions == nil || tagOptions.Dereference == nil || *tagOptions.Dereference {
+		object, err := git.ResolveObject(tagTarget + "^{}")
+		if err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("resolve '%s'", tagTarget), Err: err}
+		}
+		tagTarget = object
+	}
+
+	// An existing tag at the commit to tag is reused, e.g. from an earlier, interrupted finish.
+	// Anywhere else it is an error, unless --force-tag replaces it.
+	forceTag := tagOptions != nil && tagOptions.Force
+	if forceTag && !tagOptions.ForceRemote {
+		if err := ensureTagNotPublished(tagName); err != nil {
+			return err
+		}
+	}
+	if git.TagExists(tagName) && !forceTag {
+		tagCommit, err := git.ResolveObject("refs/tags/" + tagName + "^{}")
+		if err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("resolve tag '%s'", tagName), Err: err}
+		}
+		targetCommit, err := git.ResolveObject(tagTarget + "^{}")
+		if err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("resolve '%s'", tagTarget), Err: err}
+		}
+		sameCommit := tagCommit == targetCommit
+		if !sameCommit || (tagOptions != nil && tagOptions.NoReuse) {
+			return &errors.TagExistsError{TagName: tagName, SameCommit: sameCommit}
+		}
+
+		fmt.Printf("Tag '%s' already exists at %s, reusing it\n", tagName, shortSHA(targetCommit))
+		state.TagName = tagName
+		if err := saveMergeState(state); err != nil {
+			return &errors.GitError{Operation: "save merge state", Err: err}
+		}
+		return nil
+	}
+
+	// A reserved tag is only a placeholder until finalize replaces it with the final tag
+	if tagOptions != nil && tagOptions.Reserve {
+		return reserveTag(state, tagName, tagTarget, forceTag)
+	}
+
+	// Determine tag message
+	// Default message
+	message := fmt.Sprintf("Tagging version %s", tagName)
+
+	// Changelog section replaces the default message if requested and not empty
+	messageCleanup := ""
+	if tagOptions != nil && tagOptions.MessageFromChangelog {
+		changelogMessage, err := changelogTagMessage(state.BranchType)
+		if err != nil {
+			return err
+		}
+		if changelogMessage != "" {
+			message = changelogMessage
+			messageCleanup = "whitespace" // Keep markdown headings, which git would strip as comments
+		} else {
+			fmt.Fprintf(os.Stderr, "Warning: no changelog entries found, using default tag message\n")
+		}
+	}
+
+	// Command-line message overrides default
+	if tagOptions != nil && tagOptions.Message != "" {
+		message = tagOptions.Message
+		messageCleanup = ""
+	}
+
+	// Handle message file
+	useMessageFile := false
+	messageFilePath := ""
+
+	// 1. Check for branch-specific message file config
+	configMessageFile, err := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.messagefile", state.BranchType))
+	if err == nil && configMessageFile != "" {
+		useMessageFile = true
+		messageFilePath = configMessageFile
+	}
+
+	// 2. Command-line message file overrides config
+	if tagOptions != nil && tagOptions.MessageFile != "" {
+		useMessageFile = true
+		messageFilePath = tagOptions.MessageFile
+	}
+
+	// Determine signing options
+	shouldSign, signingKey := getTagSigning(state.BranchType, tagOptions)
+
+	// Append the commits since the baseline, the contributors and the resulting branch SHAs if requested
+	since := ""
+	if tagOptions != nil {
+		since = tagOptions.Since
+	}
+	includeContributors := tagIncludesContributors(state.BranchType, tagOptions)
+	if since != "" || includeContributors || tagIncludesSHAs(state.BranchType) {
+		if useMessageFile {
+			content, err := os.ReadFile(messageFilePath)
+			if err != nil {
+				return &errors.GitError{Operation: fmt.Sprintf("read tag message file '%s'", messageFilePath), Err: err}
+			}
+			message = strings.TrimSpace(string(content))
+			useMessageFile = false
+		}
+		if since != "" {
+			summary, err := changesSinceSummary(since, tagBranch(state))
+			if err != nil {
+				return err
+			}
+			message += "\n\n" + summary
+		}
+		if includeContributors {
+			summary, err := contributorsSummary(since, tagBranch(state), tagName)
+			if err != nil {
+				return err
+			}
+			message += "\n\n" + summary
+		}
+		if tagIncludesSHAs(state.BranchType) {
+			summary, err := branchSHAsSummary(state)
+			if err != nil {
+				return err
+			}
+			message += "\n\n" + summary
+		}
+	}
+
+	commitDate := ""
+	if finishOptions != nil {
+		commitDate = finishOptions.CommitDate
+	}
+
+	// Create the tag using the git module
+	gitTagOptions := &git.TagOptions{
+		Message:     message,
+		MessageFile: messageFilePath,
+		Sign:        shouldSign,
+		SigningKey:  signingKey,
+		Cleanup:     messageCleanup,
+		Date:        commitDate,
+		Target:      tagTarget,
+		Force:       forceTag,
+	}
+	
+	// Use MessageFile if specified, otherwise use Message
+	if useMessageFile {
+		gitTagOptions.Message = "" // Clear message since we're using file
+	} else {
+		gitTagOptions.MessageFile = "" // Clear file since we're using message
+	}
+	
+	if forceTag && git.TagExists(tagName) {
+		// The previous tag is what the remote is expected to have when the replacement is pushed
+		previous, err := git.ResolveObject("refs/tags/" + tagName)
+		if err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("resolve tag '%s'", tagName), Err: err}
+		}
+		state.ReplacedTag = previous
+		fmt.Printf("Replacing existing tag '%s'\n", tagName)
+	}
+	if err := git.CreateTag(tagName, gitTagOptions); err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("create tag '%s'", tagName), Err: err}
+	}
+	fmt.Printf("Created tag '%s'\n", tagName)
+	state.TagCreated = true
+
+	// Verify the signature right away so a broken signing setup surfaces at finish time
+	if shouldSign && shouldVerifyCreatedTag(state.BranchType) {
+		status, err := git.VerifyTag(tagName)
+		if err != nil {
+			fmt.Printf("Tag '%s' was created but its signature could not be verified. Check the signing key configuration, then delete the tag and run 'git flow %s finish --continue %s'\n", tagName, state.BranchType, state.BranchName)
+			return &errors.GitError{Operation: fmt.Sprintf("verify tag '%s'", tagName), Err: err}
+		}
+		fmt.Printf("Verified signature of tag '%s': %s\n", tagName, status)
+	}
+
+	// Remember the tag so later steps and hooks can refer to it
+	state.TagName = tagName
+	if err := saveMergeState(state); err != nil {
+		return &errors.GitError{Operation: "save merge state", Err: err}
+	}
+	return nil
+}
+
+// ensureTagNotPublished returns an error if the tag exists on the remote, where replacing it would rewrite
+// a published tag. Without a configured remote there is nothing to check.
+func ensureTagNotPublished(tagName string) error {
+	remote := finishRemote()
+	if _, err := git.GetConfig(fmt.Sprintf("remote.%s.url", remote)); err != nil {
+		return nil
+	}
+	published, err := git.RemoteTagExists(remote, tagName)
+	if err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("check whether tag '%s' was pushed (use --force-remote-tag to skip the check)", tagName), Err: err}
+	}
+	if published {
+		return &errors.PublishedTagError{TagName: tagName, Remote: remote}
+	}
+	return nil
+}
+
+// getTagSigning determines whether to sign the tag and with which key, from the
+// gitflow.<type>.finish.sign and signingkey config and the command-line flags
+func getTagSigning(branchType string, tagOptions *TagOptions) (bool, string) {
+	// 1. Start with not signing
+	shouldSign := false
+
+	// 2. Check branch-specific signing config
+	signConfig, err := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.sign", branchType))
+	if err == nil && signConfig == "true" {
+		shouldSign = true
+	}
+
+	// 3. Command-line signing flags override config
+	if tagOptions != nil && tagOptions.ShouldSign != nil {
+		shouldSign = *tagOptions.ShouldSign
+	}
+
+	// Determine signing key
+	signingKey := ""
+
+	// 1. Check branch-specific signing key
+	configSigningKey, err := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.signingkey", branchType))
+	if err == nil && configSigningKey != "" {
+		signingKey = configSigningKey
+		shouldSign = true // Specifying a key implies signing
+	}
+
+	// 2. Command-line signing key overrides config
+	if tagOptions != nil && tagOptions.SigningKey != "" {
+		signingKey = tagOptions.SigningKey
+		shouldSign = true // Specifying a key implies signing
+	}
+
+	return shouldSign, signingKey
+}
+
+// shouldVerifyCreatedTag reports whether gitflow.<type>.finish.verifycreatedtag asks to verify signed tags after creating them
+func shouldVerifyCreatedTag(branchType string) bool {
+	verifyConfig, err := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.verifycreatedtag", branchType))
+	return err == nil && verifyConfig == "true"
+}
+
+// changelogTagMessage reads the changelog committed on the current branch and returns the
+// section selected by gitflow.<type>.finish.changelogsection, or "" if there is none
+func changelogTagMessage(branchType string) (string, error) {
+	changelogFile := defaultChangelogFile
+	if value, err := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.changelogfile", branchType)); err == nil && value != "" {
+		changelogFile = value
+	}
+
+	sectionPattern := defaultChangelogSection
+	if value, err := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.changelogsection", branchType)); err == nil && value != "" {
+		sectionPattern = value
+	}
+	sectionHeader, err := regexp.Compile(sectionPattern)
+	if err != nil {
+		return "", &errors.InvalidOptionError{Option: fmt.Sprintf("gitflow.%s.finish.changelogsection", branchType), Reason: err.Error()}
+	}
+
+	content, err := git.ShowFile("HEAD", changelogFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return "", nil
+	}
+
+	return extractChangelogSection(content, sectionHeader), nil
+}
+
+// extractChangelogSection returns the body below the first line matching sectionHeader,
+// up to the next markdown heading of the same or a higher level
+func extractChangelogSection(content string, sectionHeader *regexp.Regexp) string {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	start := -1
+	level := 0
+	for i, line := range lines {
+		if sectionHeader.MatchString(line) {
+			start = i + 1
+			level = headingLevel(line)
+			break
+		}
+	}
+	if start < 0 {
+		return ""
+	}
+
+	end := len(lines)
+	for i := start; i < len(lines); i++ {
+		if l := headingLevel(lines[i]); l > 0 && (level == 0 || l <= level) {
+			end = i
+			break
+		}
+	}
+
+	return strings.TrimSpace(strings.Join(lines[start:end], "\n"))
+}
+
+// headingLevel returns the markdown heading level of a line, or 0 if it is not a heading
+func headingLevel(line string) int {
+	level := len(line) - len(strings.TrimLeft(line, "#"))
+	if level == 0 || level == len(line) || line[level] != ' ' {
+		return 0
+	}
+	return level
+}
+
+// completeMergeStep records the tip of the merged branch, if it must not move before it is deleted,
+// and moves the state on to the step after the merge
+func completeMergeStep(state *mergestate.MergeState) error {
+	if state.PreventDeleteRace && !state.IsSourceRef {
+		head, err := git.ResolveCommit(state.FullBranchName)
+		if err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("resolve branch '%s'", state.FullBranchName), Err: err}
+		}
+		state.MergedHead = head
+	}
+	state.CurrentStep = stepAfterMerge(state)
+	return nil
+}
+
+// stepAfterMerge returns the step that follows the merge into the target
+func stepAfterMerge(state *mergestate.MergeState) string {
+	if len(state.AlsoInto) > 0 {
+		return stepMergeAlsoInto
+	}
+	return stepCreateTag
+}
+
+// handleMergeAlsoIntoStep merges the branch into the next additional target, one target per run
+// so that each can be resumed on its own after a conflict
+func handleMergeAlsoIntoStep(state *mergestate.MergeState, branchConfig config.BranchConfig, tagOptions *TagOptions, retentionOptions *BranchRetentionOptions, finishOptions *FinishOptions) (*FinishResult, error) {
+	nextBranch := findNextPendingBranch(state.AlsoInto, state.MergedInto)
+
+	// If no more targets are left, move on to tag creation
+	if nextBranch == "" {
+		state.CurrentStep = stepCreateTag
+		if err := saveMergeState(state); err != nil {
+			return nil, &errors.GitError{Operation: "save merge state", Err: err}
+		}
+		return handleContinue(state, branchConfig, tagOptions, retentionOptions, finishOptions)
+	}
+
+	fmt.Printf("Merging '%s' into additional target '%s'...\n", state.FullBranchName, nextBranch)
+	message, err := mergeMessageFromFile(state.BranchType, state.FullBranchName, nextBranch)
+	if err != nil {
+		return nil, err
+	}
+	if err := update.UpdateBranchFromParentWithOptions(nextBranch, state.FullBranchName, state.AlsoIntoStrategy, true, state, &git.MergeOptions{Message: message, ReflogAction: reflogAction(state)}); err != nil {
+		if _, ok := err.(*errors.UnresolvedConflictsError); ok {
+			printConflicts(nextBranch, state.FullBranchName)
+			msg := fmt.Sprintf("Merge conflicts detected while merging into '%s'. Resolve conflicts and run 'git flow %s finish --continue %s'\n", nextBranch, state.BranchType, state.BranchName)
+			msg += fmt.Sprintf("To abort the merge, run 'git flow %s finish --abort %s'", state.BranchType, state.BranchName)
+			fmt.Println(msg)
+		}
+		return nil, err
+	}
+
+	// Mark this target as merged
+	state.MergedInto = append(state.MergedInto, nextBranch)
+	if err := saveMergeState(state); err != nil {
+		return nil, &errors.GitError{Operation: "save merge state", Err: err}
+	}
+
+	// Continue with next target
+	return handleContinue(state, branchConfig, tagOptions, retentionOptions, finishOptions)
+}
+
+// handleUpdateChildrenStep handles updating child base branches
+func handleUpdateChildrenStep(state *mergestate.MergeState, branchConfig config.BranchConfig, tagOptions *TagOptions, retentionOptions *BranchRetentionOptions, finishOptions *FinishOptions) (*FinishResult, error) {
+	// Check all children up front in parallel mode, so only the ones behind are merged one by one
+	if state.ChildrenParallel && len(state.UpdatedBranches) == 0 {
+		if err := markUpToDateChildren(state); err != nil {
+			return nil, err
+		}
+	}
+
+	// Find next child branch to update
+	nextBranch := findNextBranchToUpdate(state)
+
+	// If no more branches to update, create a deferred tag or move to final step
+	if nextBranch == "" {
+		state.CurrentStep = stepDeleteBranch
+		if state.TagAfterChildren {
+			state.CurrentStep = stepCreateTag
+		}
+		if err := saveMergeState(state); err != nil {
+			return nil, &errors.GitError{Operation: "save merge state", Err: err}
+		}
+		return handleContinue(state, branchConfig, tagOptions, retentionOptions, finishOptions)
+	}
+
+	// Skip children that already contain the parent tip to avoid no-op merges
+	upToDate := false
+	if state.SkipEmptyChildren {
+		var err error
+		upToDate, err = git.IsAncestor(state.ParentBranch, nextBranch)
+		if err != nil {
+			return nil, &errors.GitError{Operation: fmt.Sprintf("compare '%s' with '%s'", nextBranch, state.ParentBranch), Err: err}
+		}
+	}
+
+	if upToDate {
+		fmt.Printf("Child base branch '%s' already up to date with '%s'\n", nextBranch, state.ParentBranch)
+		state.UnchangedBranches = append(state.UnchangedBranches, nextBranch)
+	} else if err := updateChildBranch(nextBranch, state); err != nil {
+		// Update the next child branch
+		return nil, err
+	}
+
+	// Mark this branch as updated
+	state.UpdatedBranches = append(state.UpdatedBranches, nextBranch)
+	if err := saveMergeState(state); err != nil {
+		return nil, &errors.GitError{Operation: "save merge state", Err: err}
+	}
+
+	// Continue with next branch
+	return handleContinue(state, branchConfig, tagOptions, retentionOptions, finishOptions)
+}
+
+// markUpToDateChildren concurrently checks which remaining child branches already contain the parent
+// tip and marks them as updated. Merges share the working tree, so they still run one at a time.
+func markUpToDateChildren(state *mergestate.MergeState) error {
+	remaining := []string{}
+	for _, branch := range state.ChildBranches {
+		if !slices.Contains(state.UpdatedBranches, branch) {
+			remaining = append(remaining, branch)
+		}
+	}
+
+	upToDate := make([]bool, len(remaining))
+	errs := make([]error, len(remaining))
+	var wg sync.WaitGroup
+	for i, branch := range remaining {
+		wg.Add(1)
+		go func(i int, branch string) {
+			defer wg.Done()
+			upToDate[i], errs[i] = git.IsAncestor(state.ParentBranch, branch)
+		}(i, branch)
+	}
+	wg.Wait()
+
+	for i, branch := range remaining {
+		if errs[i] != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("compare '%s' with '%s'", branch, state.ParentBranch), Err: errs[i]}
+		}
+		if upToDate[i] {
+			fmt.Printf("Child base branch '%s' already up to date with '%s'\n", branch, state.ParentBranch)
+			state.UnchangedBranches = append(state.UnchangedBranches, branch)
+			state.UpdatedBranches = append(state.UpdatedBranches, branch)
+		}
+	}
+
+	if err := saveMergeState(state); err != nil {
+		return &errors.GitError{Operation: "save merge state", Err: err}
+	}
+	return nil
+}
 
 // findNextBranchToUpdate finds the next child branch that needs updating
 func findNextBranchToUpdate(state *mergestate.MergeState) string {
-	for _, branch := range state.ChildBranches {
-		alreadyUpdated := false
-		for _, updated := range state.UpdatedBranches {
+	return findNextPendingBranch(state.ChildBranches, state.UpdatedBranches)
+}
+
+// findNextPendingBranch finds the first of the branches that is not done yet
+func findNextPendingBranch(branches []string, done []string) string {
+	for _, branch := range branches {
+		alreadyDone := false
+		for _, updated := range done {
 			if branch == updated {
-				alreadyUpdated = true
+				alreadyDone = true
 				break
 			}
 		}
-		if !alreadyUpdated {
+		if !alreadyDone {
 			return branch
 		}
 	}
@@ -427,15 +2148,46 @@ func updateChildBranch(branchName string, state *mergestate.MergeState) error {
 		return &errors.GitError{Operation: "load configuration", Err: err}
 	}
 
-	childBranchConfig, ok := cfg.Branches[branchName]
-	if !ok {
-		return &errors.GitError{Operation: fmt.Sprintf("get config for branch '%s'", branchName), Err: fmt.Errorf("branch config not found")}
+	childBranchConfig, ok := cfg.Branches[branchName]
+	if !ok {
+		return &errors.GitError{Operation: fmt.Sprintf("get config for branch '%s'", branchName), Err: fmt.Errorf("branch config not found")}
+	}
+
+	// A state saved before the finish recorded the child heads gets them now, before the branch moves
+	if _, ok := state.ChildBranchHeads[branchName]; !ok {
+		if err := recordChildBranchHead(state, branchName); err != nil {
+			return err
+		}
+		if err := saveMergeState(state); err != nil {
+			return &errors.GitError{Operation: "save merge state", Err: err}
+		}
+	}
+
+	// The strategy is recorded when the update begins, so that continuing after a conflict uses the
+	// same strategy even if the configuration changed in the meantime
+	strategy, ok := state.ChildStrategies[branchName]
+	if !ok {
+		strategy = childBranchConfig.DownstreamStrategy
+		if state.ChildStrategies == nil {
+			state.ChildStrategies = map[string]string{}
+		}
+		state.ChildStrategies[branchName] = strategy
+		if err := saveMergeState(state); err != nil {
+			return &errors.GitError{Operation: "save merge state", Err: err}
+		}
+	}
+
+	// Use the message template configured for the child, if any
+	message, err := mergeMessageFromFile(state.BranchType, state.ParentBranch, branchName)
+	if err != nil {
+		return err
 	}
 
 	// Use the shared update logic
-	err = update.UpdateBranchFromParent(branchName, state.ParentBranch, childBranchConfig.DownstreamStrategy, true, state)
+	err = update.UpdateBranchFromParentWithOptions(branchName, state.ParentBranch, strategy, true, state, &git.MergeOptions{Message: message, ReflogAction: reflogAction(state)})
 	if err != nil {
 		if _, ok := err.(*errors.UnresolvedConflictsError); ok {
+			printConflicts(branchName, state.ParentBranch)
 			msg := fmt.Sprintf("Merge conflicts detected while updating base branch '%s'. Resolve conflicts and run 'git flow %s finish --continue %s'\n", branchName, state.BranchType, state.BranchName)
 			msg += fmt.Sprintf("To abort the merge, run 'git flow %s finish --abort %s'", state.BranchType, state.BranchName)
 			fmt.Println(msg)
@@ -448,27 +2200,279 @@ func updateChildBranch(branchName string, state *mergestate.MergeState) error {
 }
 
 // handleDeleteBranchStep handles branch deletion
-func handleDeleteBranchStep(state *mergestate.MergeState, retentionOptions *BranchRetentionOptions) error {
+func handleDeleteBranchStep(state *mergestate.MergeState, retentionOptions *BranchRetentionOptions, finishOptions *FinishOptions) (*FinishResult, error) {
 	// Ensure we're on the parent branch before deletion
 	if err := git.Checkout(state.ParentBranch); err != nil {
-		return &errors.GitError{Operation: fmt.Sprintf("checkout parent branch '%s'", state.ParentBranch), Err: err}
+		return nil, &errors.GitError{Operation: fmt.Sprintf("checkout parent branch '%s'", state.ParentBranch), Err: err}
 	}
 
 	// Get retention settings
 	keep, keepRemote, keepLocal, forceDelete := getBranchRetentionSettings(state.BranchType, retentionOptions)
+	if state.LocalOnly {
+		keepRemote = true
+	}
 
-	// Delete branches based on settings
-	if err := deleteBranchesIfNeeded(state, keep, keepRemote, keepLocal, forceDelete); err != nil {
-		return err
+	forceRemoteDelete := retentionOptions != nil && retentionOptions.ForceRemoteDelete
+	verifyRemote := shouldVerifyRemoteBranch(state.BranchType, retentionOptions)
+	keepBranchConfig := shouldKeepBranchConfig(state.BranchType, retentionOptions)
+
+	// Delete branches based on settings (a source ref has no branch to delete)
+	deleted := []string{}
+	if !state.IsSourceRef {
+		var err error
+		deleted, err = deleteBranchesIfNeeded(state, keep, keepRemote, keepLocal, forceDelete, forceRemoteDelete, verifyRemote, keepBranchConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	restoreUntrackedStash(state)
+
+	// The state is kept until the push succeeds, so that a failed push can be retried with --continue
+	if state.Push {
+		state.CurrentStep = stepPush
+		state.UntrackedStash = ""
+		state.DeletedBranches = deleted
+		if err := saveMergeState(state); err != nil {
+			return nil, &errors.GitError{Operation: "save merge state", Err: err}
+		}
+		return handlePushStep(state, finishOptions)
+	}
+
+	return completeFinish(state, deleted, finishOptions)
+}
+
+// handlePushStep pushes the branches and tag of the finish. The merges are complete locally, so a
+// failed push keeps the merge state and stops, and --continue retries the push.
+func handlePushStep(state *mergestate.MergeState, finishOptions *FinishOptions) (*FinishResult, error) {
+	if err := pushBaseBranches(state); err != nil {
+		fmt.Fprintf(os.Stderr, "The %s branch '%s' is finished locally, but pushing failed. Run 'git flow %s finish --continue %s' to retry the push, or '--abort' to skip it.\n",
+			state.BranchType, state.FullBranchName, state.BranchType, state.BranchName)
+		emitFinishEvent(state, finishOptions, finishEventPushFailed)
+		return nil, err
 	}
+	return completeFinish(state, state.DeletedBranches, finishOptions)
+}
+
+// completeFinish clears the merge state and reports the finished branch
+func completeFinish(state *mergestate.MergeState, deleted []string, finishOptions *FinishOptions) (*FinishResult, error) {
+	// Nothing is left to resume once the state is cleared
+	clearInterruptState()
 
 	// Clear the merge state
 	if err := mergestate.ClearMergeState(); err != nil {
-		return &errors.GitError{Operation: "clear merge state", Err: err}
+		return nil, &errors.GitError{Operation: "clear merge state", Err: err}
 	}
 
-	fmt.Printf("Successfully finished branch '%s' and updated %d child base branches\n", state.FullBranchName, len(state.UpdatedBranches))
-	return nil
+	result := newFinishResult(state, deleted)
+
+	// The finish is complete from here on, so the reports, hooks and the webhook below only warn on failure
+	recordFinishMetrics(state)
+	recordBaseSnapshot(state)
+	fmt.Println(formatFinishSuccess(state, finishOptions))
+	if finishOptions != nil && finishOptions.PrintTag {
+		printCreatedTag(state)
+	}
+	if finishOptions != nil && finishOptions.ChildrenReport {
+		printChildrenStrategyReport(state)
+	}
+	printStepTimings()
+
+	// Refresh the remote-tracking branches so ahead/behind reports reflect the remote, not the last fetch
+	if finishOptions != nil && finishOptions.TargetRemoteTrackingUpdate {
+		updateRemoteTrackingBranches(state)
+	}
+
+	runPostFinishCommand(state, finishOptions)
+	runIssueCommentCommand(state, finishOptions)
+	emitFinishEvent(state, finishOptions, finishEventSuccess)
+	return result, nil
+}
+
+// printCreatedTag prints the tag of the finish with the commit it points to, in the stable
+// form "tag: <name> <sha>", so that scripts can pick it up. Nothing is printed without a tag.
+func printCreatedTag(state *mergestate.MergeState) {
+	if state.TagName == "" {
+		return
+	}
+	commit, err := git.ResolveCommit("refs/tags/" + state.TagName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to resolve tag '%s': %v\n", state.TagName, err)
+		return
+	}
+	fmt.Printf("tag: %s %s\n", state.TagName, commit)
+}
+
+// updateRemoteTrackingBranches fetches the remote-tracking branches of the target and the updated child
+// base branches and reports how the local branches compare to them. Nothing is pushed.
+func updateRemoteTrackingBranches(state *mergestate.MergeState) {
+	remote := finishRemote()
+	for _, branch := range append([]string{state.ParentBranch}, state.UpdatedBranches...) {
+		if !git.RemoteBranchExists(remote, branch) {
+			continue
+		}
+		remoteRef := fmt.Sprintf("%s/%s", remote, branch)
+		if err := git.FetchBranch(remote, branch); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not update '%s': %v\n", remoteRef, err)
+			continue
+		}
+		ahead, aheadErr := git.CountCommits(remoteRef, branch)
+		behind, behindErr := git.CountCommits(branch, remoteRef)
+		if aheadErr != nil || behindErr != nil {
+			continue
+		}
+		if ahead == 0 && behind == 0 {
+			fmt.Printf("'%s' is up to date with '%s'\n", branch, remoteRef)
+			continue
+		}
+		fmt.Printf("'%s' is %d ahead and %d behind '%s'\n", branch, ahead, behind, remoteRef)
+	}
+}
+
+// restoreUntrackedStash restores untracked files stashed at the start of the finish. The branches are
+// already in their final state at this point, so a failure only warrants a warning.
+func restoreUntrackedStash(state *mergestate.MergeState) {
+	if state.UntrackedStash == "" {
+		return
+	}
+	if err := git.PopStash(state.UntrackedStash); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not restore untracked files, they are kept in stash %s: %v\n", shortSHA(state.UntrackedStash), err)
+		return
+	}
+	fmt.Println("Restored untracked files")
+}
+
+// formatFinishSuccess renders the success line, using the --output-format or gitflow.finish.outputformat
+// template if set. Supported placeholders: %branch%, %target%, %strategy%, %tag%, %childcount%
+func formatFinishSuccess(state *mergestate.MergeState, finishOptions *FinishOptions) string {
+	// 1. Check config
+	format := ""
+	configFormat, err := git.GetConfig("gitflow.finish.outputformat")
+	if err == nil && configFormat != "" {
+		format = configFormat
+	}
+
+	// 2. Command-line flag overrides config
+	if finishOptions != nil && finishOptions.OutputFormat != "" {
+		format = finishOptions.OutputFormat
+	}
+
+	if format == "" && state.MergeStrategy == string(config.MergeStrategyNone) {
+		return fmt.Sprintf("Finished %s branch '%s' without merging it, as the upstream strategy of %s is 'none'; the branch is kept", state.BranchType, state.FullBranchName, state.BranchType)
+	}
+	if format == "" {
+		return fmt.Sprintf("Successfully finished branch '%s' and updated %d child base branches", state.FullBranchName, len(state.UpdatedBranches))
+	}
+
+	replacer := strings.NewReplacer(
+		"%branch%", state.FullBranchName,
+		"%target%", state.ParentBranch,
+		"%strategy%", strings.ToLower(state.MergeStrategy),
+		"%tag%", state.TagName,
+		"%childcount%", fmt.Sprintf("%d", len(state.UpdatedBranches)),
+	)
+	return replacer.Replace(format)
+}
+
+// runPostFinishCommand runs the configured post-finish command, if any, with the
+// final state of the operation exposed through GITFLOW_* environment variables
+func runPostFinishCommand(state *mergestate.MergeState, finishOptions *FinishOptions) {
+	// 1. Check branch-specific config
+	command := ""
+	configCommand, err := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.postcommand", state.BranchType))
+	if err == nil && configCommand != "" {
+		command = configCommand
+	}
+
+	// 2. Command-line flag overrides config
+	if finishOptions != nil && finishOptions.PostFinishCommand != "" {
+		command = finishOptions.PostFinishCommand
+	}
+
+	if command == "" {
+		return
+	}
+
+	verbose := finishOptions != nil && finishOptions.Verbose
+	if verbose {
+		fmt.Printf("Running post-finish command: %s\n", command)
+	}
+
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Env = append(os.Environ(),
+		"GITFLOW_TYPE="+state.BranchType,
+		"GITFLOW_NAME="+state.BranchName,
+		"GITFLOW_BRANCH="+state.FullBranchName,
+		"GITFLOW_TARGET="+state.ParentBranch,
+		"GITFLOW_TAG="+state.TagName,
+		"GITFLOW_UPDATED_BRANCHES="+strings.Join(state.UpdatedBranches, " "),
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: post-finish command failed: %v\n", err)
+		if len(output) > 0 {
+			fmt.Fprintf(os.Stderr, "%s", output)
+		}
+		return
+	}
+
+	if verbose && len(output) > 0 {
+		fmt.Printf("%s", output)
+	}
+}
+
+// runIssueCommentCommand runs the configured issue comment command, if any, with %issue%, %tag%,
+// %branch% and %target% replaced. It is skipped for branches without a recorded issue. The placeholders
+// become quoted references to GITFLOW_* variables, so that a ref name containing $(...), backticks or ;
+// is never run by the shell; inside single quotes they are therefore not expanded.
+func runIssueCommentCommand(state *mergestate.MergeState, finishOptions *FinishOptions) {
+	// 1. Check branch-specific config
+	command := ""
+	configCommand, err := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.issuecommentcommand", state.BranchType))
+	if err == nil && configCommand != "" {
+		command = configCommand
+	}
+
+	// 2. Command-line flag overrides config
+	if finishOptions != nil && finishOptions.IssueCommand != "" {
+		command = finishOptions.IssueCommand
+	}
+
+	if command == "" || state.Issue == "" {
+		return
+	}
+
+	command = strings.NewReplacer(
+		"%issue%", `"$GITFLOW_ISSUE"`,
+		"%tag%", `"$GITFLOW_TAG"`,
+		"%branch%", `"$GITFLOW_BRANCH"`,
+		"%target%", `"$GITFLOW_TARGET"`,
+	).Replace(command)
+
+	verbose := finishOptions != nil && finishOptions.Verbose
+	if verbose {
+		fmt.Printf("Running issue comment command: %s\n", command)
+	}
+
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Env = append(os.Environ(),
+		"GITFLOW_ISSUE="+state.Issue,
+		"GITFLOW_BRANCH="+state.FullBranchName,
+		"GITFLOW_TARGET="+state.ParentBranch,
+		"GITFLOW_TAG="+state.TagName,
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: issue comment command for issue '%s' failed: %v\n", state.Issue, err)
+		if len(output) > 0 {
+			fmt.Fprintf(os.Stderr, "%s", output)
+		}
+		return
+	}
+
+	if verbose && len(output) > 0 {
+		fmt.Printf("%s", output)
+	}
 }
 
 // getBranchRetentionSettings determines branch retention settings
@@ -522,37 +2526,179 @@ func getBranchRetentionSettings(branchType string, retentionOptions *BranchReten
 	return keep, keepRemote, keepLocal, forceDelete
 }
 
-// deleteBranchesIfNeeded deletes branches based on retention settings
-func deleteBranchesIfNeeded(state *mergestate.MergeState, keep, keepRemote, keepLocal, forceDelete bool) error {
-	// Delete remote branch if not keeping it and if remote branch exists
+// shouldKeepBranchConfig reports whether the settings of a deleted branch are kept, e.g. to archive its
+// metadata, from gitflow.<type>.finish.keepbranchconfig and the command-line flags. A kept branch
+// always keeps its settings.
+func shouldKeepBranchConfig(branchType string, retentionOptions *BranchRetentionOptions) bool {
+	// 1. Check branch-specific config
+	keepConfig := false
+	configValue, err := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.keepbranchconfig", branchType))
+	if err == nil && configValue == "true" {
+		keepConfig = true
+	}
+
+	// 2. Command-line flags override config
+	if retentionOptions != nil && retentionOptions.KeepBranchConfig != nil {
+		keepConfig = *retentionOptions.KeepBranchConfig
+	}
+
+	return keepConfig
+}
+
+// shouldVerifyRemoteBranch determines whether the remote branch is fetched before it is deleted, to check
+// that it has no commits that were pushed since the last fetch and not merged
+func shouldVerifyRemoteBranch(branchType string, retentionOptions *BranchRetentionOptions) bool {
+	// 1. Check branch-specific config
+	verify := false
+	if configValue, err := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.verifyremote", branchType)); err == nil && configValue == "true" {
+		verify = true
+	}
+
+	// 2. Command-line flags override config
+	if retentionOptions != nil && retentionOptions.VerifyRemote != nil {
+		verify = *retentionOptions.VerifyRemote
+	}
+
+	return verify
+}
+
+// deleteBranchesIfNeeded deletes branches based on retention settings.
+// It returns the branches it deleted, remote ones prefixed with the remote name.
+func deleteBranchesIfNeeded(state *mergestate.MergeState, keep, keepRemote, keepLocal, forceDelete, forceRemoteDelete, verifyRemote, keepBranchConfig bool) ([]string, error) {
+	deleted := []string{}
+
+	// Delete remote branch if not keeping it and if remote branch exists. This is the only
+	// step of a finish that contacts the remote unless fetching or pushing is requested.
 	if !keepRemote {
-		// Only attempt to delete if the remote branch actually exists
-		if git.RemoteBranchExists("origin", state.FullBranchName) {
-			remoteBranch := fmt.Sprintf("origin/%s", state.FullBranchName)
-			if err := git.DeleteRemoteBranch("origin", state.FullBranchName); err != nil {
-				return &errors.GitError{Operation: fmt.Sprintf("delete remote branch '%s'", remoteBranch), Err: err}
+		// Only attempt to delete if the remote branch actually exists; the check uses the
+		// local remote-tracking branch, so a branch that was never pushed causes no network access
+		remote := finishRemote()
+		if git.RemoteBranchExists(remote, state.FullBranchName) {
+			remoteBranch := fmt.Sprintf("%s/%s", remote, state.FullBranchName)
+			if !forceRemoteDelete && !remoteBranchMerged(state, remote, remoteBranch, verifyRemote) {
+				fmt.Fprintf(os.Stderr, "Warning: keeping remote branch '%s': it has commits that were not merged into '%s'. Use --force-remote-delete to delete it anyway\n", remoteBranch, state.ParentBranch)
+			} else if err := git.DeleteRemoteBranch(remote, state.FullBranchName); err != nil {
+				return deleted, &errors.GitError{Operation: fmt.Sprintf("delete remote branch '%s'", remoteBranch), Err: err}
+			} else {
+				deleted = append(deleted, remoteBranch)
 			}
 		}
 	}
 
 	// Delete local branch if not keeping it
 	if !keepLocal {
-		if err := git.DeleteBranch(state.FullBranchName, forceDelete); err != nil {
-			return &errors.GitError{Operation: fmt.Sprintf("delete branch '%s'", state.FullBranchName), Err: err}
+		if err := ensureBranchNotMoved(state, forceDelete); err != nil {
+			return deleted, err
+		}
+		// The squash commit doesn't contain the commits of the branch, so git can't tell it was merged
+		squashed := strings.ToLower(state.MergeStrategy) == strategySquash
+		if err := git.DeleteBranch(state.FullBranchName, forceDelete || squashed); err != nil {
+			return deleted, &errors.GitError{Operation: fmt.Sprintf("delete branch '%s'", state.FullBranchName), Err: err}
 		}
+		if !keepBranchConfig {
+			cleanupBranchConfig(state.FullBranchName)
+		}
+		deleted = append(deleted, state.FullBranchName)
 	}
 
-	return nil
+	return deleted, nil
+}
+
+// ensureBranchNotMoved returns an error if the branch no longer points at the commit that was merged,
+// e.g. because another process committed to it in the meantime. Forced deletion only warns.
+func ensureBranchNotMoved(state *mergestate.MergeState, forceDelete bool) error {
+	if state.MergedHead == "" {
+		return nil
+	}
+	head, err := git.ResolveCommit(state.FullBranchName)
+	if err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("resolve branch '%s'", state.FullBranchName), Err: err}
+	}
+	if head == state.MergedHead {
+		return nil
+	}
+	if forceDelete {
+		fmt.Fprintf(os.Stderr, "Warning: branch '%s' moved from %s to %s after it was merged; deleting it anyway\n", state.FullBranchName, shortSHA(state.MergedHead), shortSHA(head))
+		return nil
+	}
+	return &errors.BranchMovedError{
+		BranchType:  state.BranchType,
+		BranchName:  state.BranchName,
+		FullName:    state.FullBranchName,
+		MergedHead:  shortSHA(state.MergedHead),
+		CurrentHead: shortSHA(head),
+	}
+}
+
+// remoteBranchMerged checks that the tip of the remote branch is contained in the parent or in the local
+// branch, so deleting it loses nothing. Only if fetch is set is the remote branch fetched first, which
+// also catches commits a teammate pushed since the last fetch.
+func remoteBranchMerged(state *mergestate.MergeState, remote string, remoteBranch string, fetch bool) bool {
+	if fetch {
+		if err := git.FetchBranch(remote, state.FullBranchName); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not fetch '%s', verifying against the last known remote state: %v\n", remoteBranch, err)
+		}
+	}
+
+	for _, branch := range []string{state.ParentBranch, state.FullBranchName} {
+		if merged, err := git.IsAncestor(remoteBranch, branch); err == nil && merged {
+			return true
+		}
+	}
+	return false
 }
 
-func finish(state *mergestate.MergeState, branchConfig config.BranchConfig, tagOptions *TagOptions, retentionOptions *BranchRetentionOptions) error {
+func finish(state *mergestate.MergeState, branchConfig config.BranchConfig, tagOptions *TagOptions, retentionOptions *BranchRetentionOptions, finishOptions *FinishOptions) (*FinishResult, error) {
+	startStepTiming(stepMerge)
+
 	// Checkout target branch
 	err := git.Checkout(state.ParentBranch)
 	if err != nil {
-		return &errors.GitError{Operation: fmt.Sprintf("checkout target branch '%s'", state.ParentBranch), Err: err}
+		return nil, &errors.GitError{Operation: fmt.Sprintf("checkout target branch '%s'", state.ParentBranch), Err: err}
 	}
 	fmt.Printf("Switched to branch '%s'\n", state.ParentBranch)
 
+	// A previous, partially failed run may already have merged the branch; reuse that merge if requested
+	if finishOptions != nil && finishOptions.ReuseExistingMerge {
+		alreadyMerged, err := git.IsAncestor(state.FullBranchName, state.ParentBranch)
+		if err != nil {
+			return nil, &errors.GitError{Operation: fmt.Sprintf("compare '%s' with '%s'", state.FullBranchName, state.ParentBranch), Err: err}
+		}
+		if alreadyMerged {
+			fmt.Printf("Branch '%s' is already merged into '%s', reusing the existing merge\n", state.FullBranchName, state.ParentBranch)
+			if err := completeMergeStep(state); err != nil {
+				return nil, err
+			}
+			if err := saveMergeState(state); err != nil {
+				return nil, &errors.GitError{Operation: "save merge state", Err: err}
+			}
+			return handleContinue(state, branchConfig, tagOptions, retentionOptions, finishOptions)
+		}
+	}
+
+	// Use the message template configured for the target, and open the editor on it if requested
+	mergeOptions := getMergeOptions(finishOptions)
+	mergeOptions.ReflogAction = reflogAction(state)
+	mergeOptions.Message, err = mergeMessageFromFile(state.BranchType, state.FullBranchName, state.ParentBranch)
+	if err != nil {
+		return nil, err
+	}
+	if state.MergeStrategy == strategySquash {
+		state.SquashMessage, err = squashMessage(state, finishOptions)
+		if err != nil {
+			return nil, err
+		}
+		mergeOptions.Message = state.SquashMessage
+	}
+	if shouldEditMergeMessage(state.BranchType, finishOptions) {
+		template, err := mergeMessageTemplate(state)
+		if err != nil {
+			return nil, err
+		}
+		mergeOptions.EditMessage = true
+		mergeOptions.Message = template
+	}
+
 	// Perform merge based on strategy
 	fmt.Printf("Merging using strategy: %v\n", strings.ToLower(branchConfig.UpstreamStrategy))
 	var mergeErr error
@@ -563,7 +2709,7 @@ func finish(state *mergestate.MergeState, branchConfig config.BranchConfig, tagO
 		// 1. Stay on feature branch
 		err = git.Checkout(state.FullBranchName)
 		if err != nil {
-			return &errors.GitError{Operation: "checkout feature branch for rebase", Err: err}
+			return nil, &errors.GitError{Operation: "checkout feature branch for rebase", Err: err}
 		}
 		// 2. Rebase onto target branch
 		mergeErr = git.Rebase(state.ParentBranch)
@@ -571,101 +2717,459 @@ func finish(state *mergestate.MergeState, branchConfig config.BranchConfig, tagO
 			// 3. If rebase succeeds, checkout target and merge (should be fast-forward)
 			err = git.Checkout(state.ParentBranch)
 			if err != nil {
-				return &errors.GitError{Operation: "checkout target branch after rebase", Err: err}
+				return nil, &errors.GitError{Operation: "checkout target branch after rebase", Err: err}
 			}
-			mergeErr = git.Merge(state.FullBranchName)
+			mergeErr = git.MergeWithOptions(state.FullBranchName, mergeOptions)
 		}
 	case strategySquash:
-		mergeErr = git.SquashMerge(state.FullBranchName)
+		mergeErr = git.SquashMergeWithOptions(state.FullBranchName, mergeOptions)
 	case strategyMerge:
-		mergeErr = git.Merge(state.FullBranchName)
+		mergeErr = git.MergeWithOptions(state.FullBranchName, mergeOptions)
 	default:
-		return &errors.GitError{Operation: fmt.Sprintf("unknown merge strategy: %s", strings.ToLower(branchConfig.UpstreamStrategy)), Err: nil}
+		return nil, &errors.GitError{Operation: fmt.Sprintf("unknown merge strategy: %s", strings.ToLower(branchConfig.UpstreamStrategy)), Err: nil}
 	}
 
 	if mergeErr != nil {
 		if strings.Contains(mergeErr.Error(), "conflict") {
 			// Save state before returning conflict error
 			state.CurrentStep = stepMerge
-			if err := mergestate.SaveMergeState(state); err != nil {
-				return &errors.GitError{Operation: "save merge state", Err: err}
+			if err := saveMergeState(state); err != nil {
+				return nil, &errors.GitError{Operation: "save merge state", Err: err}
 			}
 
+			printConflicts(state.ParentBranch, state.FullBranchName)
 			msg := fmt.Sprintf("Merge conflicts detected. Resolve conflicts and run 'git flow %s finish --continue %s'\n", state.BranchType, state.BranchName)
 			msg += fmt.Sprintf("To abort the merge, run 'git flow %s finish --abort %s'", state.BranchType, state.BranchName)
 			fmt.Println(msg)
-			return &errors.UnresolvedConflictsError{}
+			return nil, &errors.UnresolvedConflictsError{}
+		}
+		if strings.Contains(mergeErr.Error(), "edited message") {
+			// The changes are staged, only the commit is missing
+			state.CurrentStep = stepMerge
+			if err := saveMergeState(state); err != nil {
+				return nil, &errors.GitError{Operation: "save merge state", Err: err}
+			}
+			fmt.Printf("The merge is staged but was not committed. Commit it with 'git commit' and run 'git flow %s finish --continue %s'\n", state.BranchType, state.BranchName)
+			fmt.Printf("To abort the merge, run 'git flow %s finish --abort %s'\n", state.BranchType, state.BranchName)
+		}
+		if strings.Contains(mergeErr.Error(), "refusing to merge unrelated histories") {
+			// Git refused before changing anything, so there is nothing to resume
+			restoreUntrackedStash(state)
+			clearInterruptState()
+			if err := mergestate.ClearMergeState(); err != nil {
+				return nil, &errors.GitError{Operation: "clear merge state", Err: err}
+			}
+			fmt.Printf("'%s' and '%s' share no history. Run the finish again with --merge-allow-unrelated-histories to merge them anyway\n", state.FullBranchName, state.ParentBranch)
 		}
-		return &errors.GitError{Operation: "merge branch", Err: mergeErr}
+		return nil, &errors.GitError{Operation: "merge branch", Err: mergeErr}
 	}
 
-	// Move to next step (tag creation)
-	state.CurrentStep = stepCreateTag
-	if err := mergestate.SaveMergeState(state); err != nil {
-		return &errors.GitError{Operation: "save merge state", Err: err}
+	// Move to next step (additional targets or tag creation)
+	if err := completeMergeStep(state); err != nil {
+		return nil, err
+	}
+	if err := saveMergeState(state); err != nil {
+		return nil, &errors.GitError{Operation: "save merge state", Err: err}
+	}
+
+	return handleContinue(state, branchConfig, tagOptions, retentionOptions, finishOptions)
+}
+
+// conflictGuidance holds resolution hints per conflict type, keyed by git.Conflict* constants
+var conflictGuidance = map[string]string{
+	git.ConflictContent:      "edit the conflict markers, then run 'git add %s'",
+	git.ConflictAddAdd:       "both sides added this file; combine the versions, then run 'git add %s'",
+	git.ConflictRenameRename: "the file was renamed differently on each side; keep one name with 'git add' and remove the others with 'git rm' (%s)",
+	git.ConflictRenameDelete: "renamed on one side but deleted on the other; run 'git add %[1]s' to keep it or 'git rm %[1]s' to drop it",
+	git.ConflictModifyDelete: "modified on one side but deleted on the other; run 'git add %[1]s' to keep it or 'git rm %[1]s' to drop it",
+}
+
+// printConflicts lists the conflicted files with their conflict type and type-specific guidance.
+// Submodule pointers are left to printSubmoduleConflicts.
+func printConflicts(oursLabel string, theirsLabel string) {
+	conflicts, err := git.GetConflicts()
+	if err != nil || len(conflicts) == 0 {
+		return
+	}
+	submodules := map[string]bool{}
+	if conflictedSubmodules, err := git.GetConflictedSubmodules(); err == nil {
+		for _, submodule := range conflictedSubmodules {
+			submodules[submodule.Path] = true
+		}
+	}
+
+	printed := false
+	for _, conflict := range conflicts {
+		if submodules[conflict.Path] {
+			continue
+		}
+		if !printed {
+			fmt.Printf("%s\n", colorize(colorRed, fmt.Sprintf("Conflicts between '%s' and '%s':", oursLabel, theirsLabel)))
+			printed = true
+		}
+		fmt.Printf("  %s (%s): %s\n", colorize(colorRed, conflict.Path), colorize(colorYellow, conflict.Type), fmt.Sprintf(conflictGuidance[conflict.Type], conflict.Path))
+	}
+	printSubmoduleConflicts(oursLabel, theirsLabel)
+}
+
+// printSubmoduleConflicts prints resolution guidance for conflicted submodule pointers.
+// oursLabel names the branch being merged into, theirsLabel the branch being merged.
+func printSubmoduleConflicts(oursLabel string, theirsLabel string) {
+	conflicts, err := git.GetConflictedSubmodules()
+	if err != nil || len(conflicts) == 0 {
+		return
+	}
+
+	for _, conflict := range conflicts {
+		fmt.Printf("%s\n", colorize(colorRed, fmt.Sprintf("Submodule conflict in '%s':", conflict.Path)))
+		if conflict.Ours != "" {
+			fmt.Printf("  %s points to %s\n", oursLabel, shortSHA(conflict.Ours))
+		}
+		if conflict.Theirs != "" {
+			fmt.Printf("  %s points to %s\n", theirsLabel, shortSHA(conflict.Theirs))
+		}
+	}
+	fmt.Println("To resolve a submodule conflict, check out the commit you want in the submodule and stage it:")
+	for _, conflict := range conflicts {
+		fmt.Printf("  git -C %s checkout <commit> && git add %s\n", conflict.Path, conflict.Path)
+	}
+}
+
+// validateContinueState cross-checks the saved finish step against pending git operations
+// so that --continue does not act on a merge that belongs to a different step
+func validateContinueState(state *mergestate.MergeState) error {
+	if !git.HasMergeHead() && !git.HasConflicts() {
+		return nil
+	}
+
+	currentBranch, err := git.GetCurrentBranch()
+	if err != nil {
+		return &errors.GitError{Operation: "get current branch", Err: err}
+	}
+
+	reason := ""
+	switch state.CurrentStep {
+	case stepRefreshParent:
+		// A pending merge is expected on the target branch being refreshed
+		if currentBranch == state.ParentBranch {
+			return nil
+		}
+		reason = fmt.Sprintf("a git merge is in progress on '%s', which is not the target branch being updated", currentBranch)
+	case stepMerge:
+		// A pending merge is expected while the topic branch is being merged
+		return nil
+	case stepMergeAlsoInto:
+		// A pending merge is expected on the additional target being merged into
+		nextBranch := findNextPendingBranch(state.AlsoInto, state.MergedInto)
+		if nextBranch != "" && currentBranch == nextBranch {
+			return nil
+		}
+		reason = fmt.Sprintf("a git merge is in progress on '%s', which is not the additional target being merged into", currentBranch)
+	case stepUpdateChildren:
+		// A pending merge is expected on the child base branch being updated
+		nextBranch := findNextBranchToUpdate(state)
+		if nextBranch != "" && currentBranch == nextBranch {
+			return nil
+		}
+		reason = fmt.Sprintf("a git merge is in progress on '%s', which is not the child base branch being updated", currentBranch)
+	default:
+		reason = fmt.Sprintf("a git merge is in progress on '%s', but all merges of this finish have already completed", currentBranch)
+	}
+
+	return &errors.InconsistentMergeStateError{
+		Step:       state.CurrentStep,
+		Reason:     reason,
+		BranchType: state.BranchType,
+		BranchName: state.BranchName,
+	}
+}
+
+// stepTimingLabel names the step the state is at for --report-timing. Each child update is timed on its own;
+// checking that no children are left counts towards the last one.
+func stepTimingLabel(state *mergestate.MergeState) string {
+	if state.CurrentStep == stepMergeAlsoInto {
+		if nextBranch := findNextPendingBranch(state.AlsoInto, state.MergedInto); nextBranch != "" {
+			return fmt.Sprintf("%s %s", stepMergeAlsoInto, nextBranch)
+		}
+		return stepTimer.current
+	}
+	if state.CurrentStep != stepUpdateChildren {
+		return state.CurrentStep
 	}
+	if nextBranch := findNextBranchToUpdate(state); nextBranch != "" {
+		return fmt.Sprintf("%s %s", stepUpdateChildren, nextBranch)
+	}
+	return stepTimer.current
+}
 
-	return handleContinue(state, branchConfig, tagOptions, retentionOptions)
+// shortSHA abbreviates a commit SHA for display
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
 }
 
-func handleContinue(state *mergestate.MergeState, branchConfig config.BranchConfig, tagOptions *TagOptions, retentionOptions *BranchRetentionOptions) error {
+func handleContinue(state *mergestate.MergeState, branchConfig config.BranchConfig, tagOptions *TagOptions, retentionOptions *BranchRetentionOptions, finishOptions *FinishOptions) (*FinishResult, error) {
+	startStepTiming(stepTimingLabel(state))
+
 	switch state.CurrentStep {
+	case stepRefreshParent:
+		// Check if there are still conflicts from updating the target branch
+		if git.HasConflicts() {
+			return nil, &errors.UnresolvedConflictsError{}
+		}
+		return handleRefreshParentStep(state, branchConfig, tagOptions, retentionOptions, finishOptions)
+
 	case stepMerge:
 		// Check if there are still conflicts
 		if git.HasConflicts() {
-			return &errors.UnresolvedConflictsError{}
+			return nil, &errors.UnresolvedConflictsError{}
+		}
+		if err := commitStagedSquash(state); err != nil {
+			return nil, err
 		}
 
 		// Move to next step
-		state.CurrentStep = stepCreateTag
-		if err := mergestate.SaveMergeState(state); err != nil {
-			return &errors.GitError{Operation: "save merge state", Err: err}
+		if err := completeMergeStep(state); err != nil {
+			return nil, err
 		}
-		return handleContinue(state, branchConfig, tagOptions, retentionOptions)
+		if err := saveMergeState(state); err != nil {
+			return nil, &errors.GitError{Operation: "save merge state", Err: err}
+		}
+		return handleContinue(state, branchConfig, tagOptions, retentionOptions, finishOptions)
+
+	case stepMergeAlsoInto:
+		return handleMergeAlsoIntoStep(state, branchConfig, tagOptions, retentionOptions, finishOptions)
 
 	case stepCreateTag:
-		return handleCreateTagStep(state, branchConfig, tagOptions, retentionOptions)
+		return handleCreateTagStep(state, branchConfig, tagOptions, retentionOptions, finishOptions)
 
 	case stepUpdateChildren:
-		return handleUpdateChildrenStep(state, branchConfig, tagOptions, retentionOptions)
+		return handleUpdateChildrenStep(state, branchConfig, tagOptions, retentionOptions, finishOptions)
 
 	case stepDeleteBranch:
-		return handleDeleteBranchStep(state, retentionOptions)
+		return handleDeleteBranchStep(state, retentionOptions, finishOptions)
+
+	case stepPush:
+		return handlePushStep(state, finishOptions)
 
 	default:
-		return &errors.GitError{Operation: fmt.Sprintf("unknown step '%s'", state.CurrentStep), Err: nil}
+		return nil, &errors.GitError{Operation: fmt.Sprintf("unknown step '%s'", state.CurrentStep), Err: nil}
 	}
 }
 
-func handleAbort(state *mergestate.MergeState) error {
+func handleAbort(state *mergestate.MergeState, finishOptions *FinishOptions) (*FinishResult, error) {
+	// Only the push was left, and the merges can't be undone once the branch is deleted
+	if state.CurrentStep == stepPush {
+		clearInterruptState()
+		if err := mergestate.ClearMergeState(); err != nil {
+			return nil, &errors.GitError{Operation: "clear merge state", Err: err}
+		}
+		if len(state.PushedRefs) == 0 {
+			fmt.Printf("The %s branch '%s' stays finished locally; nothing was pushed\n", state.BranchType, state.FullBranchName)
+		} else {
+			fmt.Printf("The %s branch '%s' stays finished locally; already pushed to '%s': %s\n", state.BranchType, state.FullBranchName, finishRemote(), strings.Join(state.PushedRefs, ", "))
+		}
+		emitFinishEvent(state, finishOptions, finishEventAborted)
+		return nil, nil
+	}
+
 	// Abort the merge based on strategy
 	var err error
-	switch state.MergeStrategy {
-	case strategyMerge:
+	switch {
+	case state.CurrentStep == stepRefreshParent:
+		// The target branch was being updated with its own downstream strategy
+		if git.HasMergeHead() {
+			err = git.MergeAbort()
+		} else {
+			err = git.RebaseAbort()
+		}
+	case state.CurrentStep == stepMergeAlsoInto:
+		// An additional target was being merged into, with its own strategy
+		err = git.ResetMerge()
+	case state.CurrentStep == stepUpdateChildren:
+		// A child base branch was being updated, with its own downstream strategy
+		err = abortChildUpdate(state)
+	case state.CurrentStep == stepCreateTag || state.CurrentStep == stepDeleteBranch:
+		// These steps don't stop halfway through a merge, so there is nothing to abort
+	case state.MergeStrategy == strategyMerge:
 		err = git.MergeAbort()
-	case strategyRebase:
+	case state.MergeStrategy == strategyRebase:
 		err = git.RebaseAbort()
+	case state.MergeStrategy == strategySquash && !git.HasMergeHead():
+		// A squash stages its changes without recording a merge
+		err = git.ResetMerge()
 	default:
 		err = git.MergeAbort() // Default to merge abort
 	}
 
 	if err != nil {
-		return &errors.GitError{Operation: "abort merge", Err: err}
+		return nil, &errors.GitError{Operation: "abort merge", Err: err}
+	}
+
+	if err := restoreTargetAndBranch(state); err != nil {
+		return nil, err
 	}
 
-	// Checkout the original branch
-	if err := git.Checkout(state.FullBranchName); err != nil {
-		return &errors.GitError{Operation: fmt.Sprintf("checkout original branch '%s'", state.FullBranchName), Err: err}
+	// Checkout the original branch (a source ref has none, so stay on the target)
+	originalBranch := state.FullBranchName
+	if state.IsSourceRef {
+		originalBranch = state.ParentBranch
+	}
+	if err := git.Checkout(originalBranch); err != nil {
+		return nil, &errors.GitError{Operation: fmt.Sprintf("checkout original branch '%s'", originalBranch), Err: err}
+	}
+
+	if err := restoreChildBranches(state); err != nil {
+		return nil, err
+	}
+	if err := restoreTag(state); err != nil {
+		return nil, err
 	}
 
+	restoreUntrackedStash(state)
+
+	// Nothing is left to resume once the state is cleared
+	clearInterruptState()
+
 	// Clear the merge state
 	if err := mergestate.ClearMergeState(); err != nil {
-		return &errors.GitError{Operation: "clear merge state", Err: err}
+		return nil, &errors.GitError{Operation: "clear merge state", Err: err}
+	}
+
+	emitFinishEvent(state, finishOptions, finishEventAborted)
+	return nil, nil
+}
+
+// abortChildUpdate aborts the update of the child base branch that stopped on a conflict, using the
+// strategy recorded when its update began
+func abortChildUpdate(state *mergestate.MergeState) error {
+	strategy, ok := state.ChildStrategies[findNextBranchToUpdate(state)]
+	if !ok {
+		// A state saved before the strategies were recorded only tells by what git left behind
+		if git.HasMergeHead() {
+			return git.MergeAbort()
+		}
+		if operation, err := git.OperationInProgress(); err == nil && operation == strategyRebase {
+			return git.RebaseAbort()
+		}
+		return git.ResetMerge()
+	}
+	switch strings.ToLower(strategy) {
+	case strategyRebase:
+		return git.RebaseAbort()
+	case strategySquash:
+		// A squash stages its changes without recording a merge
+		return git.ResetMerge()
+	default:
+		return git.MergeAbort()
+	}
+}
+
+// restoreTargetAndBranch moves the target and the finished branch back to the commits they pointed at
+// before the finish, recreating the branch if the finish already deleted it
+func restoreTargetAndBranch(state *mergestate.MergeState) error {
+	type branchHead struct {
+		branch   string
+		original string
 	}
+	heads := []branchHead{{state.ParentBranch, state.TargetHead}}
+	if !state.IsSourceRef {
+		heads = append(heads, branchHead{state.FullBranchName, state.BranchHead})
+	}
+	currentBranch, err := git.GetCurrentBranch()
+	if err != nil {
+		return &errors.GitError{Operation: "get current branch", Err: err}
+	}
+	for _, head := range heads {
+		branch, original := head.branch, head.original
+		if original == "" {
+			continue
+		}
+		current, err := git.ResolveCommit("refs/heads/" + branch)
+		switch {
+		case err != nil:
+			err = git.CreateBranchRef(branch, original)
+		case current == original:
+			continue
+		case branch == currentBranch:
+			err = git.ResetHead(original)
+		default:
+			err = git.ResetBranch(branch, original)
+		}
+		if err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("restore branch '%s'", branch), Err: err}
+		}
+		fmt.Printf("Restored branch '%s' to %s\n", branch, shortSHA(original))
+	}
+	return nil
+}
+
+// restoreTag deletes the tag the finish created, or points a tag it replaced back at the previous object.
+// A tag the finish reused is left alone.
+func restoreTag(state *mergestate.MergeState) error {
+	if !state.TagCreated || state.TagName == "" {
+		return nil
+	}
+	if state.ReplacedTag != "" {
+		if err := git.ResetTag(state.TagName, state.ReplacedTag); err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("restore tag '%s'", state.TagName), Err: err}
+		}
+		fmt.Printf("Restored tag '%s' to %s\n", state.TagName, shortSHA(state.ReplacedTag))
+		return nil
+	}
+	if err := git.DeleteTag(state.TagName); err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("delete tag '%s'", state.TagName), Err: err}
+	}
+	if _, err := git.GetConfig(reservedTagSection(state.TagName) + ".branch"); err == nil {
+		if err := git.RemoveConfigSection(reservedTagSection(state.TagName)); err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("remove reservation of tag '%s'", state.TagName), Err: err}
+		}
+	}
+	fmt.Printf("Deleted tag '%s'\n", state.TagName)
+	return nil
+}
 
+// recordChildBranchHead remembers the commit a child branch points at, so that an abort can restore it
+func recordChildBranchHead(state *mergestate.MergeState, branch string) error {
+	commit, err := git.ResolveCommit(branch)
+	if err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("resolve child branch '%s'", branch), Err: err}
+	}
+	if state.ChildBranchHeads == nil {
+		state.ChildBranchHeads = map[string]string{}
+	}
+	state.ChildBranchHeads[branch] = commit
 	return nil
 }
 
+// restoreChildBranches moves the child branches that were already updated back to the commits
+// they pointed at before the finish
+func restoreChildBranches(state *mergestate.MergeState) error {
+	for _, branch := range append(slices.Clone(state.MergedInto), state.UpdatedBranches...) {
+		original, ok := state.ChildBranchHeads[branch]
+		if !ok {
+			continue
+		}
+		current, err := git.ResolveCommit(branch)
+		if err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("resolve child branch '%s'", branch), Err: err}
+		}
+		if current == original {
+			continue
+		}
+		if err := git.ResetBranch(branch, original); err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("restore child branch '%s'", branch), Err: err}
+		}
+		if slices.Contains(state.MergedInto, branch) {
+			fmt.Printf("Restored branch '%s' to %s\n", branch, shortSHA(original))
+			continue
+		}
+		fmt.Printf("Restored child base branch '%s' to %s\n", branch, shortSHA(original))
+	}
+	return nil
+}
 
 // getBoolFlag converts two opposite boolean flags into a single *bool value
 // If positive is true, returns &true
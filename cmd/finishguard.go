@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+)
+
+// shouldGuardCommitCount determines whether the number of commits of the branch is checked against
+// the configured limits before it is finished
+func shouldGuardCommitCount(finishOptions *FinishOptions) bool {
+	if finishOptions != nil && finishOptions.CommitCountGuard != nil {
+		return *finishOptions.CommitCountGuard
+	}
+	return true
+}
+
+// commitCountLimit returns the limit given as a flag, or else the one set in the config key, and the key
+// it came from. A limit of 0 means there is none.
+func commitCountLimit(key string, option string, flagValue *int) (int, string, error) {
+	if flagValue != nil {
+		if *flagValue < 0 {
+			return 0, "", &errors.InvalidOptionError{Option: option, Reason: fmt.Sprintf("expected a number of commits, got %d", *flagValue)}
+		}
+		return *flagValue, "", nil
+	}
+	configValue, err := git.GetConfig(key)
+	if err != nil || configValue == "" {
+		return 0, "", nil
+	}
+	limit, err := strconv.Atoi(configValue)
+	if err != nil || limit < 0 {
+		return 0, "", &errors.InvalidOptionError{Option: key, Reason: fmt.Sprintf("expected a number of commits, got '%s'", configValue)}
+	}
+	return limit, key, nil
+}
+
+// checkCommitCount refuses to finish a branch whose number of commits that are not in the target is below
+// gitflow.<type>.finish.mincommits or above gitflow.<type>.finish.maxcommits. No commits usually means the
+// wrong branch is finished, hundreds of them that it should have been split.
+func checkCommitCount(branchType string, branchName string, targetBranch string, finishOptions *FinishOptions) error {
+	if !shouldGuardCommitCount(finishOptions) {
+		return nil
+	}
+
+	var minFlag, maxFlag *int
+	if finishOptions != nil {
+		minFlag, maxFlag = finishOptions.MinCommits, finishOptions.MaxCommits
+	}
+	minimum, minKey, err := commitCountLimit(fmt.Sprintf("gitflow.%s.finish.mincommits", branchType), "--min-commits", minFlag)
+	if err != nil {
+		return err
+	}
+	maximum, maxKey, err := commitCountLimit(fmt.Sprintf("gitflow.%s.finish.maxcommits", branchType), "--max-commits", maxFlag)
+	if err != nil {
+		return err
+	}
+	if minimum == 0 && maximum == 0 {
+		return nil
+	}
+
+	count, err := git.CountCommits(targetBranch, branchName)
+	if err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("count commits of '%s'", branchName), Err: err}
+	}
+	if count < minimum {
+		return &errors.CommitCountError{BranchName: branchName, TargetBranch: targetBranch, Count: count, Limit: minimum, Key: minKey}
+	}
+	if maximum > 0 && count > maximum {
+		return &errors.CommitCountError{BranchName: branchName, TargetBranch: targetBranch, Count: count, Limit: maximum, Maximum: true, Key: maxKey}
+	}
+	return nil
+}
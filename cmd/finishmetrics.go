@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/mergestate"
+	"github.com/gittower/git-flow-next/internal/util"
+)
+
+// startFinishMetrics remembers the metrics file and measures the branch before it is merged, so that
+// the row written at the end describes the finish even if it was resumed with --continue
+func startFinishMetrics(state *mergestate.MergeState, finishOptions *FinishOptions) error {
+	if finishOptions == nil || finishOptions.RecordMetrics == "" {
+		return nil
+	}
+
+	// A resumed finish may run in another directory, so keep the file as an absolute path
+	metricsFile, err := filepath.Abs(finishOptions.RecordMetrics)
+	if err != nil {
+		return &errors.InvalidOptionError{Option: "--record-metrics", Reason: err.Error()}
+	}
+	state.MetricsFile = metricsFile
+	state.StartedAt = time.Now().Format(time.RFC3339Nano)
+
+	if commits, err := git.CountCommits(state.ParentBranch, state.FullBranchName); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to count the commits for the finish metrics: %v\n", err)
+	} else {
+		state.CommitsMerged = commits
+	}
+	if files, err := git.ChangedFiles(state.ParentBranch, state.FullBranchName); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to list the changed files for the finish metrics: %v\n", err)
+	} else {
+		state.FilesChanged = len(files)
+	}
+	return nil
+}
+
+// recordFinishConflict notes in the merge state that the finish stopped on a conflict, for the metrics row.
+// It returns the given error unchanged.
+func recordFinishConflict(err error) error {
+	if _, ok := err.(*errors.UnresolvedConflictsError); !ok {
+		return err
+	}
+	state, loadErr := mergestate.LoadMergeState()
+	if loadErr != nil || state.MetricsFile == "" || state.Conflicted {
+		return err
+	}
+	state.Conflicted = true
+	if saveErr := saveMergeState(state); saveErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to record the conflict for the finish metrics: %v\n", saveErr)
+	}
+	return err
+}
+
+// recordFinishMetrics appends a CSV row with timestamp, type, branch, commits merged, files changed,
+// whether a conflict occurred and the duration in seconds to the metrics file
+func recordFinishMetrics(state *mergestate.MergeState) {
+	if state.MetricsFile == "" {
+		return
+	}
+
+	now := time.Now()
+	duration := time.Duration(0)
+	if started, err := time.Parse(time.RFC3339Nano, state.StartedAt); err == nil {
+		duration = now.Sub(started)
+	}
+
+	var row bytes.Buffer
+	writer := csv.NewWriter(&row)
+	err := writer.Write([]string{
+		now.UTC().Format(time.RFC3339),
+		state.BranchType,
+		state.FullBranchName,
+		strconv.Itoa(state.CommitsMerged),
+		strconv.Itoa(state.FilesChanged),
+		strconv.FormatBool(state.Conflicted),
+		strconv.FormatFloat(duration.Seconds(), 'f', 3, 64),
+	})
+	if err == nil {
+		writer.Flush()
+		err = writer.Error()
+	}
+	if err == nil {
+		err = util.AppendLine(state.MetricsFile, bytes.TrimSuffix(row.Bytes(), []byte("\n")))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to record finish metrics: %v\n", err)
+	}
+}
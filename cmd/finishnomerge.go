@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/mergestate"
+)
+
+// finishWithoutMerge finishes a branch whose upstream strategy is none, such as a long-lived support
+// branch: it is neither merged into its parent nor deleted, only tagged and pushed if requested
+func finishWithoutMerge(branchType string, name string, branchConfig config.BranchConfig, cfg *config.Config, tagOptions *TagOptions, retentionOptions *BranchRetentionOptions, finishOptions *FinishOptions) (*FinishResult, error) {
+	if err := checkMergeBackOptions(branchType, branchConfig, tagOptions, retentionOptions, finishOptions); err != nil {
+		return nil, err
+	}
+	if err := checkLocalOnlyOptions(branchType, retentionOptions, finishOptions); err != nil {
+		return nil, err
+	}
+
+	// Validate that git-flow is initialized
+	initialized, err := config.IsInitialized()
+	if err != nil {
+		return nil, &errors.GitError{Operation: "check if git-flow is initialized", Err: err}
+	}
+	if !initialized {
+		return nil, &errors.NotInitializedError{}
+	}
+
+	resolvedName, err := resolveBranchName(name, branchConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureNotBaseBranch(resolvedName, branchConfig.Parent, cfg); err != nil {
+		return nil, err
+	}
+
+	// The branch is its own target: it is tagged and pushed as it is
+	state := &mergestate.MergeState{
+		Action:         "finish",
+		BranchType:     branchType,
+		BranchName:     strings.TrimPrefix(resolvedName, branchConfig.Prefix),
+		CurrentStep:    stepCreateTag,
+		ParentBranch:   resolvedName,
+		MergeStrategy:  string(config.MergeStrategyNone),
+		FullBranchName: resolvedName,
+	}
+	if err := startFinishMetrics(state, finishOptions); err != nil {
+		return nil, err
+	}
+	if err := startBaseSnapshot(state, finishOptions); err != nil {
+		return nil, err
+	}
+
+	if shouldCreateTag(branchType, branchConfig, tagOptions) {
+		if tagOptions != nil && tagOptions.Since != "" {
+			if _, err := git.ResolveCommit(tagOptions.Since); err != nil {
+				return nil, &errors.InvalidOptionError{Option: "--since", Reason: err.Error()}
+			}
+		}
+		err := createTagForBranch(state, branchConfig, tagOptions, finishOptions)
+		// The tag is recorded in a merge state, but there is nothing to resume without a merge
+		if clearErr := mergestate.ClearMergeState(); clearErr != nil && err == nil {
+			err = &errors.GitError{Operation: "clear merge state", Err: clearErr}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if shouldPushBaseBranches(branchType, finishOptions) {
+		state.TagPushSafe = shouldPushTagSafely(branchType, finishOptions)
+		if err := pushBaseBranches(state); err != nil {
+			emitFinishEvent(state, finishOptions, finishEventPushFailed)
+			return nil, err
+		}
+	}
+
+	return completeFinish(state, []string{}, finishOptions)
+}
+
+// mergesBack reports whether branches of the type are merged into their parent when finished, which
+// is the case unless their upstream strategy is none
+func mergesBack(branchConfig config.BranchConfig) bool {
+	return strings.ToLower(branchConfig.UpstreamStrategy) != string(config.MergeStrategyNone)
+}
+
+// checkMergeBackOptions returns an error if an option that only makes sense when merging back is given
+// for a branch that is not merged back, as that is a mistake rather than something to ignore
+func checkMergeBackOptions(branchType string, branchConfig config.BranchConfig, tagOptions *TagOptions, retentionOptions *BranchRetentionOptions, finishOptions *FinishOptions) error {
+	if mergesBack(branchConfig) {
+		return nil
+	}
+	if option := mergeBackOption(branchType, tagOptions, retentionOptions, finishOptions); option != "" {
+		return &errors.InvalidOptionError{Option: option, Reason: fmt.Sprintf("%s branches are not merged back or deleted because their upstream strategy is 'none'; set gitflow.branch.%s.upstreamstrategy to merge them into '%s'", branchType, branchType, branchConfig.Parent)}
+	}
+	return nil
+}
+
+// mergeBackOption returns the first option given that only applies to a finish that merges the branch
+// back and deletes it, or "" if there is none. The tag goes on the branch itself, so another branch to
+// tag set with --tag-on or gitflow.<type>.finish.tagtarget counts as well.
+func mergeBackOption(branchType string, tagOptions *TagOptions, retentionOptions *BranchRetentionOptions, finishOptions *FinishOptions) string {
+	if tagOptions != nil && tagOptions.TargetBranch != "" {
+		return "--tag-on"
+	}
+	tagTargetKey := fmt.Sprintf("gitflow.%s.finish.tagtarget", branchType)
+	if configValue, err := git.GetConfig(tagTargetKey); err == nil && configValue != "" {
+		return tagTargetKey
+	}
+	if finishOptions != nil {
+		switch {
+		case finishOptions.SourceRef != "":
+			return "--source-ref"
+		case len(finishOptions.AlsoInto) > 0:
+			return "--also-into"
+		case finishOptions.Ours || finishOptions.Theirs:
+			return "--ours/--theirs"
+		case len(finishOptions.StrategyOptions) > 0:
+			return "--strategy-option"
+		case finishOptions.AllowUnrelatedHistories:
+			return "--merge-allow-unrelated-histories"
+		case finishOptions.MergeBaseOverride != "":
+			return "--merge-base-override"
+		case finishOptions.SquashMessage != "" || finishOptions.SquashMessageFile != "":
+			return "--squash-message"
+		case finishOptions.EditMergeMessage != nil && *finishOptions.EditMergeMessage:
+			return "--merge-message-edit"
+		}
+	}
+	if retentionOptions != nil {
+		switch {
+		case retentionOptions.Keep != nil && !*retentionOptions.Keep:
+			return "--no-keep"
+		case retentionOptions.KeepLocal != nil && !*retentionOptions.KeepLocal:
+			return "--no-keeplocal"
+		case retentionOptions.KeepRemote != nil && !*retentionOptions.KeepRemote:
+			return "--no-keepremote"
+		case retentionOptions.ForceRemoteDelete:
+			return "--force-remote-delete"
+		case retentionOptions.ForceDelete != nil && *retentionOptions.ForceDelete:
+			return "--force-delete"
+		}
+	}
+	return ""
+}
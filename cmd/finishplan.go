@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+)
+
+// FinishPlan describes what a finish would do, for tools that preview a finish before running it
+type FinishPlan struct {
+	Type        string             `json:"type"`                  // Branch type, e.g. feature or release
+	Branch      string             `json:"branch"`                // Full name of the branch, or the source ref
+	Target      string             `json:"target"`                // Branch the finish merges into
+	Strategy    string             `json:"strategy"`              // Strategy used to merge into the target
+	RefreshFrom string             `json:"refreshFrom,omitempty"` // Parent the target is updated from first, if any
+	Tag         FinishPlanTag      `json:"tag"`                   // Tag decision
+	Children    []FinishPlanChild  `json:"children"`              // Child base branches updated afterwards, in order
+	Delete      FinishPlanDeletion `json:"delete"`                // Branch deletion decisions
+	Steps       []string           `json:"steps"`                 // Steps the finish runs, in order
+}
+
+// FinishPlanTag describes the tag a finish would create
+type FinishPlanTag struct {
+	Create        bool   `json:"create"`                  // Whether a tag is created
+	Name          string `json:"name,omitempty"`          // Name of the tag
+	Target        string `json:"target,omitempty"`        // Branch the tag goes on, if it is not the target
+	AfterChildren bool   `json:"afterChildren,omitempty"` // Whether the tag waits for the child updates to list their SHAs or to go on one of them
+}
+
+// FinishPlanChild describes the update of one child base branch
+type FinishPlanChild struct {
+	Branch   string `json:"branch"`   // Child base branch
+	Strategy string `json:"strategy"` // Strategy used to update it from the target
+}
+
+// FinishPlanDeletion describes which copies of the finished branch would be deleted
+type FinishPlanDeletion struct {
+	Local  bool `json:"local"`  // Whether the local branch is deleted
+	Remote bool `json:"remote"` // Whether the remote branch is deleted
+}
+
+// buildFinishPlan describes the finish of the branch without changing anything
+func buildFinishPlan(branchType string, name string, branchConfig config.BranchConfig, cfg *config.Config, tagOptions *TagOptions, retentionOptions *BranchRetentionOptions, finishOptions *FinishOptions) (*FinishPlan, error) {
+	state, err := planFinishState(branchType, name, branchConfig, cfg, tagOptions, retentionOptions, finishOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &FinishPlan{
+		Type:     branchType,
+		Branch:   state.FullBranchName,
+		Target:   state.ParentBranch,
+		Strategy: strings.ToLower(state.MergeStrategy),
+		Children: []FinishPlanChild{},
+		Steps:    []string{},
+	}
+
+	// A branch that is not merged back is only tagged and pushed
+	mergeBack := mergesBack(branchConfig)
+	if mergeBack && shouldRefreshParent(branchType, state.ParentBranch, cfg) {
+		plan.RefreshFrom = cfg.Branches[state.ParentBranch].Parent
+		plan.Steps = append(plan.Steps, stepRefreshParent)
+	}
+	if mergeBack {
+		plan.Steps = append(plan.Steps, stepMerge)
+	}
+	if len(state.AlsoInto) > 0 {
+		plan.Steps = append(plan.Steps, stepMergeAlsoInto)
+	}
+
+	plan.Tag.Create = shouldCreateTag(branchType, branchConfig, tagOptions)
+	if plan.Tag.Create {
+		plan.Tag.Name = getTagName(state, branchConfig, tagOptions)
+		tagTarget, err := getTagBranch(state, tagOptions)
+		if err != nil {
+			return nil, err
+		}
+		plan.Tag.Target = tagTarget
+		plan.Tag.AfterChildren = (tagIncludesSHAs(branchType) || slices.Contains(state.ChildBranches, tagTarget)) && len(state.ChildBranches) > 0
+		if !plan.Tag.AfterChildren {
+			plan.Steps = append(plan.Steps, stepCreateTag)
+		}
+	}
+
+	for _, child := range state.ChildBranches {
+		plan.Children = append(plan.Children, FinishPlanChild{Branch: child, Strategy: childUpdateStrategy(cfg.Branches[child])})
+	}
+	if len(plan.Children) > 0 {
+		plan.Steps = append(plan.Steps, stepUpdateChildren)
+	}
+	if plan.Tag.AfterChildren {
+		plan.Steps = append(plan.Steps, stepCreateTag)
+	}
+
+	if mergeBack && !state.IsSourceRef {
+		_, keepRemote, keepLocal, _ := getBranchRetentionSettings(branchType, retentionOptions)
+		plan.Delete.Local = !keepLocal
+		plan.Delete.Remote = !keepRemote && !state.LocalOnly && git.RemoteBranchExists(finishRemote(), state.FullBranchName)
+	}
+	if mergeBack {
+		plan.Steps = append(plan.Steps, stepDeleteBranch)
+	}
+	if shouldPushBaseBranches(branchType, finishOptions) {
+		plan.Steps = append(plan.Steps, stepPush)
+	}
+
+	return plan, nil
+}
+
+// childUpdateStrategy returns the strategy a child base branch is updated with, the way
+// update.UpdateBranchFromParent interprets its downstream strategy
+func childUpdateStrategy(childConfig config.BranchConfig) string {
+	switch strategy := strings.ToLower(childConfig.DownstreamStrategy); strategy {
+	case strategyRebase, strategySquash:
+		return strategy
+	default:
+		return strategyMerge
+	}
+}
+
+// printFinishPlanJSON prints the finish plan as indented JSON
+func printFinishPlanJSON(branchType string, name string, branchConfig config.BranchConfig, cfg *config.Config, tagOptions *TagOptions, retentionOptions *BranchRetentionOptions, finishOptions *FinishOptions) error {
+	plan, err := buildFinishPlan(branchType, name, branchConfig, cfg, tagOptions, retentionOptions, finishOptions)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return &errors.GitError{Operation: "encode finish plan", Err: err}
+	}
+	fmt.Println(string(data))
+	return nil
+}
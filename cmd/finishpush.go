@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/mergestate"
+)
+
+// finishRemote returns the remote the finish deletes, fetches and pushes branches on
+func finishRemote() string {
+	if cfg, err := config.LoadConfig(); err == nil && cfg.Remote != "" {
+		return cfg.Remote
+	}
+	return "origin"
+}
+
+// shouldPushBaseBranches determines whether the finish pushes the base branches it changed.
+// Pushing is off by default, so a finish only contacts the remote when asked to.
+func shouldPushBaseBranches(branchType string, finishOptions *FinishOptions) bool {
+	// A local-only finish never pushes
+	if shouldFinishLocally(branchType, finishOptions) {
+		return false
+	}
+
+	// 1. Check branch-specific config
+	push := false
+	if configValue, err := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.push", branchType)); err == nil && configValue == "true" {
+		push = true
+	}
+
+	// 2. Command-line flags override config
+	if finishOptions != nil && finishOptions.Push != nil {
+		push = *finishOptions.Push
+	}
+
+	return push
+}
+
+// shouldFinishLocally determines whether the finish must not contact the remote at all, via --local-only or
+// gitflow.<type>.finish.localonly. The remote branch is then kept and nothing is pushed, whatever
+// gitflow.<type>.finish.keepremote and gitflow.<type>.finish.push say.
+func shouldFinishLocally(branchType string, finishOptions *FinishOptions) bool {
+	// 1. Check branch-specific config
+	localOnly := false
+	if configValue, err := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.localonly", branchType)); err == nil && configValue == "true" {
+		localOnly = true
+	}
+
+	// 2. Command-line flags override config
+	if finishOptions != nil && finishOptions.LocalOnly != nil {
+		localOnly = *finishOptions.LocalOnly
+	}
+
+	return localOnly
+}
+
+// checkLocalOnlyOptions returns an error if a local-only finish is also asked on the command line to do
+// something that contacts the remote, as the two contradict each other
+func checkLocalOnlyOptions(branchType string, retentionOptions *BranchRetentionOptions, finishOptions *FinishOptions) error {
+	if !shouldFinishLocally(branchType, finishOptions) {
+		return nil
+	}
+
+	option := ""
+	switch {
+	case finishOptions != nil && finishOptions.Push != nil && *finishOptions.Push:
+		option = "--push"
+	case finishOptions != nil && finishOptions.TargetRemoteTrackingUpdate:
+		option = "--target-remote-tracking-update"
+	case retentionOptions != nil && retentionOptions.KeepRemote != nil && !*retentionOptions.KeepRemote:
+		option = "--no-keepremote"
+	case retentionOptions != nil && retentionOptions.ForceRemoteDelete:
+		option = "--force-remote-delete"
+	case retentionOptions != nil && retentionOptions.VerifyRemote != nil && *retentionOptions.VerifyRemote:
+		option = "--pre-delete-merge-verify-remote"
+	}
+	if option != "" {
+		return &errors.InvalidOptionError{Option: option, Reason: fmt.Sprintf("contacts the remote, which a local-only finish does not; pass --no-local-only or unset gitflow.%s.finish.localonly", branchType)}
+	}
+	return nil
+}
+
+// shouldPushTagSafely determines whether a tag replaced with --force-tag is force-pushed, guarded by
+// the value the remote had before. Without it, the push of a replaced tag is rejected by the remote.
+func shouldPushTagSafely(branchType string, finishOptions *FinishOptions) bool {
+	// 1. Check branch-specific config
+	safe := false
+	if configValue, err := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.tagpushsafe", branchType)); err == nil && configValue == "true" {
+		safe = true
+	}
+
+	// 2. Command-line flags override config
+	if finishOptions != nil && finishOptions.TagPushSafe != nil {
+		safe = *finishOptions.TagPushSafe
+	}
+
+	return safe
+}
+
+// pushBaseBranches pushes the target, the --also-into branches, the child base branches that received changes
+// and the tag of the finish to the remote, one ref at a time. A tag replaced with --force-tag is only forced if
+// safe tag pushes are enabled and the remote still has the replaced tag. During the push step, each pushed ref
+// is recorded in the merge state, so that retrying a failed push skips the refs the remote already has.
+func pushBaseBranches(state *mergestate.MergeState) error {
+	remote := finishRemote()
+
+	refs := append([]string{state.ParentBranch}, state.MergedInto...)
+	for _, branch := range state.UpdatedBranches {
+		if !slices.Contains(state.UnchangedBranches, branch) && !slices.Contains(refs, branch) {
+			refs = append(refs, branch)
+		}
+	}
+	tagRef := ""
+	if state.TagName != "" && git.TagExists(state.TagName) {
+		tagRef = "refs/tags/" + state.TagName
+		refs = append(refs, tagRef)
+	}
+
+	for _, ref := range refs {
+		if slices.Contains(state.PushedRefs, ref) && remoteRefUpToDate(remote, ref) {
+			fmt.Printf("Already pushed '%s' to '%s'\n", ref, remote)
+			continue
+		}
+
+		lease := ""
+		if ref == tagRef && state.ReplacedTag != "" && state.TagPushSafe {
+			published, err := ensureRemoteTagUnchanged(remote, state.TagName, state.ReplacedTag)
+			if err != nil {
+				return err
+			}
+			if published {
+				lease = fmt.Sprintf("%s:%s", tagRef, state.ReplacedTag)
+			}
+		}
+		if err := git.PushRefsWithLease(remote, lease, ref); err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("push '%s' to '%s'", ref, remote), Err: err}
+		}
+		fmt.Printf("Pushed '%s' to '%s'\n", ref, remote)
+
+		if !slices.Contains(state.PushedRefs, ref) {
+			state.PushedRefs = append(state.PushedRefs, ref)
+		}
+		if state.CurrentStep == stepPush {
+			if err := saveMergeState(state); err != nil {
+				return &errors.GitError{Operation: "save merge state", Err: err}
+			}
+		}
+	}
+	return nil
+}
+
+// remoteRefUpToDate reports whether the remote has a pushed branch or tag at the same object as the local one
+func remoteRefUpToDate(remote string, ref string) bool {
+	fullRef := ref
+	if !strings.HasPrefix(ref, "refs/") {
+		fullRef = "refs/heads/" + ref
+	}
+	local, err := git.ResolveObject(fullRef)
+	if err != nil {
+		return false
+	}
+	current, err := git.RemoteRefObject(remote, fullRef)
+	return err == nil && current == local
+}
+
+// ensureRemoteTagUnchanged returns whether the remote has the tag, and an error if it has it at another
+// object than the one it was replaced from, which means someone else moved it since. Tags have no upstream
+// to compare against, so the remote is asked directly; the push itself is leased on the same value to
+// close the race.
+func ensureRemoteTagUnchanged(remote string, tagName string, expected string) (bool, error) {
+	current, err := git.RemoteTagObject(remote, tagName)
+	if err != nil {
+		return false, &errors.GitError{Operation: fmt.Sprintf("check tag '%s' on '%s'", tagName, remote), Err: err}
+	}
+	if current != "" && current != expected {
+		return true, &errors.TagMovedError{TagName: tagName, Remote: remote, Expected: expected, Actual: current}
+	}
+	return current != "", nil
+}
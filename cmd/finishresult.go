@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/mergestate"
+)
+
+// FinishResult describes the outcome of a completed finish, for programs that use git-flow as a library
+type FinishResult struct {
+	Type            string   `json:"type"`             // Branch type, e.g. feature or release
+	Branch          string   `json:"branch"`           // Full name of the finished branch, or the source ref
+	Target          string   `json:"target"`           // Branch the finished branch was merged into
+	Strategy        string   `json:"strategy"`         // Strategy used to merge into the target
+	Tag             string   `json:"tag,omitempty"`    // Name of the created or reused tag, if any
+	TagSHA          string   `json:"tagSHA,omitempty"` // SHA of the tag object, or of the tagged commit for a lightweight tag
+	UpdatedChildren []string `json:"updatedChildren"`  // Child base branches that received changes, in order
+	DeletedBranches []string `json:"deletedBranches"`  // Deleted copies of the branch, remote ones prefixed with the remote name
+}
+
+// ExecuteFinish runs a finish like the finish command but returns its outcome instead of printing
+// errors and exiting. The result is nil when the finish did not complete, e.g. because it only
+// described its steps, was cancelled, or stopped on a conflict.
+func ExecuteFinish(branchType string, name string, continueOp bool, abortOp bool, force bool, tagOptions *TagOptions, retentionOptions *BranchRetentionOptions, finishOptions *FinishOptions) (*FinishResult, error) {
+	result, err := executeFinish(branchType, name, continueOp, abortOp, force, tagOptions, retentionOptions, finishOptions)
+	emitFinishConflictEvent(err, finishOptions)
+	if err := recordFinishConflict(err); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// newFinishResult describes the outcome of the completed finish
+func newFinishResult(state *mergestate.MergeState, deleted []string) *FinishResult {
+	result := &FinishResult{
+		Type:            state.BranchType,
+		Branch:          state.FullBranchName,
+		Target:          state.ParentBranch,
+		Strategy:        strings.ToLower(state.MergeStrategy),
+		Tag:             state.TagName,
+		UpdatedChildren: []string{},
+		DeletedBranches: deleted,
+	}
+	if state.TagName != "" {
+		if sha, err := git.ResolveObject("refs/tags/" + state.TagName); err == nil {
+			result.TagSHA = sha
+		}
+	}
+	for _, branch := range state.UpdatedBranches {
+		if !slices.Contains(state.UnchangedBranches, branch) {
+			result.UpdatedChildren = append(result.UpdatedChildren, branch)
+		}
+	}
+	return result
+}
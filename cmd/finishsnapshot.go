@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/mergestate"
+	"github.com/gittower/git-flow-next/internal/util"
+)
+
+// baseSnapshotFile is the log of base branch snapshots in the git directory, one JSON record per line
+const baseSnapshotFile = "gitflow/snapshots.log"
+
+// BaseSnapshot records how a finish moved the base branches, so that its effect can be audited and undone
+type BaseSnapshot struct {
+	Time     string                        `json:"time"`          // Time the finish completed (RFC 3339)
+	Type     string                        `json:"type"`          // Branch type, e.g. feature or release
+	Branch   string                        `json:"branch"`        // Full name of the finished branch, or the source ref
+	Tag      string                        `json:"tag,omitempty"` // Name of the created or reused tag, if any
+	Branches map[string]BaseBranchSnapshot `json:"branches"`      // Commits of each base branch the finish could change
+}
+
+// BaseBranchSnapshot holds the commits a base branch pointed to before and after a finish
+type BaseBranchSnapshot struct {
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// shouldRecordBaseSnapshot determines whether the base branches are recorded before and after the finish
+func shouldRecordBaseSnapshot(finishOptions *FinishOptions) bool {
+	// 1. Check config
+	record := false
+	if configValue, err := git.GetConfig("gitflow.finish.recordsnapshot"); err == nil && configValue == "true" {
+		record = true
+	}
+
+	// 2. Command-line flags override config
+	if finishOptions != nil && finishOptions.RecordSnapshot != nil {
+		record = *finishOptions.RecordSnapshot
+	}
+
+	return record
+}
+
+// startBaseSnapshot records the commits of the target, the additional targets and the child base branches
+// in the merge state before any of them is changed, so that a finish resumed with --continue is recorded
+// from where it started
+func startBaseSnapshot(state *mergestate.MergeState, finishOptions *FinishOptions) error {
+	if !shouldRecordBaseSnapshot(finishOptions) {
+		return nil
+	}
+
+	state.SnapshotBefore = map[string]string{}
+	branches := append([]string{state.ParentBranch}, state.AlsoInto...)
+	for _, branch := range append(branches, state.ChildBranches...) {
+		commit, err := git.ResolveCommit(branch)
+		if err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("resolve base branch '%s'", branch), Err: err}
+		}
+		state.SnapshotBefore[branch] = commit
+	}
+	return nil
+}
+
+// recordBaseSnapshot appends the commits of the base branches before and after the finish to the snapshot log
+func recordBaseSnapshot(state *mergestate.MergeState) {
+	if len(state.SnapshotBefore) == 0 {
+		return
+	}
+
+	snapshot := BaseSnapshot{
+		Time:     time.Now().UTC().Format(time.RFC3339),
+		Type:     state.BranchType,
+		Branch:   state.FullBranchName,
+		Tag:      state.TagName,
+		Branches: map[string]BaseBranchSnapshot{},
+	}
+	for branch, before := range state.SnapshotBefore {
+		after, err := git.ResolveCommit(branch)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to record the base snapshot: %v\n", err)
+			return
+		}
+		snapshot.Branches[branch] = BaseBranchSnapshot{Before: before, After: after}
+	}
+
+	record, err := json.Marshal(snapshot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to record the base snapshot: %v\n", err)
+		return
+	}
+	path, err := git.GitPath(baseSnapshotFile)
+	if err == nil {
+		err = util.AppendLine(path, record)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to record the base snapshot: %v\n", err)
+	}
+}
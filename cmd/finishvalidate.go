@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/mergestate"
+)
+
+// finishCheck is one preflight check run by --validate-only
+type finishCheck struct {
+	label string
+	err   error
+}
+
+// validateFinishOnly runs the checks a finish of the branch would run, prints whether each of them
+// passed and returns the first failure. Nothing is changed, not even the target's remote-tracking branch.
+func validateFinishOnly(branchType string, name string, branchConfig config.BranchConfig, cfg *config.Config, tagOptions *TagOptions, retentionOptions *BranchRetentionOptions, finishOptions *FinishOptions) error {
+	var checks []finishCheck
+	check := func(label string, err error) bool {
+		checks = append(checks, finishCheck{label: label, err: err})
+		return err == nil
+	}
+
+	check("git-flow is initialized", checkInitialized())
+	check("no operation in progress", checkNoOperationInProgress())
+
+	// Resolve the branch the finish integrates; routing still works on the prefixed name if it is missing
+	branchName := name
+	routedName := branchConfig.Prefix + strings.TrimPrefix(name, branchConfig.Prefix)
+	branchFound := false
+	if finishOptions != nil && finishOptions.SourceRef != "" {
+		_, err := git.ResolveCommit(finishOptions.SourceRef)
+		if err != nil {
+			err = &errors.GitError{Operation: fmt.Sprintf("resolve source ref '%s'", finishOptions.SourceRef), Err: err}
+		}
+		branchName = finishOptions.SourceRef
+		branchFound = check(fmt.Sprintf("source ref '%s' exists", branchName), err)
+	} else {
+		resolvedName, err := resolveBranchName(name, branchConfig)
+		if err == nil {
+			branchName = resolvedName
+			routedName = resolvedName
+		}
+		branchFound = check(fmt.Sprintf("branch '%s' exists", branchName), err)
+	}
+
+	// A branch that is not merged back has no target, only its options and base are checked
+	if !mergesBack(branchConfig) {
+		check("options apply to a branch that is not merged back", checkMergeBackOptions(branchType, branchConfig, tagOptions, retentionOptions, finishOptions))
+		check("branch is not a base branch", ensureNotBaseBranch(branchName, branchConfig.Parent, cfg))
+	} else {
+		checkFinishTarget(check, branchType, branchName, routedName, branchFound, branchConfig, cfg, finishOptions)
+	}
+
+	check("merge strategy is valid", checkMergeStrategy(branchType, branchConfig.UpstreamStrategy))
+	check("remote options are valid", checkLocalOnlyOptions(branchType, retentionOptions, finishOptions))
+	check("finish options are valid", checkFinishOptions(branchName, branchFound, branchConfig, tagOptions, finishOptions))
+
+	var firstErr error
+	fmt.Printf("Validating finish of '%s':\n", branchName)
+	for _, c := range checks {
+		if c.err == nil {
+			fmt.Printf("  %s  %s\n", colorize(colorGreen, "ok  "), c.label)
+			continue
+		}
+		fmt.Printf("  %s  %s: %v\n", colorize(colorRed, "FAIL"), c.label, c.err)
+		if firstErr == nil {
+			firstErr = c.err
+		}
+	}
+	if firstErr != nil {
+		return firstErr
+	}
+	fmt.Println("All checks passed")
+	return nil
+}
+
+// checkFinishTarget runs the checks of the target the branch is merged into
+func checkFinishTarget(check func(string, error) bool, branchType string, branchName string, routedName string, branchFound bool, branchConfig config.BranchConfig, cfg *config.Config, finishOptions *FinishOptions) {
+	targetBranch, err := getFinishTarget(branchType, routedName, branchConfig)
+	if !check("finish target is configured", err) {
+		return
+	}
+	targetExists := git.BranchExists(targetBranch) == nil
+	if !targetExists && shouldUseTargetTrackingBranch(branchType, finishOptions) {
+		// The finish would create the target from its remote-tracking branch
+		targetExists = git.RemoteBranchExists(cfg.Remote, targetBranch)
+	}
+	err = nil
+	if !targetExists {
+		err = &errors.BranchNotFoundError{BranchName: targetBranch}
+	}
+	check(fmt.Sprintf("target branch '%s' exists", targetBranch), err)
+	check("branch is not a base branch", ensureNotBaseBranch(branchName, targetBranch, cfg))
+	check("base branch parents have no cycle", checkParentCycle(targetBranch, cfg))
+	if branchFound && targetExists {
+		check("commit count is within limits", checkCommitCount(branchType, branchName, targetBranch, finishOptions))
+	}
+}
+
+// checkInitialized returns an error if git-flow is not initialized
+func checkInitialized() error {
+	initialized, err := config.IsInitialized()
+	if err != nil {
+		return &errors.GitError{Operation: "check if git-flow is initialized", Err: err}
+	}
+	if !initialized {
+		return &errors.NotInitializedError{}
+	}
+	return nil
+}
+
+// checkNoOperationInProgress returns an error if a git-flow finish or a git rebase or cherry-pick is in progress
+func checkNoOperationInProgress() error {
+	if mergestate.IsMergeInProgress() {
+		state, err := mergestate.LoadMergeState()
+		if err != nil {
+			return &errors.GitError{Operation: "load merge state", Err: err}
+		}
+		return &errors.MergeInProgressError{BranchName: state.FullBranchName}
+	}
+	return ensureNoGitOperationInProgress("finish")
+}
+
+// checkParentCycle returns an error if following the parents of the branch leads back to a branch already visited
+func checkParentCycle(branch string, cfg *config.Config) error {
+	cycle := findParentCycle(branch, cfg)
+	if len(cycle) == 0 {
+		return nil
+	}
+	return &errors.InvalidOptionError{Option: fmt.Sprintf("gitflow.branch.%s.parent", cycle[0]), Reason: fmt.Sprintf("parent chain forms a cycle: %s", strings.Join(cycle, " -> "))}
+}
+
+// checkMergeStrategy returns an error if the upstream strategy is not one the finish can merge with,
+// or none for a branch that is not merged back
+func checkMergeStrategy(branchType string, strategy string) error {
+	switch strings.ToLower(strategy) {
+	case strategyMerge, strategyRebase, strategySquash, string(config.MergeStrategyNone):
+		return nil
+	}
+	return &errors.InvalidOptionError{Option: fmt.Sprintf("gitflow.branch.%s.upstreamStrategy", branchType), Reason: fmt.Sprintf("unknown strategy '%s', expected merge, rebase, squash or none", strategy)}
+}
+
+// checkFinishOptions validates the finish options the same way the finish does before merging
+func checkFinishOptions(branchName string, branchFound bool, branchConfig config.BranchConfig, tagOptions *TagOptions, finishOptions *FinishOptions) error {
+	if err := validateFinishOptions(branchConfig, finishOptions); err != nil {
+		return err
+	}
+	if finishOptions != nil && finishOptions.MergeBaseOverride != "" && branchFound {
+		if err := validateMergeBaseOverride(finishOptions.MergeBaseOverride, branchName); err != nil {
+			return err
+		}
+	}
+	if tagOptions != nil && tagOptions.Since != "" {
+		if _, err := git.ResolveCommit(tagOptions.Since); err != nil {
+			return &errors.InvalidOptionError{Option: "--since", Reason: err.Error()}
+		}
+	}
+	if tagOptions != nil && tagOptions.Object != "" {
+		if _, err := git.ResolveObject(tagOptions.Object); err != nil {
+			return &errors.InvalidOptionError{Option: "--tag-object", Reason: err.Error()}
+		}
+	}
+	return nil
+}
@@ -113,10 +113,21 @@ func initFlow(useDefaults, createBranches bool, mainBranch, developBranch, featu
 		}
 	}
 
+	warnPrefixConflicts(cfg)
+
 	fmt.Println("Git flow has been initialized")
 	return nil
 }
 
+// warnPrefixConflicts warns about topic branch types whose prefixes overlap, since commands
+// that derive the type from a branch name cannot tell such types apart
+func warnPrefixConflicts(cfg *config.Config) {
+	for _, conflict := range config.FindPrefixConflicts(cfg) {
+		fmt.Fprintf(os.Stderr, "Warning: prefixes of branch types '%s' ('%s') and '%s' ('%s') overlap; branches of these types cannot be told apart\n",
+			conflict.TypeA, conflict.PrefixA, conflict.TypeB, conflict.PrefixB)
+	}
+}
+
 // createGitFlowBranches creates the base branches if they don't exist
 func createGitFlowBranches(cfg *config.Config) error {
 	// Find base branches
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/mergestate"
+)
+
+// interruptState is a copy of the finish or update in progress that an interrupt has to leave resumable.
+// The operation keeps changing its own state, so the handler only ever sees the copy registered last.
+var (
+	interruptMu    sync.Mutex
+	interruptState *mergestate.MergeState
+)
+
+// watchInterrupts saves the finish or update in progress and explains how to resume it when
+// the process is interrupted. The returned function stops watching.
+func watchInterrupts() func() {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case <-signals:
+			handleInterrupt()
+		case <-done:
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+		clearInterruptState()
+	}
+}
+
+// saveMergeState saves the state and registers a copy of it as the operation in progress. Both happen
+// under the lock handleInterrupt takes, so that an interrupt never writes an older state over the new one.
+func saveMergeState(state *mergestate.MergeState) error {
+	clone, err := state.Clone()
+	if err != nil {
+		return err
+	}
+	interruptMu.Lock()
+	defer interruptMu.Unlock()
+	if err := mergestate.SaveMergeState(clone); err != nil {
+		return err
+	}
+	interruptState = clone
+	return nil
+}
+
+// setInterruptState registers a copy of the state as the operation in progress
+func setInterruptState(state *mergestate.MergeState) error {
+	clone, err := state.Clone()
+	if err != nil {
+		return err
+	}
+	interruptMu.Lock()
+	defer interruptMu.Unlock()
+	interruptState = clone
+	return nil
+}
+
+// clearInterruptState unregisters the operation in progress once there is nothing left to resume
+func clearInterruptState() {
+	interruptMu.Lock()
+	defer interruptMu.Unlock()
+	interruptState = nil
+}
+
+// handleInterrupt saves the registered state, reports where the operation stopped and exits
+func handleInterrupt() {
+	interruptMu.Lock()
+	defer interruptMu.Unlock()
+
+	if interruptState == nil {
+		fmt.Fprintln(os.Stderr, "\nInterrupted")
+		os.Exit(int(errors.ExitCodeInterrupted))
+	}
+
+	// An update is only resumable while git has the merge or rebase stopped halfway
+	if interruptState.Action == "update" && !gitOperationStopped() {
+		fmt.Fprintf(os.Stderr, "\nInterrupted while updating '%s' from '%s'; run the update again to complete it\n", interruptState.FullBranchName, interruptState.ParentBranch)
+		os.Exit(int(errors.ExitCodeInterrupted))
+	}
+
+	if err := mergestate.SaveMergeState(interruptState); err != nil {
+		fmt.Fprintf(os.Stderr, "\nInterrupted, but the %s state could not be saved: %v\n", interruptState.Action, err)
+		os.Exit(int(errors.ExitCodeInterrupted))
+	}
+	fmt.Fprint(os.Stderr, formatInterruptReport(interruptState))
+	os.Exit(int(errors.ExitCodeInterrupted))
+}
+
+// gitOperationStopped reports whether git has a merge, rebase or cherry-pick stopped halfway
+func gitOperationStopped() bool {
+	if git.HasMergeHead() {
+		return true
+	}
+	operation, err := git.OperationInProgress()
+	return err != nil || operation != ""
+}
+
+// formatInterruptReport describes where a finish or update stopped and how to continue or abort it
+func formatInterruptReport(state *mergestate.MergeState) string {
+	var report strings.Builder
+	if state.Action == "update" {
+		continueCommand, abortCommand := resumeCommands(state)
+		fmt.Fprintf(&report, "\nInterrupted while updating '%s' from '%s'\n", state.FullBranchName, state.ParentBranch)
+		fmt.Fprintf(&report, "Run '%s' to complete the update or '%s' to abort it\n", continueCommand, abortCommand)
+		return report.String()
+	}
+
+	fmt.Fprintf(&report, "\nInterrupted during step '%s' of finishing '%s'\n", state.CurrentStep, state.FullBranchName)
+
+	if state.CurrentStep == stepUpdateChildren && len(state.ChildBranches) > 0 {
+		remaining := []string{}
+		for _, branch := range state.ChildBranches {
+			if !slices.Contains(state.UpdatedBranches, branch) {
+				remaining = append(remaining, branch)
+			}
+		}
+		fmt.Fprintf(&report, "Updated child base branches: %s\n", joinOrNone(state.UpdatedBranches))
+		fmt.Fprintf(&report, "Remaining child base branches: %s\n", joinOrNone(remaining))
+	}
+
+	if git.HasMergeHead() {
+		report.WriteString("A merge was still in progress; commit it or run 'git merge --abort' before continuing\n")
+	}
+
+	fmt.Fprintf(&report, "Run 'git flow %s finish --continue %s' to resume or 'git flow %s finish --abort %s' to abort\n",
+		state.BranchType, state.BranchName, state.BranchType, state.BranchName)
+	return report.String()
+}
+
+// joinOrNone joins branch names for display
+func joinOrNone(branches []string) string {
+	if len(branches) == 0 {
+		return "none"
+	}
+	return strings.Join(branches, ", ")
+}
@@ -3,16 +3,35 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/gittower/git-flow-next/internal/config"
 	"github.com/gittower/git-flow-next/internal/errors"
 	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/spf13/cobra"
 )
 
-// ListCommand is the implementation of the list command for topic branches
-func ListCommand(branchType string) {
-	if err := list(branchType); err != nil {
+// listAllCmd represents the list command for the topic branches of all types
+var listAllCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the topic branches of all types",
+	Long: `List the local topic branches of every configured branch type, grouped by type.
+The current branch is marked with an asterisk and each branch shows whether it is merged into its parent.
+With --verbose, the base branch, the upstream strategy and the commits ahead of and behind the parent are shown.`,
+	Example: `  git flow list
+  git flow list --verbose`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		ListCommand("", verbose)
+	},
+}
+
+// ListCommand is the implementation of the list command for topic branches.
+// An empty branch type lists the branches of all topic branch types.
+func ListCommand(branchType string, verbose bool) {
+	if err := list(branchType, verbose); err != nil {
 		var exitCode errors.ExitCode
 		if flowErr, ok := err.(errors.Error); ok {
 			exitCode = flowErr.ExitCode()
@@ -25,7 +44,7 @@ func ListCommand(branchType string) {
 }
 
 // list performs the actual branch listing logic and returns any errors
-func list(branchType string) error {
+func list(branchType string, verbose bool) error {
 	// Validate that git-flow is initialized
 	initialized, err := config.IsInitialized()
 	if err != nil {
@@ -41,47 +60,122 @@ func list(branchType string) error {
 		return &errors.GitError{Operation: "load configuration", Err: err}
 	}
 
-	// Get branch configuration
-	branchConfig, ok := cfg.Branches[branchType]
-	if !ok {
-		return &errors.InvalidBranchTypeError{BranchType: branchType}
+	// Get the branch types to list
+	var branchTypes []string
+	if branchType != "" {
+		if _, ok := cfg.Branches[branchType]; !ok {
+			return &errors.InvalidBranchTypeError{BranchType: branchType}
+		}
+		branchTypes = []string{branchType}
+	} else {
+		for name, branchConfig := range cfg.Branches {
+			if branchConfig.Type == string(config.BranchTypeTopic) {
+				branchTypes = append(branchTypes, name)
+			}
+		}
+		sort.Strings(branchTypes)
 	}
 
-	// Get the prefix for this branch type
-	prefix := branchConfig.Prefix
-
-	// Get all branches
-	branches, err := git.ListBranches()
+	// The current branch is marked; a detached HEAD reads as "HEAD" and marks no branch
+	currentBranch, err := git.GetCurrentBranch()
 	if err != nil {
-		return &errors.GitError{Operation: "list branches", Err: err}
+		return &errors.GitError{Operation: "get current branch", Err: err}
 	}
 
-	// Filter branches by prefix
-	var topicBranches []string
-	for _, branch := range branches {
-		if strings.HasPrefix(branch, prefix) {
-			// Remove the prefix to get the branch name
-			name := strings.TrimPrefix(branch, prefix)
-			topicBranches = append(topicBranches, name)
+	listed := 0
+	for _, name := range branchTypes {
+		branchConfig := cfg.Branches[name]
+
+		// Get the branches with the prefix of this branch type
+		branches, err := git.ListBranchesWithPrefix(branchConfig.Prefix)
+		if err != nil {
+			return &errors.GitError{Operation: "list branches", Err: err}
+		}
+		if len(branches) == 0 {
+			continue
+		}
+
+		// Separate the branch types when listing all of them
+		if listed > 0 {
+			fmt.Println()
+		}
+		listed++
+
+		// Capitalize the first letter of the branch type
+		branchTypeCapitalized := name
+		if len(name) > 0 {
+			branchTypeCapitalized = strings.ToUpper(name[:1]) + name[1:]
+		}
+
+		fmt.Printf("%s branches:\n", branchTypeCapitalized)
+		for _, branch := range branches {
+			marker := "  "
+			displayName := strings.TrimPrefix(branch, branchConfig.Prefix)
+			if branch == currentBranch {
+				marker = "* "
+				displayName = colorize(colorGreen, displayName)
+			}
+			if status := branchMergeStatus(branch, branchConfig.Parent); status != "" {
+				fmt.Printf("%s%s (%s)\n", marker, displayName, status)
+			} else {
+				fmt.Printf("%s%s\n", marker, displayName)
+			}
+			if verbose {
+				printBranchDetails(branch, branchConfig)
+			}
 		}
 	}
 
 	// Print the branches
-	if len(topicBranches) == 0 {
-		fmt.Printf("No %s branches found\n", branchType)
-		return nil
+	if listed == 0 {
+		if branchType != "" {
+			fmt.Printf("No %s branches found\n", branchType)
+		} else {
+			fmt.Println("No topic branches found")
+		}
+	}
+
+	return nil
+}
+
+// branchMergeStatus returns "merged" if the branch is contained in its parent and "unmerged" otherwise.
+// It returns an empty string if the parent can't be compared with, e.g. because it doesn't exist.
+func branchMergeStatus(branch string, parent string) string {
+	if parent == "" || git.BranchExists(parent) != nil {
+		return ""
+	}
+	merged, err := git.IsAncestor(branch, parent)
+	if err != nil {
+		return ""
+	}
+	if merged {
+		return "merged"
 	}
+	return "unmerged"
+}
 
-	// Capitalize the first letter of the branch type
-	branchTypeCapitalized := branchType
-	if len(branchType) > 0 {
-		branchTypeCapitalized = strings.ToUpper(branchType[:1]) + branchType[1:]
+// printBranchDetails prints the base branch the branch was started from, the upstream strategy of its type
+// and how many commits it is ahead of and behind its parent
+func printBranchDetails(branch string, branchConfig config.BranchConfig) {
+	base, err := git.GetConfig(fmt.Sprintf("gitflow.branch.%s.base", branch))
+	if err != nil || base == "" {
+		base = branchConfig.StartPoint
+	}
+	if base == "" {
+		base = branchConfig.Parent
 	}
+	details := fmt.Sprintf("base: %s, upstream strategy: %s", base, branchConfig.UpstreamStrategy)
 
-	fmt.Printf("%s branches:\n", branchTypeCapitalized)
-	for _, branch := range topicBranches {
-		fmt.Printf("  %s\n", branch)
+	if branchConfig.Parent != "" && git.BranchExists(branchConfig.Parent) == nil {
+		ahead, aheadErr := git.CountCommits(branchConfig.Parent, branch)
+		behind, behindErr := git.CountCommits(branch, branchConfig.Parent)
+		if aheadErr == nil && behindErr == nil {
+			details += fmt.Sprintf(", %d ahead of and %d behind '%s'", ahead, behind, branchConfig.Parent)
+		}
 	}
+	fmt.Printf("    %s\n", details)
+}
 
-	return nil
+func init() {
+	rootCmd.AddCommand(listAllCmd)
 }
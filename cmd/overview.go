@@ -161,14 +161,16 @@ func overview() error {
 	if len(topicBranches) > 0 {
 		for _, branchName := range topicBranches {
 			prefix := ""
+			displayName := branchName
 			if branchName == currentBranch {
 				prefix = "* "
+				displayName = colorize(colorGreen, branchName)
 			} else {
 				prefix = "  "
 			}
 
 			branchType := branchTypeMap[branchName]
-			fmt.Printf("%s%s (%s)\n", prefix, branchName, branchType)
+			fmt.Printf("%s%s (%s)\n", prefix, displayName, branchType)
 		}
 	} else {
 		fmt.Println("  No active topic branches")
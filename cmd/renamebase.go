@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// renameBaseCmd represents the rename-base command
+var renameBaseCmd = &cobra.Command{
+	Use:     "rename-base <old> <new>",
+	Aliases: []string{"upgrade-branches"},
+	Short:   "Rename a base branch and update all references to it",
+	Long: `Rename a base branch (e.g. master to main or develop to dev) and update the git-flow configuration.
+Every branch type and base branch that uses the renamed branch as parent or start point is updated,
+as well as the base recorded for existing topic branches.`,
+	Example: `  git flow rename-base master main
+  git flow rename-base develop dev --remote`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		remote, _ := cmd.Flags().GetBool("remote")
+		RenameBaseCommand(args[0], args[1], remote)
+	},
+}
+
+// RenameBaseCommand is the implementation of the rename-base command
+func RenameBaseCommand(oldName string, newName string, remote bool) {
+	if err := renameBase(oldName, newName, remote); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(int(exitCode))
+	}
+}
+
+// renameBase renames the base branch, updates all configuration referencing it and returns any errors
+func renameBase(oldName string, newName string, remote bool) error {
+	// Validate that git-flow is initialized
+	initialized, err := config.IsInitialized()
+	if err != nil {
+		return &errors.GitError{Operation: "check if git-flow is initialized", Err: err}
+	}
+	if !initialized {
+		return &errors.NotInitializedError{}
+	}
+
+	// Get configuration
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return &errors.GitError{Operation: "load configuration", Err: err}
+	}
+
+	baseConfig, ok := cfg.Branches[oldName]
+	if !ok {
+		return &errors.InvalidBranchTypeError{BranchType: oldName}
+	}
+	if baseConfig.Type != string(config.BranchTypeBase) {
+		return &errors.InvalidOptionError{Option: oldName, Reason: "not a base branch"}
+	}
+	if newName == "" {
+		return &errors.EmptyBranchNameError{}
+	}
+	if _, exists := cfg.Branches[newName]; exists {
+		return &errors.InvalidOptionError{Option: newName, Reason: "a branch type or base branch with this name is already configured"}
+	}
+	if err := git.BranchExists(oldName); err != nil {
+		return &errors.BranchNotFoundError{BranchName: oldName}
+	}
+	if err := git.BranchExists(newName); err == nil {
+		return &errors.BranchExistsError{BranchName: newName}
+	}
+
+	// Rename in a copy of the configuration first so orphaned branches are found before anything changes
+	oldBranches := make(map[string]config.BranchConfig, len(cfg.Branches))
+	for name, branchConfig := range cfg.Branches {
+		oldBranches[name] = branchConfig
+	}
+	cfg = config.RenameBaseBranch(cfg, oldName, newName)
+	if orphaned := config.FindOrphanedBranches(cfg); len(orphaned) > 0 {
+		return &errors.InvalidOptionError{Option: oldName, Reason: fmt.Sprintf("renaming would leave branches without a configured parent: %s", strings.Join(orphaned, ", "))}
+	}
+
+	// Rename the branch itself
+	if err := git.RenameBranch(newName, oldName); err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("rename branch '%s' to '%s'", oldName, newName), Err: err}
+	}
+	fmt.Printf("Renamed branch '%s' to '%s'\n", oldName, newName)
+
+	// Move the configuration of the base branch, then update the references to it
+	if err := git.RenameConfigSection("gitflow.branch."+oldName, "gitflow.branch."+newName); err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("rename configuration of '%s'", oldName), Err: err}
+	}
+	var names []string
+	for name := range cfg.Branches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		branchConfig := cfg.Branches[name]
+		previous, ok := oldBranches[name]
+		if !ok {
+			continue
+		}
+		if branchConfig.Parent != previous.Parent {
+			if err := git.SetConfig(fmt.Sprintf("gitflow.branch.%s.parent", name), branchConfig.Parent); err != nil {
+				return &errors.GitError{Operation: fmt.Sprintf("update parent of '%s'", name), Err: err}
+			}
+			fmt.Printf("Updated parent of '%s' to '%s'\n", name, branchConfig.Parent)
+		}
+		if branchConfig.StartPoint != previous.StartPoint {
+			if err := git.SetConfig(fmt.Sprintf("gitflow.branch.%s.startPoint", name), branchConfig.StartPoint); err != nil {
+				return &errors.GitError{Operation: fmt.Sprintf("update start point of '%s'", name), Err: err}
+			}
+			fmt.Printf("Updated start point of '%s' to '%s'\n", name, branchConfig.StartPoint)
+		}
+	}
+
+	// Existing topic branches record the base they were started from
+	bases, err := git.GetAllConfig(`^gitflow\.branch\..*\.base$`)
+	if err != nil {
+		return &errors.GitError{Operation: "read topic branch bases", Err: err}
+	}
+	var baseKeys []string
+	for key, value := range bases {
+		if value == oldName {
+			baseKeys = append(baseKeys, key)
+		}
+	}
+	sort.Strings(baseKeys)
+	for _, key := range baseKeys {
+		if err := git.SetConfig(key, newName); err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("update %s", key), Err: err}
+		}
+		branch := strings.TrimSuffix(strings.TrimPrefix(key, "gitflow.branch."), ".base")
+		fmt.Printf("Updated base of '%s' to '%s'\n", branch, newName)
+	}
+
+	// Optionally rename the branch on the remote as well
+	if remote {
+		if err := git.PushBranch(cfg.Remote, newName); err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("push branch '%s' to '%s'", newName, cfg.Remote), Err: err}
+		}
+		if git.RemoteBranchExists(cfg.Remote, oldName) {
+			if err := git.DeleteRemoteBranch(cfg.Remote, oldName); err != nil {
+				return &errors.GitError{Operation: fmt.Sprintf("delete remote branch '%s/%s'", cfg.Remote, oldName), Err: err}
+			}
+		}
+		fmt.Printf("Renamed remote branch '%s/%s' to '%s/%s'\n", cfg.Remote, oldName, cfg.Remote, newName)
+	}
+
+	return nil
+}
+
+func init() {
+	renameBaseCmd.Flags().Bool("remote", false, "Also rename the branch on the remote")
+	rootCmd.AddCommand(renameBaseCmd)
+}
@@ -16,7 +16,15 @@ It provides a set of commands to work with Git branches according to the git-flo
   git flow feature finish my-feature
   git flow release start 1.0.0
   git flow release finish 1.0.0`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		noColor, _ := cmd.Flags().GetBool("no-color")
+		initColor(noColor)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
+		if showVersion, _ := cmd.Flags().GetBool("version"); showVersion {
+			VersionCommand()
+			return
+		}
 		// If no subcommand is provided, print help
 		cmd.Help()
 	},
@@ -33,4 +41,6 @@ func init() {
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Enable verbose output")
+	rootCmd.PersistentFlags().Bool("no-color", false, "Disable colored output")
+	rootCmd.Flags().Bool("version", false, "Show version information")
 }
@@ -4,9 +4,11 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
 	"github.com/gittower/git-flow-next/internal/git"
 	"github.com/spf13/cobra"
 )
@@ -37,6 +39,7 @@ func RegisterShorthandCommands() {
 				return err
 			}
 			force, _ := cmd.Flags().GetBool("force")
+			yes, _ := cmd.Flags().GetBool("yes")
 			var remote *bool
 			if cmd.Flags().Changed("remote") {
 				r, _ := cmd.Flags().GetBool("remote")
@@ -45,10 +48,11 @@ func RegisterShorthandCommands() {
 				f := false
 				remote = &f
 			}
-			return DeleteCommand(branchType, name, force, remote)
+			return DeleteCommand(branchType, name, force, yes, remote)
 		},
 	}
 	deleteCmd.Flags().BoolP("force", "f", false, "Force delete even if unmerged")
+	deleteCmd.Flags().BoolP("yes", "y", false, "Delete without confirmation")
 	deleteCmd.Flags().BoolP("remote", "r", false, "Delete remote tracking branch")
 	deleteCmd.Flags().Bool("no-remote", false, "Don't delete remote tracking branch")
 	rootCmd.AddCommand(deleteCmd)
@@ -58,11 +62,16 @@ func RegisterShorthandCommands() {
 		Use:   "update",
 		Short: "Update the current topic branch from parent",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			useRebase, _ := cmd.Flags().GetBool("rebase")
-			return executeShorthandUpdate(useRebase, args)
+			strategy, err := getUpdateStrategyOverride(cmd)
+			if err != nil {
+				return err
+			}
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			return executeShorthandUpdate(strategy, getBoolPtr(cmd, "fetch", "no-fetch"), verbose, args)
 		},
 	}
-	updateCmd.Flags().Bool("rebase", false, "Force rebase strategy instead of configured strategy")
+	addUpdateStrategyFlags(updateCmd)
+	addUpdateFetchFlags(updateCmd)
 	rootCmd.AddCommand(updateCmd)
 
 	// Rebase (shorthand for update --rebase)
@@ -71,7 +80,8 @@ func RegisterShorthandCommands() {
 		Short: "Rebase the current topic branch from parent",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Always use rebase strategy for this shorthand
-			return executeShorthandUpdate(true, args)
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			return executeShorthandUpdate(string(config.MergeStrategyRebase), nil, verbose, args)
 		},
 	}
 	rootCmd.AddCommand(rebaseCmd)
@@ -114,7 +124,19 @@ func RegisterShorthandCommands() {
 			branchType, name, err := detectBranchTypeAndName()
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-				os.Exit(1)
+				exitCode := errors.ExitCodeGitError
+				if flowErr, ok := err.(errors.Error); ok {
+					exitCode = flowErr.ExitCode()
+				}
+				os.Exit(int(exitCode))
+			}
+			if err := applyEnvFile(cmd); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				exitCode := errors.ExitCodeGitError
+				if flowErr, ok := err.(errors.Error); ok {
+					exitCode = flowErr.ExitCode()
+				}
+				os.Exit(int(exitCode))
 			}
 			continueOp, _ := cmd.Flags().GetBool("continue")
 			abortOp, _ := cmd.Flags().GetBool("abort")
@@ -127,13 +149,28 @@ func RegisterShorthandCommands() {
 				MessageFile: cmd.Flag("messagefile").Value.String(),
 				TagName:     cmd.Flag("tagname").Value.String(),
 			}
+			tagOptions.MessageFromChangelog, _ = cmd.Flags().GetBool("tag-message-from-changelog")
+			tagOptions.RenameIfExists, _ = cmd.Flags().GetBool("rename-tag-if-exists")
+			tagOptions.Force, _ = cmd.Flags().GetBool("force-tag")
+			tagOptions.ForceRemote, _ = cmd.Flags().GetBool("force-remote-tag")
+			tagOptions.NoReuse, _ = cmd.Flags().GetBool("no-tag-reuse")
+			tagOptions.Since, _ = cmd.Flags().GetString("since")
+			tagOptions.Object, _ = cmd.Flags().GetString("tag-object")
+			tagOptions.TargetBranch, _ = cmd.Flags().GetString("tag-on")
+			tagOptions.Dereference = getBoolPtr(cmd, "dereference-annotated-tag-target", "no-dereference-annotated-tag-target")
+			tagOptions.Contributors = getBoolPtr(cmd, "tag-annotate-with-contributors", "no-tag-annotate-with-contributors")
+			tagOptions.Reserve, _ = cmd.Flags().GetBool("reserve-tag")
 			retentionOptions := &BranchRetentionOptions{
 				Keep:        getBoolPtr(cmd, "keep", "no-keep"),
 				KeepRemote:  getBoolPtr(cmd, "keepremote", "no-keepremote"),
 				KeepLocal:   getBoolPtr(cmd, "keeplocal", "no-keeplocal"),
 				ForceDelete: getBoolPtr(cmd, "force-delete", "no-force-delete"),
 			}
-			FinishCommand(branchType, name, continueOp, abortOp, force, tagOptions, retentionOptions)
+			retentionOptions.ForceRemoteDelete, _ = cmd.Flags().GetBool("force-remote-delete")
+			retentionOptions.VerifyRemote = getBoolPtr(cmd, "pre-delete-merge-verify-remote", "no-pre-delete-merge-verify-remote")
+			retentionOptions.KeepBranchConfig = getBoolPtr(cmd, "keep-branch-config", "no-keep-branch-config")
+			finishOptions := finishOptionsFromFlags(cmd)
+			FinishCommand(branchType, name, continueOp, abortOp, force, tagOptions, retentionOptions, finishOptions)
 		},
 	}
 
@@ -142,17 +179,17 @@ func RegisterShorthandCommands() {
 }
 
 // executeShorthandUpdate handles the shared logic for both update and rebase shorthand commands
-func executeShorthandUpdate(useRebase bool, args []string) error {
+func executeShorthandUpdate(strategy string, shouldFetch *bool, verbose bool, args []string) error {
 	branchType, name, err := detectBranchTypeAndName()
 	if err == nil {
-		return executeUpdate(branchType, name, useRebase)
+		return executeUpdate(branchType, name, strategy, shouldFetch, verbose)
 	}
 	// Fallback to original if not topic
 	var branchName string
 	if len(args) > 0 {
 		branchName = args[0]
 	}
-	return executeUpdate("", branchName, useRebase)
+	return executeUpdate("", branchName, strategy, shouldFetch, verbose)
 }
 
 // detectBranchTypeAndName detects type and name from current branch
@@ -165,20 +202,18 @@ func detectBranchTypeAndName() (string, string, error) {
 	if err != nil {
 		return "", "", err
 	}
-	if currentBranch == "" {
-		return "", "", fmt.Errorf("no current branch")
+	switch currentBranch {
+	case "":
+		return "", "", &errors.GitError{Operation: "get current branch", Err: fmt.Errorf("repository has no commits")}
+	case "HEAD":
+		return "", "", &errors.GitError{Operation: "get current branch", Err: fmt.Errorf("HEAD is detached")}
 	}
 
-	matches := []struct{ Type, Prefix string }{}
-	for typ, bc := range cfg.Branches {
-		if bc.Type == string(config.BranchTypeTopic) && strings.HasPrefix(currentBranch, bc.Prefix) {
-			matches = append(matches, struct{ Type, Prefix string }{typ, bc.Prefix})
-		}
-	}
+	matches := topicBranchMatches(cfg, currentBranch)
 
 	switch len(matches) {
 	case 0:
-		return "", "", fmt.Errorf("current branch '%s' is not a valid topic branch (use explicit command, e.g., git flow feature finish)", currentBranch)
+		return "", "", &errors.NotTopicBranchError{BranchName: currentBranch}
 	case 1:
 		typ := matches[0].Type
 		name := strings.TrimPrefix(currentBranch, matches[0].Prefix)
@@ -195,34 +230,45 @@ func detectBranchTypeAndName() (string, string, error) {
 		response, _ := reader.ReadString('\n')
 		response = strings.TrimSpace(strings.ToLower(response))
 		if response == "n" {
-			return "", "", fmt.Errorf("operation cancelled")
+			return "", "", &errors.OperationCancelledError{}
 		}
-		return "", "", fmt.Errorf("please use explicit command (e.g., git flow feature finish)")
+		return "", "", &errors.AmbiguousBranchError{BranchName: currentBranch, Types: typesStr}
 	}
 }
 
-// detectBranchTypeAndNameFromString detects from a given string (for delete [name])
-func detectBranchTypeAndNameFromString(branch string) (string, string, error) {
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		return "", "", err
-	}
+// topicBranchMatches returns the topic branch types whose prefix the branch starts with, sorted by type
+func topicBranchMatches(cfg *config.Config, branch string) []struct{ Type, Prefix string } {
 	matches := []struct{ Type, Prefix string }{}
 	for typ, bc := range cfg.Branches {
 		if bc.Type == string(config.BranchTypeTopic) && strings.HasPrefix(branch, bc.Prefix) {
 			matches = append(matches, struct{ Type, Prefix string }{typ, bc.Prefix})
 		}
 	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Type < matches[j].Type })
+	return matches
+}
+
+// detectBranchTypeAndNameFromString detects from a given string (for delete [name])
+func detectBranchTypeAndNameFromString(branch string) (string, string, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return "", "", err
+	}
+	matches := topicBranchMatches(cfg, branch)
 
 	switch len(matches) {
 	case 0:
-		return "", "", fmt.Errorf("branch '%s' is not a valid topic branch", branch)
+		return "", "", &errors.NotTopicBranchError{BranchName: branch}
 	case 1:
 		typ := matches[0].Type
 		name := strings.TrimPrefix(branch, matches[0].Prefix)
 		return typ, name, nil
 	default:
-		return "", "", fmt.Errorf("ambiguous branch '%s' matches multiple types", branch)
+		types := []string{}
+		for _, m := range matches {
+			types = append(types, m.Type)
+		}
+		return "", "", &errors.AmbiguousBranchError{BranchName: branch, Types: types}
 	}
 }
 
@@ -238,3 +284,12 @@ func getBoolPtr(cmd *cobra.Command, trueFlag, falseFlag string) *bool {
 	}
 	return nil
 }
+
+// getIntPtr returns the value of an int flag if it was given, nil otherwise
+func getIntPtr(cmd *cobra.Command, flag string) *int {
+	if !cmd.Flags().Changed(flag) {
+		return nil
+	}
+	value, _ := cmd.Flags().GetInt(flag)
+	return &value
+}
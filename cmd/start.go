@@ -3,6 +3,10 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/gittower/git-flow-next/internal/config"
 	"github.com/gittower/git-flow-next/internal/errors"
@@ -11,8 +15,17 @@ import (
 
 // StartCommand is the implementation of the start command for topic branches
 // If shouldFetch is nil, the function will check config for fetch preference
-func StartCommand(branchType string, name string, shouldFetch *bool) {
-	if err := start(branchType, name, shouldFetch); err != nil {
+// If trackParentCommitOnly is true, the commit the branch starts from is recorded as its base
+// If fallbackTo is empty, the gitflow.<type>.start.fallback config is used when the start point is missing;
+// noFallback disables any fallback
+// If copyConfigFrom is set, the per-branch settings of that branch are copied to the new branch
+// If fromPR is set, the branch starts from the head of that pull request, fetched with gitflow.start.prrefspec;
+// name then defaults to pr-<number>
+// If bare is true, only the branch ref is created, without fetching or touching HEAD and the working tree
+// If allowExisting is true, an existing branch of the type is checked out instead of failing
+// If base is set, the branch starts from that branch, tag or commit instead of the configured start point
+func StartCommand(branchType string, name string, base string, shouldFetch *bool, trackParentCommitOnly bool, fallbackTo string, noFallback bool, copyConfigFrom string, fromPR int, bare bool, allowExisting bool) {
+	if err := start(branchType, name, base, shouldFetch, trackParentCommitOnly, fallbackTo, noFallback, copyConfigFrom, fromPR, bare, allowExisting); err != nil {
 		var exitCode errors.ExitCode
 		if flowErr, ok := err.(errors.Error); ok {
 			exitCode = flowErr.ExitCode()
@@ -25,7 +38,7 @@ func StartCommand(branchType string, name string, shouldFetch *bool) {
 }
 
 // start performs the actual branch creation logic with optional fetch and returns any errors
-func start(branchType string, name string, shouldFetch *bool) error {
+func start(branchType string, name string, base string, shouldFetch *bool, trackParentCommitOnly bool, fallbackTo string, noFallback bool, copyConfigFrom string, fromPR int, bare bool, allowExisting bool) error {
 	// Validate that git-flow is initialized
 	initialized, err := config.IsInitialized()
 	if err != nil {
@@ -36,9 +49,32 @@ func start(branchType string, name string, shouldFetch *bool) error {
 	}
 
 	// Validate inputs
+	if fromPR < 0 {
+		return &errors.InvalidOptionError{Option: "--from-pr", Reason: fmt.Sprintf("'%d' is not a pull request number", fromPR)}
+	}
+	if name == "" && fromPR > 0 {
+		name = fmt.Sprintf("pr-%d", fromPR)
+	}
 	if name == "" {
 		return &errors.EmptyBranchNameError{}
 	}
+	if fallbackTo != "" && noFallback {
+		return &errors.InvalidOptionError{Option: "--fallback-to", Reason: "cannot be combined with --no-develop-fallback"}
+	}
+	if base != "" && fromPR > 0 {
+		return &errors.InvalidOptionError{Option: "--from-pr", Reason: "cannot be combined with a base to start from"}
+	}
+	if bare && fromPR > 0 {
+		return &errors.InvalidOptionError{Option: "--bare", Reason: "cannot be combined with --from-pr, which fetches"}
+	}
+	if bare && shouldFetch != nil && *shouldFetch {
+		return &errors.InvalidOptionError{Option: "--bare", Reason: "cannot be combined with --fetch"}
+	}
+
+	// A rebase or cherry-pick started outside git-flow must be completed first
+	if err := ensureNoGitOperationInProgress("start"); err != nil {
+		return err
+	}
 
 	// Get configuration
 	cfg, err := config.LoadConfig()
@@ -63,9 +99,9 @@ func start(branchType string, name string, shouldFetch *bool) error {
 		}
 	}
 
-	// Perform fetch if requested
+	// Perform fetch if requested (a bare start never fetches)
 	remoteName := cfg.Remote
-	if shouldFetch != nil && *shouldFetch || shouldFetch == nil && fetchFromConfig {
+	if !bare && (shouldFetch != nil && *shouldFetch || shouldFetch == nil && fetchFromConfig) {
 		// Fetch from remote
 		fmt.Printf("Fetching from %s...\n", remoteName)
 		if err := git.Fetch(remoteName); err != nil {
@@ -76,9 +112,22 @@ func start(branchType string, name string, shouldFetch *bool) error {
 	// Get full branch name
 	fullBranchName := branchConfig.Prefix + name
 
-	// Check if branch already exists
+	// Check if branch already exists, switching to it if that is allowed
 	if err := git.BranchExists(fullBranchName); err == nil {
-		return &errors.BranchExistsError{BranchName: fullBranchName}
+		if !allowExisting {
+			return &errors.BranchExistsError{BranchName: fullBranchName}
+		}
+		return switchToExistingBranch(cfg, branchType, fullBranchName, bare)
+	}
+
+	// Check the branch to copy settings from, accepting its short name as well
+	if copyConfigFrom != "" {
+		if err := git.BranchExists(copyConfigFrom); err != nil {
+			if err := git.BranchExists(branchConfig.Prefix + copyConfigFrom); err != nil {
+				return &errors.BranchNotFoundError{BranchName: copyConfigFrom}
+			}
+			copyConfigFrom = branchConfig.Prefix + copyConfigFrom
+		}
 	}
 
 	// Get start point
@@ -88,15 +137,58 @@ func start(branchType string, name string, shouldFetch *bool) error {
 		startPoint = branchConfig.StartPoint
 	}
 
-	// Check if start point exists
-	if err := git.BranchExists(startPoint); err != nil {
-		return &errors.BranchNotFoundError{BranchName: startPoint}
+	// An explicit base, e.g. the tag a support branch starts from, replaces the start point
+	if base != "" {
+		if _, err := git.ResolveCommit(base); err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("resolve base '%s'", base), Err: err}
+		}
+		startPoint = base
+	} else if err := git.BranchExists(startPoint); err != nil {
+		// The start point doesn't exist, fall back to another branch if one is configured
+		fallback := ""
+		if !noFallback {
+			fallback = getStartFallback(branchType, fallbackTo)
+		}
+		if fallback == "" {
+			return &errors.BranchNotFoundError{BranchName: startPoint}
+		}
+		if err := git.BranchExists(fallback); err != nil {
+			return &errors.BranchNotFoundError{BranchName: fallback}
+		}
+		fmt.Fprintf(os.Stderr, "Warning: start point branch '%s' does not exist, starting from '%s' instead\n", startPoint, fallback)
+		startPoint = fallback
 	}
 
-	// Create branch
-	err = git.CreateBranch(fullBranchName, startPoint)
-	if err != nil {
-		return &errors.GitError{Operation: "create branch", Err: err}
+	// Start from the head of the pull request instead, while the parent stays the branch's base
+	createFrom := startPoint
+	if fromPR > 0 {
+		prRef, err := getPullRequestRef(fromPR)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Fetching %s from %s...\n", prRef, remoteName)
+		commit, err := git.FetchRef(remoteName, prRef)
+		if err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("fetch pull request #%d", fromPR), Err: err}
+		}
+		createFrom = commit
+	}
+
+	// Create branch, only as a ref if bare
+	if bare {
+		commit, err := git.ResolveCommit(createFrom)
+		if err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("resolve start point '%s'", createFrom), Err: err}
+		}
+		err = git.CreateBranchRef(fullBranchName, commit)
+		if err != nil {
+			return &errors.GitError{Operation: "create branch", Err: err}
+		}
+	} else {
+		err = git.CreateBranch(fullBranchName, createFrom)
+		if err != nil {
+			return &errors.GitError{Operation: "create branch", Err: err}
+		}
 	}
 
 	// Store the start point in Git config
@@ -106,6 +198,127 @@ func start(branchType string, name string, shouldFetch *bool) error {
 		fmt.Fprintf(os.Stderr, "Warning: Failed to store start point in config: %v\n", err)
 	}
 
+	// Record the exact commit the branch started from so diff and log stay stable as the parent moves
+	if trackParentCommitOnly {
+		if err := recordStartCommit(fullBranchName, startPoint); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to store start commit in config: %v\n", err)
+		}
+	}
+
+	// Inherit the per-branch settings of another branch
+	if copyConfigFrom != "" {
+		copied, err := copyBranchConfig(copyConfigFrom, fullBranchName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to copy settings from '%s': %v\n", copyConfigFrom, err)
+		} else if copied > 0 {
+			fmt.Printf("Copied %d settings from '%s'\n", copied, copyConfigFrom)
+		}
+	}
+
+	if fromPR > 0 {
+		fmt.Printf("Created branch '%s' from pull request #%d\n", fullBranchName, fromPR)
+		return nil
+	}
 	fmt.Printf("Created branch '%s' from '%s'\n", fullBranchName, startPoint)
 	return nil
 }
+
+// switchToExistingBranch checks out a branch that start was asked to create but that already exists,
+// after making sure it is a topic branch of the given type rather than e.g. a base branch
+func switchToExistingBranch(cfg *config.Config, branchType string, branchName string, bare bool) error {
+	if cfg.Branches[branchType].Type != string(config.BranchTypeTopic) {
+		return &errors.InvalidOptionError{Option: "--allow-existing", Reason: fmt.Sprintf("'%s' is not a topic branch type", branchType)}
+	}
+	if branchConfig, ok := cfg.Branches[branchName]; ok && branchConfig.Type == string(config.BranchTypeBase) {
+		return &errors.InvalidOptionError{Option: "--allow-existing", Reason: fmt.Sprintf("'%s' is a base branch, not a %s branch", branchName, branchType)}
+	}
+	// A type with a longer matching prefix claims the branch, e.g. feature/ui/ over feature/
+	for otherType, branchConfig := range cfg.Branches {
+		if otherType != branchType && branchConfig.Type == string(config.BranchTypeTopic) &&
+			len(branchConfig.Prefix) > len(cfg.Branches[branchType].Prefix) && strings.HasPrefix(branchName, branchConfig.Prefix) {
+			return &errors.InvalidOptionError{Option: "--allow-existing", Reason: fmt.Sprintf("'%s' is a %s branch, not a %s branch", branchName, otherType, branchType)}
+		}
+	}
+
+	if bare {
+		fmt.Printf("Branch '%s' already exists\n", branchName)
+		return nil
+	}
+	if err := git.Checkout(branchName); err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("checkout branch '%s'", branchName), Err: err}
+	}
+	fmt.Printf("Branch '%s' already exists, switched to it\n", branchName)
+	return nil
+}
+
+// defaultPullRequestRefspec is the ref GitHub publishes the head of a pull request under
+const defaultPullRequestRefspec = "refs/pull/%d/head"
+
+// getPullRequestRef returns the remote ref of the pull request, using gitflow.start.prrefspec
+// with %d standing for the pull request number
+func getPullRequestRef(number int) (string, error) {
+	refspec, err := git.GetConfig("gitflow.start.prrefspec")
+	if err != nil || refspec == "" {
+		refspec = defaultPullRequestRefspec
+	}
+	if !strings.Contains(refspec, "%d") {
+		return "", &errors.InvalidOptionError{Option: "gitflow.start.prrefspec", Reason: fmt.Sprintf("'%s' does not contain %%d for the pull request number", refspec)}
+	}
+	return strings.ReplaceAll(refspec, "%d", strconv.Itoa(number)), nil
+}
+
+// startOwnBranchKeys are per-branch keys that describe how a branch was started, so they are not copied
+var startOwnBranchKeys = map[string]bool{
+	"base":        true,
+	"startcommit": true,
+}
+
+// copyBranchConfig copies the gitflow.branch.<source>.* settings to gitflow.branch.<target>.*
+// and returns how many were copied
+func copyBranchConfig(source string, target string) (int, error) {
+	sourcePrefix := fmt.Sprintf("gitflow.branch.%s.", source)
+	values, err := git.GetAllConfig("^" + regexp.QuoteMeta(sourcePrefix))
+	if err != nil {
+		return 0, err
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, strings.TrimPrefix(key, sourcePrefix))
+	}
+	sort.Strings(keys)
+
+	copied := 0
+	for _, key := range keys {
+		if startOwnBranchKeys[key] {
+			continue
+		}
+		if err := git.SetConfig(fmt.Sprintf("gitflow.branch.%s.%s", target, key), values[sourcePrefix+key]); err != nil {
+			return copied, err
+		}
+		copied++
+	}
+	return copied, nil
+}
+
+// getStartFallback returns the branch to start from when the configured start point is missing.
+// The --fallback-to option takes precedence over gitflow.<type>.start.fallback.
+func getStartFallback(branchType string, fallbackTo string) string {
+	if fallbackTo != "" {
+		return fallbackTo
+	}
+	fallback, err := git.GetConfig(fmt.Sprintf("gitflow.%s.start.fallback", branchType))
+	if err != nil {
+		return ""
+	}
+	return fallback
+}
+
+// recordStartCommit stores the commit ref currently points to as the start commit of branchName
+func recordStartCommit(branchName string, ref string) error {
+	commit, err := git.ResolveCommit(ref)
+	if err != nil {
+		return err
+	}
+	return git.SetConfig(fmt.Sprintf("gitflow.branch.%s.startcommit", branchName), commit)
+}
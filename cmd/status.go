@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/mergestate"
+	"github.com/spf13/cobra"
+)
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the git-flow state of the repository and any operation in progress",
+	Long: `Show whether git-flow is initialized, the current branch with its type and parent, and the
+finish or update that is in progress, if any, with the commands to continue or abort it.
+An update whose merge or rebase was already completed or aborted with git is reported as stale.
+With --porcelain, the state is printed as stable key=value lines for scripts.`,
+	Example: `  git flow status
+  git flow status --porcelain`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		porcelain, _ := cmd.Flags().GetBool("porcelain")
+		StatusCommand(porcelain)
+	},
+}
+
+// flowStatus is the state shown by the status command
+type flowStatus struct {
+	Initialized  bool
+	Branch       string // Current branch, empty if HEAD is detached or there are no commits
+	BranchType   string // Topic branch type of the current branch, or "base" for a base branch
+	BranchName   string // Name of the current branch without the prefix of its type
+	BranchParent string // Branch the current branch is merged into when finished
+
+	Operation *mergestate.MergeState // Finish or update in progress, nil if there is none
+	Remaining []string               // Child base branches the operation still has to update
+	Continue  string                 // Command to continue the operation
+	Abort     string                 // Command to abort the operation
+	Stale     bool                   // Whether the operation is an update that git no longer has in progress
+	StateFile string                 // State file to remove to clear a stale operation
+}
+
+// StatusCommand is the implementation of the status command
+func StatusCommand(porcelain bool) {
+	if err := status(porcelain); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(int(exitCode))
+	}
+}
+
+// status prints the state of the repository and returns any errors
+func status(porcelain bool) error {
+	state, err := getFlowStatus()
+	if err != nil {
+		return err
+	}
+	if porcelain {
+		printStatusPorcelain(state)
+	} else {
+		printStatus(state)
+	}
+	return nil
+}
+
+// getFlowStatus collects the state shown by the status command. An uninitialized repository is
+// reported rather than treated as an error.
+func getFlowStatus() (*flowStatus, error) {
+	initialized, err := config.IsInitialized()
+	if err != nil {
+		return nil, &errors.GitError{Operation: "check if git-flow is initialized", Err: err}
+	}
+	state := &flowStatus{Initialized: initialized}
+
+	currentBranch, err := git.GetCurrentBranch()
+	if err != nil {
+		return nil, &errors.GitError{Operation: "get current branch", Err: err}
+	}
+	if currentBranch != "HEAD" {
+		state.Branch = currentBranch
+	}
+
+	if initialized && state.Branch != "" {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return nil, &errors.GitError{Operation: "load configuration", Err: err}
+		}
+		detectStatusBranchType(state, cfg)
+	}
+
+	if mergestate.IsMergeInProgress() {
+		operation, err := mergestate.LoadMergeState()
+		if err != nil {
+			return nil, &errors.GitError{Operation: "load merge state", Err: err}
+		}
+		state.Operation = operation
+
+		// An update completed or aborted with git leaves its state behind, with nothing left to continue
+		stale, err := updateStateStale(operation)
+		if err != nil {
+			return nil, err
+		}
+		if stale {
+			state.Stale = true
+			state.StateFile = mergestate.StatePath()
+			return state, nil
+		}
+
+		for _, branch := range operation.ChildBranches {
+			if !slices.Contains(operation.UpdatedBranches, branch) {
+				state.Remaining = append(state.Remaining, branch)
+			}
+		}
+		state.Continue, state.Abort = resumeCommands(operation)
+	}
+
+	return state, nil
+}
+
+// detectStatusBranchType sets the type, short name and parent of the current branch. A branch matching
+// the prefixes of several topic types belongs to the type with the longest prefix.
+func detectStatusBranchType(state *flowStatus, cfg *config.Config) {
+	if branchConfig, ok := cfg.Branches[state.Branch]; ok && branchConfig.Type == string(config.BranchTypeBase) {
+		state.BranchType = string(config.BranchTypeBase)
+		state.BranchName = state.Branch
+		state.BranchParent = branchConfig.Parent
+		return
+	}
+	prefix := ""
+	for _, match := range topicBranchMatches(cfg, state.Branch) {
+		if state.BranchType == "" || len(match.Prefix) > len(prefix) {
+			state.BranchType = match.Type
+			prefix = match.Prefix
+		}
+	}
+	if state.BranchType != "" {
+		state.BranchName = strings.TrimPrefix(state.Branch, prefix)
+		state.BranchParent = cfg.Branches[state.BranchType].Parent
+	}
+}
+
+// resumeCommands returns the commands that continue and abort the operation in progress. A finish is
+// resumed by git-flow; an update stops in a plain git merge or rebase, which git completes.
+func resumeCommands(operation *mergestate.MergeState) (string, string) {
+	if operation.Action == "finish" {
+		return fmt.Sprintf("git flow %s finish --continue %s", operation.BranchType, operation.BranchName),
+			fmt.Sprintf("git flow %s finish --abort %s", operation.BranchType, operation.BranchName)
+	}
+	if strings.ToLower(operation.MergeStrategy) == strategyRebase {
+		return "git rebase --continue", "git rebase --abort"
+	}
+	return "git commit", "git merge --abort"
+}
+
+// printStatus prints the state for humans
+func printStatus(state *flowStatus) {
+	if !state.Initialized {
+		fmt.Println("git-flow is not initialized")
+	} else {
+		fmt.Println("git-flow is initialized")
+	}
+
+	switch {
+	case state.Branch == "":
+		fmt.Println("Not on a branch")
+	case state.BranchType == string(config.BranchTypeBase) && state.BranchParent == "":
+		fmt.Printf("On base branch '%s'\n", state.Branch)
+	case state.BranchType == string(config.BranchTypeBase):
+		fmt.Printf("On base branch '%s' (parent '%s')\n", state.Branch, state.BranchParent)
+	case state.BranchType != "":
+		fmt.Printf("On %s branch '%s' (parent '%s')\n", state.BranchType, state.BranchName, state.BranchParent)
+	default:
+		fmt.Printf("On branch '%s', which is not a git-flow branch\n", state.Branch)
+	}
+
+	operation := state.Operation
+	if operation == nil {
+		fmt.Println("No operation in progress")
+		return
+	}
+	if state.Stale {
+		fmt.Printf("Update of '%s' from '%s' is no longer in progress, but its state was left behind\n", operation.FullBranchName, operation.ParentBranch)
+		fmt.Printf("To clear it, remove '%s'\n", state.StateFile)
+		return
+	}
+	if operation.Action == "finish" {
+		fmt.Printf("Finish of %s branch '%s' into '%s' in progress\n", operation.BranchType, operation.BranchName, operation.ParentBranch)
+	} else {
+		fmt.Printf("Update of '%s' from '%s' in progress\n", operation.FullBranchName, operation.ParentBranch)
+	}
+	fmt.Printf("  Step: %s\n", operation.CurrentStep)
+	fmt.Printf("  Strategy: %s\n", operation.MergeStrategy)
+	if len(state.Remaining) > 0 {
+		fmt.Printf("  Child branches to update: %s\n", strings.Join(state.Remaining, ", "))
+	}
+	if git.HasConflicts() {
+		fmt.Println("  There are unresolved conflicts")
+	}
+	fmt.Printf("To continue, run '%s'\n", state.Continue)
+	fmt.Printf("To abort, run '%s'\n", state.Abort)
+}
+
+// printStatusPorcelain prints the state as key=value lines whose keys don't change between versions.
+// Keys without a value are left out, lists are separated by spaces.
+func printStatusPorcelain(state *flowStatus) {
+	line := func(key string, value string) {
+		if value != "" {
+			fmt.Printf("%s=%s\n", key, value)
+		}
+	}
+	line("initialized", fmt.Sprintf("%t", state.Initialized))
+	line("branch", state.Branch)
+	line("branch.type", state.BranchType)
+	line("branch.name", state.BranchName)
+	line("branch.parent", state.BranchParent)
+
+	operation := state.Operation
+	if operation == nil {
+		line("operation", "none")
+		return
+	}
+	if state.Stale {
+		line("operation", "stale")
+		line("operation.branch", operation.FullBranchName)
+		line("operation.parent", operation.ParentBranch)
+		line("operation.statefile", state.StateFile)
+		return
+	}
+	line("operation", operation.Action)
+	line("operation.type", operation.BranchType)
+	line("operation.branch", operation.FullBranchName)
+	line("operation.name", operation.BranchName)
+	line("operation.parent", operation.ParentBranch)
+	line("operation.step", operation.CurrentStep)
+	line("operation.strategy", operation.MergeStrategy)
+	line("operation.remaining", strings.Join(state.Remaining, " "))
+	line("operation.conflicts", fmt.Sprintf("%t", git.HasConflicts()))
+	line("operation.continue", state.Continue)
+	line("operation.abort", state.Abort)
+}
+
+func init() {
+	statusCmd.Flags().Bool("porcelain", false, "Print the state as stable key=value lines for scripts")
+	rootCmd.AddCommand(statusCmd)
+}
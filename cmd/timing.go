@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+)
+
+// stepTiming is the measured duration of one finish step
+type stepTiming struct {
+	label    string
+	duration time.Duration
+}
+
+// stepTimer measures the finish steps for --report-timing. Steps hand over to each other through
+// nested calls, so each step is timed from its start until the next step starts.
+var stepTimer struct {
+	enabled bool
+	current string
+	started time.Time
+	timings []stepTiming
+}
+
+// enableStepTiming turns on step timing for the current process
+func enableStepTiming() {
+	stepTimer.enabled = true
+}
+
+// startStepTiming ends the running step, if any, and starts timing the step with the given label.
+// Starting the step that is already running does nothing.
+func startStepTiming(label string) {
+	if !stepTimer.enabled || label == stepTimer.current {
+		return
+	}
+	stopStepTiming()
+	stepTimer.current = label
+	stepTimer.started = time.Now()
+}
+
+// stopStepTiming ends the running step, if any
+func stopStepTiming() {
+	if stepTimer.current == "" {
+		return
+	}
+	stepTimer.timings = append(stepTimer.timings, stepTiming{label: stepTimer.current, duration: time.Since(stepTimer.started)})
+	stepTimer.current = ""
+}
+
+// printStepTimings prints a table with the duration of each step and the total
+func printStepTimings() {
+	if !stepTimer.enabled {
+		return
+	}
+	stopStepTiming()
+	if len(stepTimer.timings) == 0 {
+		return
+	}
+
+	width := len("total")
+	for _, timing := range stepTimer.timings {
+		width = max(width, len(timing.label))
+	}
+
+	var total time.Duration
+	fmt.Println("Step timings:")
+	for _, timing := range stepTimer.timings {
+		fmt.Printf("  %-*s  %s\n", width, timing.label, formatStepDuration(timing.duration))
+		total += timing.duration
+	}
+	fmt.Printf("  %-*s  %s\n", width, "total", formatStepDuration(total))
+}
+
+// formatStepDuration renders a step duration with millisecond precision
+func formatStepDuration(duration time.Duration) string {
+	return fmt.Sprintf("%.3fs", duration.Seconds())
+}
@@ -44,7 +44,7 @@ func RegisterTopicBranchCommands() {
 // registerDefaultBranchCommands registers commands for standard branch types
 func registerDefaultBranchCommands() {
 	// Standard branch types
-	branchTypes := []string{"feature", "release", "hotfix", "support"}
+	branchTypes := []string{"feature", "bugfix", "release", "hotfix", "support"}
 
 	// Register commands for each branch type
 	for _, branchType := range branchTypes {
@@ -67,11 +67,17 @@ func registerBranchCommand(branchType string) {
 
 	// Add start subcommand
 	startCmd := &cobra.Command{
-		Use:     "start [name]",
+		Use:     "start [name] [base]",
 		Short:   fmt.Sprintf("Start a new %s branch", branchType),
-		Long:    fmt.Sprintf("Start a new %s branch from the appropriate base branch", branchType),
-		Example: fmt.Sprintf("  git flow %s start my-new-feature", branchType),
-		Args:    cobra.ExactArgs(1),
+		Long:    fmt.Sprintf("Start a new %s branch from the appropriate base branch, or from the given base branch, tag or commit", branchType),
+		Example: fmt.Sprintf("  git flow %s start my-new-feature\n  git flow %s start my-new-feature v1.2.0\n  git flow %s start --from-pr 42", branchType, branchType, branchType),
+		Args: func(cmd *cobra.Command, args []string) error {
+			// The name may be left out when starting from a pull request
+			if fromPR, _ := cmd.Flags().GetInt("from-pr"); fromPR != 0 {
+				return cobra.MaximumNArgs(1)(cmd, args)
+			}
+			return cobra.RangeArgs(1, 2)(cmd, args)
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			// Get fetch flag values
 			fetch, _ := cmd.Flags().GetBool("fetch")
@@ -87,14 +93,38 @@ func registerBranchCommand(branchType string) {
 				shouldFetch = &f
 			}
 
+			trackParentCommitOnly, _ := cmd.Flags().GetBool("track-parent-commit-only")
+			fallbackTo, _ := cmd.Flags().GetString("fallback-to")
+			noFallback, _ := cmd.Flags().GetBool("no-develop-fallback")
+			copyConfigFrom, _ := cmd.Flags().GetString("copy-config-from")
+			fromPR, _ := cmd.Flags().GetInt("from-pr")
+			bare, _ := cmd.Flags().GetBool("bare")
+			allowExisting, _ := cmd.Flags().GetBool("allow-existing")
+			name, base := "", ""
+			if len(args) > 0 {
+				name = args[0]
+			}
+			if len(args) > 1 {
+				base = args[1]
+			}
+
 			// Call the generic start command with the branch type, name, and fetch flags
-			StartCommand(branchType, args[0], shouldFetch)
+			StartCommand(branchType, name, base, shouldFetch, trackParentCommitOnly, fallbackTo, noFallback, copyConfigFrom, fromPR, bare, allowExisting)
 		},
 	}
 
 	// Add fetch-related flags
 	startCmd.Flags().Bool("fetch", false, "Fetch from remote before creating branch")
 	startCmd.Flags().Bool("no-fetch", false, "Don't fetch from remote before creating branch")
+	startCmd.Flags().Bool("track-parent-commit-only", false, "Record the parent commit as the branch base for diff and log")
+
+	// Add start point fallback flags
+	startCmd.Flags().String("fallback-to", "", "Start from the given branch if the configured start point doesn't exist")
+	startCmd.Flags().Bool("no-develop-fallback", false, "Fail if the configured start point doesn't exist, ignoring any configured fallback")
+	startCmd.Flags().String("copy-config-from", "", "Copy the per-branch settings of the given branch to the new branch")
+	startCmd.Flags().Bool("bare", false, "Only create the branch ref, without fetching, checking it out or touching the working tree")
+	startCmd.Flags().Bool("allow-existing", false, "Check out the branch if it already exists instead of failing")
+	startCmd.Flags().Int("from-pr", 0, "Start from the head of the given pull request, fetched with gitflow.start.prrefspec (default \"refs/pull/%d/head\")")
 
 	branchCmd.AddCommand(startCmd)
 
@@ -106,6 +136,18 @@ func registerBranchCommand(branchType string) {
 		Example: fmt.Sprintf("  git flow %s finish my-feature\n  git flow %s finish other/branch -f", branchType, branchType),
 		Args:    cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
+			// Options from --env-file apply to every flag not given on the command line
+			if err := applyEnvFile(cmd); err != nil {
+				var exitCode errors.ExitCode
+				if flowErr, ok := err.(errors.Error); ok {
+					exitCode = flowErr.ExitCode()
+				} else {
+					exitCode = errors.ExitCodeGitError
+				}
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(int(exitCode))
+			}
+
 			// Get flags
 			continueOp, _ := cmd.Flags().GetBool("continue")
 			abortOp, _ := cmd.Flags().GetBool("abort")
@@ -120,6 +162,19 @@ func registerBranchCommand(branchType string) {
 			message, _ := cmd.Flags().GetString("message")
 			messageFile, _ := cmd.Flags().GetString("messagefile")
 			tagName, _ := cmd.Flags().GetString("tagname")
+			messageFromChangelog, _ := cmd.Flags().GetBool("tag-message-from-changelog")
+			renameTagIfExists, _ := cmd.Flags().GetBool("rename-tag-if-exists")
+			forceTag, _ := cmd.Flags().GetBool("force-tag")
+			forceRemoteTag, _ := cmd.Flags().GetBool("force-remote-tag")
+			noTagReuse, _ := cmd.Flags().GetBool("no-tag-reuse")
+			since, _ := cmd.Flags().GetString("since")
+			contributors, _ := cmd.Flags().GetBool("tag-annotate-with-contributors")
+			noContributors, _ := cmd.Flags().GetBool("no-tag-annotate-with-contributors")
+			tagObject, _ := cmd.Flags().GetString("tag-object")
+			tagOn, _ := cmd.Flags().GetString("tag-on")
+			dereferenceTagTarget, _ := cmd.Flags().GetBool("dereference-annotated-tag-target")
+			noDereferenceTagTarget, _ := cmd.Flags().GetBool("no-dereference-annotated-tag-target")
+			reserveTag, _ := cmd.Flags().GetBool("reserve-tag")
 
 			// Get branch retention flags
 			keep, _ := cmd.Flags().GetBool("keep")
@@ -130,6 +185,11 @@ func registerBranchCommand(branchType string) {
 			noKeepLocal, _ := cmd.Flags().GetBool("no-keeplocal")
 			forceDelete, _ := cmd.Flags().GetBool("force-delete")
 			noForceDelete, _ := cmd.Flags().GetBool("no-force-delete")
+			forceRemoteDelete, _ := cmd.Flags().GetBool("force-remote-delete")
+			verifyRemote, _ := cmd.Flags().GetBool("pre-delete-merge-verify-remote")
+			noVerifyRemote, _ := cmd.Flags().GetBool("no-pre-delete-merge-verify-remote")
+			keepBranchConfig, _ := cmd.Flags().GetBool("keep-branch-config")
+			noKeepBranchConfig, _ := cmd.Flags().GetBool("no-keep-branch-config")
 
 			// Create tag options
 			tagOptions := &TagOptions{
@@ -139,6 +199,21 @@ func registerBranchCommand(branchType string) {
 				Message:     message,
 				MessageFile: messageFile,
 				TagName:     tagName,
+
+				MessageFromChangelog: messageFromChangelog,
+				RenameIfExists:       renameTagIfExists,
+				Force:                forceTag,
+				ForceRemote:          forceRemoteTag,
+				NoReuse:              noTagReuse,
+
+				Since:        since,
+				Object:       tagObject,
+				TargetBranch: tagOn,
+				Dereference:  getBoolFlag(dereferenceTagTarget, noDereferenceTagTarget),
+
+				Contributors: getBoolFlag(contributors, noContributors),
+
+				Reserve: reserveTag,
 			}
 
 			// Create branch retention options
@@ -147,26 +222,74 @@ func registerBranchCommand(branchType string) {
 				KeepRemote:  getBoolFlag(keepRemote, noKeepRemote),
 				KeepLocal:   getBoolFlag(keepLocal, noKeepLocal),
 				ForceDelete: getBoolFlag(forceDelete, noForceDelete),
+
+				ForceRemoteDelete: forceRemoteDelete,
+				VerifyRemote:      getBoolFlag(verifyRemote, noVerifyRemote),
+
+				KeepBranchConfig: getBoolFlag(keepBranchConfig, noKeepBranchConfig),
 			}
 
+			// Create general finish options
+			finishOptions := finishOptionsFromFlags(cmd)
+
 			// Call the generic finish command with the branch type and name
-			FinishCommand(branchType, args[0], continueOp, abortOp, force, tagOptions, retentionOptions)
+			FinishCommand(branchType, args[0], continueOp, abortOp, force, tagOptions, retentionOptions, finishOptions)
 		},
 	}
 
 	addFinishFlags(finishCmd)
 	branchCmd.AddCommand(finishCmd)
 
+	// Add finalize subcommand
+	finalizeCmd := &cobra.Command{
+		Use:     "finalize [version]",
+		Short:   "Replace a reserved tag with the final tag",
+		Long:    fmt.Sprintf("Replace a tag reserved by '%s finish --reserve-tag' with the final annotated tag, at the current tip of the branch it was reserved on or at the given commit", branchType),
+		Example: fmt.Sprintf("  git flow %s finalize 1.2.0\n  git flow %s finalize 1.2.0 --commit HEAD -m \"Release 1.2.0\"", branchType, branchType),
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			commit, _ := cmd.Flags().GetString("commit")
+			tagOptions := &TagOptions{
+				ShouldSign:  getBoolPtr(cmd, "sign", "no-sign"),
+				SigningKey:  cmd.Flag("signingkey").Value.String(),
+				Message:     cmd.Flag("message").Value.String(),
+				MessageFile: cmd.Flag("messagefile").Value.String(),
+			}
+			if err := FinalizeCommand(branchType, args[0], commit, tagOptions); err != nil {
+				var exitCode errors.ExitCode
+				if flowErr, ok := err.(errors.Error); ok {
+					exitCode = flowErr.ExitCode()
+				} else {
+					exitCode = errors.ExitCodeGitError
+				}
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(int(exitCode))
+			}
+			return nil
+		},
+	}
+
+	// Add flags
+	finalizeCmd.Flags().String("commit", "", "Commit to tag instead of the tip of the branch the tag was reserved on")
+	finalizeCmd.Flags().StringP("message", "m", "", "Use the given message for the tag")
+	finalizeCmd.Flags().String("messagefile", "", "Use the contents of the given file as the tag message")
+	finalizeCmd.Flags().Bool("sign", false, "Sign the tag cryptographically")
+	finalizeCmd.Flags().Bool("no-sign", false, "Don't sign the tag cryptographically")
+	finalizeCmd.Flags().String("signingkey", "", "Use the given GPG key for the digital signature")
+
+	branchCmd.AddCommand(finalizeCmd)
+
 	// Add list subcommand
 	listCmd := &cobra.Command{
 		Use:     "list",
 		Short:   fmt.Sprintf("List all %s branches", branchType),
-		Long:    fmt.Sprintf("List all %s branches in the repository", branchType),
-		Example: fmt.Sprintf("  git flow %s list", branchType),
+		Long:    fmt.Sprintf("List all %s branches in the repository, marking the current branch and whether each is merged into its parent", branchType),
+		Example: fmt.Sprintf("  git flow %s list\n  git flow %s list --verbose", branchType, branchType),
 		Args:    cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
 			// Call the generic list command with the branch type
-			ListCommand(branchType)
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			ListCommand(branchType, verbose)
 		},
 	}
 	branchCmd.AddCommand(listCmd)
@@ -183,7 +306,12 @@ func registerBranchCommand(branchType string) {
 			if len(args) > 0 {
 				name = args[0]
 			}
-			if err := executeUpdate(branchType, name, false); err != nil {
+			strategy, err := getUpdateStrategyOverride(cmd)
+			if err == nil {
+				verbose, _ := cmd.Flags().GetBool("verbose")
+				err = executeUpdate(branchType, name, strategy, getBoolPtr(cmd, "fetch", "no-fetch"), verbose)
+			}
+			if err != nil {
 				var exitCode errors.ExitCode
 				if flowErr, ok := err.(errors.Error); ok {
 					exitCode = flowErr.ExitCode()
@@ -196,17 +324,74 @@ func registerBranchCommand(branchType string) {
 			return nil
 		},
 	}
+	addUpdateStrategyFlags(updateCmd)
+	addUpdateFetchFlags(updateCmd)
 	branchCmd.AddCommand(updateCmd)
 
+	// Add diff subcommand
+	diffCmd := &cobra.Command{
+		Use:     "diff [name]",
+		Short:   fmt.Sprintf("Show the changes made on a %s branch", branchType),
+		Long:    fmt.Sprintf("Show the changes made on a %s branch since its recorded start commit, or since it diverged from its parent branch", branchType),
+		Example: fmt.Sprintf("  git flow %s diff my-feature", branchType),
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var name string
+			if len(args) > 0 {
+				name = args[0]
+			}
+			if err := DiffCommand(branchType, name); err != nil {
+				var exitCode errors.ExitCode
+				if flowErr, ok := err.(errors.Error); ok {
+					exitCode = flowErr.ExitCode()
+				} else {
+					exitCode = errors.ExitCodeGitError
+				}
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(int(exitCode))
+			}
+			return nil
+		},
+	}
+	branchCmd.AddCommand(diffCmd)
+
+	// Add log subcommand
+	logCmd := &cobra.Command{
+		Use:     "log [name]",
+		Short:   fmt.Sprintf("Show the commits made on a %s branch", branchType),
+		Long:    fmt.Sprintf("Show the commits made on a %s branch since its recorded start commit, or since it diverged from its parent branch", branchType),
+		Example: fmt.Sprintf("  git flow %s log my-feature", branchType),
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var name string
+			if len(args) > 0 {
+				name = args[0]
+			}
+			if err := LogCommand(branchType, name); err != nil {
+				var exitCode errors.ExitCode
+				if flowErr, ok := err.(errors.Error); ok {
+					exitCode = flowErr.ExitCode()
+				} else {
+					exitCode = errors.ExitCodeGitError
+				}
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(int(exitCode))
+			}
+			return nil
+		},
+	}
+	branchCmd.AddCommand(logCmd)
+
 	// Add delete subcommand
 	deleteCmd := &cobra.Command{
 		Use:     "delete [name]",
 		Short:   fmt.Sprintf("Delete a %s branch", branchType),
 		Long:    fmt.Sprintf("Delete a %s branch from the repository", branchType),
-		Example: fmt.Sprintf("  git flow %s delete my-feature\n  git flow %s delete -f my-feature", branchType, branchType),
+		Example: fmt.Sprintf("  git flow %s delete my-feature\n  git flow %s delete -y my-feature\n  git flow %s delete -f my-feature", branchType, branchType, branchType),
 		Args:    cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			force, _ := cmd.Flags().GetBool("force")
+			yes, _ := cmd.Flags().GetBool("yes")
 			remote, _ := cmd.Flags().GetBool("remote")
 			noRemote, _ := cmd.Flags().GetBool("no-remote")
 
@@ -219,7 +404,7 @@ func registerBranchCommand(branchType string) {
 				remotePtr = &falseBool
 			}
 
-			if err := DeleteCommand(branchType, args[0], force, remotePtr); err != nil {
+			if err := DeleteCommand(branchType, args[0], force, yes, remotePtr); err != nil {
 				var exitCode errors.ExitCode
 				if flowErr, ok := err.(errors.Error); ok {
 					exitCode = flowErr.ExitCode()
@@ -235,6 +420,7 @@ func registerBranchCommand(branchType string) {
 
 	// Add flags
 	deleteCmd.Flags().BoolP("force", "f", false, "Force delete the branch even if it has unmerged changes")
+	deleteCmd.Flags().BoolP("yes", "y", false, "Delete without asking for confirmation")
 	deleteCmd.Flags().BoolP("remote", "r", false, "Delete the remote tracking branch")
 	deleteCmd.Flags().Bool("no-remote", false, "Don't delete the remote tracking branch")
 
@@ -311,6 +497,8 @@ func addFinishFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolP("continue", "c", false, "Continue the finish operation after resolving conflicts")
 	cmd.Flags().BoolP("abort", "a", false, "Abort the finish operation and return to the original state")
 	cmd.Flags().BoolP("force", "f", false, "Force finish a non-standard branch using this branch type's strategy")
+	cmd.Flags().Bool("assume-yes", false, "Confirm finishing a non-standard branch without asking (unlike --force, nothing else is forced)")
+	cmd.Flags().String("env-file", "", "Read finish options from a file of KEY=VALUE lines, e.g. SIGNINGKEY=ABC123; command-line flags take precedence")
 
 	// Tag-related Flags
 	cmd.Flags().Bool("tag", false, "Create a tag for the finished branch")
@@ -321,14 +509,159 @@ func addFinishFlags(cmd *cobra.Command) {
 	cmd.Flags().StringP("message", "m", "", "Use the given message for the tag")
 	cmd.Flags().String("messagefile", "", "Use contents of the given file as tag message")
 	cmd.Flags().String("tagname", "", "Use the given tag name instead of the default")
+	cmd.Flags().Bool("tag-message-from-changelog", false, "Use the unreleased section of the changelog as the tag message")
+	cmd.Flags().Bool("rename-tag-if-exists", false, "Append a numeric suffix to the tag name if the tag already exists")
+	cmd.Flags().Bool("force-tag", false, "Replace the tag if it already exists")
+	cmd.Flags().Bool("force-remote-tag", false, "With --force-tag, replace the tag even if it was already pushed to the remote")
+	cmd.Flags().Bool("no-tag-reuse", false, "Fail instead of reusing an existing tag at the commit to tag")
+	cmd.Flags().String("since", "", "List the commits since the given ref in the tag message")
+	cmd.Flags().Bool("tag-annotate-with-contributors", false, "List the authors since the last tag (or --since) in the tag message")
+	cmd.Flags().Bool("no-tag-annotate-with-contributors", false, "Don't list the authors since the last tag in the tag message")
+	cmd.Flags().String("tag-object", "", "Tag the given object (commit, tree or blob) instead of the tip of the target branch")
+	cmd.Flags().Bool("dereference-annotated-tag-target", false, "Tag the commit an annotated tag points to rather than the tag itself (default)")
+	cmd.Flags().Bool("no-dereference-annotated-tag-target", false, "Tag an annotated tag given with --tag-object itself, nesting the tag objects")
+	cmd.Flags().String("tag-on", "", "Tag the tip of the given branch instead of the target, e.g. an --also-into or child base branch")
+	cmd.Flags().Bool("reserve-tag", false, "Only create a lightweight placeholder tag, to be replaced with the final tag by finalize")
 
 	// Branch Retention Flags
 	cmd.Flags().Bool("keep", false, "Keep the branch after finishing")
 	cmd.Flags().Bool("no-keep", false, "Delete the branch after finishing")
-	cmd.Flags().Bool("keepremote", false, "Keep the remote branch after finishing")
-	cmd.Flags().Bool("no-keepremote", false, "Delete the remote branch after finishing")
+	cmd.Flags().Bool("keepremote", false, "Keep the remote branch after finishing, so the finish does not contact the remote to delete it")
+	cmd.Flags().Bool("no-keepremote", false, "Delete the remote branch after finishing, if it has a remote-tracking branch")
 	cmd.Flags().Bool("keeplocal", false, "Keep the local branch after finishing")
 	cmd.Flags().Bool("no-keeplocal", false, "Delete the local branch after finishing")
 	cmd.Flags().Bool("force-delete", false, "Force delete the branch")
 	cmd.Flags().Bool("no-force-delete", false, "Don't force delete the branch")
+	cmd.Flags().Bool("force-remote-delete", false, "Delete the remote branch even if it has commits that were not merged")
+	cmd.Flags().Bool("pre-delete-merge-verify-remote", false, "Fetch the remote branch before deleting it and keep it if it has commits that were not merged")
+	cmd.Flags().Bool("no-pre-delete-merge-verify-remote", false, "Check the remote branch against the last fetched state only")
+	cmd.Flags().Bool("prevent-fast-forward-delete-race", false, "Refuse to delete the branch if it received new commits after it was merged")
+	cmd.Flags().Bool("keep-branch-config", false, "Keep the gitflow.branch.<name>.* settings when the branch is deleted")
+	cmd.Flags().Bool("no-keep-branch-config", false, "Remove the gitflow.branch.<name>.* settings when the branch is deleted")
+
+	// Merge Flags
+	cmd.Flags().Bool("ours", false, "Resolve conflicting hunks in favor of the target branch (merge/squash only)")
+	cmd.Flags().Bool("theirs", false, "Resolve conflicting hunks in favor of the finished branch (merge/squash only)")
+	cmd.Flags().StringArray("strategy-option", nil, "Pass a merge strategy option to git merge, e.g. find-renames=30% (merge/squash only, can be repeated)")
+	cmd.Flags().Bool("merge-allow-unrelated-histories", false, "Allow merging a branch that shares no history with the target (merge/squash only)")
+	cmd.Flags().String("merge-base-override", "", "Squash only the changes made on the branch since the given commit (squash only)")
+	cmd.Flags().String("squash-message", "", "Commit message of the squash commit (squash only)")
+	cmd.Flags().String("squash-message-file", "", "Read the commit message of the squash commit from a file (squash only)")
+	cmd.Flags().String("source-ref", "", "Integrate the given ref (tag, commit) instead of a topic branch")
+	cmd.Flags().Bool("target-tracking-branch", false, "Create or fast-forward the target branch from its remote-tracking branch before merging")
+	cmd.Flags().Bool("target-remote-tracking-update", false, "Fetch the remote-tracking branches of the updated base branches after finishing, without pushing")
+	cmd.Flags().Bool("push", false, "Push the target, the updated child base branches and the tag to the remote after finishing")
+	cmd.Flags().Bool("no-push", false, "Don't push the base branches after finishing")
+	cmd.Flags().Bool("tag-push-force-with-lease", false, "With --push, force-push a tag replaced by --force-tag only if the remote still has the replaced tag")
+	cmd.Flags().Bool("no-tag-push-force-with-lease", false, "Don't force-push a replaced tag")
+	cmd.Flags().Bool("local-only", false, "Don't contact the remote: keep the remote branch and push nothing")
+	cmd.Flags().Bool("no-local-only", false, "Delete the remote branch and push as configured")
+	cmd.Flags().Bool("skip-empty-children", false, "Skip updating child base branches that are already up to date")
+	cmd.Flags().Bool("autoupdate-children", false, "Update all child base branches, including those without autoUpdate")
+	cmd.Flags().Bool("no-autoupdate-children", false, "Don't update any child base branches")
+	cmd.Flags().Int("min-commits", 0, "Refuse to finish a branch with fewer commits not in the target (overrides gitflow.<type>.finish.mincommits, 0 for no limit)")
+	cmd.Flags().Int("max-commits", 0, "Refuse to finish a branch with more commits not in the target (overrides gitflow.<type>.finish.maxcommits, 0 for no limit)")
+	cmd.Flags().Bool("commit-count-guard", false, "Check the commit count of the branch against --min-commits and --max-commits (default)")
+	cmd.Flags().Bool("no-commit-count-guard", false, "Finish the branch whatever its number of commits")
+	cmd.Flags().Bool("children-parallel", false, "Check child base branches concurrently and only merge into the ones that are behind")
+	cmd.Flags().Bool("keep-history-note", false, "Attach a git note with the branch type, name and strategy to the merge commit")
+	cmd.Flags().String("commit-date", "", "Author and committer date for the merge commit and tag (e.g. 2024-01-15T12:00:00Z)")
+	cmd.Flags().StringArray("also-into", nil, "Also merge the branch into the given branch after the target (can be repeated)")
+	cmd.Flags().String("also-into-strategy", "", "Strategy for merging into the --also-into branches: merge or squash (default merge)")
+	cmd.Flags().Bool("merge-message-edit", false, "Edit the merge or squash commit message in the editor before committing")
+	cmd.Flags().Bool("no-merge-message-edit", false, "Don't edit the merge or squash commit message")
+
+	// Hook Flags
+	cmd.Flags().String("post-finish-command", "", "Shell command to run after a successful finish")
+	cmd.Flags().String("comment-on-issue", "", "Shell command to comment on the branch's issue after a successful finish (%issue%, %tag%, %branch%, %target%, also as GITFLOW_ISSUE, GITFLOW_TAG, GITFLOW_BRANCH, GITFLOW_TARGET)")
+	cmd.Flags().String("emit-event", "", "Post the finish outcome as JSON to the given webhook URL, signed with gitflow.<type>.finish.webhooksecret or $GITFLOW_WEBHOOK_SECRET")
+
+	// Output Flags
+	cmd.Flags().String("reflog-message", "", "Reflog message for the git operations of the finish (default \"git-flow finish <branch>\")")
+	cmd.Flags().String("output-format", "", "Template for the success line (%branch%, %target%, %strategy%, %tag%, %childcount%)")
+	cmd.Flags().Bool("confirm", false, "Show the finish plan and ask for confirmation before acting")
+	cmd.Flags().Bool("no-confirm", false, "Don't ask for confirmation before acting")
+	cmd.Flags().Bool("stash-untracked", false, "Stash untracked files during the finish and restore them afterwards")
+	cmd.Flags().String("approve", "", "Approve merging into the given protected target branch without asking")
+	cmd.Flags().Bool("list-steps", false, "Only list the steps the finish would run, without changing anything")
+	cmd.Flags().Bool("validate-only", false, "Only run the checks of the finish and report whether they pass, without changing anything")
+	cmd.Flags().Bool("dry-run", false, "Only describe what the finish would do, without changing anything")
+	cmd.Flags().Bool("json", false, "With --dry-run, print the finish plan as JSON")
+	cmd.Flags().Bool("idempotent", false, "Succeed without changes if the branch was already finished and its tag is on the target")
+	cmd.Flags().Bool("tag-existing-commit-reuse", false, "If the branch is already merged into the target, tag the existing merge instead of merging again")
+	cmd.Flags().Bool("report-timing", false, "Print the duration of each finish step at the end")
+	cmd.Flags().Bool("print-tag", false, "Print a 'tag: <name> <sha>' line for the created tag")
+	cmd.Flags().String("record-metrics", "", "Append a CSV row (timestamp, type, branch, commits, files changed, conflict, duration in seconds) to the given file")
+	cmd.Flags().Bool("record-base-snapshot", false, "Log the commits of the base branches before and after the finish to .git/gitflow/snapshots.log")
+	cmd.Flags().Bool("no-record-base-snapshot", false, "Don't log the base branches of the finish")
+	cmd.Flags().Bool("children-strategy-report", false, "Print the strategy and outcome of each child base branch update")
+}
+
+// finishOptionsFromFlags returns the general finish options given by the flags added with addFinishFlags
+func finishOptionsFromFlags(cmd *cobra.Command) *FinishOptions {
+	boolFlag := func(positive string, negative string) *bool {
+		positiveValue, _ := cmd.Flags().GetBool(positive)
+		negativeValue, _ := cmd.Flags().GetBool(negative)
+		return getBoolFlag(positiveValue, negativeValue)
+	}
+
+	finishOptions := &FinishOptions{}
+	finishOptions.Ours, _ = cmd.Flags().GetBool("ours")
+	finishOptions.Theirs, _ = cmd.Flags().GetBool("theirs")
+	finishOptions.StrategyOptions, _ = cmd.Flags().GetStringArray("strategy-option")
+
+	finishOptions.AllowUnrelatedHistories, _ = cmd.Flags().GetBool("merge-allow-unrelated-histories")
+	finishOptions.MergeBaseOverride, _ = cmd.Flags().GetString("merge-base-override")
+	finishOptions.SquashMessage, _ = cmd.Flags().GetString("squash-message")
+	finishOptions.SquashMessageFile, _ = cmd.Flags().GetString("squash-message-file")
+
+	finishOptions.SourceRef, _ = cmd.Flags().GetString("source-ref")
+
+	finishOptions.MinCommits = getIntPtr(cmd, "min-commits")
+	finishOptions.MaxCommits = getIntPtr(cmd, "max-commits")
+	finishOptions.CommitCountGuard = boolFlag("commit-count-guard", "no-commit-count-guard")
+
+	finishOptions.TargetTrackingBranch, _ = cmd.Flags().GetBool("target-tracking-branch")
+
+	finishOptions.TargetRemoteTrackingUpdate, _ = cmd.Flags().GetBool("target-remote-tracking-update")
+	finishOptions.Push = boolFlag("push", "no-push")
+	finishOptions.TagPushSafe = boolFlag("tag-push-force-with-lease", "no-tag-push-force-with-lease")
+	finishOptions.LocalOnly = boolFlag("local-only", "no-local-only")
+
+	finishOptions.SkipEmptyChildren, _ = cmd.Flags().GetBool("skip-empty-children")
+	finishOptions.AutoUpdateChildren = boolFlag("autoupdate-children", "no-autoupdate-children")
+	finishOptions.ChildrenParallel, _ = cmd.Flags().GetBool("children-parallel")
+	finishOptions.KeepHistoryNote, _ = cmd.Flags().GetBool("keep-history-note")
+
+	finishOptions.CommitDate, _ = cmd.Flags().GetString("commit-date")
+
+	finishOptions.EditMergeMessage = boolFlag("merge-message-edit", "no-merge-message-edit")
+
+	finishOptions.AlsoInto, _ = cmd.Flags().GetStringArray("also-into")
+	finishOptions.AlsoIntoStrategy, _ = cmd.Flags().GetString("also-into-strategy")
+
+	finishOptions.PreventDeleteRace, _ = cmd.Flags().GetBool("prevent-fast-forward-delete-race")
+	finishOptions.Idempotent, _ = cmd.Flags().GetBool("idempotent")
+	finishOptions.ReuseExistingMerge, _ = cmd.Flags().GetBool("tag-existing-commit-reuse")
+
+	finishOptions.PostFinishCommand, _ = cmd.Flags().GetString("post-finish-command")
+	finishOptions.IssueCommand, _ = cmd.Flags().GetString("comment-on-issue")
+	finishOptions.EmitEvent, _ = cmd.Flags().GetString("emit-event")
+	finishOptions.OutputFormat, _ = cmd.Flags().GetString("output-format")
+	finishOptions.ReflogMessage, _ = cmd.Flags().GetString("reflog-message")
+	finishOptions.Confirm = boolFlag("confirm", "no-confirm")
+	finishOptions.AssumeYes, _ = cmd.Flags().GetBool("assume-yes")
+	finishOptions.StashUntracked, _ = cmd.Flags().GetBool("stash-untracked")
+	finishOptions.Approve, _ = cmd.Flags().GetString("approve")
+	finishOptions.ListSteps, _ = cmd.Flags().GetBool("list-steps")
+	finishOptions.ValidateOnly, _ = cmd.Flags().GetBool("validate-only")
+	finishOptions.DryRun, _ = cmd.Flags().GetBool("dry-run")
+	finishOptions.JSON, _ = cmd.Flags().GetBool("json")
+	finishOptions.ReportTiming, _ = cmd.Flags().GetBool("report-timing")
+	finishOptions.PrintTag, _ = cmd.Flags().GetBool("print-tag")
+	finishOptions.RecordMetrics, _ = cmd.Flags().GetString("record-metrics")
+	finishOptions.RecordSnapshot = boolFlag("record-base-snapshot", "no-record-base-snapshot")
+	finishOptions.ChildrenReport, _ = cmd.Flags().GetBool("children-strategy-report")
+	finishOptions.Verbose, _ = cmd.Flags().GetBool("verbose")
+	return finishOptions
 }
@@ -25,8 +25,12 @@ If merge conflicts occur, they will be handled according to the configured merge
 		if len(args) > 0 {
 			branchName = args[0]
 		}
-		useRebase, _ := cmd.Flags().GetBool("rebase")
-		if err := executeUpdate("", branchName, useRebase); err != nil {
+		strategy, err := getUpdateStrategyOverride(cmd)
+		if err == nil {
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			err = executeUpdate("", branchName, strategy, getBoolPtr(cmd, "fetch", "no-fetch"), verbose)
+		}
+		if err != nil {
 			var exitCode errors.ExitCode
 			if flowErr, ok := err.(errors.Error); ok {
 				exitCode = flowErr.ExitCode()
@@ -54,8 +58,12 @@ If merge conflicts occur, they will be handled according to the configured merge
 			if len(args) > 0 {
 				name = args[0]
 			}
-			useRebase, _ := cmd.Flags().GetBool("rebase")
-			if err := executeUpdate(branchType, name, useRebase); err != nil {
+			strategy, err := getUpdateStrategyOverride(cmd)
+			if err == nil {
+				verbose, _ := cmd.Flags().GetBool("verbose")
+				err = executeUpdate(branchType, name, strategy, getBoolPtr(cmd, "fetch", "no-fetch"), verbose)
+			}
+			if err != nil {
 				var exitCode errors.ExitCode
 				if flowErr, ok := err.(errors.Error); ok {
 					exitCode = flowErr.ExitCode()
@@ -69,15 +77,17 @@ If merge conflicts occur, they will be handled according to the configured merge
 		},
 	}
 	
-	// Add --rebase flag to the command
-	cmd.Flags().Bool("rebase", false, "Force rebase strategy instead of configured strategy")
+	// Add strategy override and fetch flags to the command
+	addUpdateStrategyFlags(cmd)
+	addUpdateFetchFlags(cmd)
 	
 	return cmd
 }
 
 func init() {
-	// Add --rebase flag to the root update command
-	updateCmd.Flags().Bool("rebase", false, "Force rebase strategy instead of configured strategy")
+	// Add strategy override and fetch flags to the root update command
+	addUpdateStrategyFlags(updateCmd)
+	addUpdateFetchFlags(updateCmd)
 	rootCmd.AddCommand(updateCmd)
 }
 
@@ -86,8 +96,36 @@ func AddUpdateCommand(parentCmd *cobra.Command) {
 	parentCmd.AddCommand(createUpdateCommand(parentCmd.Name()))
 }
 
+// addUpdateStrategyFlags adds the --strategy flag and its --rebase alias to an update command
+func addUpdateStrategyFlags(cmd *cobra.Command) {
+	cmd.Flags().String("strategy", "", "Use the given strategy (merge, rebase or squash) instead of the configured strategy")
+	cmd.Flags().Bool("rebase", false, "Force rebase strategy instead of configured strategy (alias for --strategy rebase)")
+}
+
+// addUpdateFetchFlags adds the --fetch and --no-fetch flags to an update command
+func addUpdateFetchFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("fetch", false, "Fetch the parent branch from the remote before updating")
+	cmd.Flags().Bool("no-fetch", false, "Don't fetch the parent branch from the remote before updating")
+}
+
+// getUpdateStrategyOverride returns the strategy requested with --strategy or --rebase, or "" for the configured one
+func getUpdateStrategyOverride(cmd *cobra.Command) (string, error) {
+	strategy, _ := cmd.Flags().GetString("strategy")
+	useRebase, _ := cmd.Flags().GetBool("rebase")
+	if !useRebase {
+		return strategy, nil
+	}
+	if strategy != "" && strings.ToLower(strategy) != string(config.MergeStrategyRebase) {
+		return "", &errors.InvalidOptionError{Option: "--rebase", Reason: fmt.Sprintf("cannot be combined with --strategy %s", strategy)}
+	}
+	return string(config.MergeStrategyRebase), nil
+}
+
 // executeUpdate updates a branch with changes from its parent branch
-func executeUpdate(branchType string, name string, useRebase bool) error {
+// If strategy is not empty, it overrides the configured downstream strategy
+// If shouldFetch is nil, gitflow.<type>.update.fetch decides whether to fetch the parent first
+// If verbose is set, the summary of what came in lists the commits and the diffstat
+func executeUpdate(branchType string, name string, strategyOverride string, shouldFetch *bool, verbose bool) error {
 	// Validate that git-flow is initialized
 	initialized, err := config.IsInitialized()
 	if err != nil {
@@ -97,6 +135,18 @@ func executeUpdate(branchType string, name string, useRebase bool) error {
 		return &errors.NotInitializedError{}
 	}
 
+	// A rebase or cherry-pick started outside git-flow must be completed first
+	if err := ensureNoGitOperationInProgress("update"); err != nil {
+		return err
+	}
+
+	// Validate the strategy override
+	switch config.MergeStrategy(strings.ToLower(strategyOverride)) {
+	case "", config.MergeStrategyMerge, config.MergeStrategyRebase, config.MergeStrategySquash:
+	default:
+		return &errors.InvalidOptionError{Option: "--strategy", Reason: fmt.Sprintf("unknown strategy '%s', expected merge, rebase or squash", strategyOverride)}
+	}
+
 	// Get configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -159,24 +209,48 @@ func executeUpdate(branchType string, name string, useRebase bool) error {
 
 	// Get branch configuration for merge strategy
 	var strategy string
+	configType := branchType
 	for branchKey, bc := range cfg.Branches {
 		if bc.Type == string(config.BranchTypeBase) && branchKey == branchName {
 			strategy = bc.DownstreamStrategy
+			configType = branchKey
 			break
 		}
 		if bc.Type == string(config.BranchTypeTopic) && bc.Prefix != "" && strings.HasPrefix(branchName, bc.Prefix) {
 			strategy = bc.DownstreamStrategy
+			configType = branchKey
 			break
 		}
 	}
 
+	// Determine if we should fetch
+	fetch := false
+	if shouldFetch != nil {
+		// Command line flag takes precedence
+		fetch = *shouldFetch
+	} else if configType != "" {
+		// Check config if not specified
+		fetchConfig, err := git.GetConfig(fmt.Sprintf("gitflow.%s.update.fetch", configType))
+		if err == nil && fetchConfig == "true" {
+			fetch = true
+		}
+	}
+
+	// Bring the local parent up to date with its remote-tracking branch before updating from it
+	if fetch {
+		fmt.Printf("Fetching from %s...\n", cfg.Remote)
+		if err := git.FetchAndFastForward(cfg.Remote, parentBranch); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+
 	if strategy == "" {
 		strategy = "merge" // Default to merge if no strategy configured
 	}
 
-	// Override strategy if --rebase flag is set
-	if useRebase {
-		strategy = "rebase"
+	// Override strategy if --strategy or --rebase is set
+	if strategyOverride != "" {
+		strategy = strings.ToLower(strategyOverride)
 	}
 
 	// Create merge state
@@ -189,14 +263,108 @@ func executeUpdate(branchType string, name string, useRebase bool) error {
 		FullBranchName: branchName,
 	}
 
+	// Leave a resumable state behind when interrupted
+	stopWatching := watchInterrupts()
+	defer stopWatching()
+	if err := setInterruptState(state); err != nil {
+		return &errors.GitError{Operation: "register merge state", Err: err}
+	}
+
+	// Remember the tip of the branch to summarize what the update brings in
+	before, err := git.ResolveCommit(branchName)
+	if err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("resolve branch '%s'", branchName), Err: err}
+	}
+
 	// Update the branch using shared logic
-	return update.UpdateBranchFromParent(branchName, parentBranch, strategy, true, state)
+	if err := update.UpdateBranchFromParent(branchName, parentBranch, strategy, true, state); err != nil {
+		return err
+	}
+	printUpdateSummary(parentBranch, before, verbose)
+
+	// A recorded start commit moves to the parent commit the branch now contains
+	if _, err := git.GetConfig(fmt.Sprintf("gitflow.branch.%s.startcommit", branchName)); err == nil {
+		if err := recordStartCommit(branchName, parentBranch); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to update start commit in config: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// updateStateStale reports whether the state is that of an update whose merge or rebase git no longer
+// has in progress, because it was completed or aborted with git itself. Nothing clears the state then.
+func updateStateStale(state *mergestate.MergeState) (bool, error) {
+	if state.Action != "update" || git.HasMergeHead() || git.HasConflicts() {
+		return false, nil
+	}
+	operation, err := git.OperationInProgress()
+	if err != nil {
+		return false, &errors.GitError{Operation: "check for operations in progress", Err: err}
+	}
+	return operation == "", nil
+}
+
+// clearStaleUpdateState removes the merge state of an update that git has completed or aborted, so that
+// it no longer blocks other operations
+func clearStaleUpdateState() error {
+	state, err := mergestate.LoadMergeState()
+	if err != nil {
+		return &errors.GitError{Operation: "load merge state", Err: err}
+	}
+	if state == nil {
+		return nil
+	}
+	stale, err := updateStateStale(state)
+	if err != nil || !stale {
+		return err
+	}
+	if err := mergestate.ClearMergeState(); err != nil {
+		return &errors.GitError{Operation: "clear merge state", Err: err}
+	}
+	return nil
+}
+
+// printUpdateSummary prints how many commits came in from the parent and how many files they changed,
+// relative to before, the tip of the branch before the update. Verbose output lists the commits and the diffstat.
+func printUpdateSummary(parentBranch string, before string, verbose bool) {
+	commits, err := git.CountCommits(before, parentBranch)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to count commits pulled in from '%s': %v\n", parentBranch, err)
+		return
+	}
+	if commits == 0 {
+		fmt.Printf("No new commits from '%s'\n", parentBranch)
+		return
+	}
+	files, err := git.ChangedFiles(before, parentBranch)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to list files changed by '%s': %v\n", parentBranch, err)
+		return
+	}
+	fmt.Printf("Pulled in %d commit(s) from '%s' changing %d file(s)\n", commits, parentBranch, len(files))
+
+	if !verbose {
+		return
+	}
+	log, err := git.Log(before, parentBranch)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to log commits pulled in from '%s': %v\n", parentBranch, err)
+		return
+	}
+	fmt.Print(log)
+	stat, err := git.DiffStat(before, parentBranch)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to show changes pulled in from '%s': %v\n", parentBranch, err)
+		return
+	}
+	fmt.Print(stat)
 }
 
 func updateWithMerge(branchName, parentBranch string) error {
 	// Merge parent branch
 	if err := git.Merge(parentBranch); err != nil {
 		if strings.Contains(err.Error(), "merge conflict") {
+			printConflicts(branchName, parentBranch)
 			fmt.Printf("Merge conflicts detected. Please resolve them and then:\n")
 			fmt.Printf("1. git add <resolved-files>\n")
 			fmt.Printf("2. git commit\n")
@@ -216,6 +384,7 @@ func updateWithRebase(branchName, parentBranch string) error {
 	// Rebase onto parent branch
 	if err := git.Rebase(parentBranch); err != nil {
 		if strings.Contains(err.Error(), "rebase conflict") {
+			printConflicts(branchName, parentBranch)
 			fmt.Printf("Rebase conflicts detected. Please resolve them and then:\n")
 			fmt.Printf("1. git add <resolved-files>\n")
 			fmt.Printf("2. git rebase --continue\n")
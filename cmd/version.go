@@ -2,28 +2,92 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
 
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/version"
 	"github.com/spf13/cobra"
 )
 
-// Version information
-var (
-	Version   = "0.1.0"
-	BuildDate = "unknown"
-	GitCommit = "unknown"
-)
-
+// versionCmd represents the version command
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Show version information",
-	Long:  `Display version information for git-flow-next.`,
+	Long: `Display the version of git-flow-next and, inside a repository where git-flow is initialized,
+the version of its git-flow configuration (gitflow.version).`,
+	Args: cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("git-flow-next version %s\n", Version)
-		fmt.Printf("Build date: %s\n", BuildDate)
-		fmt.Printf("Git commit: %s\n", GitCommit)
+		VersionCommand()
 	},
 }
 
+// VersionCommand is the implementation of the version command and the --version flag
+func VersionCommand() {
+	if err := printVersion(); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(int(exitCode))
+	}
+}
+
+// printVersion prints the version of the binary and of the repository's configuration, and returns any errors.
+// Outside a repository, or in one where git-flow is not initialized, only the version of the binary is printed.
+func printVersion() error {
+	fmt.Printf("git-flow-next version %s\n", version.GetVersionInfo())
+
+	initialized, err := config.IsInitialized()
+	if err != nil || !initialized {
+		return nil
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return &errors.GitError{Operation: "load configuration", Err: err}
+	}
+	fmt.Printf("Config version: %s\n", cfg.Version)
+	if compareVersions(cfg.Version, config.SchemaVersion) < 0 {
+		fmt.Printf("The configuration of this repository is older than version %s, which this git-flow uses; run 'git flow config doctor --fix' to upgrade it\n", config.SchemaVersion)
+	}
+	return nil
+}
+
+// compareVersions compares two dotted version numbers part by part and returns -1, 0 or 1. Parts that are
+// not numbers are compared as text, missing parts count as 0.
+func compareVersions(a string, b string) int {
+	partsA, partsB := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(partsA) || i < len(partsB); i++ {
+		partA, partB := "0", "0"
+		if i < len(partsA) {
+			partA = partsA[i]
+		}
+		if i < len(partsB) {
+			partB = partsB[i]
+		}
+		numberA, errA := strconv.Atoi(partA)
+		numberB, errB := strconv.Atoi(partB)
+		switch {
+		case errA == nil && errB == nil && numberA != numberB:
+			if numberA < numberB {
+				return -1
+			}
+			return 1
+		case (errA != nil || errB != nil) && partA != partB:
+			if partA < partB {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
 func init() {
 	rootCmd.AddCommand(versionCmd)
 }
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/mergestate"
+)
+
+// webhookTimeout bounds how long a finish waits for the webhook endpoint
+const webhookTimeout = 5 * time.Second
+
+// webhookSignatureHeader carries the HMAC-SHA256 of the payload when a secret is configured
+const webhookSignatureHeader = "X-GitFlow-Signature"
+
+// Results reported in the finish event
+const (
+	finishEventSuccess    = "success"     // the finish completed
+	finishEventConflict   = "conflict"    // the finish stopped on a conflict and waits for --continue
+	finishEventPushFailed = "push-failed" // the finish completed locally, but pushing failed
+	finishEventAborted    = "aborted"     // the finish was aborted with --abort
+)
+
+// finishEvent is the JSON payload posted to the finish webhook
+type finishEvent struct {
+	Type     string `json:"type"`
+	Branch   string `json:"branch"`
+	Target   string `json:"target"`
+	Tag      string `json:"tag,omitempty"`
+	Strategy string `json:"strategy"`
+	Result   string `json:"result"`
+}
+
+// webhookSecretEnv names the environment variable that overrides gitflow.<type>.finish.webhooksecret.
+// There is no flag for the secret, as command lines end up in the process list and the shell history.
+const webhookSecretEnv = "GITFLOW_WEBHOOK_SECRET"
+
+// emitFinishEvent posts the outcome of the finish to the webhook set by --emit-event or
+// gitflow.<type>.finish.webhook, signed with the webhook secret if one is set. A URL given with
+// --emit-event when the finish started is kept in the merge state, so --continue and --abort
+// report to it as well.
+func emitFinishEvent(state *mergestate.MergeState, finishOptions *FinishOptions, result string) {
+	// 1. Check branch-specific config
+	url, _ := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.webhook", state.BranchType))
+	secret, _ := git.GetConfig(fmt.Sprintf("gitflow.%s.finish.webhooksecret", state.BranchType))
+
+	// 2. The command-line flag overrides the URL, the environment the secret
+	if state.Webhook != "" {
+		url = state.Webhook
+	}
+	if finishOptions != nil && finishOptions.EmitEvent != "" {
+		url = finishOptions.EmitEvent
+	}
+	if envSecret := os.Getenv(webhookSecretEnv); envSecret != "" {
+		secret = envSecret
+	}
+
+	if url == "" {
+		return
+	}
+
+	payload, err := json.Marshal(finishEvent{
+		Type:     state.BranchType,
+		Branch:   state.FullBranchName,
+		Target:   state.ParentBranch,
+		Tag:      state.TagName,
+		Strategy: strings.ToLower(state.MergeStrategy),
+		Result:   result,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not encode webhook payload: %v\n", err)
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: invalid webhook URL '%s': %v\n", url, err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(payload)
+		req.Header.Set(webhookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	client := &http.Client{Timeout: webhookTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: webhook request failed: %v\n", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		fmt.Fprintf(os.Stderr, "Warning: webhook returned %s\n", resp.Status)
+	}
+}
+
+// emitFinishConflictEvent reports a finish that stopped on a conflict to the webhook. The conflict
+// can surface in any step, so it is detected from the error and the saved merge state.
+func emitFinishConflictEvent(err error, finishOptions *FinishOptions) {
+	if _, ok := err.(*errors.UnresolvedConflictsError); !ok {
+		return
+	}
+	state, loadErr := mergestate.LoadMergeState()
+	if loadErr != nil || state == nil {
+		return
+	}
+	emitFinishEvent(state, finishOptions, finishEventConflict)
+}
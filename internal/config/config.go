@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -15,6 +16,9 @@ import (
 // Types and constants
 //
 
+// SchemaVersion is the version of the configuration schema, stored in gitflow.version
+const SchemaVersion = "1.0"
+
 // Config represents the git-flow configuration
 type Config struct {
 	Version  string
@@ -33,6 +37,7 @@ type BranchConfig struct {
 	AutoUpdate         bool
 	Tag                bool   // whether to create a tag when finishing
 	TagPrefix          string // prefix to use for tag names
+	Protected          bool   // whether merging into the branch requires explicit approval
 }
 
 // MergeStrategy represents the strategy for merging branches
@@ -78,7 +83,7 @@ type ConfigOverrides struct {
 // DefaultConfig returns a default git-flow configuration
 func DefaultConfig() *Config {
 	return &Config{
-		Version: "1.0",
+		Version: SchemaVersion,
 		Remote:  "origin", // Default remote name
 		Branches: map[string]BranchConfig{
 			"main": {
@@ -247,6 +252,9 @@ func LoadConfig() (*Config, error) {
 		if tag, ok := properties["tag"]; ok {
 			branchConfig.Tag = tag == "true"
 		}
+		if protected, ok := properties["protected"]; ok {
+			branchConfig.Protected = protected == "true"
+		}
 
 		// Handle tag prefix
 		if tagPrefix, ok := properties["tagprefix"]; ok {
@@ -499,6 +507,82 @@ func ApplyOverrides(cfg *Config, overrides ConfigOverrides) *Config {
 	return cfg
 }
 
+// RenameBaseBranch renames a base branch in the configuration and updates
+// every branch that references it as parent or start point.
+func RenameBaseBranch(cfg *Config, oldName string, newName string) *Config {
+	baseConfig, ok := cfg.Branches[oldName]
+	if !ok {
+		return cfg
+	}
+	delete(cfg.Branches, oldName)
+	cfg.Branches[newName] = baseConfig
+
+	// Update all branches that reference the old name
+	for name, branch := range cfg.Branches {
+		if branch.Parent == oldName {
+			branch.Parent = newName
+			cfg.Branches[name] = branch
+		}
+		if branch.StartPoint == oldName {
+			branch.StartPoint = newName
+			cfg.Branches[name] = branch
+		}
+	}
+
+	return cfg
+}
+
+// FindOrphanedBranches returns the branches, sorted by name, whose parent or start point
+// is not a configured branch.
+func FindOrphanedBranches(cfg *Config) []string {
+	var orphaned []string
+	for name, branch := range cfg.Branches {
+		_, parentOK := cfg.Branches[branch.Parent]
+		_, startOK := cfg.Branches[branch.StartPoint]
+		if (branch.Parent != "" && !parentOK) || (branch.StartPoint != "" && !startOK) {
+			orphaned = append(orphaned, name)
+		}
+	}
+	sort.Strings(orphaned)
+	return orphaned
+}
+
+//
+// Validation functions
+//
+
+// PrefixConflict describes two topic branch types whose prefixes overlap
+type PrefixConflict struct {
+	TypeA   string // Name of the first branch type
+	PrefixA string // Prefix of the first branch type
+	TypeB   string // Name of the second branch type
+	PrefixB string // Prefix of the second branch type
+}
+
+// FindPrefixConflicts returns the pairs of topic branch types whose prefixes are equal or where
+// one prefix starts with the other. Branches of such types cannot be told apart by their name.
+func FindPrefixConflicts(cfg *Config) []PrefixConflict {
+	var names []string
+	for name, branchConfig := range cfg.Branches {
+		if branchConfig.Type == string(BranchTypeTopic) && branchConfig.Prefix != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var conflicts []PrefixConflict
+	for i, nameA := range names {
+		for _, nameB := range names[i+1:] {
+			prefixA := cfg.Branches[nameA].Prefix
+			prefixB := cfg.Branches[nameB].Prefix
+			if strings.HasPrefix(prefixA, prefixB) || strings.HasPrefix(prefixB, prefixA) {
+				conflicts = append(conflicts, PrefixConflict{TypeA: nameA, PrefixA: prefixA, TypeB: nameB, PrefixB: prefixB})
+			}
+		}
+	}
+	return conflicts
+}
+
 //
 // Writing and saving functions
 //
@@ -580,6 +664,14 @@ func SaveConfig(config *Config) error {
 				return fmt.Errorf("failed to set tag prefix for %s: %w", branchName, err)
 			}
 		}
+
+		// Set protection only if true (false is default)
+		if branchConfig.Protected {
+			err = git.SetConfig(fmt.Sprintf("gitflow.branch.%s.protected", branchName), "true")
+			if err != nil {
+				return fmt.Errorf("failed to set protection for %s: %w", branchName, err)
+			}
+		}
 	}
 
 	return nil
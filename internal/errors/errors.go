@@ -1,6 +1,9 @@
 package errors
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // ExitCode represents the process exit code
 type ExitCode int
@@ -18,6 +21,8 @@ const (
 	ExitCodeBranchExists ExitCode = 4
 	// ExitCodeBranchNotFound indicates a required branch does not exist
 	ExitCodeBranchNotFound ExitCode = 5
+	// ExitCodeInterrupted indicates the operation was interrupted and can be resumed
+	ExitCodeInterrupted ExitCode = 130
 )
 
 // Error is the base interface for all git-flow errors
@@ -66,6 +71,20 @@ func (e *InvalidBranchTypeError) ExitCode() ExitCode {
 	return ExitCodeInvalidInput
 }
 
+// InvalidOptionError indicates an option was used in a way that is not supported
+type InvalidOptionError struct {
+	Option string
+	Reason string
+}
+
+func (e *InvalidOptionError) Error() string {
+	return fmt.Sprintf("invalid option %s: %s", e.Option, e.Reason)
+}
+
+func (e *InvalidOptionError) ExitCode() ExitCode {
+	return ExitCodeInvalidInput
+}
+
 // BranchExistsError indicates a branch already exists
 type BranchExistsError struct {
 	BranchName string
@@ -79,6 +98,80 @@ func (e *BranchExistsError) ExitCode() ExitCode {
 	return ExitCodeBranchExists
 }
 
+// TagExistsError indicates a tag that finish would create already exists
+type TagExistsError struct {
+	TagName    string
+	SameCommit bool // Whether the existing tag points at the commit that would be tagged
+}
+
+func (e *TagExistsError) Error() string {
+	if e.SameCommit {
+		return fmt.Sprintf("tag '%s' already exists at the commit to tag. Run without --no-tag-reuse to reuse it, or use --force-tag to replace it", e.TagName)
+	}
+	return fmt.Sprintf("tag '%s' already exists at a different commit. Use --force-tag to replace it or --rename-tag-if-exists to pick another name", e.TagName)
+}
+
+func (e *TagExistsError) ExitCode() ExitCode {
+	return ExitCodeGitError
+}
+
+// PublishedTagError indicates --force-tag would replace a tag that was already pushed to the remote
+type PublishedTagError struct {
+	TagName string
+	Remote  string
+}
+
+func (e *PublishedTagError) Error() string {
+	return fmt.Sprintf("tag '%s' was already pushed to '%s'. Replacing a published tag needs --force-remote-tag in addition to --force-tag", e.TagName, e.Remote)
+}
+
+func (e *PublishedTagError) ExitCode() ExitCode {
+	return ExitCodeGitError
+}
+
+// TagMovedError indicates a replaced tag was not pushed because the remote tag no longer points
+// to the object it was replaced from
+type TagMovedError struct {
+	TagName  string
+	Remote   string
+	Expected string
+	Actual   string
+}
+
+func (e *TagMovedError) Error() string {
+	return fmt.Sprintf("tag '%s' on '%s' was moved to %s since it was replaced from %s; not overwriting it", e.TagName, e.Remote, e.Actual, e.Expected)
+}
+
+func (e *TagMovedError) ExitCode() ExitCode {
+	return ExitCodeGitError
+}
+
+// CommitCountError indicates a finish was refused because the branch has fewer or more unique commits
+// than the configured limit
+type CommitCountError struct {
+	BranchName   string
+	TargetBranch string
+	Count        int
+	Limit        int
+	Maximum      bool   // Whether Limit is the maximum rather than the minimum
+	Key          string // Config key the limit comes from, empty if it was given as a flag
+}
+
+func (e *CommitCountError) Error() string {
+	bound := fmt.Sprintf("fewer than the minimum of %d", e.Limit)
+	if e.Maximum {
+		bound = fmt.Sprintf("more than the maximum of %d", e.Limit)
+	}
+	if e.Key != "" {
+		bound += fmt.Sprintf(" set in %s", e.Key)
+	}
+	return fmt.Sprintf("branch '%s' has %d commits not in '%s', %s; use --no-commit-count-guard to finish it anyway", e.BranchName, e.Count, e.TargetBranch, bound)
+}
+
+func (e *CommitCountError) ExitCode() ExitCode {
+	return ExitCodeInvalidInput
+}
+
 // BranchNotFoundError indicates a required branch does not exist
 type BranchNotFoundError struct {
 	BranchName string
@@ -92,6 +185,30 @@ func (e *BranchNotFoundError) ExitCode() ExitCode {
 	return ExitCodeBranchNotFound
 }
 
+// BaseBranchFinishError indicates an attempt to finish a base branch as if it were a topic branch
+type BaseBranchFinishError struct {
+	BranchName   string
+	TargetBranch string
+	RefersTo     string // Branch that BranchName is another name for (e.g. a symbolic ref to it), if any
+}
+
+func (e *BaseBranchFinishError) Error() string {
+	if e.BranchName == e.TargetBranch {
+		return fmt.Sprintf("cannot finish '%s': it is the target branch of this finish and cannot be merged into itself", e.BranchName)
+	}
+	if e.RefersTo != "" && e.RefersTo == e.TargetBranch {
+		return fmt.Sprintf("cannot finish '%s': it refers to the target branch '%s' and cannot be merged into itself", e.BranchName, e.RefersTo)
+	}
+	if e.RefersTo != "" {
+		return fmt.Sprintf("cannot finish '%s': it refers to the base branch '%s', only topic branches can be finished", e.BranchName, e.RefersTo)
+	}
+	return fmt.Sprintf("cannot finish '%s': it is a base branch, only topic branches can be finished", e.BranchName)
+}
+
+func (e *BaseBranchFinishError) ExitCode() ExitCode {
+	return ExitCodeInvalidInput
+}
+
 // GitError indicates a Git operation failed
 type GitError struct {
 	Operation string
@@ -134,6 +251,67 @@ func (e *NoMergeInProgressError) ExitCode() uint8 {
 	return 1
 }
 
+// InconsistentMergeStateError indicates that the saved finish step does not match the state of the repository
+type InconsistentMergeStateError struct {
+	Step       string
+	Reason     string
+	BranchType string
+	BranchName string
+}
+
+func (e *InconsistentMergeStateError) Error() string {
+	return fmt.Sprintf("saved finish step '%s' does not match the repository: %s. "+
+		"Complete or discard the pending git operation (e.g. 'git merge --abort'), or run 'git flow %s finish --abort %s' to start over",
+		e.Step, e.Reason, e.BranchType, e.BranchName)
+}
+
+func (e *InconsistentMergeStateError) ExitCode() ExitCode {
+	return ExitCodeGitError
+}
+
+// UncommittedChangesError indicates that tracked files have uncommitted changes
+type UncommittedChangesError struct {
+	Operation string
+}
+
+func (e *UncommittedChangesError) Error() string {
+	return fmt.Sprintf("cannot %s: working tree has uncommitted changes to tracked files. Commit or stash them first", e.Operation)
+}
+
+func (e *UncommittedChangesError) ExitCode() ExitCode {
+	return ExitCodeGitError
+}
+
+// GitOperationInProgressError indicates that a git operation started outside git-flow is stopped halfway
+type GitOperationInProgressError struct {
+	Operation    string // git-flow operation that was refused
+	GitOperation string // git operation in progress, e.g. rebase or cherry-pick
+}
+
+func (e *GitOperationInProgressError) Error() string {
+	return fmt.Sprintf("cannot %s: a git %s is in progress. Finish it with 'git %s --continue' or cancel it with 'git %s --abort' first",
+		e.Operation, e.GitOperation, e.GitOperation, e.GitOperation)
+}
+
+func (e *GitOperationInProgressError) ExitCode() ExitCode {
+	return ExitCodeGitError
+}
+
+// BranchInOtherWorktreeError indicates that a branch is checked out in another worktree
+type BranchInOtherWorktreeError struct {
+	BranchName string
+	Path       string
+}
+
+func (e *BranchInOtherWorktreeError) Error() string {
+	return fmt.Sprintf("branch '%s' is checked out in another worktree at '%s'. "+
+		"Switch that worktree to a different branch or run the command there", e.BranchName, e.Path)
+}
+
+func (e *BranchInOtherWorktreeError) ExitCode() ExitCode {
+	return ExitCodeGitError
+}
+
 // InvalidBranchNameError represents an error when an invalid branch name is provided
 type InvalidBranchNameError struct {
 	Name string
@@ -147,6 +325,95 @@ func (e *InvalidBranchNameError) ExitCode() uint8 {
 	return 1
 }
 
+// OperationCancelledError indicates that the user declined a confirmation prompt
+type OperationCancelledError struct{}
+
+func (e *OperationCancelledError) Error() string {
+	return "operation cancelled by user"
+}
+
+func (e *OperationCancelledError) ExitCode() ExitCode {
+	return ExitCodeInvalidInput
+}
+
+// NotTopicBranchError indicates that a branch does not match the prefix of any topic branch type
+type NotTopicBranchError struct {
+	BranchName string
+}
+
+func (e *NotTopicBranchError) Error() string {
+	return fmt.Sprintf("branch '%s' is not a valid topic branch (use explicit command, e.g., git flow feature finish)", e.BranchName)
+}
+
+func (e *NotTopicBranchError) ExitCode() ExitCode {
+	return ExitCodeInvalidInput
+}
+
+// AmbiguousBranchError indicates that a branch matches the prefixes of several topic branch types
+type AmbiguousBranchError struct {
+	BranchName string
+	Types      []string // Branch types whose prefix matches, sorted
+}
+
+func (e *AmbiguousBranchError) Error() string {
+	return fmt.Sprintf("ambiguous branch '%s' matches multiple types (%s). Use an explicit command, e.g., git flow %s finish",
+		e.BranchName, strings.Join(e.Types, ", "), e.Types[0])
+}
+
+func (e *AmbiguousBranchError) ExitCode() ExitCode {
+	return ExitCodeInvalidInput
+}
+
+// TagNotReservedError indicates that finalize was asked to replace a tag that was not reserved
+type TagNotReservedError struct {
+	TagName string
+}
+
+func (e *TagNotReservedError) Error() string {
+	return fmt.Sprintf("tag '%s' is not reserved. Reserve it with 'finish --reserve-tag' first", e.TagName)
+}
+
+func (e *TagNotReservedError) ExitCode() ExitCode {
+	return ExitCodeInvalidInput
+}
+
+// BranchMovedError indicates that a finished branch received new commits after it was merged
+type BranchMovedError struct {
+	BranchType  string
+	BranchName  string // Short name, as passed to finish
+	FullName    string
+	MergedHead  string
+	CurrentHead string
+}
+
+func (e *BranchMovedError) Error() string {
+	return fmt.Sprintf("branch '%s' moved from %s to %s after it was merged and was not deleted. "+
+		"Run 'git flow %s finish --continue %s --force-delete' to delete it anyway, or add --keeplocal to keep it",
+		e.FullName, e.MergedHead, e.CurrentHead, e.BranchType, e.BranchName)
+}
+
+func (e *BranchMovedError) ExitCode() ExitCode {
+	return ExitCodeGitError
+}
+
+// ConfigProblemsError indicates that the git-flow configuration has problems that were not fixed
+type ConfigProblemsError struct {
+	Count   int // Number of problems left
+	Fixable int // Number of the problems left that config doctor --fix can repair
+}
+
+func (e *ConfigProblemsError) Error() string {
+	message := fmt.Sprintf("the git-flow configuration has %d problem(s)", e.Count)
+	if e.Fixable > 0 {
+		message += fmt.Sprintf(". Run 'git flow config doctor --fix' to repair %d of them", e.Fixable)
+	}
+	return message
+}
+
+func (e *ConfigProblemsError) ExitCode() ExitCode {
+	return ExitCodeInvalidInput
+}
+
 // UnresolvedConflictsError represents an error when there are unresolved conflicts
 type UnresolvedConflictsError struct{}
 
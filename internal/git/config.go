@@ -27,6 +27,27 @@ func GetConfigInDir(dir, key string) (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
+// GetConfigValues gets all values of a multi-valued Git config key, in configuration order
+func GetConfigValues(key string) ([]string, error) {
+	cmd := exec.Command("git", "config", "--get-all", key)
+	output, err := cmd.Output()
+	if err != nil {
+		// If the key is not set, don't treat it as an error
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("failed to get git config %s: %w", key, err)
+	}
+
+	values := []string{}
+	for _, line := range strings.Split(string(output), "\n") {
+		if line != "" {
+			values = append(values, strings.TrimSpace(line))
+		}
+	}
+	return values, nil
+}
+
 // SetConfig sets a Git config value
 func SetConfig(key string, value string) error {
 	cmd := exec.Command("git", "config", key, value)
@@ -64,6 +85,26 @@ func GetAllConfig(pattern string) (map[string]string, error) {
 	return config, nil
 }
 
+// RemoveConfigSection removes a Git config section with all of its keys
+func RemoveConfigSection(section string) error {
+	cmd := exec.Command("git", "config", "--remove-section", section)
+	_, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to remove git config section %s: %w", section, err)
+	}
+	return nil
+}
+
+// RenameConfigSection renames a Git config section, keeping all of its values
+func RenameConfigSection(oldSection string, newSection string) error {
+	cmd := exec.Command("git", "config", "--rename-section", oldSection, newSection)
+	_, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to rename git config section %s to %s: %w", oldSection, newSection, err)
+	}
+	return nil
+}
+
 // UnsetConfig unsets a Git config value
 func UnsetConfig(key string) error {
 	cmd := exec.Command("git", "config", "--unset", key)
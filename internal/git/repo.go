@@ -1,9 +1,12 @@
 package git
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -44,6 +47,37 @@ func BranchExists(branch string) error {
 	return nil
 }
 
+// ResolveCommit resolves any ref (branch, tag, SHA) to the SHA of the commit it points to
+func ResolveCommit(ref string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("ref '%s' does not point to a commit", ref)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ResolveObject resolves any ref or object name to the SHA of the object it names, which may
+// also be a tree, blob or tag
+func ResolveObject(ref string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", ref+"^{object}")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("ref '%s' does not name an object", ref)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ShowFile returns the contents of a file as stored in the given ref
+func ShowFile(ref string, path string) (string, error) {
+	cmd := exec.Command("git", "show", ref+":"+path)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("file '%s' does not exist in '%s'", path, ref)
+	}
+	return string(output), nil
+}
+
 // CreateBranch creates a new branch
 func CreateBranch(name string, startPoint string) error {
 	// Check if we have any commits
@@ -78,6 +112,58 @@ func CreateBranch(name string, startPoint string) error {
 	return nil
 }
 
+// CommitParents returns the parents of a commit, in order
+func CommitParents(commit string) ([]string, error) {
+	cmd := exec.Command("git", "rev-list", "--parents", "-n", "1", commit)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parents of '%s': %w", commit, err)
+	}
+	fields := strings.Fields(string(output))
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("commit '%s' not found", commit)
+	}
+	return fields[1:], nil
+}
+
+// CreateBranchRef creates a branch pointing at the commit with git update-ref, without checking it
+// out or touching the working tree. It fails if the branch already exists.
+func CreateBranchRef(name string, commit string) error {
+	cmd := exec.Command("git", "update-ref", "-m", "branch: Created from "+commit, "refs/heads/"+name, commit, "")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to create branch '%s' at '%s': %s: %w", name, commit, strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// CreateTrackingBranch creates a local branch from a remote-tracking branch without checking it out
+func CreateTrackingBranch(branch string, upstream string) error {
+	cmd := exec.Command("git", "branch", "--track", branch, upstream)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to create branch '%s' from '%s': %s: %w", branch, upstream, strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// ResetBranch points a branch that is not checked out at the given ref
+func ResetBranch(branch string, ref string) error {
+	cmd := exec.Command("git", "branch", "-f", branch, ref)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to move branch '%s' to '%s': %s: %w", branch, ref, strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// ResetHead moves the checked out branch to the given ref with git reset --keep, which keeps local
+// changes and refuses to overwrite them
+func ResetHead(ref string) error {
+	cmd := exec.Command("git", "reset", "--keep", ref)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to reset to '%s': %s: %w", ref, strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
 // Checkout checks out a branch
 func Checkout(branch string) error {
 	cmd := exec.Command("git", "checkout", branch)
@@ -149,9 +235,41 @@ func CreateInitialCommit(branch string) error {
 	return nil
 }
 
+// MergeOptions contains options for merging a branch into the current branch
+type MergeOptions struct {
+	StrategyOptions []string // Values passed to git merge as -X <option> (e.g. "ours", "theirs")
+	CommitDate      string   // Author and committer date of the created commit (optional)
+
+	AllowUnrelatedHistories bool // Whether to merge histories that share no common ancestor
+
+	SquashBase string // Commit a squash merge takes the changes from, instead of the merge base git finds (optional)
+
+	EditMessage bool   // Open the editor on the commit message before committing (optional)
+	Message     string // Commit message, used as the template for the editor with EditMessage (optional)
+
+	ReflogAction string // Label of the reflog entries written by the merge, passed as GIT_REFLOG_ACTION (optional)
+}
+
 // Merge merges a branch into the current branch
 func Merge(branch string) error {
-	cmd := exec.Command("git", "merge", "--no-ff", branch)
+	return MergeWithOptions(branch, nil)
+}
+
+// MergeWithOptions merges a branch into the current branch using the given options
+func MergeWithOptions(branch string, options *MergeOptions) error {
+	args := []string{"merge", "--no-ff"}
+	editMessage := options != nil && options.EditMessage
+	if editMessage {
+		// Stop before committing, so that conflicts are detected before the editor opens
+		args = append(args, "--no-commit")
+	} else if options != nil && options.Message != "" {
+		args = append(args, "-m", options.Message)
+	}
+	args = append(args, mergeOptionArgs(options)...)
+	args = append(args, branch)
+
+	cmd := exec.Command("git", args...)
+	cmd.Env = mergeOptionEnv(options)
 	output, err := cmd.CombinedOutput()
 	outputStr := string(output)
 
@@ -171,9 +289,69 @@ func Merge(branch string) error {
 		return fmt.Errorf("failed to merge branch: %s", outputStr)
 	}
 
+	if editMessage && HasMergeHead() {
+		return commitWithEditor(options.Message, mergeOptionEnv(options))
+	}
 	return nil
 }
 
+// commitWithEditor commits the staged changes after opening the editor on message, with the
+// editor attached to the terminal
+func commitWithEditor(message string, env []string) error {
+	args := []string{"commit", "--edit"}
+	if message != "" {
+		args = append(args, "-m", message)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to commit with the edited message: %w", err)
+	}
+	return nil
+}
+
+// commitDateEnv returns the environment for a git command that creates commits dated date
+func commitDateEnv(date string) []string {
+	if date == "" {
+		return nil
+	}
+	return append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+}
+
+// mergeOptionEnv returns the environment for a git command that commits with the date and reflog
+// action of the merge options, or nil to inherit the environment if they set neither
+func mergeOptionEnv(options *MergeOptions) []string {
+	if options == nil {
+		return nil
+	}
+	env := commitDateEnv(options.CommitDate)
+	if options.ReflogAction != "" {
+		if env == nil {
+			env = os.Environ()
+		}
+		env = append(env, "GIT_REFLOG_ACTION="+options.ReflogAction)
+	}
+	return env
+}
+
+// mergeOptionArgs converts merge options into git merge arguments
+func mergeOptionArgs(options *MergeOptions) []string {
+	args := []string{}
+	if options == nil {
+		return args
+	}
+	for _, option := range options.StrategyOptions {
+		args = append(args, "-X", option)
+	}
+	if options.AllowUnrelatedHistories {
+		args = append(args, "--allow-unrelated-histories")
+	}
+	return args
+}
+
 // Rebase rebases the current branch onto another branch
 func Rebase(branch string) error {
 	cmd := exec.Command("git", "rebase", branch)
@@ -189,22 +367,89 @@ func Rebase(branch string) error {
 
 // SquashMerge performs a squash merge of a branch into the current branch
 func SquashMerge(branch string) error {
-	cmd := exec.Command("git", "merge", "--squash", branch)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		if strings.Contains(string(output), "conflict") {
-			return fmt.Errorf("squash merge conflict: %s", string(output))
+	return SquashMergeWithOptions(branch, nil)
+}
+
+// SquashMergeWithOptions performs a squash merge of a branch into the current branch using the given options
+func SquashMergeWithOptions(branch string, options *MergeOptions) error {
+	if options != nil && options.SquashBase != "" {
+		if err := squashFromBase(branch, options.SquashBase); err != nil {
+			return err
+		}
+	} else {
+		args := []string{"merge", "--squash"}
+		args = append(args, mergeOptionArgs(options)...)
+		args = append(args, branch)
+
+		cmd := exec.Command("git", args...)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			if strings.Contains(string(output), "conflict") {
+				return fmt.Errorf("squash merge conflict: %s", string(output))
+			}
+			return fmt.Errorf("failed to squash merge branch: %s", string(output))
 		}
-		return fmt.Errorf("failed to squash merge branch: %s", string(output))
 	}
 
 	// Commit the squashed changes
-	cmd = exec.Command("git", "commit", "-m", fmt.Sprintf("Squashed commit of branch '%s'", branch))
-	output, err = cmd.CombinedOutput()
+	if options != nil && options.EditMessage {
+		return commitWithEditor(options.Message, mergeOptionEnv(options))
+	}
+	message := fmt.Sprintf("Squashed commit of branch '%s'", branch)
+	if options != nil && options.Message != "" {
+		message = options.Message
+	}
+	return commitStaged(message, mergeOptionEnv(options))
+}
+
+// CommitStaged commits the staged changes with the given message, dated date if it is set
+func CommitStaged(message string, date string) error {
+	return commitStaged(message, commitDateEnv(date))
+}
+
+// commitStaged commits the staged changes with the given message in the given environment
+func commitStaged(message string, env []string) error {
+	cmd := exec.Command("git", "commit", "-m", message)
+	cmd.Env = env
+	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("failed to commit squashed changes: %s", string(output))
 	}
+	return nil
+}
+
+// HasStagedChanges reports whether the index differs from HEAD
+func HasStagedChanges() (bool, error) {
+	err := exec.Command("git", "diff", "--cached", "--quiet").Run()
+	if err == nil {
+		return false, nil
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		return true, nil
+	}
+	return false, fmt.Errorf("failed to check for staged changes: %w", err)
+}
 
+// squashFromBase stages the changes between base and branch on top of the current branch,
+// using a three-way merge for files that changed on both sides
+func squashFromBase(branch string, base string) error {
+	diff, err := exec.Command("git", "diff", "--binary", base, branch).Output()
+	if err != nil {
+		return fmt.Errorf("failed to diff '%s' and '%s': %w", base, branch, err)
+	}
+	if len(diff) == 0 {
+		return fmt.Errorf("failed to squash merge branch: no changes between '%s' and '%s'", base, branch)
+	}
+
+	cmd := exec.Command("git", "apply", "--3way", "--index")
+	cmd.Stdin = bytes.NewReader(diff)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if strings.Contains(string(output), "with conflicts") {
+			return fmt.Errorf("squash merge conflict: %s", string(output))
+		}
+		return fmt.Errorf("failed to squash merge branch: %s", string(output))
+	}
 	return nil
 }
 
@@ -227,6 +472,184 @@ func ListBranches() ([]string, error) {
 	return branches, nil
 }
 
+// BranchRef returns the full ref of a branch, following symbolic refs, so that another name
+// for a branch (e.g. a symbolic ref to develop) resolves to that branch's ref (refs/heads/develop)
+func BranchRef(branch string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--symbolic-full-name", "refs/heads/"+branch)
+	output, err := cmd.Output()
+	ref := strings.TrimSpace(string(output))
+	if err != nil || ref == "" {
+		return "", fmt.Errorf("failed to resolve branch '%s'", branch)
+	}
+	return ref, nil
+}
+
+// ListBranchesWithPrefix returns the local branches whose names start with prefix, sorted by name
+func ListBranchesWithPrefix(prefix string) ([]string, error) {
+	branches, err := ListBranches()
+	if err != nil {
+		return nil, err
+	}
+	matching := []string{}
+	for _, branch := range branches {
+		if strings.HasPrefix(branch, prefix) {
+			matching = append(matching, branch)
+		}
+	}
+	return matching, nil
+}
+
+// CountCommits returns the number of commits reachable from to but not from from (from..to)
+func CountCommits(from string, to string) (int, error) {
+	cmd := exec.Command("git", "rev-list", "--count", from+".."+to)
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count commits between '%s' and '%s': %w", from, to, err)
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(string(output)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse commit count: %w", err)
+	}
+	return count, nil
+}
+
+// IsAncestor checks whether ancestor is reachable from descendant
+func IsAncestor(ancestor string, descendant string) (bool, error) {
+	cmd := exec.Command("git", "merge-base", "--is-ancestor", ancestor, descendant)
+	err := cmd.Run()
+	if err == nil {
+		return true, nil
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to check if '%s' is an ancestor of '%s': %w", ancestor, descendant, err)
+}
+
+// MergeBase returns the best common ancestor of two refs
+func MergeBase(a string, b string) (string, error) {
+	cmd := exec.Command("git", "merge-base", a, b)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find merge base of '%s' and '%s': %w", a, b, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// Diff returns the diff between two refs
+func Diff(from string, to string) (string, error) {
+	cmd := exec.Command("git", "diff", from, to)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to diff '%s' and '%s': %s", from, to, string(output))
+	}
+	return string(output), nil
+}
+
+// DiffStat returns the diffstat of the changes on to since it forked from from (from...to)
+func DiffStat(from string, to string) (string, error) {
+	cmd := exec.Command("git", "diff", "--stat", from+"..."+to)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to diff '%s' and '%s': %s", from, to, string(output))
+	}
+	return string(output), nil
+}
+
+// Log returns the one-line log of commits reachable from to but not from from (from..to)
+func Log(from string, to string) (string, error) {
+	cmd := exec.Command("git", "log", "--oneline", from+".."+to)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to list commits between '%s' and '%s': %s", from, to, string(output))
+	}
+	return string(output), nil
+}
+
+// LastTag returns the most recent tag reachable from ref, ignoring the tag named exclude,
+// or an empty string if no tag is reachable from it
+func LastTag(ref string, exclude string) (string, error) {
+	args := []string{"describe", "--tags", "--abbrev=0"}
+	if exclude != "" {
+		args = append(args, "--exclude", exclude)
+	}
+	cmd := exec.Command("git", append(args, ref)...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		// git describe fails the same way for a ref without tags as for an invalid ref
+		if _, resolveErr := ResolveCommit(ref); resolveErr == nil {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to find a tag reachable from '%s': %s", ref, strings.TrimSpace(string(output)))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// Shortlog returns the authors of the commits in the revision range with their commit counts,
+// one "<count>\t<name> <email>" line per author, most commits first
+func Shortlog(revisionRange string) (string, error) {
+	cmd := exec.Command("git", "shortlog", "-sne", revisionRange)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize authors of '%s': %s", revisionRange, string(output))
+	}
+	return string(output), nil
+}
+
+// ChangedFiles returns the files changed on to since it forked from from (from...to)
+func ChangedFiles(from string, to string) ([]string, error) {
+	cmd := exec.Command("git", "diff", "--name-only", from+"..."+to)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files changed between '%s' and '%s': %s", from, to, string(output))
+	}
+	files := []string{}
+	for _, line := range strings.Split(string(output), "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// OperationInProgress returns the git operation that is stopped halfway in the repository,
+// "rebase" or "cherry-pick", or an empty string if there is none
+func OperationInProgress() (string, error) {
+	operations := []struct {
+		path      string
+		operation string
+	}{
+		{"rebase-merge", "rebase"},
+		{"rebase-apply", "rebase"},
+		{"CHERRY_PICK_HEAD", "cherry-pick"},
+	}
+	for _, op := range operations {
+		path, err := GitPath(op.path)
+		if err != nil {
+			return "", err
+		}
+		if _, err := os.Stat(path); err == nil {
+			return op.operation, nil
+		}
+	}
+	return "", nil
+}
+
+// GitPath resolves a path inside the git directory, honoring GIT_DIR, GIT_COMMON_DIR and linked worktrees
+func GitPath(path string) (string, error) {
+	output, err := exec.Command("git", "rev-parse", "--git-path", path).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate '%s': %w", path, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// HasMergeHead checks if a git merge is in progress (MERGE_HEAD exists)
+func HasMergeHead() bool {
+	cmd := exec.Command("git", "rev-parse", "--quiet", "--verify", "MERGE_HEAD")
+	return cmd.Run() == nil
+}
+
 // HasConflicts checks if there are unresolved conflicts
 func HasConflicts() bool {
 	// Check for unmerged paths
@@ -238,6 +661,242 @@ func HasConflicts() bool {
 	return len(output) > 0
 }
 
+// HasTrackedChanges checks if tracked files have staged or unstaged changes
+func HasTrackedChanges() (bool, error) {
+	cmd := exec.Command("git", "status", "--porcelain", "--untracked-files=no")
+	output, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to get working tree status: %w", err)
+	}
+	return len(strings.TrimSpace(string(output))) > 0, nil
+}
+
+// HasUntrackedFiles checks if there are untracked files that are not ignored
+func HasUntrackedFiles() (bool, error) {
+	cmd := exec.Command("git", "ls-files", "--others", "--exclude-standard")
+	output, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+	return len(strings.TrimSpace(string(output))) > 0, nil
+}
+
+// StashUntracked stashes the working tree including untracked files and returns the stash commit
+func StashUntracked(message string) (string, error) {
+	cmd := exec.Command("git", "stash", "push", "--include-untracked", "-m", message)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to stash untracked files: %s", string(output))
+	}
+	return ResolveCommit("refs/stash")
+}
+
+// PopStash restores the given stash commit and drops it from the stash list
+func PopStash(stash string) error {
+	cmd := exec.Command("git", "stash", "list", "--format=%H")
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to list stashes: %w", err)
+	}
+	for i, sha := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if sha != stash {
+			continue
+		}
+		cmd = exec.Command("git", "stash", "pop", fmt.Sprintf("stash@{%d}", i))
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to restore stash %s: %s", stash, string(output))
+		}
+		return nil
+	}
+	return fmt.Errorf("stash %s not found", stash)
+}
+
+// OtherWorktreeForBranch returns the path of another worktree that has the branch checked out,
+// or an empty string if no other worktree does
+func OtherWorktreeForBranch(branch string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel", "--absolute-git-dir")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get worktree root: %w", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	current := canonicalPath(lines[0])
+
+	// With GIT_DIR and GIT_WORK_TREE set, git lists the main worktree under the git directory
+	currentGitDir := current
+	if len(lines) > 1 {
+		currentGitDir = canonicalPath(lines[1])
+	}
+
+	cmd = exec.Command("git", "worktree", "list", "--porcelain")
+	output, err = cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	path := ""
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(line, "worktree ") {
+			path = strings.TrimPrefix(line, "worktree ")
+		} else if line == "branch refs/heads/"+branch && canonicalPath(path) != current && canonicalPath(path) != currentGitDir {
+			return path, nil
+		}
+	}
+	return "", nil
+}
+
+// canonicalPath resolves symlinks so that worktree paths can be compared
+func canonicalPath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return filepath.Clean(path)
+}
+
+// SubmoduleConflict describes a conflicted submodule pointer
+type SubmoduleConflict struct {
+	Path   string // Path of the submodule in the superproject
+	Base   string // Commit recorded in the merge base (stage 1)
+	Ours   string // Commit recorded on the current branch (stage 2)
+	Theirs string // Commit recorded on the branch being merged (stage 3)
+}
+
+// GetConflictedSubmodules returns the submodules with conflicting pointers in the index
+func GetConflictedSubmodules() ([]SubmoduleConflict, error) {
+	cmd := exec.Command("git", "ls-files", "-u")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unmerged files: %w", err)
+	}
+
+	conflicts := []SubmoduleConflict{}
+	index := map[string]int{}
+	for _, line := range strings.Split(string(output), "\n") {
+		// Format: <mode> <object> <stage>\t<path>
+		parts := strings.SplitN(line, "\t", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		fields := strings.Fields(parts[0])
+		if len(fields) != 3 || fields[0] != "160000" {
+			continue
+		}
+
+		path := parts[1]
+		i, ok := index[path]
+		if !ok {
+			conflicts = append(conflicts, SubmoduleConflict{Path: path})
+			i = len(conflicts) - 1
+			index[path] = i
+		}
+		switch fields[2] {
+		case "1":
+			conflicts[i].Base = fields[1]
+		case "2":
+			conflicts[i].Ours = fields[1]
+		case "3":
+			conflicts[i].Theirs = fields[1]
+		}
+	}
+
+	return conflicts, nil
+}
+
+// Conflict types reported by GetConflicts
+const (
+	ConflictContent      = "content"
+	ConflictAddAdd       = "add/add"
+	ConflictRenameRename = "rename/rename"
+	ConflictRenameDelete = "rename/delete"
+	ConflictModifyDelete = "modify/delete"
+)
+
+// Conflict describes an unmerged path and the kind of conflict that produced it
+type Conflict struct {
+	Path string // Path of the conflicted file
+	Type string // One of the Conflict* constants
+}
+
+// GetConflicts returns the unmerged paths in the index, classified by conflict type
+func GetConflicts() ([]Conflict, error) {
+	cmd := exec.Command("git", "status", "--porcelain=v2", "--untracked-files=no")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status: %w", err)
+	}
+
+	type unmerged struct {
+		path   string
+		status string
+	}
+	entries := []unmerged{}
+	bothDeleted := false
+	for _, line := range strings.Split(string(output), "\n") {
+		// Format: u <XY> <sub> <m1> <m2> <m3> <mW> <h1> <h2> <h3> <path>
+		fields := strings.SplitN(line, " ", 11)
+		if len(fields) != 11 || fields[0] != "u" {
+			continue
+		}
+		entries = append(entries, unmerged{path: fields[10], status: fields[1]})
+		if fields[1] == "DD" {
+			bothDeleted = true
+		}
+	}
+
+	base := conflictMergeBase()
+	conflicts := make([]Conflict, 0, len(entries))
+	for _, entry := range entries {
+		conflictType := ConflictContent
+		switch entry.status {
+		case "AA":
+			conflictType = ConflictAddAdd
+		case "DD":
+			conflictType = ConflictRenameRename
+		case "AU", "UA":
+			// Paths added by one side only are the new names of a rename/rename conflict
+			if bothDeleted {
+				conflictType = ConflictRenameRename
+			} else {
+				conflictType = ConflictAddAdd
+			}
+		case "DU", "UD":
+			// A path missing from the merge base was introduced by a rename
+			conflictType = ConflictModifyDelete
+			if base != "" && exec.Command("git", "cat-file", "-e", base+":"+entry.path).Run() != nil {
+				conflictType = ConflictRenameDelete
+			}
+		}
+		conflicts = append(conflicts, Conflict{Path: entry.path, Type: conflictType})
+	}
+
+	return conflicts, nil
+}
+
+// conflictMergeBase returns the merge base of HEAD and the commit being merged or rebased,
+// or an empty string if no such operation is in progress
+func conflictMergeBase() string {
+	for _, ref := range []string{"MERGE_HEAD", "REBASE_HEAD", "CHERRY_PICK_HEAD"} {
+		if exec.Command("git", "rev-parse", "--quiet", "--verify", ref).Run() != nil {
+			continue
+		}
+		base, err := MergeBase("HEAD", ref)
+		if err != nil {
+			return ""
+		}
+		return base
+	}
+	return ""
+}
+
+// AddNote attaches a note to a commit under the given notes ref, replacing any existing note
+func AddNote(notesRef string, commit string, message string) error {
+	cmd := exec.Command("git", "notes", "--ref", notesRef, "add", "-f", "-m", message, commit)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to add note to '%s': %s: %w", commit, strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
 // MergeAbort aborts the current merge
 func MergeAbort() error {
 	cmd := exec.Command("git", "merge", "--abort")
@@ -247,6 +906,17 @@ func MergeAbort() error {
 	return nil
 }
 
+// ResetMerge discards staged changes left by a squash merge, which unlike a regular merge
+// cannot be aborted with git merge --abort
+func ResetMerge() error {
+	cmd := exec.Command("git", "reset", "--merge")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to reset merge: %s", string(output))
+	}
+	return nil
+}
+
 // RebaseAbort aborts the current rebase
 func RebaseAbort() error {
 	cmd := exec.Command("git", "rebase", "--abort")
@@ -284,6 +954,38 @@ func Fetch(remote string) error {
 	return nil
 }
 
+// FetchBranch fetches a single branch from the specified remote, updating its remote-tracking branch
+func FetchBranch(remote string, branch string) error {
+	cmd := exec.Command("git", "fetch", remote, branch)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to fetch '%s' from remote '%s': %s", branch, remote, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// FetchRef fetches a single ref, such as a pull request head, from the specified remote without
+// storing it under a local name, and returns the commit it points to
+func FetchRef(remote string, ref string) (string, error) {
+	cmd := exec.Command("git", "fetch", "--no-tags", remote, ref)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch '%s' from remote '%s': %s", ref, remote, strings.TrimSpace(string(output)))
+	}
+	return ResolveCommit("FETCH_HEAD")
+}
+
+// FetchAndFastForward fetches a branch from the specified remote and fast-forwards the local
+// branch of the same name to it. It fails if the local branch has diverged or is checked out.
+func FetchAndFastForward(remote string, branch string) error {
+	cmd := exec.Command("git", "fetch", remote, fmt.Sprintf("refs/heads/%s:refs/heads/%s", branch, branch))
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to fast-forward '%s' from remote '%s': %s", branch, remote, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
 // DeleteRemoteBranch deletes a branch from a remote repository
 func DeleteRemoteBranch(remote, branch string) error {
 	cmd := exec.Command("git", "push", remote, ":"+branch)
@@ -294,6 +996,42 @@ func DeleteRemoteBranch(remote, branch string) error {
 	return nil
 }
 
+// PushBranch pushes a local branch to the remote and sets it as the upstream of the branch
+func PushBranch(remote, branch string) error {
+	cmd := exec.Command("git", "push", "--set-upstream", remote, branch)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to push branch: %s", string(output))
+	}
+	return nil
+}
+
+// PushRefs pushes the given branches and tags to the remote in a single push
+func PushRefs(remote string, refs ...string) error {
+	args := append([]string{"push", remote}, refs...)
+	cmd := exec.Command("git", args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to push to %s: %s", remote, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// PushRefsWithLease pushes refs like PushRefs, forcing the ref of the lease "<ref>:<expected>" only if the
+// remote still has it at the expected object. An empty lease pushes without forcing anything.
+func PushRefsWithLease(remote string, lease string, refs ...string) error {
+	if lease == "" {
+		return PushRefs(remote, refs...)
+	}
+	args := append([]string{"push", "--force-with-lease=" + lease, remote}, refs...)
+	cmd := exec.Command("git", args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to push to %s: %s", remote, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
 // RemoteBranchExists checks if a remote branch exists
 func RemoteBranchExists(remote, branch string) bool {
 	// Check if the remote tracking branch exists
@@ -302,25 +1040,113 @@ func RemoteBranchExists(remote, branch string) bool {
 	return cmd.Run() == nil
 }
 
+// RemoteTagExists asks the remote whether it has the given tag
+func RemoteTagExists(remote, tagName string) (bool, error) {
+	cmd := exec.Command("git", "ls-remote", "--tags", remote, "refs/tags/"+tagName)
+	output, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to list tags of remote '%s': %w", remote, err)
+	}
+	return strings.TrimSpace(string(output)) != "", nil
+}
+
+// RemoteTagObject asks the remote for the object its tag points to, or "" if it has no such tag
+func RemoteTagObject(remote, tagName string) (string, error) {
+	return RemoteRefObject(remote, "refs/tags/"+tagName)
+}
+
+// RemoteRefObject asks the remote for the object a full ref name points to, or "" if it has no such ref
+func RemoteRefObject(remote, ref string) (string, error) {
+	cmd := exec.Command("git", "ls-remote", remote, ref)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to list refs of remote '%s': %w", remote, err)
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[1] == ref {
+			return fields[0], nil
+		}
+	}
+	return "", nil
+}
+
+// TagExists checks if a tag exists
+func TagExists(tagName string) bool {
+	cmd := exec.Command("git", "show-ref", "--tags", "--verify", "--quiet", "refs/tags/"+tagName)
+	return cmd.Run() == nil
+}
+
+// DeleteTag deletes a local tag
+func DeleteTag(tagName string) error {
+	cmd := exec.Command("git", "tag", "-d", tagName)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to delete tag '%s': %s: %w", tagName, strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// ResetTag points the tag at the given object, creating it if it doesn't exist
+func ResetTag(tagName string, object string) error {
+	cmd := exec.Command("git", "update-ref", "refs/tags/"+tagName, object)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to move tag '%s' to '%s': %s: %w", tagName, object, strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// VerifyTag verifies the signature of a tag and returns the signature status reported by git
+func VerifyTag(tagName string) (string, error) {
+	cmd := exec.Command("git", "tag", "-v", tagName)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	status := strings.TrimSpace(stderr.String())
+	if err != nil {
+		return status, fmt.Errorf("signature verification failed: %s", status)
+	}
+	return status, nil
+}
+
 // TagOptions contains options for tag creation
 type TagOptions struct {
 	Message     string // Tag message (required for annotated tags)
 	MessageFile string // File containing the message (optional, overrides Message)
 	Sign        bool   // Whether to sign the tag (optional)
 	SigningKey  string // Key to use for signing (optional, implies Sign=true)
+	Cleanup     string // Message cleanup mode passed to --cleanup (optional, e.g. "whitespace" to keep '#' lines)
+	Date        string // Tagger date (optional)
+	Target      string // Ref to tag (optional, defaults to HEAD)
+	Force       bool   // Replace the tag if it already exists (optional)
+	Lightweight bool   // Create a lightweight tag, ignoring the message and signing options (optional)
 }
 
 // CreateTag creates a Git tag with the specified options
 func CreateTag(tagName string, options *TagOptions) error {
 	// Check if tag already exists
-	cmd := exec.Command("git", "show-ref", "--tags", tagName)
-	if err := cmd.Run(); err == nil {
+	if TagExists(tagName) && !options.Force {
 		// Tag already exists, skip creation
 		return nil
 	}
 
 	// Build command arguments
 	args := []string{"tag"}
+	if options.Force {
+		args = append(args, "-f")
+	}
+
+	// A lightweight tag only needs a name and a target
+	if options.Lightweight {
+		args = append(args, tagName)
+		if options.Target != "" {
+			args = append(args, options.Target)
+		}
+		output, err := exec.Command("git", args...).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("failed to create tag '%s': %w (output: %s)", tagName, err, string(output))
+		}
+		return nil
+	}
 
 	// Use annotated tag
 	args = append(args, "-a")
@@ -348,8 +1174,19 @@ func CreateTag(tagName string, options *TagOptions) error {
 		return fmt.Errorf("tag message is required for annotated tags")
 	}
 
+	// Apply message cleanup mode
+	if options.Cleanup != "" {
+		args = append(args, "--cleanup="+options.Cleanup)
+	}
+
+	// Apply tag target
+	if options.Target != "" {
+		args = append(args, options.Target)
+	}
+
 	// Execute tag command
-	cmd = exec.Command("git", args...)
+	cmd := exec.Command("git", args...)
+	cmd.Env = commitDateEnv(options.Date)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("failed to create tag '%s': %w (output: %s)", tagName, err, string(output))
@@ -5,30 +5,80 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/gittower/git-flow-next/internal/git"
 )
 
 const (
-	stateDir  = ".git/gitflow/state"
-	stateFile = "merge.json"
+	defaultStateDir = ".git/gitflow/state"
+	stateFile       = "merge.json"
 )
 
+// stateDir returns the directory of the state file inside the git directory, which is not
+// necessarily .git, e.g. when GIT_DIR is set or in a linked worktree
+func stateDir() string {
+	dir, err := git.GitPath("gitflow/state")
+	if err != nil {
+		return defaultStateDir
+	}
+	return dir
+}
+
 // MergeState represents the state of a merge operation
 type MergeState struct {
-	Action          string   `json:"action"`          // "finish"
-	BranchType      string   `json:"branchType"`      // feature, release, hotfix, etc.
-	BranchName      string   `json:"branchName"`      // name of the branch being merged
-	CurrentStep     string   `json:"currentStep"`     // current step in the process (merge, update_children, delete_branch)
-	ParentBranch    string   `json:"parentBranch"`    // target branch for the merge
-	MergeStrategy   string   `json:"mergeStrategy"`   // merge strategy being used
-	FullBranchName  string   `json:"fullBranchName"`  // full name of the branch (with prefix)
-	ChildBranches   []string `json:"childBranches"`   // child branches that need to be updated
-	UpdatedBranches []string `json:"updatedBranches"` // child branches that have been updated
+	Action            string   `json:"action"`                      // "finish"
+	BranchType        string   `json:"branchType"`                  // feature, release, hotfix, etc.
+	BranchName        string   `json:"branchName"`                  // name of the branch being merged
+	CurrentStep       string   `json:"currentStep"`                 // current step in the process (merge, update_children, delete_branch)
+	ParentBranch      string   `json:"parentBranch"`                // target branch for the merge
+	MergeStrategy     string   `json:"mergeStrategy"`               // merge strategy being used
+	FullBranchName    string   `json:"fullBranchName"`              // full name of the branch (with prefix), or the source ref
+	IsSourceRef       bool     `json:"isSourceRef,omitempty"`       // whether FullBranchName is an arbitrary ref rather than a branch
+	ChildBranches     []string `json:"childBranches"`               // child branches that need to be updated
+	UpdatedBranches   []string `json:"updatedBranches"`             // child branches that have been updated
+	UnchangedBranches []string `json:"unchangedBranches,omitempty"` // child branches that were already up to date
+	SkipEmptyChildren bool     `json:"skipEmptyChildren,omitempty"` // whether to skip child branches that already contain the parent tip
+	ChildrenParallel  bool     `json:"childrenParallel,omitempty"`  // whether child branches are checked concurrently before updating
+	TagName           string   `json:"tagName,omitempty"`           // name of the tag created during the operation, if any
+	TagAfterChildren  bool     `json:"tagAfterChildren,omitempty"`  // whether tag creation is deferred until child branches are updated
+	TagTarget         string   `json:"tagTarget,omitempty"`         // branch whose tip is tagged, if it is not the target
+	ReplacedTag       string   `json:"replacedTag,omitempty"`       // object the tag pointed to before --force-tag replaced it
+	Push              bool     `json:"push,omitempty"`              // whether the updated base branches and the tag are pushed once the branch is deleted
+	LocalOnly         bool     `json:"localOnly,omitempty"`         // whether the finish keeps the remote branch and pushes nothing
+	TagPushSafe       bool     `json:"tagPushSafe,omitempty"`       // whether a replaced tag is force-pushed if the remote still has the replaced object
+	UntrackedStash    string   `json:"untrackedStash,omitempty"`    // stash commit holding untracked files to restore after the operation
+	HistoryNote       bool     `json:"historyNote,omitempty"`       // whether to attach a git note with the git-flow metadata to the merge commit
+	Issue             string   `json:"issue,omitempty"`             // issue id recorded for the branch, kept because the branch settings are removed on delete
+	Webhook           string   `json:"webhook,omitempty"`           // webhook URL given with --emit-event, which also receives the outcome of --continue and --abort
+	AlsoInto          []string `json:"alsoInto,omitempty"`          // additional targets the branch is merged into after the target
+	AlsoIntoStrategy  string   `json:"alsoIntoStrategy,omitempty"`  // strategy used to merge into the additional targets
+	MergedInto        []string `json:"mergedInto,omitempty"`        // additional targets the branch has been merged into
+	PreventDeleteRace bool     `json:"preventDeleteRace,omitempty"` // whether to refuse deleting the branch if it moved after the merge
+	MergedHead        string   `json:"mergedHead,omitempty"`        // tip of the branch when it was merged, compared before deleting it
+	TargetHead        string   `json:"targetHead,omitempty"`        // commit of the target before the finish, restored on abort
+	BranchHead        string   `json:"branchHead,omitempty"`        // commit of the finished branch before the finish, restored on abort
+	TagCreated        bool     `json:"tagCreated,omitempty"`        // whether the finish created or replaced the tag rather than reusing it, undone on abort
+	SquashMessage     string   `json:"squashMessage,omitempty"`     // message of the squash commit, used when a squash is committed on continue
+	ReflogAction      string   `json:"reflogAction,omitempty"`      // label of the reflog entries written by the merges, if not the default
+	DeletedBranches   []string `json:"deletedBranches,omitempty"`   // branches deleted by the finish, reported once the push succeeds
+	PushedRefs        []string `json:"pushedRefs,omitempty"`        // refs pushed so far, skipped when a failed push is retried
+
+	MetricsFile   string `json:"metricsFile,omitempty"`   // CSV file a row with the metrics of the finish is appended to when it completes
+	StartedAt     string `json:"startedAt,omitempty"`     // time the finish started (RFC 3339), for the recorded duration
+	CommitsMerged int    `json:"commitsMerged,omitempty"` // number of commits of the branch that were not on the target yet
+	FilesChanged  int    `json:"filesChanged,omitempty"`  // number of files the branch changed compared to the target
+	Conflicted    bool   `json:"conflicted,omitempty"`    // whether the finish stopped on a conflict at any point
+
+	ChildBranchHeads map[string]string `json:"childBranchHeads,omitempty"` // commits of the child branches and additional targets before the finish, restored on abort
+	ChildStrategies  map[string]string `json:"childStrategies,omitempty"`  // strategies the child branches are updated with, recorded when their update begins
+	SnapshotBefore   map[string]string `json:"snapshotBefore,omitempty"`   // commits of the base branches before the finish, logged with their new commits when it completes
 }
 
 // SaveMergeState saves the current merge state to a file
 func SaveMergeState(state *MergeState) error {
 	// Create state directory if it doesn't exist
-	if err := os.MkdirAll(stateDir, 0755); err != nil {
+	dir := stateDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("failed to create state directory: %w", err)
 	}
 
@@ -39,7 +89,7 @@ func SaveMergeState(state *MergeState) error {
 	}
 
 	// Write state to file
-	statePath := filepath.Join(stateDir, stateFile)
+	statePath := filepath.Join(dir, stateFile)
 	if err := os.WriteFile(statePath, data, 0644); err != nil {
 		return fmt.Errorf("failed to write state file: %w", err)
 	}
@@ -47,9 +97,22 @@ func SaveMergeState(state *MergeState) error {
 	return nil
 }
 
+// Clone returns a copy of the state that shares no slices or maps with it
+func (s *MergeState) Clone() (*MergeState, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal state: %w", err)
+	}
+	var clone MergeState
+	if err := json.Unmarshal(data, &clone); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal state: %w", err)
+	}
+	return &clone, nil
+}
+
 // LoadMergeState loads the current merge state from file
 func LoadMergeState() (*MergeState, error) {
-	statePath := filepath.Join(stateDir, stateFile)
+	statePath := filepath.Join(stateDir(), stateFile)
 	data, err := os.ReadFile(statePath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -66,9 +129,14 @@ func LoadMergeState() (*MergeState, error) {
 	return &state, nil
 }
 
+// StatePath returns the path of the merge state file, for removing a state that is no longer needed by hand
+func StatePath() string {
+	return filepath.Join(stateDir(), stateFile)
+}
+
 // ClearMergeState removes the merge state file
 func ClearMergeState() error {
-	statePath := filepath.Join(stateDir, stateFile)
+	statePath := filepath.Join(stateDir(), stateFile)
 	err := os.Remove(statePath)
 	if err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to remove state file: %w", err)
@@ -12,6 +12,12 @@ import (
 
 // UpdateBranchFromParent updates a branch with changes from its parent branch using the configured strategy
 func UpdateBranchFromParent(branchName string, parentBranch string, strategy string, saveState bool, state *mergestate.MergeState) error {
+	return UpdateBranchFromParentWithOptions(branchName, parentBranch, strategy, saveState, state, nil)
+}
+
+// UpdateBranchFromParentWithOptions updates a branch with changes from its parent branch using the configured
+// strategy, passing the merge options (e.g. the commit message) to merges and squash merges
+func UpdateBranchFromParentWithOptions(branchName string, parentBranch string, strategy string, saveState bool, state *mergestate.MergeState, mergeOptions *git.MergeOptions) error {
 	// Checkout the branch if needed
 	currentBranch, err := git.GetCurrentBranch()
 	if err != nil {
@@ -31,10 +37,10 @@ func UpdateBranchFromParent(branchName string, parentBranch string, strategy str
 		mergeErr = git.Rebase(parentBranch)
 	case "squash":
 		fmt.Printf("Using squash strategy for '%s'\n", branchName)
-		mergeErr = git.SquashMerge(parentBranch)
+		mergeErr = git.SquashMergeWithOptions(parentBranch, mergeOptions)
 	default:
 		fmt.Printf("Using merge strategy for '%s'\n", branchName)
-		mergeErr = git.Merge(parentBranch)
+		mergeErr = git.MergeWithOptions(parentBranch, mergeOptions)
 	}
 
 	if mergeErr != nil {
@@ -0,0 +1,22 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// AppendLine appends line and a newline to the file at path, creating the file and its directory if
+// needed. The line is written with a single write to a file opened for appending, so lines appended by
+// concurrent processes don't interleave.
+func AppendLine(path string, line []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
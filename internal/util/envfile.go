@@ -0,0 +1,60 @@
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EnvEntry is one KEY=VALUE pair read from an env file
+type EnvEntry struct {
+	Key   string
+	Value string
+	Line  int
+}
+
+// ReadEnvFile reads KEY=VALUE pairs from a dotenv-style file, in file order. Blank lines and lines
+// starting with '#' are skipped, an optional "export " prefix is ignored, and values may be wrapped
+// in single or double quotes.
+func ReadEnvFile(path string) ([]EnvEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []EnvEntry
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, lineNumber)
+		}
+		entries = append(entries, EnvEntry{Key: key, Value: unquoteEnvValue(strings.TrimSpace(value)), Line: lineNumber})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// unquoteEnvValue removes matching single or double quotes around a value
+func unquoteEnvValue(value string) string {
+	if len(value) >= 2 {
+		first, last := value[0], value[len(value)-1]
+		if (first == '"' || first == '\'') && first == last {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
@@ -3,6 +3,7 @@ package util
 import (
 	"regexp"
 	"strings"
+	"time"
 )
 
 // IsValidBranchName checks if a branch name is valid
@@ -54,3 +55,27 @@ func IsValidPrefix(prefix string) bool {
 	// Remove the trailing "/" and check if it's a valid branch name
 	return IsValidBranchName(strings.TrimSuffix(prefix, "/"))
 }
+
+// commitDateLayouts lists the date formats accepted for commit dates, all of which git understands
+var commitDateLayouts = []string{
+	time.RFC3339,
+	time.RFC1123Z,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05 -0700",
+}
+
+// IsValidCommitDate checks if a date can be used as GIT_AUTHOR_DATE/GIT_COMMITTER_DATE
+func IsValidCommitDate(date string) bool {
+	// Git's internal format: @<unix timestamp> [<timezone offset>]
+	if regexp.MustCompile(`^@\d+( [+-]\d{4})?$`).MatchString(date) {
+		return true
+	}
+
+	for _, layout := range commitDateLayouts {
+		if _, err := time.Parse(layout, date); err == nil {
+			return true
+		}
+	}
+	return false
+}
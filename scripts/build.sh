@@ -13,7 +13,7 @@ GIT_COMMIT=$(git rev-parse --short HEAD)
 BUILD_TIME=$(date -u '+%Y-%m-%d %H:%M:%S')
 
 # Build flags
-BUILD_FLAGS="-X github.com/gittower/git-flow-next/version.BuildTime='${BUILD_TIME}' -X github.com/gittower/git-flow-next/version.GitCommit=${GIT_COMMIT}"
+BUILD_FLAGS="-X github.com/gittower/git-flow-next/version.Version=${VERSION} -X github.com/gittower/git-flow-next/version.BuildTime='${BUILD_TIME}' -X github.com/gittower/git-flow-next/version.GitCommit=${GIT_COMMIT}"
 
 # Create build directory if it doesn't exist
 mkdir -p $BUILD_DIR
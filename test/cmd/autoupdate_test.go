@@ -0,0 +1,205 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestAutoUpdatePropagatesHotfix tests that autoupdate brings develop up to date with main.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Lands a hotfix commit directly on main
+// 3. Runs git flow autoupdate
+// 4. Verifies develop contains the hotfix commit
+// 5. Verifies the original branch is checked out again
+func TestAutoUpdatePropagatesHotfix(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Land a hotfix on main without updating develop
+	_, err = testutil.RunGit(t, dir, "checkout", "main")
+	if err != nil {
+		t.Fatalf("Failed to checkout main: %v", err)
+	}
+	testutil.WriteFile(t, dir, "hotfix.txt", "hotfix content")
+	_, err = testutil.RunGit(t, dir, "add", "hotfix.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Hotfix on main")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Run autoupdate
+	output, err = testutil.RunGitFlow(t, dir, "autoupdate")
+	if err != nil {
+		t.Fatalf("Failed to run autoupdate: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Successfully updated branch 'develop' from 'main'") {
+		t.Errorf("Expected per-branch update result for develop, got: %s", output)
+	}
+	if !strings.Contains(output, "Auto-updated 1 of 1 branches") {
+		t.Errorf("Expected auto-update summary, got: %s", output)
+	}
+
+	// Verify develop now contains the hotfix
+	content, err := testutil.RunGit(t, dir, "show", "develop:hotfix.txt")
+	if err != nil {
+		t.Fatalf("Expected hotfix.txt to exist on develop: %v", err)
+	}
+	if content != "hotfix content" {
+		t.Errorf("Expected hotfix content on develop, got '%s'", content)
+	}
+
+	// Verify we're back on main
+	if current := testutil.GetCurrentBranch(t, dir); current != "main" {
+		t.Errorf("Expected to be back on 'main', got '%s'", current)
+	}
+
+	// A second run has nothing to do
+	output, err = testutil.RunGitFlow(t, dir, "autoupdate")
+	if err != nil {
+		t.Fatalf("Failed to run autoupdate: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "'develop' is already up to date with 'main'") {
+		t.Errorf("Expected develop to be reported as up to date, got: %s", output)
+	}
+}
+
+// TestAutoUpdateDryRun tests that autoupdate --dry-run reports without changing branches.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Lands a commit directly on main
+// 3. Runs git flow autoupdate --dry-run
+// 4. Verifies the planned update is reported
+// 5. Verifies develop was not modified
+func TestAutoUpdateDryRun(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	developBefore, err := testutil.RunGit(t, dir, "rev-parse", "develop")
+	if err != nil {
+		t.Fatalf("Failed to get develop SHA: %v", err)
+	}
+
+	// Land a commit on main
+	_, err = testutil.RunGit(t, dir, "checkout", "main")
+	if err != nil {
+		t.Fatalf("Failed to checkout main: %v", err)
+	}
+	testutil.WriteFile(t, dir, "hotfix.txt", "hotfix content")
+	_, err = testutil.RunGit(t, dir, "add", "hotfix.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Hotfix on main")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Run autoupdate in dry-run mode
+	output, err = testutil.RunGitFlow(t, dir, "autoupdate", "--dry-run")
+	if err != nil {
+		t.Fatalf("Failed to run autoupdate --dry-run: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Would update 'develop' from 'main' using merge strategy (1 new commits)") {
+		t.Errorf("Expected dry-run report for develop, got: %s", output)
+	}
+
+	// Verify develop is unchanged
+	developAfter, err := testutil.RunGit(t, dir, "rev-parse", "develop")
+	if err != nil {
+		t.Fatalf("Failed to get develop SHA: %v", err)
+	}
+	if developBefore != developAfter {
+		t.Error("Expected develop to be unchanged by dry-run")
+	}
+}
+
+// TestAutoUpdateAfterResolvedConflict tests that re-running autoupdate after resolving a conflict clears
+// the update state, so that a later finish is not blocked by it.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Commits conflicting changes to main and develop
+// 3. Runs git flow autoupdate and verifies it stops on the conflict
+// 4. Resolves and commits the merge, then runs git flow autoupdate again
+// 5. Verifies the merge state is cleared and a feature branch can be finished
+func TestAutoUpdateAfterResolvedConflict(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Conflicting changes on main and develop
+	for _, branch := range []string{"main", "develop"} {
+		if _, err := testutil.RunGit(t, dir, "checkout", branch); err != nil {
+			t.Fatalf("Failed to checkout %s: %v", branch, err)
+		}
+		testutil.WriteFile(t, dir, "version.txt", branch+" version")
+		if _, err := testutil.RunGit(t, dir, "add", "version.txt"); err != nil {
+			t.Fatalf("Failed to add file: %v", err)
+		}
+		if _, err := testutil.RunGit(t, dir, "commit", "-m", "Set version on "+branch); err != nil {
+			t.Fatalf("Failed to commit file: %v", err)
+		}
+	}
+
+	// The update of develop stops on the conflict
+	output, err = testutil.RunGitFlow(t, dir, "autoupdate")
+	if err == nil {
+		t.Fatalf("Expected autoupdate to stop on the conflict\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "run 'git flow autoupdate' again") {
+		t.Errorf("Expected instructions to re-run autoupdate, got: %s", output)
+	}
+
+	// Resolve, commit and re-run as instructed
+	testutil.WriteFile(t, dir, "version.txt", "merged version")
+	if _, err := testutil.RunGit(t, dir, "add", "version.txt"); err != nil {
+		t.Fatalf("Failed to add resolved file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Merge main into develop"); err != nil {
+		t.Fatalf("Failed to commit merge resolution: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "autoupdate")
+	if err != nil {
+		t.Fatalf("Failed to run autoupdate: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "'develop' is already up to date with 'main'") {
+		t.Errorf("Expected develop to be reported as up to date, got: %s", output)
+	}
+	if testutil.IsMergeInProgress(t, dir) {
+		t.Error("Expected the update state to be cleared")
+	}
+
+	// A finish is no longer blocked
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "after-update")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "after-update")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+}
@@ -0,0 +1,572 @@
+package cmd_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	gitflow "github.com/gittower/git-flow-next/cmd"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestConfigBranchShowsResolvedConfig tests printing the resolved configuration of a branch type.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Runs git flow config branch feature
+// 3. Verifies every setting is printed, including the tag default that is not configured
+func TestConfigBranchShowsResolvedConfig(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Show the feature configuration
+	output, err = testutil.RunGitFlow(t, dir, "config", "branch", "feature")
+	if err != nil {
+		t.Fatalf("Failed to show branch configuration: %v\nOutput: %s", err, output)
+	}
+
+	// Verify the resolved values
+	expected := []string{
+		"type: topic",
+		"parent: develop",
+		"startpoint: develop",
+		"upstreamstrategy: merge",
+		"downstreamstrategy: rebase",
+		"prefix: feature/",
+		"tag: false",
+		"tagprefix: ",
+		"autoupdate: false",
+		"protected: false",
+	}
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines, got %d:\n%s", len(expected), len(lines), output)
+	}
+	for i, line := range lines {
+		if line != expected[i] {
+			t.Errorf("Expected line %d to be '%s', got '%s'", i+1, expected[i], line)
+		}
+	}
+}
+
+// TestConfigBranchJSON tests printing the resolved configuration of a branch as JSON.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Configures a tag prefix for release
+// 3. Runs git flow config branch release --json
+// 4. Verifies the configured values and the defaults of the release type
+// 5. Verifies an unknown branch is rejected
+func TestConfigBranchJSON(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Configure the release type
+	_, err = testutil.RunGit(t, dir, "config", "gitflow.branch.release.tagprefix", "v")
+	if err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+
+	// Show the release configuration as JSON
+	output, err = testutil.RunGitFlow(t, dir, "config", "branch", "release", "--json")
+	if err != nil {
+		t.Fatalf("Failed to show branch configuration: %v\nOutput: %s", err, output)
+	}
+	var view gitflow.BranchConfigView
+	if err := json.Unmarshal([]byte(output), &view); err != nil {
+		t.Fatalf("Failed to parse JSON: %v\nOutput: %s", err, output)
+	}
+
+	// Verify the configured values and defaults
+	expected := gitflow.BranchConfigView{
+		Name:               "release",
+		Type:               "topic",
+		Parent:             "main",
+		StartPoint:         "develop",
+		UpstreamStrategy:   "merge",
+		DownstreamStrategy: "merge",
+		Prefix:             "release/",
+		Tag:                true,
+		TagPrefix:          "v",
+	}
+	if view != expected {
+		t.Errorf("Expected %+v, got %+v", expected, view)
+	}
+
+	// An unknown branch is rejected
+	output, err = testutil.RunGitFlow(t, dir, "config", "branch", "unknown")
+	if err == nil {
+		t.Fatalf("Expected an error for an unknown branch\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "unknown branch type: unknown") {
+		t.Errorf("Expected unknown branch type error, got: %s", output)
+	}
+}
+
+// TestConfigDoctorCreatesMissingDevelop tests that config doctor --fix creates a missing develop branch from main.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Deletes the develop branch
+// 3. Runs git flow config doctor and verifies the problem is reported with a failing exit code
+// 4. Runs git flow config doctor --fix without a terminal and verifies it asks for --yes
+// 5. Runs git flow config doctor --fix --yes and verifies develop is created at main
+// 6. Verifies config doctor finds no more problems
+func TestConfigDoctorCreatesMissingDevelop(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Delete develop
+	if _, err := testutil.RunGit(t, dir, "checkout", "main"); err != nil {
+		t.Fatalf("Failed to checkout main: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "branch", "-D", "develop"); err != nil {
+		t.Fatalf("Failed to delete develop: %v", err)
+	}
+
+	// The problem is reported
+	output, err = testutil.RunGitFlow(t, dir, "config", "doctor")
+	if err == nil {
+		t.Fatalf("Expected config doctor to fail\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "base branch 'develop' does not exist (fix: create it from 'main')") {
+		t.Errorf("Expected missing develop to be reported, got: %s", output)
+	}
+
+	// Fixing without a terminal requires --yes
+	output, err = testutil.RunGitFlow(t, dir, "config", "doctor", "--fix")
+	if err == nil {
+		t.Fatalf("Expected config doctor --fix without --yes to fail\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "--yes") || testutil.BranchExists(t, dir, "develop") {
+		t.Errorf("Expected nothing to be fixed without --yes, got: %s", output)
+	}
+
+	// Fix it
+	output, err = testutil.RunGitFlow(t, dir, "config", "doctor", "--fix", "--yes")
+	if err != nil {
+		t.Fatalf("Failed to fix configuration: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Fixed: base branch 'develop' does not exist (create it from 'main')") {
+		t.Errorf("Expected the fix to be reported, got: %s", output)
+	}
+	develop, err := testutil.RunGit(t, dir, "rev-parse", "develop")
+	if err != nil {
+		t.Fatalf("Expected develop to exist: %v", err)
+	}
+	main, _ := testutil.RunGit(t, dir, "rev-parse", "main")
+	if develop != main {
+		t.Errorf("Expected develop to be created at main")
+	}
+	if current := testutil.GetCurrentBranch(t, dir); current != "main" {
+		t.Errorf("Expected to stay on main, got '%s'", current)
+	}
+
+	// Nothing is left
+	output, err = testutil.RunGitFlow(t, dir, "config", "doctor")
+	if err != nil || !strings.Contains(output, "No problems found") {
+		t.Errorf("Expected no problems after fixing: %v\nOutput: %s", err, output)
+	}
+}
+
+// TestConfigDoctorSetsEmptyStrategies tests that config doctor --fix sets default strategies where they are empty.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Removes the upstream strategy of feature and the downstream strategy of release
+// 3. Runs git flow config doctor --fix --yes
+// 4. Verifies the default strategies of feature and release are set
+func TestConfigDoctorSetsEmptyStrategies(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Empty the strategies
+	if _, err := testutil.RunGit(t, dir, "config", "--unset", "gitflow.branch.feature.upstreamstrategy"); err != nil {
+		t.Fatalf("Failed to unset config: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch.release.downstreamstrategy", ""); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+
+	// Fix them
+	output, err = testutil.RunGitFlow(t, dir, "config", "doctor", "--fix", "--yes")
+	if err != nil {
+		t.Fatalf("Failed to fix configuration: %v\nOutput: %s", err, output)
+	}
+
+	// Verify the defaults are set
+	expected := map[string]string{
+		"gitflow.branch.feature.upstreamstrategy":   "merge",
+		"gitflow.branch.release.downstreamstrategy": "merge",
+	}
+	for key, value := range expected {
+		actual, err := testutil.RunGit(t, dir, "config", "--get", key)
+		if err != nil || strings.TrimSpace(actual) != value {
+			t.Errorf("Expected %s to be '%s', got '%s'", key, value, strings.TrimSpace(actual))
+		}
+	}
+}
+
+// TestConfigDoctorNormalizesPrefixes tests that config doctor --fix makes prefixes end with a single slash.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Sets a feature prefix without and a bugfix prefix with two trailing slashes
+// 3. Runs git flow config doctor --fix --yes
+// 4. Verifies both prefixes end with a single slash and other prefixes are unchanged
+func TestConfigDoctorNormalizesPrefixes(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Misconfigure the prefixes
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch.feature.prefix", "feat"); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch.bugfix.prefix", "bugfix//"); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+
+	// Fix them
+	output, err = testutil.RunGitFlow(t, dir, "config", "doctor", "--fix", "--yes")
+	if err != nil {
+		t.Fatalf("Failed to fix configuration: %v\nOutput: %s", err, output)
+	}
+
+	// Verify the prefixes
+	expected := map[string]string{
+		"gitflow.branch.feature.prefix": "feat/",
+		"gitflow.branch.bugfix.prefix":  "bugfix/",
+		"gitflow.branch.release.prefix": "release/",
+	}
+	for key, value := range expected {
+		actual, err := testutil.RunGit(t, dir, "config", "--get", key)
+		if err != nil || strings.TrimSpace(actual) != value {
+			t.Errorf("Expected %s to be '%s', got '%s'", key, value, strings.TrimSpace(actual))
+		}
+	}
+}
+
+// TestConfigDoctorRemovesSettingsOfDeletedBranches tests that config doctor --fix removes settings of deleted branches.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Starts two feature branches and deletes one of them with git
+// 3. Runs git flow config doctor --fix --yes
+// 4. Verifies the settings of the deleted branch are removed and the others are kept
+func TestConfigDoctorRemovesSettingsOfDeletedBranches(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Start two feature branches and delete one of them behind git-flow's back
+	for _, name := range []string{"kept", "v1.0-gone"} {
+		output, err = testutil.RunGitFlow(t, dir, "feature", "start", name)
+		if err != nil {
+			t.Fatalf("Failed to start feature branch: %v\nOutput: %s", err, output)
+		}
+	}
+	if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+		t.Fatalf("Failed to checkout develop: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "branch", "-D", "feature/v1.0-gone"); err != nil {
+		t.Fatalf("Failed to delete branch: %v", err)
+	}
+
+	// Fix the configuration
+	output, err = testutil.RunGitFlow(t, dir, "config", "doctor", "--fix", "--yes")
+	if err != nil {
+		t.Fatalf("Failed to fix configuration: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "settings of deleted branch 'feature/v1.0-gone'") {
+		t.Errorf("Expected the removed settings to be reported, got: %s", output)
+	}
+
+	// Verify only the settings of the deleted branch are removed
+	if _, err := testutil.RunGit(t, dir, "config", "--get", "gitflow.branch.feature/v1.0-gone.base"); err == nil {
+		t.Error("Expected settings of the deleted branch to be removed")
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "--get", "gitflow.branch.feature/kept.base"); err != nil {
+		t.Error("Expected settings of the existing branch to be kept")
+	}
+}
+
+// TestConfigDoctorReportsUnfixableProblems tests that config doctor --fix leaves ambiguous problems as errors.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Makes main a child of develop to form a parent cycle
+// 3. Runs git flow config doctor --fix --yes and verifies it fails reporting the cycle
+// 4. Removes the cycle and deletes main
+// 5. Runs git flow config doctor --fix --yes and verifies it fails without creating main
+func TestConfigDoctorReportsUnfixableProblems(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// A parent cycle is reported but not fixed
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch.main.parent", "develop"); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "config", "doctor", "--fix", "--yes")
+	if err == nil {
+		t.Fatalf("Expected config doctor to fail on a parent cycle\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "parent chain forms a cycle: develop -> main -> develop") {
+		t.Errorf("Expected the cycle to be reported, got: %s", output)
+	}
+	if !strings.Contains(output, "has 1 problem(s)") {
+		t.Errorf("Expected one problem to be left, got: %s", output)
+	}
+
+	// A missing main branch is reported but not created
+	if _, err := testutil.RunGit(t, dir, "config", "--unset", "gitflow.branch.main.parent"); err != nil {
+		t.Fatalf("Failed to unset config: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+		t.Fatalf("Failed to checkout develop: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "branch", "-D", "main"); err != nil {
+		t.Fatalf("Failed to delete main: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "config", "doctor", "--fix", "--yes")
+	if err == nil {
+		t.Fatalf("Expected config doctor to fail on a missing main branch\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "Problem: base branch 'main' does not exist") {
+		t.Errorf("Expected the missing main branch to be reported, got: %s", output)
+	}
+	if testutil.BranchExists(t, dir, "main") {
+		t.Error("Expected main not to be created")
+	}
+}
+
+// TestConfigDoctorReportsOverlappingPrefixes tests that config doctor reports topic branch types whose prefixes overlap.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Sets the bugfix prefix to one that starts with the feature prefix
+// 3. Runs git flow config doctor --fix --yes and verifies it fails reporting the overlap
+// 4. Verifies the prefixes are unchanged
+func TestConfigDoctorReportsOverlappingPrefixes(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Make the bugfix prefix overlap the feature prefix
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch.bugfix.prefix", "feature/fix/"); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+
+	// The overlap is reported but not fixed
+	output, err = testutil.RunGitFlow(t, dir, "config", "doctor", "--fix", "--yes")
+	if err == nil {
+		t.Fatalf("Expected config doctor to fail on overlapping prefixes\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "Problem: prefixes of branch types 'bugfix' ('feature/fix/') and 'feature' ('feature/') overlap") {
+		t.Errorf("Expected the overlap to be reported, got: %s", output)
+	}
+	if !strings.Contains(output, "has 1 problem(s)") {
+		t.Errorf("Expected one problem to be left, got: %s", output)
+	}
+	actual, err := testutil.RunGit(t, dir, "config", "--get", "gitflow.branch.bugfix.prefix")
+	if err != nil || strings.TrimSpace(actual) != "feature/fix/" {
+		t.Errorf("Expected the bugfix prefix to be unchanged, got '%s'", strings.TrimSpace(actual))
+	}
+}
+
+// TestConfigSetAndGet tests changing branch settings with config set and reading them with config get.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Sets the upstream strategy of feature to squash, in mixed case
+// 3. Verifies config get and config branch report the new value, stored in lower case
+// 4. Sets autoupdate of develop to false and verifies config get reports it
+func TestConfigSetAndGet(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "config", "get", "feature", "upstreamstrategy")
+	if err != nil {
+		t.Fatalf("Failed to get setting: %v\nOutput: %s", err, output)
+	}
+	if output != "merge\n" {
+		t.Errorf("Expected upstream strategy merge, got: %s", output)
+	}
+
+	// Set a strategy
+	output, err = testutil.RunGitFlow(t, dir, "config", "set", "feature", "upstreamStrategy", "Squash")
+	if err != nil {
+		t.Fatalf("Failed to set setting: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Set gitflow.branch.feature.upstreamStrategy to 'squash'") {
+		t.Errorf("Expected the change to be reported, got: %s", output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "config", "get", "feature", "upstreamstrategy")
+	if err != nil {
+		t.Fatalf("Failed to get setting: %v\nOutput: %s", err, output)
+	}
+	if output != "squash\n" {
+		t.Errorf("Expected upstream strategy squash, got: %s", output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "config", "branch", "feature")
+	if err != nil {
+		t.Fatalf("Failed to show branch configuration: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "upstreamstrategy: squash\n") {
+		t.Errorf("Expected config branch to show the new strategy, got: %s", output)
+	}
+	value, err := testutil.RunGit(t, dir, "config", "gitflow.branch.feature.upstreamStrategy")
+	if err != nil || strings.TrimSpace(value) != "squash" {
+		t.Errorf("Expected the git config key to be squash, got '%s' (%v)", strings.TrimSpace(value), err)
+	}
+
+	// Set a flag
+	if output, err = testutil.RunGitFlow(t, dir, "config", "set", "develop", "autoupdate", "false"); err != nil {
+		t.Fatalf("Failed to set setting: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "config", "get", "develop", "autoupdate")
+	if err != nil {
+		t.Fatalf("Failed to get setting: %v\nOutput: %s", err, output)
+	}
+	if output != "false\n" {
+		t.Errorf("Expected autoupdate false, got: %s", output)
+	}
+}
+
+// TestConfigSetRejectsInvalidValues tests that config set refuses unknown properties and invalid values.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Tries an invalid strategy, an unknown property, an unknown branch, a parent cycle and a non-boolean flag
+// 3. Verifies each fails with exit code 2 and a clear error
+// 4. Verifies the configuration is unchanged
+func TestConfigSetRejectsInvalidValues(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"feature", "upstreamstrategy", "fast-forward"}, "unknown strategy 'fast-forward', expected merge, rebase, squash or none"},
+		{[]string{"feature", "colour", "red"}, "unknown property 'colour'"},
+		{[]string{"unknown", "upstreamstrategy", "merge"}, "unknown branch type: unknown"},
+		{[]string{"main", "parent", "develop"}, "parent chain would form a cycle: main -> develop -> main"},
+		{[]string{"feature", "parent", "feature"}, "'feature' is not a configured base branch"},
+		{[]string{"develop", "autoupdate", "yes"}, "expected true or false, got 'yes'"},
+		{[]string{"feature", "prefix", "feat"}, "prefix 'feat' has to end with a single '/'"},
+	}
+	for _, test := range tests {
+		output, err := testutil.RunGitFlow(t, dir, append([]string{"config", "set"}, test.args...)...)
+		if exitErr, ok := err.(*testutil.ExitError); !ok || exitErr.ExitCode != 2 {
+			t.Errorf("Expected config set %s to fail with exit code 2, got %v\nOutput: %s", strings.Join(test.args, " "), err, output)
+		}
+		if !strings.Contains(output, test.expected) {
+			t.Errorf("Expected error '%s', got: %s", test.expected, output)
+		}
+	}
+
+	// Nothing was changed
+	for key, expected := range map[string]string{
+		"gitflow.branch.feature.upstreamStrategy": "merge",
+		"gitflow.branch.feature.prefix":           "feature/",
+		"gitflow.branch.develop.autoUpdate":       "true",
+	} {
+		value, err := testutil.RunGit(t, dir, "config", key)
+		if err != nil || strings.TrimSpace(value) != expected {
+			t.Errorf("Expected %s to stay '%s', got '%s'", key, expected, strings.TrimSpace(value))
+		}
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch.main.parent"); err == nil {
+		t.Error("Expected main to have no parent")
+	}
+}
+
+// TestConfigList tests printing the configuration of all branches as a tree.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Runs git flow config list
+// 3. Verifies develop is listed below main and feature below develop, each with its settings
+func TestConfigList(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "config", "list")
+	if err != nil {
+		t.Fatalf("Failed to list configuration: %v\nOutput: %s", err, output)
+	}
+	for _, expected := range []string{
+		"remote: origin\nmain (base)\n  startpoint: \n  upstreamstrategy: none\n",
+		"\n  develop (base)\n    startpoint: \n    upstreamstrategy: merge\n",
+		"\n    feature (topic)\n      startpoint: develop\n      upstreamstrategy: merge\n      downstreamstrategy: rebase\n      prefix: feature/\n",
+		"\n  hotfix (topic)\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected the list to contain:\n%s\ngot:\n%s", expected, output)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package cmd_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/gittower/git-flow-next/test/testutil"
@@ -14,6 +15,7 @@ import (
 // 4. Attempts to delete without force flag (should fail)
 // 5. Deletes with force flag
 // 6. Verifies the branch is deleted
+// 7. Verifies the per-branch settings are removed
 func TestDeleteFeature(t *testing.T) {
 	// Setup
 	dir := testutil.SetupTestRepo(t)
@@ -43,7 +45,7 @@ func TestDeleteFeature(t *testing.T) {
 	}
 
 	// Try to delete without force flag (should fail)
-	output, err = testutil.RunGitFlow(t, dir, "feature", "delete", "test-feature")
+	output, err = testutil.RunGitFlow(t, dir, "feature", "delete", "test-feature", "--yes")
 	if err == nil {
 		t.Fatal("Expected delete to fail without force flag")
 	}
@@ -58,6 +60,12 @@ func TestDeleteFeature(t *testing.T) {
 	if testutil.BranchExists(t, dir, "feature/test-feature") {
 		t.Error("Expected feature branch to be deleted")
 	}
+
+	// Verify the per-branch settings are removed
+	keys, err := testutil.RunGit(t, dir, "config", "--get-regexp", `^gitflow\.branch\.feature/test-feature\.`)
+	if err == nil || strings.TrimSpace(keys) != "" {
+		t.Errorf("Expected no settings for the deleted branch, got: %s", keys)
+	}
 }
 
 // TestDeleteCurrentFeature tests deleting a feature branch while it is checked out.
@@ -168,7 +176,7 @@ func TestDeleteMergedFeature(t *testing.T) {
 	}
 
 	// Try to delete the already merged branch (should fail)
-	output, err = testutil.RunGitFlow(t, dir, "feature", "delete", "merged-feature")
+	output, err = testutil.RunGitFlow(t, dir, "feature", "delete", "merged-feature", "--yes")
 	if err == nil {
 		t.Fatal("Expected delete to fail for already merged branch")
 	}
@@ -236,7 +244,7 @@ func TestDeleteFeatureWithRemote(t *testing.T) {
 	}
 
 	// Delete feature branch with remote deletion
-	_, err = testutil.RunGitFlow(t, dir, "feature", "delete", "test-feature", "--remote")
+	_, err = testutil.RunGitFlow(t, dir, "feature", "delete", "test-feature", "--yes", "--remote")
 	if err != nil {
 		t.Fatalf("Failed to delete feature branch: %v", err)
 	}
@@ -298,7 +306,7 @@ func TestDeleteFeatureWithConfigEnabled(t *testing.T) {
 	}
 
 	// Delete feature branch without remote flag
-	_, err = testutil.RunGitFlow(t, dir, "feature", "delete", "test-feature")
+	_, err = testutil.RunGitFlow(t, dir, "feature", "delete", "test-feature", "--yes")
 	if err != nil {
 		t.Fatalf("Failed to delete feature branch: %v", err)
 	}
@@ -360,7 +368,7 @@ func TestDeleteFeatureWithConfigDisabled(t *testing.T) {
 	}
 
 	// Delete feature branch without remote flag
-	_, err = testutil.RunGitFlow(t, dir, "feature", "delete", "test-feature")
+	_, err = testutil.RunGitFlow(t, dir, "feature", "delete", "test-feature", "--yes")
 	if err != nil {
 		t.Fatalf("Failed to delete feature branch: %v", err)
 	}
@@ -422,7 +430,7 @@ func TestDeleteFeatureWithCommandLineOverride(t *testing.T) {
 	}
 
 	// Delete feature branch with remote flag to override config
-	_, err = testutil.RunGitFlow(t, dir, "feature", "delete", "test-feature", "--remote")
+	_, err = testutil.RunGitFlow(t, dir, "feature", "delete", "test-feature", "--yes", "--remote")
 	if err != nil {
 		t.Fatalf("Failed to delete feature branch: %v", err)
 	}
@@ -477,7 +485,7 @@ func TestDeleteFeatureWithNonExistentRemote(t *testing.T) {
 	}
 
 	// Delete feature branch with remote deletion - should fail
-	_, err = testutil.RunGitFlow(t, dir, "feature", "delete", "test-feature", "--remote")
+	_, err = testutil.RunGitFlow(t, dir, "feature", "delete", "test-feature", "--yes", "--remote")
 	if err == nil {
 		t.Fatalf("Expected error when deleting non-existent remote branch")
 	}
@@ -535,7 +543,7 @@ func TestDeleteFeatureWithCustomRemote(t *testing.T) {
 	}
 
 	// Delete feature branch with remote deletion
-	_, err = testutil.RunGitFlow(t, dir, "feature", "delete", "test-feature", "--remote")
+	_, err = testutil.RunGitFlow(t, dir, "feature", "delete", "test-feature", "--yes", "--remote")
 	if err != nil {
 		t.Fatalf("Failed to delete feature branch: %v", err)
 	}
@@ -597,7 +605,7 @@ func TestDeleteFeatureWithNoRemoteOverride(t *testing.T) {
 	}
 
 	// Delete feature branch with no-remote flag to override config
-	_, err = testutil.RunGitFlow(t, dir, "feature", "delete", "test-feature", "--no-remote")
+	_, err = testutil.RunGitFlow(t, dir, "feature", "delete", "test-feature", "--yes", "--no-remote")
 	if err != nil {
 		t.Fatalf("Failed to delete feature branch: %v", err)
 	}
@@ -612,3 +620,119 @@ func TestDeleteFeatureWithNoRemoteOverride(t *testing.T) {
 		t.Errorf("Feature branch should still exist on remote")
 	}
 }
+
+// TestDeleteFeatureConfirmation tests the confirmation shown before deleting a branch.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates a feature branch with a pushed commit and an unpushed commit
+// 3. Answers "n" and verifies the summary is shown and the branch is kept
+// 4. Verifies deleting without an answer fails instead of waiting
+// 5. Verifies --force deletes without asking
+// 6. Creates another feature branch, answers "y" and verifies it is deleted
+func TestDeleteFeatureConfirmation(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create a feature branch with a pushed commit
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "unmerged")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "pushed.txt", "pushed content")
+	_, err = testutil.RunGit(t, dir, "add", "pushed.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add pushed file")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	bareDir, err := testutil.AddRemote(t, dir, "origin", true)
+	if err != nil {
+		t.Fatalf("Failed to add remote: %v", err)
+	}
+	defer testutil.CleanupTestRepo(t, bareDir)
+	_, err = testutil.RunGit(t, dir, "fetch", "origin")
+	if err != nil {
+		t.Fatalf("Failed to fetch: %v", err)
+	}
+
+	// Add an unpushed commit
+	testutil.WriteFile(t, dir, "unpushed.txt", "unpushed content")
+	_, err = testutil.RunGit(t, dir, "add", "unpushed.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add unpushed file")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Decline the confirmation
+	output, err = testutil.RunGitFlowWithInput(t, dir, "n\n", "feature", "delete", "unmerged")
+	if err != nil {
+		t.Fatalf("Expected declined delete to succeed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Branch 'feature/unmerged' has 2 commit(s) not merged into 'develop'") {
+		t.Errorf("Expected unmerged commit count in output, got: %s", output)
+	}
+	if !strings.Contains(output, "Remote backup 'origin/feature/unmerged' is missing 1 commit(s)") {
+		t.Errorf("Expected remote backup status in output, got: %s", output)
+	}
+	if !strings.Contains(output, "Delete cancelled") {
+		t.Errorf("Expected cancellation message, got: %s", output)
+	}
+	if !testutil.BranchExists(t, dir, "feature/unmerged") {
+		t.Error("Expected feature branch to still exist after declining")
+	}
+	if testutil.GetCurrentBranch(t, dir) != "feature/unmerged" {
+		t.Error("Expected to stay on the feature branch after declining")
+	}
+
+	// Without any answer the command fails instead of waiting
+	output, err = testutil.RunGitFlowWithInput(t, dir, "", "feature", "delete", "unmerged")
+	if err == nil {
+		t.Fatalf("Expected delete without an answer to fail, got: %s", output)
+	}
+	if !strings.Contains(output, "--yes") {
+		t.Errorf("Expected error to mention --yes, got: %s", output)
+	}
+	if !testutil.BranchExists(t, dir, "feature/unmerged") {
+		t.Error("Expected feature branch to still exist without an answer")
+	}
+
+	// --force skips the confirmation
+	output, err = testutil.RunGitFlowWithInput(t, dir, "", "feature", "delete", "unmerged", "--force")
+	if err != nil {
+		t.Fatalf("Failed to force delete feature branch: %v\nOutput: %s", err, output)
+	}
+	if strings.Contains(output, "[y/N]") {
+		t.Errorf("Expected no confirmation with --force, got: %s", output)
+	}
+	if testutil.BranchExists(t, dir, "feature/unmerged") {
+		t.Error("Expected feature branch to be deleted with --force")
+	}
+
+	// Accept the confirmation for a branch without remote backup
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "merged")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlowWithInput(t, dir, "y\n", "feature", "delete", "merged")
+	if err != nil {
+		t.Fatalf("Failed to delete feature branch: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "No remote backup: 'origin/feature/merged' does not exist") {
+		t.Errorf("Expected missing remote backup in output, got: %s", output)
+	}
+	if testutil.BranchExists(t, dir, "feature/merged") {
+		t.Error("Expected feature branch to be deleted after confirming")
+	}
+}
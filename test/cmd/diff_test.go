@@ -0,0 +1,118 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// commitFile writes a file with the given content and commits it on the current branch
+func commitFile(t *testing.T, dir string, name string, content string) {
+	t.Helper()
+
+	testutil.WriteFile(t, dir, name, content)
+	if _, err := testutil.RunGit(t, dir, "add", name); err != nil {
+		t.Fatalf("Failed to add %s: %v", name, err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add "+name); err != nil {
+		t.Fatalf("Failed to commit %s: %v", name, err)
+	}
+}
+
+// setupTrackedFeature starts a feature branch with --track-parent-commit-only on top of a
+// develop commit adding base.txt, commits feature.txt on it, and then rewrites develop
+// so that base.txt is dropped and parent.txt is added. Only the recorded start commit
+// still identifies where the feature branch really started.
+func setupTrackedFeature(t *testing.T, dir string, name string) string {
+	t.Helper()
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	commitFile(t, dir, "base.txt", "base content")
+	startCommit, err := testutil.RunGit(t, dir, "rev-parse", "develop")
+	if err != nil {
+		t.Fatalf("Failed to resolve develop: %v", err)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", name, "--track-parent-commit-only")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "checkout", "feature/"+name); err != nil {
+		t.Fatalf("Failed to checkout feature branch: %v", err)
+	}
+	commitFile(t, dir, "feature.txt", "feature content")
+
+	// Rewrite develop and let it advance
+	if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+		t.Fatalf("Failed to checkout develop: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "reset", "--hard", "HEAD~1"); err != nil {
+		t.Fatalf("Failed to rewind develop: %v", err)
+	}
+	commitFile(t, dir, "parent.txt", "parent content")
+
+	return strings.TrimSpace(startCommit)
+}
+
+// TestDiffUsesRecordedStartCommit tests that diff compares against the recorded start commit.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Starts a feature branch with --track-parent-commit-only and commits on it
+// 3. Rewrites and advances develop
+// 4. Verifies the start commit was recorded and diff only shows the feature changes
+func TestDiffUsesRecordedStartCommit(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	startCommit := setupTrackedFeature(t, dir, "tracked")
+
+	// Verify the start commit was recorded
+	recorded, err := testutil.RunGit(t, dir, "config", "gitflow.branch.feature/tracked.startcommit")
+	if err != nil {
+		t.Fatalf("Expected start commit to be recorded: %v", err)
+	}
+	if strings.TrimSpace(recorded) != startCommit {
+		t.Errorf("Expected recorded start commit '%s', got '%s'", startCommit, strings.TrimSpace(recorded))
+	}
+
+	// Diff the feature branch
+	output, err := testutil.RunGitFlow(t, dir, "feature", "diff", "tracked")
+	if err != nil {
+		t.Fatalf("Failed to run feature diff: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "feature.txt") {
+		t.Errorf("Expected diff to contain feature.txt, got: %s", output)
+	}
+	if strings.Contains(output, "base.txt") || strings.Contains(output, "parent.txt") {
+		t.Errorf("Expected diff to only contain feature changes, got: %s", output)
+	}
+}
+
+// TestLogUsesRecordedStartCommit tests that log lists commits since the recorded start commit.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Starts a feature branch with --track-parent-commit-only and commits on it
+// 3. Rewrites and advances develop
+// 4. Verifies log only lists the feature commit
+func TestLogUsesRecordedStartCommit(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	setupTrackedFeature(t, dir, "tracked")
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "log", "tracked")
+	if err != nil {
+		t.Fatalf("Failed to run feature log: %v\nOutput: %s", err, output)
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 1 || !strings.Contains(lines[0], "Add feature.txt") {
+		t.Errorf("Expected log to only list the feature commit, got: %s", output)
+	}
+}
@@ -2,11 +2,24 @@ package cmd_test
 
 import (
 	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gittower/git-flow-next/test/testutil"
 )
@@ -1099,6 +1112,7 @@ func TestFinishNonStandardBranchWithForce(t *testing.T) {
 // 2. Creates a non-standard branch
 // 3. Attempts to finish the branch without force flag
 // 4. Verifies the operation fails with appropriate error
+// 5. Answers "yes" to the confirmation and verifies the branch is finished
 func TestFinishNonStandardBranchWithoutForce(t *testing.T) {
 	// Setup
 	dir := testutil.SetupTestRepo(t)
@@ -1147,6 +1161,71 @@ func TestFinishNonStandardBranchWithoutForce(t *testing.T) {
 	if currentBranch != "custom/my-branch" {
 		t.Errorf("Expected to be on custom/my-branch, got %s", currentBranch)
 	}
+
+	// Confirm the finish
+	output, err = testutil.RunGitFlowWithInput(t, dir, "yes\n", "feature", "finish", "custom/my-branch")
+	if err != nil {
+		t.Fatalf("Failed to finish custom branch after confirming: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Do you want to continue? [y/N]") {
+		t.Errorf("Expected confirmation prompt, got: %s", output)
+	}
+	if testutil.BranchExists(t, dir, "custom/my-branch") {
+		t.Error("Expected custom branch to be deleted")
+	}
+}
+
+// TestFinishNonStandardBranchWithAssumeYes tests finishing a non-standard branch non-interactively with --assume-yes.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates a non-standard branch with changes
+// 3. Finishes the branch with --assume-yes and no input
+// 4. Verifies no confirmation was asked and the branch is merged into develop and deleted
+func TestFinishNonStandardBranchWithAssumeYes(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create a non-standard branch from develop
+	_, err = testutil.RunGit(t, dir, "checkout", "-b", "custom/ci-branch", "develop")
+	if err != nil {
+		t.Fatalf("Failed to create custom branch: %v", err)
+	}
+	testutil.WriteFile(t, dir, "ci.txt", "ci content")
+	_, err = testutil.RunGit(t, dir, "add", "ci.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add ci file")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Finish the branch without any input
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "--assume-yes", "custom/ci-branch")
+	if err != nil {
+		t.Fatalf("Failed to finish custom branch with --assume-yes: %v\nOutput: %s", err, output)
+	}
+	if strings.Contains(output, "Do you want to continue?") {
+		t.Errorf("Expected no confirmation prompt, got: %s", output)
+	}
+
+	// Verify the branch was merged into develop and deleted
+	if testutil.GetCurrentBranch(t, dir) != "develop" {
+		t.Errorf("Expected to be on develop, got %s", testutil.GetCurrentBranch(t, dir))
+	}
+	if !testutil.FileExists(t, dir, "ci.txt") {
+		t.Error("Expected ci.txt to exist in develop branch")
+	}
+	if testutil.BranchExists(t, dir, "custom/ci-branch") {
+		t.Error("Expected custom branch to be deleted")
+	}
 }
 
 // TestFinishNonStandardBranchWithTag tests finishing a non-standard branch with tag creation.
@@ -2410,3 +2489,7296 @@ func TestFinishWithConsecutiveConflicts(t *testing.T) {
 		t.Errorf("Expected develop branch to have both release and develop-specific content, got: %s", developContent)
 	}
 }
+
+// TestFinishBaseBranchIsRefused tests that finishing a base branch is refused.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Attempts to finish the develop branch as a feature
+// 3. Verifies the operation fails with a protective error
+// 4. Verifies develop still exists and no merge state was saved
+func TestFinishBaseBranchIsRefused(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Try to finish develop as if it were a feature branch
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "develop")
+	if err == nil {
+		t.Fatalf("Expected finishing develop to fail\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "cannot finish 'develop'") {
+		t.Errorf("Expected protective error about finishing develop, got: %s", output)
+	}
+	if strings.Contains(output, "Do you want to continue?") {
+		t.Errorf("Expected no confirmation prompt for a base branch, got: %s", output)
+	}
+
+	// Verify develop is untouched and no merge state exists
+	if !testutil.BranchExists(t, dir, "develop") {
+		t.Error("Expected develop branch to still exist")
+	}
+	if _, err := testutil.LoadMergeState(t, dir); err == nil {
+		t.Error("Expected no merge state to be saved")
+	}
+}
+
+// TestFinishBranchReferringToBaseBranchIsRefused tests that a topic branch that is another name for a base
+// branch is not finished.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates feature/loop as a symbolic ref to develop and feature/trunk as a symbolic ref to main
+// 3. Attempts to finish feature loop and verifies it fails as a merge of develop into itself
+// 4. Attempts to finish feature trunk and verifies it fails as finishing the base branch main
+// 5. Verifies the branches are untouched and no merge state was saved
+func TestFinishBranchReferringToBaseBranchIsRefused(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create feature branches that are other names for base branches
+	if _, err := testutil.RunGit(t, dir, "symbolic-ref", "refs/heads/feature/loop", "refs/heads/develop"); err != nil {
+		t.Fatalf("Failed to create symbolic ref: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "symbolic-ref", "refs/heads/feature/trunk", "refs/heads/main"); err != nil {
+		t.Fatalf("Failed to create symbolic ref: %v", err)
+	}
+	developBefore, _ := testutil.RunGit(t, dir, "rev-parse", "develop")
+
+	// A name for the target would be merged into itself
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "loop")
+	if err == nil {
+		t.Fatalf("Expected finishing a branch referring to develop to fail\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "cannot finish 'feature/loop': it refers to the target branch 'develop'") {
+		t.Errorf("Expected error about merging develop into itself, got: %s", output)
+	}
+
+	// A name for another base branch is a base branch as well
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "trunk")
+	if err == nil {
+		t.Fatalf("Expected finishing a branch referring to main to fail\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "cannot finish 'feature/trunk': it refers to the base branch 'main'") {
+		t.Errorf("Expected error about finishing main, got: %s", output)
+	}
+
+	// Verify nothing changed
+	if developAfter, _ := testutil.RunGit(t, dir, "rev-parse", "develop"); developAfter != developBefore {
+		t.Error("Expected develop to be unchanged")
+	}
+	for _, branch := range []string{"feature/loop", "feature/trunk", "develop", "main"} {
+		if !testutil.BranchExists(t, dir, branch) {
+			t.Errorf("Expected branch '%s' to still exist", branch)
+		}
+	}
+	if _, err := testutil.LoadMergeState(t, dir); err == nil {
+		t.Error("Expected no merge state to be saved")
+	}
+}
+
+// setupConflictingFeature creates a feature branch and a develop commit that both
+// modify conflict.txt so that finishing the feature results in a content conflict.
+func setupConflictingFeature(t *testing.T, dir string, name string) {
+	t.Helper()
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "start", name)
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "conflict.txt", "feature content")
+	if _, err := testutil.RunGit(t, dir, "add", "conflict.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add conflict.txt in feature"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+		t.Fatalf("Failed to checkout develop: %v", err)
+	}
+	testutil.WriteFile(t, dir, "conflict.txt", "develop content")
+	if _, err := testutil.RunGit(t, dir, "add", "conflict.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add conflict.txt in develop"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+}
+
+// TestFinishWithTheirsAndOurs tests resolving conflicts in favor of one side during finish.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates conflicting changes in a feature branch and develop
+// 3. Finishes with --theirs and verifies the feature content wins
+// 4. Repeats with --ours and verifies the develop content wins
+// 5. Verifies a warning about silently dropped changes is printed
+func TestFinishWithTheirsAndOurs(t *testing.T) {
+	tests := []struct {
+		flag     string
+		expected string
+	}{
+		{"--theirs", "feature content"},
+		{"--ours", "develop content"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.flag, func(t *testing.T) {
+			dir := testutil.SetupTestRepo(t)
+			defer testutil.CleanupTestRepo(t, dir)
+
+			output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+			if err != nil {
+				t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+			}
+
+			setupConflictingFeature(t, dir, "biased")
+
+			// Finish with the resolution bias
+			output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "biased", tc.flag)
+			if err != nil {
+				t.Fatalf("Expected finish %s to succeed: %v\nOutput: %s", tc.flag, err, output)
+			}
+			if !strings.Contains(output, "silently dropped") {
+				t.Errorf("Expected warning about dropped changes, got: %s", output)
+			}
+
+			// Verify the expected side won
+			content, err := testutil.RunGit(t, dir, "show", "develop:conflict.txt")
+			if err != nil {
+				t.Fatalf("Failed to read conflict.txt from develop: %v", err)
+			}
+			if content != tc.expected {
+				t.Errorf("Expected conflict.txt to be '%s', got '%s'", tc.expected, content)
+			}
+			if testutil.BranchExists(t, dir, "feature/biased") {
+				t.Error("Expected feature branch to be deleted")
+			}
+		})
+	}
+}
+
+// TestFinishWithTheirsRejectedForRebase tests that --theirs is rejected with the rebase strategy.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Configures feature branches to finish with rebase
+// 3. Attempts to finish with --theirs
+// 4. Verifies the operation fails without merging
+func TestFinishWithTheirsRejectedForRebase(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	_, err = testutil.RunGit(t, dir, "config", "gitflow.branch.feature.upstreamstrategy", "rebase")
+	if err != nil {
+		t.Fatalf("Failed to set upstream strategy: %v", err)
+	}
+
+	setupConflictingFeature(t, dir, "rebase-biased")
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "rebase-biased", "--theirs")
+	if err == nil {
+		t.Fatalf("Expected --theirs to be rejected for rebase strategy\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "only supported with the merge and squash strategies") {
+		t.Errorf("Expected strategy validation error, got: %s", output)
+	}
+	if !testutil.BranchExists(t, dir, "feature/rebase-biased") {
+		t.Error("Expected feature branch to still exist")
+	}
+}
+
+// TestFinishWithStrategyOptionRenameThreshold tests passing a rename threshold to the merge with --strategy-option.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Renames and rewrites a file in a feature branch while develop changes its last line
+// 3. Finishes without options and verifies the rename is missed and the merge conflicts
+// 4. Aborts and finishes with --strategy-option find-renames=30%
+// 5. Verifies the rename is detected and develop's change is kept in the renamed file
+// 6. Verifies invalid strategy options are rejected
+func TestFinishWithStrategyOptionRenameThreshold(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	lines := []string{}
+	for i := 1; i <= 10; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	if err := testutil.WriteFile(t, dir, "renamed.txt", strings.Join(lines, "\n")+"\n"); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "add", "renamed.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add renamed.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Rename the file in the feature branch and change enough of it to fall below the default threshold
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "rename")
+	if err != nil {
+		t.Fatalf("Failed to start feature branch: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "mv", "renamed.txt", "moved.txt"); err != nil {
+		t.Fatalf("Failed to rename file: %v", err)
+	}
+	featureLines := append([]string{}, lines...)
+	for i := 0; i < 4; i++ {
+		featureLines[i] = "changed " + featureLines[i]
+	}
+	if err := testutil.WriteFile(t, dir, "moved.txt", strings.Join(featureLines, "\n")+"\n"); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-am", "Rename and rewrite renamed.txt"); err != nil {
+		t.Fatalf("Failed to commit rename: %v", err)
+	}
+
+	// Change the last line in develop
+	if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+		t.Fatalf("Failed to checkout develop: %v", err)
+	}
+	lines[9] = "line ten"
+	if err := testutil.WriteFile(t, dir, "renamed.txt", strings.Join(lines, "\n")+"\n"); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-am", "Change the last line"); err != nil {
+		t.Fatalf("Failed to commit change: %v", err)
+	}
+
+	// Without a lower threshold the rename is not detected
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "rename")
+	if err == nil {
+		t.Fatalf("Expected finish to conflict without a rename threshold\nOutput: %s", output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "--abort", "rename")
+	if err != nil {
+		t.Fatalf("Failed to abort finish: %v\nOutput: %s", err, output)
+	}
+
+	// Invalid options are rejected before merging
+	for _, option := range []string{"find-renames=30", "rename-threshold=150%", "theirs", "octopus"} {
+		output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "rename", "--strategy-option", option)
+		if err == nil {
+			t.Fatalf("Expected --strategy-option %s to be rejected\nOutput: %s", option, output)
+		}
+		if !strings.Contains(output, "invalid option --strategy-option") {
+			t.Errorf("Expected strategy option validation error for %s, got: %s", option, output)
+		}
+	}
+
+	// A lower threshold detects the rename and merges cleanly
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "rename", "--strategy-option", "find-renames=30%")
+	if err != nil {
+		t.Fatalf("Expected finish with find-renames=30%% to succeed: %v\nOutput: %s", err, output)
+	}
+	content, err := testutil.RunGit(t, dir, "show", "develop:moved.txt")
+	if err != nil {
+		t.Fatalf("Failed to read moved.txt from develop: %v", err)
+	}
+	if !strings.Contains(content, "changed line 1") || !strings.Contains(content, "line ten") {
+		t.Errorf("Expected moved.txt to contain both changes, got:\n%s", content)
+	}
+	if testutil.FileExists(t, dir, "renamed.txt") {
+		t.Error("Expected renamed.txt to be gone after the merge")
+	}
+}
+
+// TestFinishWithPostFinishCommand tests running a command after a successful finish.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates a release branch with a commit
+// 3. Finishes it with --post-finish-command writing the GITFLOW_* variables to a file
+// 4. Verifies the command ran with branch, target and tag populated
+func TestFinishWithPostFinishCommand(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create a release branch with a commit
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release content")
+	_, err = testutil.RunGit(t, dir, "add", "release.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add release file")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Finish with a post-finish command
+	hook := `echo "$GITFLOW_BRANCH|$GITFLOW_TARGET|$GITFLOW_TAG" > hook.out`
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--post-finish-command", hook)
+	if err != nil {
+		t.Fatalf("Failed to finish release branch: %v\nOutput: %s", err, output)
+	}
+
+	// Verify the command ran with the expected environment
+	if !testutil.FileExists(t, dir, "hook.out") {
+		t.Fatalf("Expected post-finish command to create hook.out\nOutput: %s", output)
+	}
+	content := strings.TrimSpace(testutil.ReadFile(t, dir, "hook.out"))
+	if content != "release/1.0.0|main|1.0.0" {
+		t.Errorf("Expected hook environment 'release/1.0.0|main|1.0.0', got '%s'", content)
+	}
+}
+
+// TestFinishWithFailingPostFinishCommand tests that a failing hook does not fail the finish.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Configures a failing post-finish command via gitflow.feature.finish.postcommand
+// 3. Finishes a feature branch
+// 4. Verifies the finish succeeds and a warning is printed
+func TestFinishWithFailingPostFinishCommand(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	_, err = testutil.RunGit(t, dir, "config", "gitflow.feature.finish.postcommand", "exit 3")
+	if err != nil {
+		t.Fatalf("Failed to configure post-finish command: %v", err)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "hooked")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "hooked.txt", "content")
+	_, err = testutil.RunGit(t, dir, "add", "hooked.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add hooked file")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Finish should succeed despite the failing hook
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "hooked")
+	if err != nil {
+		t.Fatalf("Expected finish to succeed despite failing hook: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Warning: post-finish command failed") {
+		t.Errorf("Expected warning about failing post-finish command, got: %s", output)
+	}
+	if testutil.BranchExists(t, dir, "feature/hooked") {
+		t.Error("Expected feature branch to be deleted")
+	}
+}
+
+// TestFinishWithSourceRef tests finishing an arbitrary ref instead of a topic branch.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates a tagged commit that is not on any git-flow branch
+// 3. Finishes the tag into develop using --source-ref
+// 4. Verifies the tagged changes are merged into develop
+// 5. Verifies the tag still exists and no merge state remains
+func TestFinishWithSourceRef(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create a tagged commit on a throwaway branch, then remove the branch
+	_, err = testutil.RunGit(t, dir, "checkout", "-b", "import", "develop")
+	if err != nil {
+		t.Fatalf("Failed to create import branch: %v", err)
+	}
+	testutil.WriteFile(t, dir, "imported.txt", "imported content")
+	_, err = testutil.RunGit(t, dir, "add", "imported.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add imported file")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "tag", "-a", "import-1.0", "-m", "Import 1.0")
+	if err != nil {
+		t.Fatalf("Failed to create tag: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "checkout", "develop")
+	if err != nil {
+		t.Fatalf("Failed to checkout develop: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "branch", "-D", "import")
+	if err != nil {
+		t.Fatalf("Failed to delete import branch: %v", err)
+	}
+
+	// Finish the tag into develop
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "imported", "--source-ref", "import-1.0")
+	if err != nil {
+		t.Fatalf("Failed to finish source ref: %v\nOutput: %s", err, output)
+	}
+
+	// Verify the changes are on develop
+	content, err := testutil.RunGit(t, dir, "show", "develop:imported.txt")
+	if err != nil {
+		t.Fatalf("Expected imported.txt on develop: %v", err)
+	}
+	if content != "imported content" {
+		t.Errorf("Expected imported content on develop, got '%s'", content)
+	}
+
+	// Verify the tag is untouched and the state is cleared
+	output, err = testutil.RunGit(t, dir, "tag", "-l", "import-1.0")
+	if err != nil || strings.TrimSpace(output) != "import-1.0" {
+		t.Errorf("Expected tag 'import-1.0' to still exist, got: %s", output)
+	}
+	if _, err := testutil.LoadMergeState(t, dir); err == nil {
+		t.Error("Expected merge state to be cleared")
+	}
+}
+
+// TestFinishWithMissingSourceRef tests that a missing source ref is rejected.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Attempts to finish a non-existent ref with --source-ref
+// 3. Verifies the operation fails with a clear error
+func TestFinishWithMissingSourceRef(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "ghost", "--source-ref", "no-such-tag")
+	if err == nil {
+		t.Fatalf("Expected finish with a missing source ref to fail\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "resolve source ref 'no-such-tag'") {
+		t.Errorf("Expected error about the missing source ref, got: %s", output)
+	}
+}
+
+// TestFinishWithSubmoduleConflict tests the guidance printed for conflicting submodule pointers.
+// Steps:
+// 1. Sets up a submodule repository with two diverging commits
+// 2. Sets up a test repository, initializes git-flow and adds the submodule on develop
+// 3. Points the submodule to different commits in a feature branch and develop
+// 4. Attempts to finish the feature branch
+// 5. Verifies submodule-specific guidance with both commits is printed
+func TestFinishWithSubmoduleConflict(t *testing.T) {
+	// Setup the submodule repository with two diverging branches
+	subDir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, subDir)
+
+	commits := map[string]string{}
+	for _, branch := range []string{"left", "right"} {
+		_, err := testutil.RunGit(t, subDir, "checkout", "-b", branch, "main")
+		if err != nil {
+			t.Fatalf("Failed to create submodule branch: %v", err)
+		}
+		_, err = testutil.RunGit(t, subDir, "commit", "--allow-empty", "-m", "Commit on "+branch)
+		if err != nil {
+			t.Fatalf("Failed to commit in submodule: %v", err)
+		}
+		sha, err := testutil.RunGit(t, subDir, "rev-parse", "HEAD")
+		if err != nil {
+			t.Fatalf("Failed to get submodule SHA: %v", err)
+		}
+		commits[branch] = strings.TrimSpace(sha)
+	}
+	_, err := testutil.RunGit(t, subDir, "checkout", "main")
+	if err != nil {
+		t.Fatalf("Failed to checkout submodule main: %v", err)
+	}
+
+	// Setup the superproject
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	_, err = testutil.RunGit(t, dir, "-c", "protocol.file.allow=always", "submodule", "add", subDir, "sub")
+	if err != nil {
+		t.Fatalf("Failed to add submodule: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add submodule")
+	if err != nil {
+		t.Fatalf("Failed to commit submodule: %v", err)
+	}
+
+	// Point the submodule to different commits on feature and develop
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "sub-update")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	for _, step := range []struct{ branch, subBranch string }{{"feature/sub-update", "left"}, {"develop", "right"}} {
+		_, err = testutil.RunGit(t, dir, "checkout", step.branch)
+		if err != nil {
+			t.Fatalf("Failed to checkout %s: %v", step.branch, err)
+		}
+		_, err = testutil.RunGit(t, filepath.Join(dir, "sub"), "checkout", step.subBranch)
+		if err != nil {
+			t.Fatalf("Failed to move submodule: %v", err)
+		}
+		_, err = testutil.RunGit(t, dir, "add", "sub")
+		if err != nil {
+			t.Fatalf("Failed to stage submodule: %v", err)
+		}
+		_, err = testutil.RunGit(t, dir, "commit", "-m", "Move submodule to "+step.subBranch)
+		if err != nil {
+			t.Fatalf("Failed to commit submodule pointer: %v", err)
+		}
+	}
+
+	// Finishing should stop with submodule guidance
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "sub-update")
+	if err == nil {
+		t.Fatalf("Expected finish to fail due to submodule conflict\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "Submodule conflict in 'sub'") {
+		t.Errorf("Expected submodule conflict guidance, got: %s", output)
+	}
+	if !strings.Contains(output, "develop points to "+commits["right"][:7]) {
+		t.Errorf("Expected develop's submodule commit in output, got: %s", output)
+	}
+	if !strings.Contains(output, "feature/sub-update points to "+commits["left"][:7]) {
+		t.Errorf("Expected the feature's submodule commit in output, got: %s", output)
+	}
+	if !strings.Contains(output, "git -C sub checkout <commit> && git add sub") {
+		t.Errorf("Expected resolution instructions, got: %s", output)
+	}
+}
+
+// TestFinishWithSkipEmptyChildren tests that child base branches already containing the parent tip are skipped.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Adds two child base branches 'staging' and 'qa' of develop
+// 3. Finishes a conflicting feature with --skip-empty-children and resolves the conflict
+// 4. Fast-forwards 'staging' to develop before continuing
+// 5. Verifies 'staging' is reported as up to date and left untouched
+// 6. Verifies only 'qa' receives a merge commit
+func TestFinishWithSkipEmptyChildren(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Add child base branches of develop
+	for _, child := range []string{"staging", "qa"} {
+		if _, err := testutil.RunGit(t, dir, "branch", child, "develop"); err != nil {
+			t.Fatalf("Failed to create branch '%s': %v", child, err)
+		}
+		if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch."+child+".type", "base"); err != nil {
+			t.Fatalf("Failed to set type for '%s': %v", child, err)
+		}
+		if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch."+child+".parent", "develop"); err != nil {
+			t.Fatalf("Failed to set parent for '%s': %v", child, err)
+		}
+		if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch."+child+".autoUpdate", "true"); err != nil {
+			t.Fatalf("Failed to enable auto update for '%s': %v", child, err)
+		}
+		if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch."+child+".downstreamStrategy", "merge"); err != nil {
+			t.Fatalf("Failed to set downstream strategy for '%s': %v", child, err)
+		}
+	}
+
+	setupConflictingFeature(t, dir, "skip-children")
+
+	// Finish runs into a conflict while merging into develop
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "skip-children", "--skip-empty-children")
+	if err == nil {
+		t.Fatalf("Expected finish to stop on conflict\nOutput: %s", output)
+	}
+
+	// Resolve and commit the merge
+	testutil.WriteFile(t, dir, "conflict.txt", "resolved content")
+	if _, err := testutil.RunGit(t, dir, "add", "conflict.txt"); err != nil {
+		t.Fatalf("Failed to stage resolved file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "--no-edit"); err != nil {
+		t.Fatalf("Failed to commit resolved merge: %v", err)
+	}
+
+	// Bring staging up to date so it already contains the develop tip
+	if _, err := testutil.RunGit(t, dir, "branch", "-f", "staging", "develop"); err != nil {
+		t.Fatalf("Failed to fast-forward staging: %v", err)
+	}
+	developTip, err := testutil.RunGit(t, dir, "rev-parse", "develop")
+	if err != nil {
+		t.Fatalf("Failed to resolve develop: %v", err)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "--continue", "skip-children")
+	if err != nil {
+		t.Fatalf("Failed to continue finish: %v\nOutput: %s", err, output)
+	}
+
+	// Verify staging was skipped
+	if !strings.Contains(output, "Child base branch 'staging' already up to date") {
+		t.Errorf("Expected staging to be reported as up to date, got: %s", output)
+	}
+	stagingTip, err := testutil.RunGit(t, dir, "rev-parse", "staging")
+	if err != nil {
+		t.Fatalf("Failed to resolve staging: %v", err)
+	}
+	if stagingTip != developTip {
+		t.Errorf("Expected staging to stay at develop tip %s, got %s", developTip, stagingTip)
+	}
+
+	// Verify qa received a merge from develop
+	if strings.Contains(output, "Child base branch 'qa' already up to date") {
+		t.Errorf("Expected qa to be updated, got: %s", output)
+	}
+	parents, err := testutil.RunGit(t, dir, "log", "-1", "--format=%P", "qa")
+	if err != nil {
+		t.Fatalf("Failed to read qa parents: %v", err)
+	}
+	if len(strings.Fields(parents)) != 2 || !strings.Contains(parents, developTip) {
+		t.Errorf("Expected qa tip to be a merge of develop, got parents '%s'", parents)
+	}
+}
+
+// TestFinishWithTagMessageFromChangelog tests using the changelog's unreleased section as the tag message.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates a release branch with a CHANGELOG.md containing an unreleased section
+// 3. Finishes it with --tag-message-from-changelog
+// 4. Verifies the tag message contains only the unreleased entries
+func TestFinishWithTagMessageFromChangelog(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create a release branch with a changelog
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.1.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	changelog := "# Changelog\n\n## [Unreleased]\n\n### Added\n- Login page\n\n### Fixed\n- Crash on startup\n\n## [1.0.0] - 2024-01-01\n\n- Initial release\n"
+	testutil.WriteFile(t, dir, "CHANGELOG.md", changelog)
+	_, err = testutil.RunGit(t, dir, "add", "CHANGELOG.md")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Update changelog")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Finish using the changelog as the tag message
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.1.0", "--tag-message-from-changelog")
+	if err != nil {
+		t.Fatalf("Failed to finish release branch: %v\nOutput: %s", err, output)
+	}
+
+	// Verify the tag message is the unreleased section
+	message, err := testutil.RunGit(t, dir, "for-each-ref", "--format=%(contents)", "refs/tags/1.1.0")
+	if err != nil {
+		t.Fatalf("Failed to read tag message: %v", err)
+	}
+	expected := "### Added\n- Login page\n\n### Fixed\n- Crash on startup"
+	if strings.TrimSpace(message) != expected {
+		t.Errorf("Expected tag message '%s', got '%s'", expected, strings.TrimSpace(message))
+	}
+}
+
+// TestFinishContinueWithInconsistentState tests that --continue detects a saved step that does not match git.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Finishes a conflicting feature so the topic merge is left in progress
+// 3. Rewrites the saved step to 'update_children' to desync state and repository
+// 4. Verifies --continue fails with a mismatch error and leaves the merge untouched
+func TestFinishContinueWithInconsistentState(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	setupConflictingFeature(t, dir, "desync")
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "desync")
+	if err == nil {
+		t.Fatalf("Expected finish to stop on conflict\nOutput: %s", output)
+	}
+
+	// Desync the saved step from the pending topic merge
+	state, err := testutil.LoadMergeState(t, dir)
+	if err != nil {
+		t.Fatalf("Failed to load merge state: %v", err)
+	}
+	state.CurrentStep = "update_children"
+	testutil.SaveMergeState(t, dir, state)
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "--continue", "desync")
+	if err == nil {
+		t.Fatalf("Expected --continue to fail on inconsistent state\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "saved finish step 'update_children' does not match the repository") {
+		t.Errorf("Expected mismatch error, got: %s", output)
+	}
+	if !strings.Contains(output, "git flow feature finish --abort desync") {
+		t.Errorf("Expected recovery suggestion, got: %s", output)
+	}
+
+	// Verify nothing was touched
+	if !testutil.IsMergeInProgress(t, dir) {
+		t.Error("Expected the topic merge to still be in progress")
+	}
+	if testutil.GetCurrentBranch(t, dir) != "develop" {
+		t.Errorf("Expected to still be on develop, got '%s'", testutil.GetCurrentBranch(t, dir))
+	}
+}
+
+// TestFinishWithRenameTagIfExists tests that an existing tag name gets a numeric suffix.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates a tag '1.0.0' and a release branch '1.0.0'
+// 3. Finishes the release with --rename-tag-if-exists
+// 4. Verifies the tag '1.0.0-2' is created on main and the original tag is untouched
+func TestFinishWithRenameTagIfExists(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Tag the current main as an earlier 1.0.0
+	_, err = testutil.RunGit(t, dir, "tag", "-a", "1.0.0", "-m", "Earlier 1.0.0", "main")
+	if err != nil {
+		t.Fatalf("Failed to create tag: %v", err)
+	}
+	originalTag, err := testutil.RunGit(t, dir, "rev-parse", "1.0.0^{commit}")
+	if err != nil {
+		t.Fatalf("Failed to resolve tag: %v", err)
+	}
+
+	// Create a release branch with a commit
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release content")
+	_, err = testutil.RunGit(t, dir, "add", "release.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add release file")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Finish with renaming enabled
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--rename-tag-if-exists")
+	if err != nil {
+		t.Fatalf("Failed to finish release branch: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Tag '1.0.0' already exists, using '1.0.0-2' instead") {
+		t.Errorf("Expected output to report the renamed tag, got: %s", output)
+	}
+
+	// Verify the suffixed tag points to main
+	renamedTag, err := testutil.RunGit(t, dir, "rev-parse", "1.0.0-2^{commit}")
+	if err != nil {
+		t.Fatalf("Expected tag '1.0.0-2' to exist: %v", err)
+	}
+	mainCommit, err := testutil.RunGit(t, dir, "rev-parse", "main")
+	if err != nil {
+		t.Fatalf("Failed to resolve main: %v", err)
+	}
+	if renamedTag != mainCommit {
+		t.Errorf("Expected tag '1.0.0-2' to point to main %s, got %s", mainCommit, renamedTag)
+	}
+
+	// Verify the original tag is untouched
+	currentTag, err := testutil.RunGit(t, dir, "rev-parse", "1.0.0^{commit}")
+	if err != nil {
+		t.Fatalf("Failed to resolve tag: %v", err)
+	}
+	if currentTag != originalTag {
+		t.Errorf("Expected tag '1.0.0' to stay at %s, got %s", originalTag, currentTag)
+	}
+}
+
+// TestFinishWithRefreshParent tests updating a stale target branch from its parent before merging.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Enables gitflow.feature.finish.refreshparent
+// 3. Creates a feature branch with a commit and a newer commit on main
+// 4. Finishes the feature branch
+// 5. Verifies develop was updated from main before the feature was merged
+func TestFinishWithRefreshParent(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	_, err = testutil.RunGit(t, dir, "config", "gitflow.feature.finish.refreshparent", "true")
+	if err != nil {
+		t.Fatalf("Failed to enable refreshparent: %v", err)
+	}
+
+	// Create a feature branch with a commit
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "refresh")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "feature.txt", "feature content")
+	_, err = testutil.RunGit(t, dir, "add", "feature.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add feature file")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Make develop stale relative to main
+	_, err = testutil.RunGit(t, dir, "checkout", "main")
+	if err != nil {
+		t.Fatalf("Failed to checkout main: %v", err)
+	}
+	testutil.WriteFile(t, dir, "hotfix.txt", "hotfix content")
+	_, err = testutil.RunGit(t, dir, "add", "hotfix.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add hotfix file")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Finish the feature
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "refresh")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Updating 'develop' from 'main' before merging") {
+		t.Errorf("Expected develop to be refreshed from main, got: %s", output)
+	}
+
+	// Verify develop contains main and the feature was merged on top of the refreshed develop
+	_, err = testutil.RunGit(t, dir, "merge-base", "--is-ancestor", "main", "develop")
+	if err != nil {
+		t.Error("Expected develop to contain main")
+	}
+	refreshedDevelop, err := testutil.RunGit(t, dir, "rev-parse", "develop^1")
+	if err != nil {
+		t.Fatalf("Failed to resolve first parent of develop: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "merge-base", "--is-ancestor", "main", strings.TrimSpace(refreshedDevelop))
+	if err != nil {
+		t.Error("Expected develop to be updated from main before the feature merge")
+	}
+	if !testutil.FileExists(t, dir, "feature.txt") || !testutil.FileExists(t, dir, "hotfix.txt") {
+		t.Error("Expected develop to contain both the feature and the hotfix file")
+	}
+}
+
+// TestFinishWithOutputFormat tests customizing the success line with --output-format.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates a release branch with a commit
+// 3. Finishes it with an --output-format template using all placeholders
+// 4. Verifies the rendered line replaces the default success message
+func TestFinishWithOutputFormat(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create a release branch with a commit
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release content")
+	_, err = testutil.RunGit(t, dir, "add", "release.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add release file")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Finish with a custom success line
+	format := "Done: %branch% -> %target% (%strategy%, tag %tag%, %childcount% children)"
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--output-format", format)
+	if err != nil {
+		t.Fatalf("Failed to finish release branch: %v\nOutput: %s", err, output)
+	}
+
+	if !strings.Contains(output, "Done: release/1.0.0 -> main (merge, tag 1.0.0, 1 children)\n") {
+		t.Errorf("Expected rendered success line, got: %s", output)
+	}
+	if strings.Contains(output, "Successfully finished branch") {
+		t.Errorf("Expected default success line to be replaced, got: %s", output)
+	}
+}
+
+// TestFinishWithCommitDate tests setting a fixed date for the merge commit and tag.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates a release branch with a commit
+// 3. Verifies an invalid --commit-date is rejected
+// 4. Finishes it with a fixed --commit-date
+// 5. Verifies the merge commit's author and committer date and the tag date
+func TestFinishWithCommitDate(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create a release branch with a commit
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release content")
+	_, err = testutil.RunGit(t, dir, "add", "release.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add release file")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// An invalid date is rejected before anything is merged
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--commit-date", "yesterday-ish")
+	if err == nil {
+		t.Fatalf("Expected invalid --commit-date to be rejected\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "unsupported date 'yesterday-ish'") {
+		t.Errorf("Expected date validation error, got: %s", output)
+	}
+
+	// Finish with a fixed date
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--commit-date", "2024-01-15T12:00:00Z")
+	if err != nil {
+		t.Fatalf("Failed to finish release branch: %v\nOutput: %s", err, output)
+	}
+
+	// Verify the merge commit's dates
+	dates, err := testutil.RunGit(t, dir, "log", "-1", "--format=%aI %cI", "main")
+	if err != nil {
+		t.Fatalf("Failed to read merge commit dates: %v", err)
+	}
+	if strings.TrimSpace(dates) != "2024-01-15T12:00:00+00:00 2024-01-15T12:00:00+00:00" {
+		t.Errorf("Expected merge commit dated 2024-01-15T12:00:00+00:00, got '%s'", strings.TrimSpace(dates))
+	}
+
+	// Verify the tag date
+	tagDate, err := testutil.RunGit(t, dir, "for-each-ref", "--format=%(taggerdate:iso-strict)", "refs/tags/1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to read tag date: %v", err)
+	}
+	if strings.TrimSpace(tagDate) != "2024-01-15T12:00:00+00:00" {
+		t.Errorf("Expected tag dated 2024-01-15T12:00:00+00:00, got '%s'", strings.TrimSpace(tagDate))
+	}
+}
+
+// TestFinishExcludesTargetFromChildBranches tests that the target branch is never updated as its own child.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Misconfigures develop as its own parent
+// 3. Finishes a feature branch into develop
+// 4. Verifies develop is not treated as a child base branch
+func TestFinishExcludesTargetFromChildBranches(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create a feature branch with a commit
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "self-parent")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "feature.txt", "feature content")
+	_, err = testutil.RunGit(t, dir, "add", "feature.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add feature file")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Misconfigure develop as its own parent
+	_, err = testutil.RunGit(t, dir, "config", "gitflow.branch.develop.parent", "develop")
+	if err != nil {
+		t.Fatalf("Failed to set develop parent: %v", err)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "self-parent")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+	if strings.Contains(output, "Found child base branch 'develop'") {
+		t.Errorf("Expected develop not to be treated as its own child, got: %s", output)
+	}
+	if !strings.Contains(output, "updated 0 child base branches") {
+		t.Errorf("Expected no child base branches to be updated, got: %s", output)
+	}
+}
+
+// TestFinishWithTagIncludingSHAs tests listing the resulting branch SHAs in the tag message.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Enables gitflow.release.finish.tagincludeshas
+// 3. Creates and finishes a release branch
+// 4. Verifies the tag points to main and lists the final SHAs of main and develop
+func TestFinishWithTagIncludingSHAs(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	_, err = testutil.RunGit(t, dir, "config", "gitflow.release.finish.tagincludeshas", "true")
+	if err != nil {
+		t.Fatalf("Failed to enable tagincludeshas: %v", err)
+	}
+
+	// Create a release branch with a commit
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "2.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release content")
+	_, err = testutil.RunGit(t, dir, "add", "release.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add release file")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "2.0.0")
+	if err != nil {
+		t.Fatalf("Failed to finish release branch: %v\nOutput: %s", err, output)
+	}
+
+	mainSHA, err := testutil.RunGit(t, dir, "rev-parse", "main")
+	if err != nil {
+		t.Fatalf("Failed to resolve main: %v", err)
+	}
+	developSHA, err := testutil.RunGit(t, dir, "rev-parse", "develop")
+	if err != nil {
+		t.Fatalf("Failed to resolve develop: %v", err)
+	}
+
+	// Verify the tag points to main even though it was created after updating develop
+	tagSHA, err := testutil.RunGit(t, dir, "rev-parse", "2.0.0^{commit}")
+	if err != nil {
+		t.Fatalf("Expected tag '2.0.0' to exist: %v", err)
+	}
+	if tagSHA != mainSHA {
+		t.Errorf("Expected tag to point to main %s, got %s", mainSHA, tagSHA)
+	}
+
+	// Verify the SHAs appear in the tag message
+	message, err := testutil.RunGit(t, dir, "for-each-ref", "--format=%(contents)", "refs/tags/2.0.0")
+	if err != nil {
+		t.Fatalf("Failed to read tag message: %v", err)
+	}
+	for _, expected := range []string{"main " + strings.TrimSpace(mainSHA), "develop " + strings.TrimSpace(developSHA)} {
+		if !strings.Contains(message, expected) {
+			t.Errorf("Expected tag message to contain '%s', got: %s", expected, message)
+		}
+	}
+}
+
+// TestFinishWithConfirm tests asking for confirmation before finishing.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates a feature branch with a commit
+// 3. Answers "n" to the confirmation and verifies nothing changed
+// 4. Verifies finishing without any answer fails instead of waiting
+// 5. Enables gitflow.finish.confirm, answers "y" and verifies the branch was finished
+func TestFinishWithConfirm(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create a feature branch with a commit
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "confirm")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "feature.txt", "feature content")
+	_, err = testutil.RunGit(t, dir, "add", "feature.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add feature file")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Decline the confirmation
+	output, err = testutil.RunGitFlowWithInput(t, dir, "n\n", "feature", "finish", "confirm", "--confirm")
+	if exitErr, ok := err.(*testutil.ExitError); !ok || exitErr.ExitCode != 2 {
+		t.Fatalf("Expected declined finish to fail with exit code 2, got: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Merge 'feature/confirm' into 'develop' using merge strategy") {
+		t.Errorf("Expected finish plan in output, got: %s", output)
+	}
+	if !strings.Contains(output, "operation cancelled by user") {
+		t.Errorf("Expected cancellation error, got: %s", output)
+	}
+	if !testutil.BranchExists(t, dir, "feature/confirm") {
+		t.Error("Expected feature branch to still exist after declining")
+	}
+	currentBranch := testutil.GetCurrentBranch(t, dir)
+	if currentBranch != "feature/confirm" {
+		t.Errorf("Expected to stay on feature/confirm, got %s", currentBranch)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git", "gitflow", "state", "merge.json")); err == nil {
+		t.Error("Expected no merge state after declining")
+	}
+
+	// Without any answer the command fails instead of waiting
+	output, err = testutil.RunGitFlowWithInput(t, dir, "", "feature", "finish", "confirm", "--confirm")
+	if err == nil {
+		t.Fatalf("Expected finish without an answer to fail, got: %s", output)
+	}
+	if !testutil.BranchExists(t, dir, "feature/confirm") {
+		t.Error("Expected feature branch to still exist without an answer")
+	}
+
+	// Enable confirmation by config and accept
+	_, err = testutil.RunGit(t, dir, "config", "gitflow.finish.confirm", "true")
+	if err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+	output, err = testutil.RunGitFlowWithInput(t, dir, "y\n", "feature", "finish", "confirm")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Proceed? [y/N]") {
+		t.Errorf("Expected confirmation prompt, got: %s", output)
+	}
+	if testutil.BranchExists(t, dir, "feature/confirm") {
+		t.Error("Expected feature branch to be deleted after confirming")
+	}
+	mergeOutput, err := testutil.RunGit(t, dir, "log", "--format=%s", "-1", "develop")
+	if err != nil {
+		t.Fatalf("Failed to read develop log: %v", err)
+	}
+	if !strings.Contains(mergeOutput, "feature/confirm") {
+		t.Errorf("Expected feature to be merged into develop, got: %s", mergeOutput)
+	}
+}
+
+// TestFinishWithRouting tests selecting the finish target by branch name patterns.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates develop-ui and develop-api branches and routes feature/ui-* and feature/api-* to them
+// 3. Finishes a feature of each pattern and one matching no pattern
+// 4. Verifies each feature was merged into its routed target and the unmatched one into develop
+// 5. Verifies a malformed routing entry is rejected
+func TestFinishWithRouting(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create the routed targets and the routing map
+	for _, branch := range []string{"develop-ui", "develop-api"} {
+		_, err = testutil.RunGit(t, dir, "branch", branch, "develop")
+		if err != nil {
+			t.Fatalf("Failed to create %s: %v", branch, err)
+		}
+	}
+	for _, route := range []string{"feature/ui-*=develop-ui", "feature/api-*=develop-api"} {
+		_, err = testutil.RunGit(t, dir, "config", "--add", "gitflow.feature.finish.routing", route)
+		if err != nil {
+			t.Fatalf("Failed to add routing: %v", err)
+		}
+	}
+
+	// Finish a feature of each pattern and one matching none. The unrouted one goes last,
+	// as later features start from develop and would otherwise carry its file along.
+	features := []struct{ name, target string }{
+		{"ui-button", "develop-ui"},
+		{"api-client", "develop-api"},
+		{"plain", "develop"},
+	}
+	for _, feature := range features {
+		name, target := feature.name, feature.target
+		output, err = testutil.RunGitFlow(t, dir, "feature", "start", name)
+		if err != nil {
+			t.Fatalf("Failed to create feature branch %s: %v\nOutput: %s", name, err, output)
+		}
+		testutil.WriteFile(t, dir, name+".txt", name+" content")
+		_, err = testutil.RunGit(t, dir, "add", name+".txt")
+		if err != nil {
+			t.Fatalf("Failed to add file: %v", err)
+		}
+		_, err = testutil.RunGit(t, dir, "commit", "-m", "Add "+name)
+		if err != nil {
+			t.Fatalf("Failed to commit file: %v", err)
+		}
+
+		output, err = testutil.RunGitFlow(t, dir, "feature", "finish", name)
+		if err != nil {
+			t.Fatalf("Failed to finish feature branch %s: %v\nOutput: %s", name, err, output)
+		}
+		if currentBranch := testutil.GetCurrentBranch(t, dir); currentBranch != target {
+			t.Errorf("Expected to be on %s after finishing %s, got %s", target, name, currentBranch)
+		}
+	}
+
+	// Verify each file only reached its routed target
+	for _, feature := range features {
+		name, target := feature.name, feature.target
+		for _, branch := range []string{"develop-ui", "develop-api", "develop"} {
+			_, err = testutil.RunGit(t, dir, "cat-file", "-e", branch+":"+name+".txt")
+			if branch == target && err != nil {
+				t.Errorf("Expected %s to be merged into %s", name, branch)
+			}
+			if branch != target && err == nil {
+				t.Errorf("Expected %s not to be merged into %s", name, branch)
+			}
+		}
+	}
+
+	// A malformed routing entry is rejected
+	_, err = testutil.RunGit(t, dir, "config", "--add", "gitflow.feature.finish.routing", "feature/broken")
+	if err != nil {
+		t.Fatalf("Failed to add routing: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "other")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "other")
+	if err == nil {
+		t.Fatalf("Expected finish with malformed routing to fail, got: %s", output)
+	}
+	if !strings.Contains(output, "<pattern>=<target>") {
+		t.Errorf("Expected error about the routing format, got: %s", output)
+	}
+}
+
+// TestFinishWithUncommittedChanges tests how uncommitted changes affect a finish.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates a feature branch with a commit
+// 3. Verifies a modified tracked file blocks the finish
+// 4. Verifies an untracked file alone does not block the finish and is left in place
+func TestFinishWithUncommittedChanges(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create a feature branch with a commit
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "dirty")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "feature.txt", "feature content")
+	_, err = testutil.RunGit(t, dir, "add", "feature.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add feature file")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// A modified tracked file blocks the finish
+	testutil.WriteFile(t, dir, "feature.txt", "modified content")
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "dirty")
+	if err == nil {
+		t.Fatalf("Expected finish with tracked changes to fail, got: %s", output)
+	}
+	if !strings.Contains(output, "uncommitted changes to tracked files") {
+		t.Errorf("Expected uncommitted changes error, got: %s", output)
+	}
+	if !testutil.BranchExists(t, dir, "feature/dirty") {
+		t.Error("Expected feature branch to still exist")
+	}
+	_, err = testutil.RunGit(t, dir, "checkout", "--", "feature.txt")
+	if err != nil {
+		t.Fatalf("Failed to discard changes: %v", err)
+	}
+
+	// An untracked file alone does not block the finish
+	testutil.WriteFile(t, dir, "build.log", "build output")
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "dirty")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch with untracked file: %v\nOutput: %s", err, output)
+	}
+	if testutil.BranchExists(t, dir, "feature/dirty") {
+		t.Error("Expected feature branch to be deleted")
+	}
+	if testutil.ReadFile(t, dir, "build.log") != "build output" {
+		t.Error("Expected untracked file to be left in place")
+	}
+}
+
+// TestFinishWithStashUntracked tests stashing untracked files during a finish.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates a feature branch with a commit and an untracked file
+// 3. Finishes with --stash-untracked
+// 4. Verifies the untracked file was stashed, restored and the stash dropped
+func TestFinishWithStashUntracked(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create a feature branch with a commit and an untracked file
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "stash")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "feature.txt", "feature content")
+	_, err = testutil.RunGit(t, dir, "add", "feature.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add feature file")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	testutil.WriteFile(t, dir, "notes.txt", "local notes")
+
+	// Finish with --stash-untracked
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "stash", "--stash-untracked")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Stashed untracked files") || !strings.Contains(output, "Restored untracked files") {
+		t.Errorf("Expected untracked files to be stashed and restored, got: %s", output)
+	}
+
+	// Verify the file is back and the stash is gone
+	if testutil.ReadFile(t, dir, "notes.txt") != "local notes" {
+		t.Error("Expected untracked file to be restored")
+	}
+	stashList, err := testutil.RunGit(t, dir, "stash", "list")
+	if err != nil {
+		t.Fatalf("Failed to list stashes: %v", err)
+	}
+	if strings.TrimSpace(stashList) != "" {
+		t.Errorf("Expected stash to be dropped, got: %s", stashList)
+	}
+	if testutil.GetCurrentBranch(t, dir) != "develop" {
+		t.Error("Expected to be on develop after finishing")
+	}
+}
+
+// TestFinishWithTargetInOtherWorktree tests finishing while the target branch is checked out in another worktree.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates a feature branch with a commit
+// 3. Checks out develop in a second worktree
+// 4. Verifies the finish fails naming the worktree and leaves everything untouched
+// 5. Removes the worktree and verifies the finish succeeds
+func TestFinishWithTargetInOtherWorktree(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create a feature branch with a commit
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "worktree")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "feature.txt", "feature content")
+	_, err = testutil.RunGit(t, dir, "add", "feature.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add feature file")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Check out develop in a second worktree
+	worktreeDir := filepath.Join(t.TempDir(), "develop-worktree")
+	_, err = testutil.RunGit(t, dir, "worktree", "add", worktreeDir, "develop")
+	if err != nil {
+		t.Fatalf("Failed to add worktree: %v", err)
+	}
+
+	// The finish fails naming the worktree
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "worktree")
+	if err == nil {
+		t.Fatalf("Expected finish to fail while develop is checked out elsewhere, got: %s", output)
+	}
+	if !strings.Contains(output, "branch 'develop' is checked out in another worktree") || !strings.Contains(output, "develop-worktree") {
+		t.Errorf("Expected error naming the worktree, got: %s", output)
+	}
+	if !testutil.BranchExists(t, dir, "feature/worktree") {
+		t.Error("Expected feature branch to still exist")
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git", "gitflow", "state", "merge.json")); err == nil {
+		t.Error("Expected no merge state after the refused finish")
+	}
+
+	// Without the worktree the finish succeeds
+	_, err = testutil.RunGit(t, dir, "worktree", "remove", worktreeDir)
+	if err != nil {
+		t.Fatalf("Failed to remove worktree: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "worktree")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+	if testutil.BranchExists(t, dir, "feature/worktree") {
+		t.Error("Expected feature branch to be deleted")
+	}
+}
+
+// TestFinishIntoProtectedBranch tests the approval required to finish into a protected branch.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Marks develop as protected and creates a feature branch with a commit
+// 3. Verifies finishing without approval fails and declining the prompt cancels
+// 4. Verifies an approval for a different branch is rejected
+// 5. Verifies finishing with --approve develop merges the feature
+func TestFinishIntoProtectedBranch(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	_, err = testutil.RunGit(t, dir, "config", "gitflow.branch.develop.protected", "true")
+	if err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+
+	// Create a feature branch with a commit
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "protected")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "feature.txt", "feature content")
+	_, err = testutil.RunGit(t, dir, "add", "feature.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add feature file")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Without approval the finish fails
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "protected")
+	if err == nil {
+		t.Fatalf("Expected finish without approval to fail, got: %s", output)
+	}
+	if !strings.Contains(output, "--approve develop") {
+		t.Errorf("Expected error to suggest --approve, got: %s", output)
+	}
+
+	// Declining the prompt cancels
+	output, err = testutil.RunGitFlowWithInput(t, dir, "n\n", "feature", "finish", "protected")
+	if exitErr, ok := err.(*testutil.ExitError); !ok || exitErr.ExitCode != 2 {
+		t.Fatalf("Expected declined finish to fail with exit code 2, got: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Branch 'develop' is protected") || !strings.Contains(output, "operation cancelled by user") {
+		t.Errorf("Expected protection prompt and cancellation, got: %s", output)
+	}
+	if !testutil.BranchExists(t, dir, "feature/protected") {
+		t.Error("Expected feature branch to still exist")
+	}
+
+	// An approval for another branch is rejected
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "protected", "--approve", "main")
+	if err == nil {
+		t.Fatalf("Expected finish with mismatched approval to fail, got: %s", output)
+	}
+
+	// With approval the finish succeeds
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "protected", "--approve", "develop")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+	if testutil.BranchExists(t, dir, "feature/protected") {
+		t.Error("Expected feature branch to be deleted")
+	}
+	_, err = testutil.RunGit(t, dir, "cat-file", "-e", "develop:feature.txt")
+	if err != nil {
+		t.Error("Expected feature to be merged into develop")
+	}
+}
+
+// TestFinishInterruptedDuringChildUpdate tests the report left behind when a finish is interrupted.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates a release branch with a commit
+// 3. Installs a post-merge hook that interrupts git-flow while develop is updated
+// 4. Verifies the finish exits with the interrupted code, reports progress and keeps the merge state
+// 5. Removes the hook and verifies --continue completes the finish
+func TestFinishInterruptedDuringChildUpdate(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create a release branch with a commit
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release content")
+	_, err = testutil.RunGit(t, dir, "add", "release.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add release file")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Interrupt git-flow (the parent of git) while develop is being updated
+	hookPath := filepath.Join(dir, ".git", "hooks", "post-merge")
+	hook := "#!/bin/sh\n" +
+		"if [ \"$(git rev-parse --abbrev-ref HEAD)\" = \"develop\" ]; then\n" +
+		"  kill -INT $(ps -o ppid= -p $PPID)\n" +
+		"  sleep 1\n" +
+		"fi\n"
+	if err := os.WriteFile(hookPath, []byte(hook), 0755); err != nil {
+		t.Fatalf("Failed to write hook: %v", err)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0")
+	if err == nil {
+		t.Fatalf("Expected finish to be interrupted, got: %s", output)
+	}
+	if exitErr, ok := err.(*testutil.ExitError); !ok || exitErr.ExitCode != 130 {
+		t.Errorf("Expected exit code 130, got: %v", err)
+	}
+	if !strings.Contains(output, "Interrupted during step 'update_children' of finishing 'release/1.0.0'") {
+		t.Errorf("Expected interrupted step in output, got: %s", output)
+	}
+	if !strings.Contains(output, "Remaining child base branches: develop") {
+		t.Errorf("Expected remaining child branches in output, got: %s", output)
+	}
+	if !strings.Contains(output, "git flow release finish --continue 1.0.0") {
+		t.Errorf("Expected continue instructions in output, got: %s", output)
+	}
+
+	state, err := testutil.LoadMergeState(t, dir)
+	if err != nil {
+		t.Fatalf("Expected merge state to be kept: %v", err)
+	}
+	if state.CurrentStep != "update_children" {
+		t.Errorf("Expected saved step update_children, got %s", state.CurrentStep)
+	}
+
+	// Resume without the hook, once the orphaned git merge has completed
+	if err := os.Remove(hookPath); err != nil {
+		t.Fatalf("Failed to remove hook: %v", err)
+	}
+	mergeHead := filepath.Join(dir, ".git", "MERGE_HEAD")
+	for i := 0; i < 50; i++ {
+		if _, err := os.Stat(mergeHead); os.IsNotExist(err) {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "--continue", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to continue finish: %v\nOutput: %s", err, output)
+	}
+	if testutil.BranchExists(t, dir, "release/1.0.0") {
+		t.Error("Expected release branch to be deleted")
+	}
+	_, err = testutil.RunGit(t, dir, "cat-file", "-e", "develop:release.txt")
+	if err != nil {
+		t.Error("Expected release to be merged into develop")
+	}
+}
+
+// TestFinishKeepsRemoteBranchWithNewerCommits tests that finish with --pre-delete-merge-verify-remote doesn't
+// delete a remote branch with unmerged commits.
+// Steps:
+// 1. Sets up a test repository with a remote and initializes git-flow
+// 2. Creates and pushes a feature branch, then pushes a newer commit to the remote only
+// 3. Finishes the feature with --pre-delete-merge-verify-remote and verifies the remote branch is kept with a warning
+// 4. Repeats with --force-remote-delete and verifies the remote branch is deleted
+// 5. Repeats without --pre-delete-merge-verify-remote and verifies the remote branch isn't fetched and is deleted
+func TestFinishKeepsRemoteBranchWithNewerCommits(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	bareDir, err := testutil.AddRemote(t, dir, "origin", true)
+	if err != nil {
+		t.Fatalf("Failed to add remote: %v", err)
+	}
+	defer testutil.CleanupTestRepo(t, bareDir)
+
+	testCases := []struct {
+		name       string
+		flags      []string
+		remoteKept bool
+	}{
+		{"teammate", []string{"--pre-delete-merge-verify-remote"}, true},
+		{"teammate-forced", []string{"--pre-delete-merge-verify-remote", "--force-remote-delete"}, false},
+		{"teammate-unverified", nil, false},
+	}
+
+	for _, tc := range testCases {
+		name := tc.name
+		branch := "feature/" + name
+
+		// Create and push a feature branch with a commit
+		output, err = testutil.RunGitFlow(t, dir, "feature", "start", name)
+		if err != nil {
+			t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+		}
+		testutil.WriteFile(t, dir, name+".txt", "feature content")
+		_, err = testutil.RunGit(t, dir, "add", name+".txt")
+		if err != nil {
+			t.Fatalf("Failed to add file: %v", err)
+		}
+		_, err = testutil.RunGit(t, dir, "commit", "-m", "Add feature file")
+		if err != nil {
+			t.Fatalf("Failed to commit file: %v", err)
+		}
+		_, err = testutil.RunGit(t, dir, "push", "origin", branch)
+		if err != nil {
+			t.Fatalf("Failed to push feature branch: %v", err)
+		}
+
+		// A teammate pushes a newer commit that the local repository hasn't fetched yet
+		knownTip, err := testutil.RunGit(t, dir, "rev-parse", "origin/"+branch)
+		if err != nil {
+			t.Fatalf("Failed to resolve remote branch: %v", err)
+		}
+		_, err = testutil.RunGit(t, dir, "checkout", "-b", "teammate-work")
+		if err != nil {
+			t.Fatalf("Failed to create branch: %v", err)
+		}
+		testutil.WriteFile(t, dir, name+"-teammate.txt", "teammate content")
+		_, err = testutil.RunGit(t, dir, "add", name+"-teammate.txt")
+		if err != nil {
+			t.Fatalf("Failed to add file: %v", err)
+		}
+		_, err = testutil.RunGit(t, dir, "commit", "-m", "Add teammate file")
+		if err != nil {
+			t.Fatalf("Failed to commit file: %v", err)
+		}
+		_, err = testutil.RunGit(t, dir, "push", "origin", "teammate-work:"+branch)
+		if err != nil {
+			t.Fatalf("Failed to push teammate commit: %v", err)
+		}
+		_, err = testutil.RunGit(t, dir, "checkout", branch)
+		if err != nil {
+			t.Fatalf("Failed to checkout feature branch: %v", err)
+		}
+		_, err = testutil.RunGit(t, dir, "branch", "-D", "teammate-work")
+		if err != nil {
+			t.Fatalf("Failed to delete branch: %v", err)
+		}
+		_, err = testutil.RunGit(t, dir, "update-ref", "refs/remotes/origin/"+branch, strings.TrimSpace(knownTip))
+		if err != nil {
+			t.Fatalf("Failed to reset remote-tracking branch: %v", err)
+		}
+
+		// Finish the feature
+		args := append([]string{"feature", "finish", name}, tc.flags...)
+		output, err = testutil.RunGitFlow(t, dir, args...)
+		if err != nil {
+			t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+		}
+		if testutil.BranchExists(t, dir, branch) {
+			t.Errorf("Expected local branch %s to be deleted", branch)
+		}
+
+		remoteKept := testutil.BranchExists(t, bareDir, branch)
+		warned := strings.Contains(output, "Warning: keeping remote branch 'origin/"+branch+"'")
+		if tc.remoteKept && (!remoteKept || !warned) {
+			t.Errorf("Expected remote branch %s to be kept with a warning, got: %s", branch, output)
+		}
+		if !tc.remoteKept && (remoteKept || warned) {
+			t.Errorf("Expected remote branch %s to be deleted with %v, got: %s", branch, tc.flags, output)
+		}
+	}
+}
+
+// TestFinishReusesExistingMerge tests finishing a branch that was already merged by an earlier attempt
+// with --tag-existing-commit-reuse.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates a release branch with a commit and merges it into main manually
+// 3. Runs finish with --tag-existing-commit-reuse and verifies it reuses the existing merge commit
+// 4. Verifies the tag points at the existing merge and the branch is deleted
+func TestFinishReusesExistingMerge(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create a release branch with a commit
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release content")
+	_, err = testutil.RunGit(t, dir, "add", "release.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add release file")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Merge it into main manually, as a partially failed finish would have
+	_, err = testutil.RunGit(t, dir, "checkout", "main")
+	if err != nil {
+		t.Fatalf("Failed to checkout main: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "merge", "--no-ff", "-m", "Merge release/1.0.0", "release/1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to merge release branch: %v", err)
+	}
+	existingMerge, err := testutil.RunGit(t, dir, "rev-parse", "main")
+	if err != nil {
+		t.Fatalf("Failed to resolve main: %v", err)
+	}
+	existingMerge = strings.TrimSpace(existingMerge)
+
+	// Finish reuses the existing merge
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--tag-existing-commit-reuse")
+	if err != nil {
+		t.Fatalf("Failed to finish release branch: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Branch 'release/1.0.0' is already merged into 'main', reusing the existing merge") {
+		t.Errorf("Expected existing merge to be reused, got: %s", output)
+	}
+
+	mainTip, err := testutil.RunGit(t, dir, "rev-parse", "main")
+	if err != nil {
+		t.Fatalf("Failed to resolve main: %v", err)
+	}
+	if strings.TrimSpace(mainTip) != existingMerge {
+		t.Errorf("Expected no additional merge on main, got %s instead of %s", strings.TrimSpace(mainTip), existingMerge)
+	}
+	tagTarget, err := testutil.RunGit(t, dir, "rev-parse", "1.0.0^{commit}")
+	if err != nil {
+		t.Fatalf("Expected tag 1.0.0 to exist: %v", err)
+	}
+	if strings.TrimSpace(tagTarget) != existingMerge {
+		t.Errorf("Expected tag to point at the existing merge, got %s", strings.TrimSpace(tagTarget))
+	}
+	if testutil.BranchExists(t, dir, "release/1.0.0") {
+		t.Error("Expected release branch to be deleted")
+	}
+}
+
+// TestFinishWithListSteps tests listing the finish steps without running them.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates a feature and a release branch with commits
+// 3. Lists the steps for both and verifies the order, skipped steps and options are reflected
+// 4. Verifies nothing was merged or deleted
+func TestFinishWithListSteps(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create a feature and a release branch with commits
+	for _, branchType := range []string{"feature", "release"} {
+		name := "1.0.0"
+		if branchType == "feature" {
+			name = "steps"
+		}
+		output, err = testutil.RunGitFlow(t, dir, branchType, "start", name)
+		if err != nil {
+			t.Fatalf("Failed to create %s branch: %v\nOutput: %s", branchType, err, output)
+		}
+		testutil.WriteFile(t, dir, branchType+".txt", branchType+" content")
+		_, err = testutil.RunGit(t, dir, "add", branchType+".txt")
+		if err != nil {
+			t.Fatalf("Failed to add file: %v", err)
+		}
+		_, err = testutil.RunGit(t, dir, "commit", "-m", "Add "+branchType+" file")
+		if err != nil {
+			t.Fatalf("Failed to commit file: %v", err)
+		}
+	}
+
+	// A feature is merged and deleted, without tag or child updates
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "steps", "--list-steps")
+	if err != nil {
+		t.Fatalf("Failed to list steps: %v\nOutput: %s", err, output)
+	}
+	expected := "Finish steps for 'feature/steps':\n" +
+		"  1. merge: Merge 'feature/steps' into 'develop' using merge strategy\n" +
+		"  2. delete_branch: Delete local branch 'feature/steps' and its remote branch if it exists\n" +
+		"  - create_tag (skipped: no tag is created)\n" +
+		"  - update_children (skipped: no child base branches)\n"
+	if output != expected {
+		t.Errorf("Expected feature steps:\n%s\ngot:\n%s", expected, output)
+	}
+
+	// A release is tagged and updates develop; options are reflected
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--list-steps", "--tagname", "v1", "--keep")
+	if err != nil {
+		t.Fatalf("Failed to list steps: %v\nOutput: %s", err, output)
+	}
+	expected = "Finish steps for 'release/1.0.0':\n" +
+		"  1. merge: Merge 'release/1.0.0' into 'main' using merge strategy\n" +
+		"  2. create_tag: Create tag 'v1'\n" +
+		"  3. update_children: Update child base branches: develop\n" +
+		"  4. delete_branch: Keep 'release/1.0.0'\n"
+	if output != expected {
+		t.Errorf("Expected release steps:\n%s\ngot:\n%s", expected, output)
+	}
+
+	// Nothing was changed
+	if !testutil.BranchExists(t, dir, "feature/steps") || !testutil.BranchExists(t, dir, "release/1.0.0") {
+		t.Error("Expected branches to still exist")
+	}
+	if _, err := testutil.RunGit(t, dir, "cat-file", "-e", "develop:feature.txt"); err == nil {
+		t.Error("Expected feature not to be merged")
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git", "gitflow", "state", "merge.json")); err == nil {
+		t.Error("Expected no merge state")
+	}
+}
+
+// TestFinishDryRun tests that --dry-run prints the plan of a finish without changing the repository.
+// Steps:
+// 1. Adds a child base branch 'staging' of main without autoUpdate
+// 2. Creates a feature and a release branch with commits
+// 3. Runs feature finish --dry-run and verifies the merge and delete steps
+// 4. Runs release finish --dry-run --push and verifies the tag, child, delete and push steps
+// 5. Verifies no branch was merged or deleted, no tag was created and no merge state was saved
+func TestFinishDryRun(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "branch", "staging", "main"); err != nil {
+		t.Fatalf("Failed to create staging: %v", err)
+	}
+	for key, value := range map[string]string{"type": "base", "parent": "main"} {
+		if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch.staging."+key, value); err != nil {
+			t.Fatalf("Failed to set %s for staging: %v", key, err)
+		}
+	}
+
+	// Create a feature and a release branch with commits
+	for _, branch := range [][2]string{{"feature", "preview"}, {"release", "1.0.0"}} {
+		branchType, name := branch[0], branch[1]
+		output, err = testutil.RunGitFlow(t, dir, branchType, "start", name)
+		if err != nil {
+			t.Fatalf("Failed to create %s branch: %v\nOutput: %s", branchType, err, output)
+		}
+		testutil.WriteFile(t, dir, branchType+".txt", branchType+" content")
+		if _, err := testutil.RunGit(t, dir, "add", branchType+".txt"); err != nil {
+			t.Fatalf("Failed to add file: %v", err)
+		}
+		if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add "+branchType+" file"); err != nil {
+			t.Fatalf("Failed to commit file: %v", err)
+		}
+	}
+	mainBefore, _ := testutil.RunGit(t, dir, "rev-parse", "main")
+	developBefore, _ := testutil.RunGit(t, dir, "rev-parse", "develop")
+
+	// A feature is merged into develop and deleted
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "preview", "--dry-run")
+	if err != nil {
+		t.Fatalf("Failed to run feature finish --dry-run: %v\nOutput: %s", err, output)
+	}
+	expected := "Finish steps for 'feature/preview':\n" +
+		"  1. merge: Merge 'feature/preview' into 'develop' using merge strategy\n" +
+		"  2. delete_branch: Delete local branch 'feature/preview' and its remote branch if it exists\n" +
+		"  - create_tag (skipped: no tag is created)\n" +
+		"  - update_children (skipped: no child base branches)\n"
+	if output != expected {
+		t.Errorf("Expected feature plan:\n%s\ngot:\n%s", expected, output)
+	}
+
+	// A release is tagged, updates develop but not staging, and is pushed
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--dry-run", "--push")
+	if err != nil {
+		t.Fatalf("Failed to run release finish --dry-run: %v\nOutput: %s", err, output)
+	}
+	expected = "Finish steps for 'release/1.0.0':\n" +
+		"  1. merge: Merge 'release/1.0.0' into 'main' using merge strategy\n" +
+		"  2. create_tag: Create tag '1.0.0'\n" +
+		"  3. update_children: Update child base branches: develop\n" +
+		"  4. delete_branch: Delete local branch 'release/1.0.0' and its remote branch if it exists\n" +
+		"  5. push: Push 'main', 'develop' to 'origin' with tag '1.0.0'\n" +
+		"  - update_children (skipped: not updated automatically: staging)\n"
+	if output != expected {
+		t.Errorf("Expected release plan:\n%s\ngot:\n%s", expected, output)
+	}
+
+	// Nothing was changed
+	if !testutil.BranchExists(t, dir, "feature/preview") || !testutil.BranchExists(t, dir, "release/1.0.0") {
+		t.Error("Expected branches to still exist")
+	}
+	mainAfter, _ := testutil.RunGit(t, dir, "rev-parse", "main")
+	developAfter, _ := testutil.RunGit(t, dir, "rev-parse", "develop")
+	if mainAfter != mainBefore || developAfter != developBefore {
+		t.Error("Expected main and develop to be unchanged")
+	}
+	if _, err := testutil.RunGit(t, dir, "rev-parse", "--verify", "refs/tags/1.0.0"); err == nil {
+		t.Error("Expected no tag to be created")
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git", "gitflow", "state", "merge.json")); err == nil {
+		t.Error("Expected no merge state")
+	}
+	if branch := testutil.GetCurrentBranch(t, dir); branch != "release/1.0.0" {
+		t.Errorf("Expected to stay on release/1.0.0, got %s", branch)
+	}
+}
+
+// TestFinishWithChildrenParallel tests checking child base branches concurrently before updating them.
+// Steps:
+// 1. Sets up a test repository with child base branches 'preview', 'qa' and 'staging' of develop
+// 2. Merges a feature into develop manually and fast-forwards 'staging' to develop
+// 3. Gives 'qa' a change that conflicts with the feature
+// 4. Finishes with --children-parallel and verifies 'staging' is skipped, 'preview' updated and 'qa' conflicts
+// 5. Resolves the conflict, continues and verifies all children contain the feature
+func TestFinishWithChildrenParallel(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Add child base branches of develop
+	for _, child := range []string{"preview", "qa", "staging"} {
+		if _, err := testutil.RunGit(t, dir, "branch", child, "develop"); err != nil {
+			t.Fatalf("Failed to create branch '%s': %v", child, err)
+		}
+		if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch."+child+".type", "base"); err != nil {
+			t.Fatalf("Failed to set type for '%s': %v", child, err)
+		}
+		if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch."+child+".parent", "develop"); err != nil {
+			t.Fatalf("Failed to set parent for '%s': %v", child, err)
+		}
+		if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch."+child+".autoUpdate", "true"); err != nil {
+			t.Fatalf("Failed to enable auto update for '%s': %v", child, err)
+		}
+		if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch."+child+".downstreamStrategy", "merge"); err != nil {
+			t.Fatalf("Failed to set downstream strategy for '%s': %v", child, err)
+		}
+	}
+
+	// Give qa a change that conflicts with the feature
+	if _, err := testutil.RunGit(t, dir, "checkout", "qa"); err != nil {
+		t.Fatalf("Failed to checkout qa: %v", err)
+	}
+	testutil.WriteFile(t, dir, "feature.txt", "qa content")
+	if _, err := testutil.RunGit(t, dir, "add", "feature.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add feature.txt in qa"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Create the feature and merge it into develop manually
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "parallel")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "feature.txt", "feature content")
+	if _, err := testutil.RunGit(t, dir, "add", "feature.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add feature.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+		t.Fatalf("Failed to checkout develop: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "merge", "--no-ff", "-m", "Merge feature/parallel", "feature/parallel"); err != nil {
+		t.Fatalf("Failed to merge feature: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "branch", "-f", "staging", "develop"); err != nil {
+		t.Fatalf("Failed to fast-forward staging: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "checkout", "feature/parallel"); err != nil {
+		t.Fatalf("Failed to checkout feature: %v", err)
+	}
+
+	// Finish checks all children up front and stops on the qa conflict
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "parallel", "--children-parallel")
+	if err == nil {
+		t.Fatalf("Expected finish to stop on the qa conflict\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "Child base branch 'staging' already up to date with 'develop'") {
+		t.Errorf("Expected staging to be reported as up to date, got: %s", output)
+	}
+	state, err := testutil.LoadMergeState(t, dir)
+	if err != nil {
+		t.Fatalf("Failed to load merge state: %v", err)
+	}
+	if !state.ChildrenParallel {
+		t.Error("Expected parallel mode to be kept in the merge state")
+	}
+	if !slices.Contains(state.UpdatedBranches, "preview") || !slices.Contains(state.UnchangedBranches, "staging") || slices.Contains(state.UpdatedBranches, "qa") {
+		t.Errorf("Expected preview updated, staging unchanged and qa pending, got updated %v, unchanged %v", state.UpdatedBranches, state.UnchangedBranches)
+	}
+
+	// Resolve the conflict and continue
+	testutil.WriteFile(t, dir, "feature.txt", "resolved content")
+	if _, err := testutil.RunGit(t, dir, "add", "feature.txt"); err != nil {
+		t.Fatalf("Failed to stage resolved file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "--no-edit"); err != nil {
+		t.Fatalf("Failed to commit resolved merge: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "--continue", "parallel")
+	if err != nil {
+		t.Fatalf("Failed to continue finish: %v\nOutput: %s", err, output)
+	}
+
+	// Every child contains develop
+	for _, child := range []string{"preview", "qa", "staging"} {
+		if _, err := testutil.RunGit(t, dir, "merge-base", "--is-ancestor", "develop", child); err != nil {
+			t.Errorf("Expected '%s' to contain develop", child)
+		}
+	}
+	if testutil.BranchExists(t, dir, "feature/parallel") {
+		t.Error("Expected feature branch to be deleted")
+	}
+}
+
+// TestFinishWithRenameDeleteConflict tests that finish classifies a rename/delete conflict.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Commits a file on develop and starts a feature branch
+// 3. Renames the file on the feature branch and deletes it on develop
+// 4. Attempts to finish the feature branch
+// 5. Verifies the conflict is reported as rename/delete with matching guidance
+func TestFinishWithRenameDeleteConflict(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Commit the file that will be renamed and deleted
+	testutil.WriteFile(t, dir, "old.txt", "line one\nline two\nline three\n")
+	if _, err := testutil.RunGit(t, dir, "add", "old.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add old.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Rename the file on the feature branch
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "rename-test")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "mv", "old.txt", "new.txt"); err != nil {
+		t.Fatalf("Failed to rename file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Rename old.txt"); err != nil {
+		t.Fatalf("Failed to commit rename: %v", err)
+	}
+
+	// Delete the file on develop
+	if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+		t.Fatalf("Failed to checkout develop: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "rm", "old.txt"); err != nil {
+		t.Fatalf("Failed to delete file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Delete old.txt"); err != nil {
+		t.Fatalf("Failed to commit deletion: %v", err)
+	}
+
+	// Finish should stop on the conflict
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "rename-test")
+	if err == nil {
+		t.Fatalf("Expected finish to fail due to merge conflict\nOutput: %s", output)
+	}
+
+	// Verify the conflict is classified with rename/delete guidance
+	if !strings.Contains(output, "new.txt (rename/delete)") {
+		t.Errorf("Expected output to classify new.txt as a rename/delete conflict, got: %s", output)
+	}
+	if !strings.Contains(output, "git rm new.txt") {
+		t.Errorf("Expected output to suggest removing new.txt, got: %s", output)
+	}
+	if strings.Contains(output, "(modify/delete)") {
+		t.Errorf("Expected no modify/delete classification, got: %s", output)
+	}
+}
+
+// TestFinishWithKeepHistoryNote tests that --keep-history-note attaches the git-flow metadata as a note.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Configures a custom notes ref via gitflow.finish.notesref
+// 3. Creates a feature branch with a commit
+// 4. Finishes the feature with --keep-history-note
+// 5. Verifies the merge commit on develop carries a note with branch type, name and strategy
+func TestFinishWithKeepHistoryNote(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.finish.notesref", "refs/notes/provenance"); err != nil {
+		t.Fatalf("Failed to set notes ref: %v", err)
+	}
+
+	// Create a feature branch with a commit
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "noted")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "noted.txt", "noted content")
+	if _, err := testutil.RunGit(t, dir, "add", "noted.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add noted.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Finish with a history note
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "noted", "--keep-history-note")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+
+	// Verify the note on the merge commit
+	note, err := testutil.RunGit(t, dir, "notes", "--ref", "refs/notes/provenance", "show", "develop")
+	if err != nil {
+		t.Fatalf("Expected a note on the merge commit: %v\nOutput: %s", err, note)
+	}
+	for _, expected := range []string{"git-flow-branch-type: feature", "git-flow-branch-name: noted", "git-flow-strategy: merge"} {
+		if !strings.Contains(note, expected) {
+			t.Errorf("Expected note to contain '%s', got: %s", expected, note)
+		}
+	}
+
+	// Verify the note survives the branch deletion
+	if testutil.BranchExists(t, dir, "feature/noted") {
+		t.Error("Expected feature branch to be deleted")
+	}
+}
+
+// TestFinishWithEmitEvent tests that --emit-event posts a signed JSON payload to the webhook.
+// Steps:
+// 1. Starts a local HTTP server recording the request
+// 2. Sets up a test repository with a webhook secret configured for features
+// 3. Creates a feature branch with a commit
+// 4. Finishes the feature with --emit-event pointing at the server
+// 5. Verifies the payload fields and the HMAC signature header
+// 6. Finishes another feature with GITFLOW_WEBHOOK_SECRET set and verifies it signs instead of the config
+// 7. Verifies the secret can't be given on the command line
+func TestFinishWithEmitEvent(t *testing.T) {
+	// Record the webhook request
+	var body []byte
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get("X-GitFlow-Signature")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and configure the signing secret
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.feature.finish.webhooksecret", "s3cret"); err != nil {
+		t.Fatalf("Failed to set webhook secret: %v", err)
+	}
+
+	// Create a feature branch with a commit
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "hooked")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "hooked.txt", "hooked content")
+	if _, err := testutil.RunGit(t, dir, "add", "hooked.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add hooked.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Finish with the webhook
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "hooked", "--emit-event", server.URL)
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+
+	// Verify the payload
+	var event map[string]string
+	if err := json.Unmarshal(body, &event); err != nil {
+		t.Fatalf("Failed to decode webhook payload %q: %v", body, err)
+	}
+	expected := map[string]string{
+		"type":     "feature",
+		"branch":   "feature/hooked",
+		"target":   "develop",
+		"strategy": "merge",
+		"result":   "success",
+	}
+	for key, value := range expected {
+		if event[key] != value {
+			t.Errorf("Expected payload %s to be '%s', got '%s'", key, value, event[key])
+		}
+	}
+
+	// Verify the signature
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); signature != want {
+		t.Errorf("Expected signature '%s', got '%s'", want, signature)
+	}
+
+	// The environment overrides the configured secret
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "hooked-env")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlowWithEnv(t, dir, []string{"GITFLOW_WEBHOOK_SECRET=env-s3cret"}, "feature", "finish", "hooked-env", "--emit-event", server.URL)
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+	mac = hmac.New(sha256.New, []byte("env-s3cret"))
+	mac.Write(body)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); signature != want {
+		t.Errorf("Expected signature with the secret from the environment '%s', got '%s'", want, signature)
+	}
+
+	// The secret is not accepted as a flag, where it would show in the process list
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "hooked", "--webhook-secret", "s3cret")
+	if err == nil || !strings.Contains(output, "unknown flag: --webhook-secret") {
+		t.Errorf("Expected --webhook-secret to be rejected, got: %v\nOutput: %s", err, output)
+	}
+}
+
+// TestFinishWithFailingEmitEvent tests that an unreachable webhook only warns.
+func TestFinishWithFailingEmitEvent(t *testing.T) {
+	// A server that is closed right away refuses connections
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.feature.finish.webhook", url); err != nil {
+		t.Fatalf("Failed to set webhook: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "unreachable")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+
+	// Finish still succeeds
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "unreachable")
+	if err != nil {
+		t.Fatalf("Expected finish to succeed despite the webhook failure: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Warning: webhook request failed") {
+		t.Errorf("Expected webhook warning, got: %s", output)
+	}
+	if testutil.BranchExists(t, dir, "feature/unreachable") {
+		t.Error("Expected feature branch to be deleted")
+	}
+}
+
+// TestFinishEmitEventOutcomes tests that the webhook also reports conflicts, failed pushes and aborts.
+// Steps:
+// 1. Starts a local HTTP server recording the result of each payload
+// 2. Finishes a conflicting feature with --emit-event and verifies a conflict event
+// 3. Aborts the finish without --emit-event and verifies an aborted event
+// 4. Finishes a feature with --push to a remote that rejects pushes and verifies a push-failed event
+func TestFinishEmitEventOutcomes(t *testing.T) {
+	// Record the result of every webhook request
+	results := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event map[string]string
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &event); err != nil {
+			results <- "invalid payload: " + string(body)
+		} else {
+			results <- event["result"]
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	expectResult := func(want string) {
+		t.Helper()
+		select {
+		case got := <-results:
+			if got != want {
+				t.Errorf("Expected webhook result '%s', got '%s'", want, got)
+			}
+		default:
+			t.Errorf("Expected a webhook request with result '%s', got none", want)
+		}
+	}
+
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// A feature that conflicts with develop
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "conflicting")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "shared.txt", "feature content")
+	if _, err := testutil.RunGit(t, dir, "add", "shared.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add shared.txt on the feature"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+		t.Fatalf("Failed to checkout develop: %v", err)
+	}
+	testutil.WriteFile(t, dir, "shared.txt", "develop content")
+	if _, err := testutil.RunGit(t, dir, "add", "shared.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add shared.txt on develop"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// The conflict is reported
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "conflicting", "--emit-event", server.URL)
+	if err == nil {
+		t.Fatalf("Expected the finish to stop on the conflict\nOutput: %s", output)
+	}
+	expectResult("conflict")
+
+	// The abort is reported to the webhook the finish was started with
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "--abort", "conflicting")
+	if err != nil {
+		t.Fatalf("Failed to abort the finish: %v\nOutput: %s", err, output)
+	}
+	expectResult("aborted")
+
+	// A rejected push is reported
+	remoteDir, err := testutil.AddRemote(t, dir, "origin", true)
+	if err != nil {
+		t.Fatalf("Failed to add remote: %v", err)
+	}
+	defer os.RemoveAll(remoteDir)
+	if err := os.WriteFile(filepath.Join(remoteDir, "hooks", "pre-receive"), []byte("#!/bin/sh\nexit 1\n"), 0755); err != nil {
+		t.Fatalf("Failed to write hook: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "rejected")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "rejected.txt", "feature content")
+	if _, err := testutil.RunGit(t, dir, "add", "rejected.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add rejected.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "rejected", "--push", "--emit-event", server.URL)
+	if err == nil {
+		t.Fatalf("Expected the finish to fail when the push is rejected\nOutput: %s", output)
+	}
+	expectResult("push-failed")
+}
+
+// setupSSHTagSigning configures SSH tag signing with a fresh key. If trusted is false, the key is
+// missing from the allowed signers file, so signing works but verification fails.
+func setupSSHTagSigning(t *testing.T, dir string, trusted bool) {
+	t.Helper()
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not available")
+	}
+
+	keyPath := filepath.Join(t.TempDir(), "signing_key")
+	if output, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", keyPath).CombinedOutput(); err != nil {
+		t.Fatalf("Failed to generate signing key: %v\nOutput: %s", err, output)
+	}
+	publicKey, err := os.ReadFile(keyPath + ".pub")
+	if err != nil {
+		t.Fatalf("Failed to read public key: %v", err)
+	}
+
+	allowedSigners := ""
+	if trusted {
+		allowedSigners = "test@example.com " + string(publicKey)
+	}
+	allowedSignersPath := filepath.Join(t.TempDir(), "allowed_signers")
+	if err := os.WriteFile(allowedSignersPath, []byte(allowedSigners), 0644); err != nil {
+		t.Fatalf("Failed to write allowed signers: %v", err)
+	}
+
+	for key, value := range map[string]string{
+		"gpg.format":                              "ssh",
+		"user.signingkey":                         keyPath,
+		"gpg.ssh.allowedSignersFile":              allowedSignersPath,
+		"gitflow.release.finish.sign":             "true",
+		"gitflow.release.finish.verifycreatedtag": "true",
+	} {
+		if _, err := testutil.RunGit(t, dir, "config", key, value); err != nil {
+			t.Fatalf("Failed to set %s: %v", key, err)
+		}
+	}
+}
+
+// TestFinishVerifiesSignedTag tests that gitflow.<type>.finish.verifycreatedtag verifies a signed tag.
+// Steps:
+// 1. Sets up a test repository with SSH tag signing and a trusted key
+// 2. Creates and finishes a release branch
+// 3. Verifies the finish reports the signature as verified
+func TestFinishVerifiesSignedTag(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	setupSSHTagSigning(t, dir, true)
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+
+	// Finish and verify the signature is checked
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to finish release branch: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Verified signature of tag '1.0.0': Good") {
+		t.Errorf("Expected verified signature status, got: %s", output)
+	}
+	if testutil.BranchExists(t, dir, "release/1.0.0") {
+		t.Error("Expected release branch to be deleted")
+	}
+}
+
+// TestFinishFailsOnUnverifiableSignedTag tests that finish stops when a created tag cannot be verified.
+// Steps:
+// 1. Sets up a test repository with SSH tag signing and a key missing from the allowed signers
+// 2. Creates and finishes a release branch
+// 3. Verifies the finish fails with a clear message and keeps the branch and merge state
+func TestFinishFailsOnUnverifiableSignedTag(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	setupSSHTagSigning(t, dir, false)
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+
+	// Finish must fail on verification
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0")
+	if err == nil {
+		t.Fatalf("Expected finish to fail on tag verification\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "Error: failed to verify tag '1.0.0': signature verification failed") {
+		t.Errorf("Expected verification error, got: %s", output)
+	}
+	if !strings.Contains(output, "Check the signing key configuration") {
+		t.Errorf("Expected signing key hint, got: %s", output)
+	}
+
+	// The branch is kept and the finish can be resumed
+	if !testutil.BranchExists(t, dir, "release/1.0.0") {
+		t.Error("Expected release branch to be kept")
+	}
+	state, err := testutil.LoadMergeState(t, dir)
+	if err != nil {
+		t.Fatalf("Expected merge state to be saved: %v", err)
+	}
+	if state.CurrentStep != "create_tag" {
+		t.Errorf("Expected current step to be 'create_tag', got '%s'", state.CurrentStep)
+	}
+}
+
+// TestFinishWithReflogMessage tests that finish labels its reflog entries.
+// Steps:
+// 1. Sets up a test repository and finishes a feature branch with the default reflog message
+// 2. Verifies the merge entry in the develop reflog names git-flow and the branch
+// 3. Finishes another feature branch with --reflog-message
+// 4. Verifies the custom message is used
+func TestFinishWithReflogMessage(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Finish a feature with the default reflog message
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "logged")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "--allow-empty", "-m", "Logged change"); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "logged")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+
+	reflog, err := testutil.RunGit(t, dir, "reflog", "show", "--format=%gs", "-1", "develop")
+	if err != nil {
+		t.Fatalf("Failed to read reflog: %v", err)
+	}
+	if !strings.HasPrefix(reflog, "git-flow finish feature/logged: Merge") {
+		t.Errorf("Expected develop reflog entry to name the finish, got: %s", reflog)
+	}
+
+	// Finish a feature with a custom reflog message
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "custom")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "--allow-empty", "-m", "Custom change"); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "custom", "--reflog-message", "release train 42")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+
+	reflog, err = testutil.RunGit(t, dir, "reflog", "show", "--format=%gs", "-1", "develop")
+	if err != nil {
+		t.Fatalf("Failed to read reflog: %v", err)
+	}
+	if !strings.HasPrefix(reflog, "release train 42: Merge") {
+		t.Errorf("Expected develop reflog entry to use the custom message, got: %s", reflog)
+	}
+}
+
+// TestFinishWithOnlyRemoteTrackingTarget tests finishing when the target only exists as a remote-tracking branch.
+// Steps:
+// 1. Sets up a test repository with a feature branch and pushes all branches to a remote
+// 2. Deletes the local develop branch so only origin/develop remains
+// 3. Verifies finish fails without the option
+// 4. Enables gitflow.feature.finish.createlocaltarget and finishes again
+// 5. Verifies develop is created from origin/develop, tracks it and contains the feature
+func TestFinishWithOnlyRemoteTrackingTarget(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create a feature branch with a commit
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "ci")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "ci.txt", "ci content")
+	if _, err := testutil.RunGit(t, dir, "add", "ci.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add ci.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Push everything, then drop the local develop branch
+	remoteDir, err := testutil.AddRemote(t, dir, "origin", true)
+	if err != nil {
+		t.Fatalf("Failed to add remote: %v", err)
+	}
+	defer os.RemoveAll(remoteDir)
+	if _, err := testutil.RunGit(t, dir, "fetch", "origin"); err != nil {
+		t.Fatalf("Failed to fetch: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "branch", "-D", "develop"); err != nil {
+		t.Fatalf("Failed to delete develop: %v", err)
+	}
+
+	// Without the option the missing target is an error
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "ci", "--keepremote")
+	if err == nil {
+		t.Fatalf("Expected finish to fail without a local develop branch\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "develop") {
+		t.Errorf("Expected error to name the missing target, got: %s", output)
+	}
+
+	// With the option develop is created from origin/develop
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.feature.finish.createlocaltarget", "true"); err != nil {
+		t.Fatalf("Failed to set createlocaltarget: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "ci", "--keepremote")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Created local branch 'develop' from 'origin/develop'") {
+		t.Errorf("Expected output to report the created target, got: %s", output)
+	}
+
+	upstream, err := testutil.RunGit(t, dir, "rev-parse", "--abbrev-ref", "develop@{upstream}")
+	if err != nil {
+		t.Fatalf("Expected develop to track a remote branch: %v", err)
+	}
+	if strings.TrimSpace(upstream) != "origin/develop" {
+		t.Errorf("Expected develop to track 'origin/develop', got '%s'", strings.TrimSpace(upstream))
+	}
+	if _, err := testutil.RunGit(t, dir, "cat-file", "-e", "develop:ci.txt"); err != nil {
+		t.Error("Expected develop to contain the feature changes")
+	}
+}
+
+// TestFinishWithReportTiming tests that --report-timing prints the duration of each step.
+// Steps:
+// 1. Sets up a test repository and creates a release branch with a commit
+// 2. Finishes the release with --report-timing
+// 3. Verifies the timing table lists the merge, tag, child update and delete steps and a total
+func TestFinishWithReportTiming(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release content")
+	if _, err := testutil.RunGit(t, dir, "add", "release.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add release.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Finish with timing
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--report-timing")
+	if err != nil {
+		t.Fatalf("Failed to finish release branch: %v\nOutput: %s", err, output)
+	}
+
+	// Verify the timing table, without relying on the durations
+	if !strings.Contains(output, "Step timings:") {
+		t.Fatalf("Expected a step timings table, got: %s", output)
+	}
+	for _, step := range []string{"merge", "create_tag", "update_children develop", "delete_branch", "total"} {
+		pattern := regexp.MustCompile(`(?m)^  ` + regexp.QuoteMeta(step) + `\s+\d+\.\d{3}s$`)
+		if !pattern.MatchString(output) {
+			t.Errorf("Expected a timing line for '%s', got: %s", step, output)
+		}
+	}
+}
+
+// TestFinishWithUnrelatedHistories tests finishing a branch that shares no history with its target.
+// Steps:
+// 1. Sets up a test repository and creates an orphan feature branch with its own root commit
+// 2. Verifies finish refuses the merge, hints at the option and leaves no merge state behind
+// 3. Finishes again with --merge-allow-unrelated-histories
+// 4. Verifies the warning and that develop contains the imported file
+func TestFinishWithUnrelatedHistories(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create a feature branch with an unrelated root and no files from develop
+	if _, err := testutil.RunGit(t, dir, "checkout", "--orphan", "feature/import"); err != nil {
+		t.Fatalf("Failed to create orphan branch: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "rm", "-rf", "--ignore-unmatch", "."); err != nil {
+		t.Fatalf("Failed to clear index: %v", err)
+	}
+	testutil.WriteFile(t, dir, "imported.txt", "imported content")
+	if _, err := testutil.RunGit(t, dir, "add", "imported.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Import library"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Without the option the merge is refused
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "import")
+	if err == nil {
+		t.Fatalf("Expected finish to refuse unrelated histories\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "--merge-allow-unrelated-histories") {
+		t.Errorf("Expected output to hint at --merge-allow-unrelated-histories, got: %s", output)
+	}
+	if _, err := testutil.LoadMergeState(t, dir); err == nil {
+		t.Error("Expected no merge state to be left behind")
+	}
+
+	// With the option the histories are joined
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "import", "--merge-allow-unrelated-histories")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Warning: histories without a common ancestor will be merged") {
+		t.Errorf("Expected unrelated histories warning, got: %s", output)
+	}
+	if _, err := testutil.RunGit(t, dir, "cat-file", "-e", "develop:imported.txt"); err != nil {
+		t.Error("Expected develop to contain the imported file")
+	}
+	if testutil.BranchExists(t, dir, "feature/import") {
+		t.Error("Expected feature branch to be deleted")
+	}
+}
+
+// TestFinishRemovesBranchConfig tests that finish removes the per-branch settings of the deleted branch.
+// Steps:
+// 1. Sets up a test repository and starts a feature branch recording its start commit
+// 2. Adds a custom per-branch setting
+// 3. Finishes the feature branch
+// 4. Verifies no gitflow.branch.feature/<name>.* keys remain
+func TestFinishRemovesBranchConfig(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "tidy", "--track-parent-commit-only")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch.feature/tidy.description", "Tidy up"); err != nil {
+		t.Fatalf("Failed to set description: %v", err)
+	}
+	keys, _ := testutil.RunGit(t, dir, "config", "--get-regexp", `^gitflow\.branch\.feature/tidy\.`)
+	if !strings.Contains(keys, "startcommit") || !strings.Contains(keys, "base") {
+		t.Fatalf("Expected base and start commit to be recorded, got: %s", keys)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "tidy")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+
+	// Verify no stale keys remain
+	keys, err = testutil.RunGit(t, dir, "config", "--get-regexp", `^gitflow\.branch\.feature/tidy\.`)
+	if err == nil || strings.TrimSpace(keys) != "" {
+		t.Errorf("Expected no settings for the finished branch, got: %s", keys)
+	}
+
+	// Settings of base branches are untouched
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch.develop.type"); err != nil {
+		t.Error("Expected develop settings to be kept")
+	}
+}
+
+// TestFinishDryRunJSON tests that --dry-run --json prints a plan that matches the actual finish.
+// Steps:
+// 1. Sets up a test repository and creates a release branch with a commit
+// 2. Requests the JSON plan and verifies nothing in the repository changed
+// 3. Verifies the plan: target, strategy, tag, child updates, deletion and step order
+// 4. Finishes the release and verifies the outcome matches the plan
+func TestFinishDryRunJSON(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release content")
+	if _, err := testutil.RunGit(t, dir, "add", "release.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add release.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Request the plan without changing anything
+	refsBefore, _ := testutil.RunGit(t, dir, "for-each-ref")
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--dry-run", "--json")
+	if err != nil {
+		t.Fatalf("Failed to get finish plan: %v\nOutput: %s", err, output)
+	}
+	refsAfter, _ := testutil.RunGit(t, dir, "for-each-ref")
+	if refsBefore != refsAfter {
+		t.Errorf("Expected no refs to change, before:\n%s\nafter:\n%s", refsBefore, refsAfter)
+	}
+	if branch := testutil.GetCurrentBranch(t, dir); branch != "release/1.0.0" {
+		t.Errorf("Expected to stay on 'release/1.0.0', got '%s'", branch)
+	}
+	if _, err := testutil.LoadMergeState(t, dir); err == nil {
+		t.Error("Expected no merge state to be saved")
+	}
+
+	// Verify the plan
+	var plan struct {
+		Type     string `json:"type"`
+		Branch   string `json:"branch"`
+		Target   string `json:"target"`
+		Strategy string `json:"strategy"`
+		Tag      struct {
+			Create bool   `json:"create"`
+			Name   string `json:"name"`
+		} `json:"tag"`
+		Children []struct {
+			Branch   string `json:"branch"`
+			Strategy string `json:"strategy"`
+		} `json:"children"`
+		Delete struct {
+			Local  bool `json:"local"`
+			Remote bool `json:"remote"`
+		} `json:"delete"`
+		Steps []string `json:"steps"`
+	}
+	if err := json.Unmarshal([]byte(output), &plan); err != nil {
+		t.Fatalf("Failed to decode plan: %v\nOutput: %s", err, output)
+	}
+	if plan.Type != "release" || plan.Branch != "release/1.0.0" || plan.Target != "main" || plan.Strategy != "merge" {
+		t.Errorf("Unexpected plan header: %+v", plan)
+	}
+	if !plan.Tag.Create || plan.Tag.Name != "1.0.0" {
+		t.Errorf("Expected tag '1.0.0' to be created, got %+v", plan.Tag)
+	}
+	if len(plan.Children) != 1 || plan.Children[0].Branch != "develop" || plan.Children[0].Strategy != "merge" {
+		t.Errorf("Expected develop to be updated with merge, got %+v", plan.Children)
+	}
+	if !plan.Delete.Local || plan.Delete.Remote {
+		t.Errorf("Expected only the local branch to be deleted, got %+v", plan.Delete)
+	}
+	expectedSteps := []string{"merge", "create_tag", "update_children", "delete_branch"}
+	if !slices.Equal(plan.Steps, expectedSteps) {
+		t.Errorf("Expected steps %v, got %v", expectedSteps, plan.Steps)
+	}
+
+	// Finish and verify the outcome matches the plan
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to finish release branch: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "rev-parse", "--verify", "refs/tags/"+plan.Tag.Name); err != nil {
+		t.Errorf("Expected tag '%s' to exist", plan.Tag.Name)
+	}
+	if _, err := testutil.RunGit(t, dir, "cat-file", "-e", plan.Target+":release.txt"); err != nil {
+		t.Errorf("Expected '%s' to contain the release", plan.Target)
+	}
+	if _, err := testutil.RunGit(t, dir, "cat-file", "-e", plan.Children[0].Branch+":release.txt"); err != nil {
+		t.Errorf("Expected '%s' to contain the release", plan.Children[0].Branch)
+	}
+	if testutil.BranchExists(t, dir, plan.Branch) {
+		t.Errorf("Expected '%s' to be deleted", plan.Branch)
+	}
+
+	// --json on its own is rejected
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "anything", "--json")
+	if err == nil || !strings.Contains(output, "--dry-run") {
+		t.Errorf("Expected --json without --dry-run to fail, got: %s", output)
+	}
+}
+
+// setupReleaseWithTag creates release/1.0.0 with a commit and tags 1.0.0. If merged is true, the release
+// is merged into main first, so the tag points at the commit finish would tag; otherwise at the old main.
+func setupReleaseWithTag(t *testing.T, dir string, merged bool) {
+	t.Helper()
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release content")
+	if _, err := testutil.RunGit(t, dir, "add", "release.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add release.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	if _, err := testutil.RunGit(t, dir, "checkout", "main"); err != nil {
+		t.Fatalf("Failed to checkout main: %v", err)
+	}
+	if merged {
+		if _, err := testutil.RunGit(t, dir, "merge", "--no-ff", "-m", "Merge release/1.0.0", "release/1.0.0"); err != nil {
+			t.Fatalf("Failed to merge release: %v", err)
+		}
+	}
+	if _, err := testutil.RunGit(t, dir, "tag", "-a", "-m", "Existing tag", "1.0.0"); err != nil {
+		t.Fatalf("Failed to create tag: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "checkout", "release/1.0.0"); err != nil {
+		t.Fatalf("Failed to checkout release: %v", err)
+	}
+}
+
+// TestFinishReusesTagAtSameCommit tests that an existing tag at the commit to tag is reused,
+// and that --no-tag-reuse turns this into an error.
+func TestFinishReusesTagAtSameCommit(t *testing.T) {
+	t.Run("Reuse", func(t *testing.T) {
+		dir := testutil.SetupTestRepo(t)
+		defer testutil.CleanupTestRepo(t, dir)
+		setupReleaseWithTag(t, dir, true)
+
+		output, err := testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0")
+		if err != nil {
+			t.Fatalf("Failed to finish release branch: %v\nOutput: %s", err, output)
+		}
+		if !strings.Contains(output, "Tag '1.0.0' already exists at") || !strings.Contains(output, "reusing it") {
+			t.Errorf("Expected tag reuse to be reported, got: %s", output)
+		}
+		message, _ := testutil.RunGit(t, dir, "tag", "-l", "--format=%(contents:subject)", "1.0.0")
+		if strings.TrimSpace(message) != "Existing tag" {
+			t.Errorf("Expected the existing tag to be kept, got message '%s'", strings.TrimSpace(message))
+		}
+	})
+
+	t.Run("NoReuse", func(t *testing.T) {
+		dir := testutil.SetupTestRepo(t)
+		defer testutil.CleanupTestRepo(t, dir)
+		setupReleaseWithTag(t, dir, true)
+
+		output, err := testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--no-tag-reuse")
+		if err == nil {
+			t.Fatalf("Expected finish to fail with --no-tag-reuse\nOutput: %s", output)
+		}
+		if !strings.Contains(output, "tag '1.0.0' already exists at the commit to tag") {
+			t.Errorf("Expected tag exists error, got: %s", output)
+		}
+	})
+}
+
+// TestFinishWithTagAtDifferentCommit tests that an existing tag at another commit is an error,
+// and that --force-tag replaces it when resuming the finish.
+func TestFinishWithTagAtDifferentCommit(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	setupReleaseWithTag(t, dir, false)
+	oldTagCommit, _ := testutil.RunGit(t, dir, "rev-parse", "1.0.0^{commit}")
+
+	// Without --force-tag the finish stops at the tag
+	output, err := testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0")
+	if err == nil {
+		t.Fatalf("Expected finish to fail for a tag at a different commit\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "tag '1.0.0' already exists at a different commit") {
+		t.Errorf("Expected tag exists error, got: %s", output)
+	}
+	newTagCommit, _ := testutil.RunGit(t, dir, "rev-parse", "1.0.0^{commit}")
+	if newTagCommit != oldTagCommit {
+		t.Error("Expected the existing tag to be left alone")
+	}
+
+	// Resuming with --force-tag replaces it
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "--continue", "1.0.0", "--force-tag")
+	if err != nil {
+		t.Fatalf("Failed to continue finish with --force-tag: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Replacing existing tag '1.0.0'") {
+		t.Errorf("Expected tag replacement to be reported, got: %s", output)
+	}
+	tagCommit, _ := testutil.RunGit(t, dir, "rev-parse", "1.0.0^{commit}")
+	mainCommit, _ := testutil.RunGit(t, dir, "rev-parse", "main")
+	if tagCommit != mainCommit {
+		t.Errorf("Expected tag to point at main (%s), got %s", strings.TrimSpace(mainCommit), strings.TrimSpace(tagCommit))
+	}
+}
+
+// TestFinishRefusesToReplacePublishedTag tests that --force-tag does not replace a tag that was pushed
+// to the remote unless --force-remote-tag is given as well.
+// Steps:
+// 1. Sets up a release branch and a tag at a different commit, and pushes the tag to a remote
+// 2. Finishes with --force-tag and verifies the finish refuses to replace the published tag
+// 3. Resumes with --force-tag --force-remote-tag and verifies the tag is replaced
+func TestFinishRefusesToReplacePublishedTag(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	setupReleaseWithTag(t, dir, false)
+	remoteDir, err := testutil.AddRemote(t, dir, "origin", false)
+	if err != nil {
+		t.Fatalf("Failed to add remote: %v", err)
+	}
+	defer os.RemoveAll(remoteDir)
+	if _, err := testutil.RunGit(t, dir, "push", "origin", "main", "refs/tags/1.0.0"); err != nil {
+		t.Fatalf("Failed to push tag: %v", err)
+	}
+	oldTagCommit, _ := testutil.RunGit(t, dir, "rev-parse", "1.0.0^{commit}")
+
+	// --force-tag alone refuses to replace the published tag
+	output, err := testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--force-tag")
+	if err == nil {
+		t.Fatalf("Expected finish to refuse replacing a published tag\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "tag '1.0.0' was already pushed to 'origin'") {
+		t.Errorf("Expected published tag error, got: %s", output)
+	}
+	tagCommit, _ := testutil.RunGit(t, dir, "rev-parse", "1.0.0^{commit}")
+	if tagCommit != oldTagCommit {
+		t.Error("Expected the existing tag to be left alone")
+	}
+
+	// Adding --force-remote-tag replaces it
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "--continue", "1.0.0", "--force-tag", "--force-remote-tag")
+	if err != nil {
+		t.Fatalf("Failed to continue finish with --force-remote-tag: %v\nOutput: %s", err, output)
+	}
+	tagCommit, _ = testutil.RunGit(t, dir, "rev-parse", "1.0.0^{commit}")
+	mainCommit, _ := testutil.RunGit(t, dir, "rev-parse", "main")
+	if tagCommit != mainCommit {
+		t.Errorf("Expected tag to point at main (%s), got %s", strings.TrimSpace(mainCommit), strings.TrimSpace(tagCommit))
+	}
+}
+
+// TestFinishKeepsChildStrategyOnContinue tests that a child base branch is updated with the strategy recorded
+// when its update began, even if the configuration changes while resolving a conflict.
+// Steps:
+// 1. Commits a change to develop and a conflicting change to a hotfix branch
+// 2. Finishes the hotfix and verifies updating develop by merge stops on a conflict
+// 3. Verifies the merge state records the merge strategy for develop
+// 4. Resolves the conflict, changes develop's downstream strategy to squash and continues
+// 5. Verifies the merge strategy is still used and the finish completes
+func TestFinishKeepsChildStrategyOnContinue(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Conflicting changes on develop and the hotfix
+	if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+		t.Fatalf("Failed to checkout develop: %v", err)
+	}
+	testutil.WriteFile(t, dir, "version.txt", "develop")
+	if _, err := testutil.RunGit(t, dir, "add", "version.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Change version on develop"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "hotfix", "start", "1.0.1")
+	if err != nil {
+		t.Fatalf("Failed to create hotfix branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "version.txt", "hotfix")
+	if _, err := testutil.RunGit(t, dir, "add", "version.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Change version on hotfix"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Updating develop stops on the conflict
+	output, err = testutil.RunGitFlow(t, dir, "hotfix", "finish", "1.0.1")
+	if err == nil {
+		t.Fatalf("Expected the finish to stop on a conflict in develop\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "Merge conflicts detected while updating base branch 'develop'") {
+		t.Errorf("Expected a conflict while updating develop, got: %s", output)
+	}
+	state, err := testutil.LoadMergeState(t, dir)
+	if err != nil {
+		t.Fatalf("Failed to load merge state: %v", err)
+	}
+	if state.ChildStrategies["develop"] != "merge" {
+		t.Errorf("Expected the merge strategy to be recorded for develop, got %v", state.ChildStrategies)
+	}
+
+	// Resolve the conflict and change the strategy before continuing
+	testutil.WriteFile(t, dir, "version.txt", "resolved")
+	if _, err := testutil.RunGit(t, dir, "add", "version.txt"); err != nil {
+		t.Fatalf("Failed to stage resolved file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "--no-edit"); err != nil {
+		t.Fatalf("Failed to commit resolved merge: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch.develop.downstreamStrategy", "squash"); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "hotfix", "finish", "--continue", "1.0.1")
+	if err != nil {
+		t.Fatalf("Failed to continue the finish: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Using merge strategy for 'develop'") || strings.Contains(output, "Using squash strategy") {
+		t.Errorf("Expected the recorded merge strategy to be used, got: %s", output)
+	}
+	parents, err := testutil.RunGit(t, dir, "rev-list", "--parents", "-n", "1", "develop")
+	if err != nil {
+		t.Fatalf("Failed to get develop parents: %v", err)
+	}
+	if len(strings.Fields(parents)) != 3 {
+		t.Errorf("Expected develop tip to be the resolved merge commit, got parents '%s'", strings.TrimSpace(parents))
+	}
+}
+
+// TestFinishHonorsChildAutoUpdate tests that finish only updates child base branches with autoUpdate enabled.
+// Steps:
+// 1. Adds child base branches 'qa' with autoUpdate and 'staging' without it to develop
+// 2. Finishes a feature and verifies only qa receives it and staging is reported as skipped
+// 3. Finishes a feature with --autoupdate-children and verifies staging receives it
+// 4. Finishes a feature with --no-autoupdate-children and verifies neither child receives it
+func TestFinishHonorsChildAutoUpdate(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Add child base branches of develop
+	for child, autoUpdate := range map[string]string{"qa": "true", "staging": "false"} {
+		if _, err := testutil.RunGit(t, dir, "branch", child, "develop"); err != nil {
+			t.Fatalf("Failed to create branch '%s': %v", child, err)
+		}
+		for key, value := range map[string]string{"type": "base", "parent": "develop", "autoUpdate": autoUpdate} {
+			if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch."+child+"."+key, value); err != nil {
+				t.Fatalf("Failed to set %s for '%s': %v", key, child, err)
+			}
+		}
+	}
+
+	finishFeature := func(name string, args ...string) string {
+		output, err := testutil.RunGitFlow(t, dir, "feature", "start", name)
+		if err != nil {
+			t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+		}
+		testutil.WriteFile(t, dir, name+".txt", "feature content")
+		if _, err := testutil.RunGit(t, dir, "add", name+".txt"); err != nil {
+			t.Fatalf("Failed to add file: %v", err)
+		}
+		if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add "+name+".txt"); err != nil {
+			t.Fatalf("Failed to commit file: %v", err)
+		}
+		output, err = testutil.RunGitFlow(t, dir, append([]string{"feature", "finish", name}, args...)...)
+		if err != nil {
+			t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+		}
+		return output
+	}
+	contains := func(branch string, file string) bool {
+		_, err := testutil.RunGit(t, dir, "cat-file", "-e", branch+":"+file)
+		return err == nil
+	}
+
+	// Only the child with autoUpdate is updated by default
+	output = finishFeature("default")
+	if !contains("qa", "default.txt") {
+		t.Error("Expected qa to be updated")
+	}
+	if contains("staging", "default.txt") {
+		t.Error("Expected staging not to be updated")
+	}
+	if !strings.Contains(output, "Skipping child base branch 'staging'") {
+		t.Errorf("Expected output to report staging as skipped, got: %s", output)
+	}
+
+	// --autoupdate-children updates all children
+	finishFeature("all", "--autoupdate-children")
+	if !contains("qa", "all.txt") || !contains("staging", "all.txt") {
+		t.Error("Expected qa and staging to be updated with --autoupdate-children")
+	}
+
+	// --no-autoupdate-children updates none
+	finishFeature("none", "--no-autoupdate-children")
+	if contains("qa", "none.txt") || contains("staging", "none.txt") {
+		t.Error("Expected no child to be updated with --no-autoupdate-children")
+	}
+}
+
+// TestFinishUpdatesChildrenInStableOrder tests that child base branches are updated in the same order on every run.
+// Steps:
+// 1. Sets up three child base branches of develop whose names are not in creation order
+// 2. Requests the finish plan several times and verifies the children are listed by name each time
+// 3. Finishes the feature and verifies the children are updated in that order
+func TestFinishUpdatesChildrenInStableOrder(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Add child base branches of develop
+	for _, child := range []string{"zeta", "alpha", "mid"} {
+		if _, err := testutil.RunGit(t, dir, "branch", child, "develop"); err != nil {
+			t.Fatalf("Failed to create branch '%s': %v", child, err)
+		}
+		if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch."+child+".type", "base"); err != nil {
+			t.Fatalf("Failed to set type for '%s': %v", child, err)
+		}
+		if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch."+child+".parent", "develop"); err != nil {
+			t.Fatalf("Failed to set parent for '%s': %v", child, err)
+		}
+		if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch."+child+".autoUpdate", "true"); err != nil {
+			t.Fatalf("Failed to enable auto update for '%s': %v", child, err)
+		}
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "ordered")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "ordered.txt", "ordered content")
+	if _, err := testutil.RunGit(t, dir, "add", "ordered.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add ordered.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// The plan lists the children in the same order on every run
+	expected := []string{"alpha", "mid", "zeta"}
+	for run := 0; run < 5; run++ {
+		output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "ordered", "--dry-run", "--json")
+		if err != nil {
+			t.Fatalf("Failed to get finish plan: %v\nOutput: %s", err, output)
+		}
+		var plan struct {
+			Children []struct {
+				Branch string `json:"branch"`
+			} `json:"children"`
+		}
+		if err := json.Unmarshal([]byte(output), &plan); err != nil {
+			t.Fatalf("Failed to decode plan: %v\nOutput: %s", err, output)
+		}
+		children := []string{}
+		for _, child := range plan.Children {
+			children = append(children, child.Branch)
+		}
+		if strings.Join(children, ",") != strings.Join(expected, ",") {
+			t.Fatalf("Run %d: expected children %v, got %v", run+1, expected, children)
+		}
+	}
+
+	// The finish updates the children in that order
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "ordered")
+	if err != nil {
+		t.Fatalf("Failed to finish feature: %v\nOutput: %s", err, output)
+	}
+	last := -1
+	for _, child := range expected {
+		index := strings.Index(output, "Updating child base branch '"+child+"' from 'develop'")
+		if index == -1 {
+			t.Fatalf("Expected '%s' to be updated, got: %s", child, output)
+		}
+		if index < last {
+			t.Errorf("Expected '%s' to be updated after the previous child, got: %s", child, output)
+		}
+		last = index
+	}
+}
+
+// TestFinishSavesChildBranchesInStableOrder tests that the merge state lists the child base branches in a stable order.
+// Steps:
+// 1. Sets up three child base branches of develop, where the middle one conflicts with the feature
+// 2. Finishes the feature and verifies it stops on the middle child
+// 3. Verifies the merge state lists the children by name with only the first one updated
+// 4. Resolves the conflict, continues, and verifies the last child is updated afterwards
+func TestFinishSavesChildBranchesInStableOrder(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Add child base branches of develop
+	for _, child := range []string{"zeta", "mid", "alpha"} {
+		if _, err := testutil.RunGit(t, dir, "branch", child, "develop"); err != nil {
+			t.Fatalf("Failed to create branch '%s': %v", child, err)
+		}
+		if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch."+child+".type", "base"); err != nil {
+			t.Fatalf("Failed to set type for '%s': %v", child, err)
+		}
+		if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch."+child+".parent", "develop"); err != nil {
+			t.Fatalf("Failed to set parent for '%s': %v", child, err)
+		}
+		if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch."+child+".autoUpdate", "true"); err != nil {
+			t.Fatalf("Failed to enable auto update for '%s': %v", child, err)
+		}
+	}
+
+	// Give mid a change that conflicts with the feature
+	if _, err := testutil.RunGit(t, dir, "checkout", "mid"); err != nil {
+		t.Fatalf("Failed to checkout mid: %v", err)
+	}
+	testutil.WriteFile(t, dir, "ordered.txt", "mid content")
+	if _, err := testutil.RunGit(t, dir, "add", "ordered.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add ordered.txt in mid"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "ordered")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "ordered.txt", "feature content")
+	if _, err := testutil.RunGit(t, dir, "add", "ordered.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add ordered.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Finish stops on the mid conflict
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "ordered")
+	if err == nil {
+		t.Fatalf("Expected finish to stop on the mid conflict\nOutput: %s", output)
+	}
+	state, err := testutil.LoadMergeState(t, dir)
+	if err != nil {
+		t.Fatalf("Failed to load merge state: %v", err)
+	}
+	if strings.Join(state.ChildBranches, ",") != "alpha,mid,zeta" {
+		t.Errorf("Expected child branches [alpha mid zeta], got %v", state.ChildBranches)
+	}
+	if strings.Join(state.UpdatedBranches, ",") != "alpha" {
+		t.Errorf("Expected only alpha to be updated, got %v", state.UpdatedBranches)
+	}
+
+	// Resolve the conflict and continue
+	testutil.WriteFile(t, dir, "ordered.txt", "resolved content")
+	if _, err := testutil.RunGit(t, dir, "add", "ordered.txt"); err != nil {
+		t.Fatalf("Failed to stage resolved file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "--no-edit"); err != nil {
+		t.Fatalf("Failed to commit resolved merge: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "--continue", "ordered")
+	if err != nil {
+		t.Fatalf("Failed to continue finish: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Updating child base branch 'zeta' from 'develop'") {
+		t.Errorf("Expected zeta to be updated after continuing, got: %s", output)
+	}
+	if _, err := testutil.RunGit(t, dir, "merge-base", "--is-ancestor", "develop", "zeta"); err != nil {
+		t.Error("Expected zeta to contain develop")
+	}
+}
+
+// TestFinishAbortRestoresUpdatedChildBranches tests that aborting a finish restores child branches updated before the conflict.
+// Steps:
+// 1. Sets up two child base branches of develop, where the second one conflicts with the feature
+// 2. Finishes the feature and verifies it stops on the second child after updating the first
+// 3. Aborts the finish
+// 4. Verifies the first child and develop are back at their original commits and the feature branch is checked out
+func TestFinishAbortRestoresUpdatedChildBranches(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Add child base branches of develop
+	for _, child := range []string{"alpha", "beta"} {
+		if _, err := testutil.RunGit(t, dir, "branch", child, "develop"); err != nil {
+			t.Fatalf("Failed to create branch '%s': %v", child, err)
+		}
+		if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch."+child+".type", "base"); err != nil {
+			t.Fatalf("Failed to set type for '%s': %v", child, err)
+		}
+		if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch."+child+".parent", "develop"); err != nil {
+			t.Fatalf("Failed to set parent for '%s': %v", child, err)
+		}
+		if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch."+child+".autoUpdate", "true"); err != nil {
+			t.Fatalf("Failed to enable auto update for '%s': %v", child, err)
+		}
+	}
+
+	// Give beta a change that conflicts with the feature
+	if _, err := testutil.RunGit(t, dir, "checkout", "beta"); err != nil {
+		t.Fatalf("Failed to checkout beta: %v", err)
+	}
+	testutil.WriteFile(t, dir, "abort.txt", "beta content")
+	if _, err := testutil.RunGit(t, dir, "add", "abort.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add abort.txt in beta"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "abort-children")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "abort.txt", "feature content")
+	if _, err := testutil.RunGit(t, dir, "add", "abort.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add abort.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	alphaBefore, err := testutil.RunGit(t, dir, "rev-parse", "alpha")
+	if err != nil {
+		t.Fatalf("Failed to resolve alpha: %v", err)
+	}
+	developBefore, err := testutil.RunGit(t, dir, "rev-parse", "develop")
+	if err != nil {
+		t.Fatalf("Failed to resolve develop: %v", err)
+	}
+	betaBefore, err := testutil.RunGit(t, dir, "rev-parse", "beta")
+	if err != nil {
+		t.Fatalf("Failed to resolve beta: %v", err)
+	}
+
+	// Finish updates alpha and stops on the beta conflict
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "abort-children")
+	if err == nil {
+		t.Fatalf("Expected finish to stop on the beta conflict\nOutput: %s", output)
+	}
+	alphaUpdated, err := testutil.RunGit(t, dir, "rev-parse", "alpha")
+	if err != nil {
+		t.Fatalf("Failed to resolve alpha: %v", err)
+	}
+	if alphaUpdated == alphaBefore {
+		t.Fatal("Expected alpha to be updated before the conflict")
+	}
+
+	// Abort the finish
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "--abort", "abort-children")
+	if err != nil {
+		t.Fatalf("Failed to abort finish: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Restored child base branch 'alpha'") {
+		t.Errorf("Expected output to report restoring alpha, got: %s", output)
+	}
+
+	// Both children are back where they were
+	alphaAfter, err := testutil.RunGit(t, dir, "rev-parse", "alpha")
+	if err != nil {
+		t.Fatalf("Failed to resolve alpha: %v", err)
+	}
+	if alphaAfter != alphaBefore {
+		t.Errorf("Expected alpha to be restored to %s, got %s", strings.TrimSpace(alphaBefore), strings.TrimSpace(alphaAfter))
+	}
+	betaAfter, err := testutil.RunGit(t, dir, "rev-parse", "beta")
+	if err != nil {
+		t.Fatalf("Failed to resolve beta: %v", err)
+	}
+	if betaAfter != betaBefore {
+		t.Errorf("Expected beta to be unchanged, got %s", strings.TrimSpace(betaAfter))
+	}
+	developAfter, err := testutil.RunGit(t, dir, "rev-parse", "develop")
+	if err != nil {
+		t.Fatalf("Failed to resolve develop: %v", err)
+	}
+	if developAfter != developBefore {
+		t.Errorf("Expected develop to be restored to %s, got %s", strings.TrimSpace(developBefore), strings.TrimSpace(developAfter))
+	}
+	if branch := testutil.GetCurrentBranch(t, dir); branch != "feature/abort-children" {
+		t.Errorf("Expected to be on 'feature/abort-children', got '%s'", branch)
+	}
+	if _, err := testutil.LoadMergeState(t, dir); err == nil {
+		t.Error("Expected merge state to be cleared")
+	}
+}
+
+// TestFinishAbortRebaseChildRestoresTargetAndTag tests aborting a finish stopped on a child updated by rebase.
+// Steps:
+// 1. Sets up a test repository and makes develop update from main by rebase
+// 2. Starts a release and commits a change that conflicts with a later commit on develop
+// 3. Finishes the release and verifies it stops on the rebase of develop after merging and tagging
+// 4. Aborts the finish
+// 5. Verifies the rebase is aborted, main and develop are back at their commits and the tag is deleted
+func TestFinishAbortRebaseChildRestoresTargetAndTag(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch.develop.downstreamStrategy", "rebase"); err != nil {
+		t.Fatalf("Failed to set downstream strategy: %v", err)
+	}
+
+	// The release and develop change the same file
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "version.txt", "1.0.0")
+	if _, err := testutil.RunGit(t, dir, "add", "version.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Set version 1.0.0"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+		t.Fatalf("Failed to checkout develop: %v", err)
+	}
+	testutil.WriteFile(t, dir, "version.txt", "2.0.0-dev")
+	if _, err := testutil.RunGit(t, dir, "add", "version.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Set version 2.0.0-dev"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "checkout", "release/1.0.0"); err != nil {
+		t.Fatalf("Failed to checkout release: %v", err)
+	}
+	mainBefore, _ := testutil.RunGit(t, dir, "rev-parse", "main")
+	developBefore, _ := testutil.RunGit(t, dir, "rev-parse", "develop")
+
+	// Finish merges into main, tags it and stops on the rebase of develop
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0")
+	if err == nil {
+		t.Fatalf("Expected finish to stop on the develop conflict\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "Using rebase strategy for 'develop'") {
+		t.Fatalf("Expected develop to be rebased, got: %s", output)
+	}
+	if mainMerged, _ := testutil.RunGit(t, dir, "rev-parse", "main"); mainMerged == mainBefore {
+		t.Fatal("Expected the release to be merged into main before the conflict")
+	}
+	if _, err := testutil.RunGit(t, dir, "rev-parse", "refs/tags/1.0.0"); err != nil {
+		t.Fatalf("Expected tag 1.0.0 to be created before the conflict: %v", err)
+	}
+
+	// Abort the finish
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "--abort", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to abort finish: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Restored branch 'main'") || !strings.Contains(output, "Deleted tag '1.0.0'") {
+		t.Errorf("Expected output to report restoring main and deleting the tag, got: %s", output)
+	}
+
+	// Everything is back where it was before the finish
+	if operation, _ := testutil.RunGit(t, dir, "status"); strings.Contains(operation, "rebase in progress") {
+		t.Errorf("Expected the rebase to be aborted, got:\n%s", operation)
+	}
+	if mainAfter, _ := testutil.RunGit(t, dir, "rev-parse", "main"); mainAfter != mainBefore {
+		t.Errorf("Expected main to be restored to %s, got %s", strings.TrimSpace(mainBefore), strings.TrimSpace(mainAfter))
+	}
+	if developAfter, _ := testutil.RunGit(t, dir, "rev-parse", "develop"); developAfter != developBefore {
+		t.Errorf("Expected develop to be unchanged, got %s", strings.TrimSpace(developAfter))
+	}
+	if _, err := testutil.RunGit(t, dir, "rev-parse", "--verify", "--quiet", "refs/tags/1.0.0"); err == nil {
+		t.Error("Expected tag 1.0.0 to be deleted")
+	}
+	if branch := testutil.GetCurrentBranch(t, dir); branch != "release/1.0.0" {
+		t.Errorf("Expected to be on 'release/1.0.0', got '%s'", branch)
+	}
+	if _, err := testutil.LoadMergeState(t, dir); err == nil {
+		t.Error("Expected merge state to be cleared")
+	}
+}
+
+// TestFinishWithSince tests that --since lists the commits after the given baseline in the tag message.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Commits on develop, tags the baseline, and commits again
+// 3. Starts a release and commits on it
+// 4. Finishes the release with --since set to the baseline
+// 5. Verifies the tag message lists only the commits after the baseline
+// 6. Verifies an unknown baseline is rejected before anything is merged
+func TestFinishWithSince(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Commit before and after the baseline on develop
+	for _, subject := range []string{"Before baseline", "After baseline"} {
+		testutil.WriteFile(t, dir, "history.txt", subject)
+		if _, err := testutil.RunGit(t, dir, "add", "history.txt"); err != nil {
+			t.Fatalf("Failed to add file: %v", err)
+		}
+		if _, err := testutil.RunGit(t, dir, "commit", "-m", subject); err != nil {
+			t.Fatalf("Failed to commit file: %v", err)
+		}
+		if subject == "Before baseline" {
+			if _, err := testutil.RunGit(t, dir, "tag", "baseline"); err != nil {
+				t.Fatalf("Failed to tag baseline: %v", err)
+			}
+		}
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.1.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release content")
+	if _, err := testutil.RunGit(t, dir, "add", "release.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Release work"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// An unknown baseline is rejected before anything is merged
+	mainBefore, _ := testutil.RunGit(t, dir, "rev-parse", "main")
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.1.0", "--since", "no-such-ref")
+	if err == nil {
+		t.Fatalf("Expected finish to fail with an unknown baseline\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "--since") {
+		t.Errorf("Expected error to mention --since, got: %s", output)
+	}
+	if mainAfter, _ := testutil.RunGit(t, dir, "rev-parse", "main"); mainAfter != mainBefore {
+		t.Error("Expected main to be unchanged")
+	}
+
+	// Finish with the baseline
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.1.0", "--since", "baseline")
+	if err != nil {
+		t.Fatalf("Failed to finish release: %v\nOutput: %s", err, output)
+	}
+
+	message, err := testutil.RunGit(t, dir, "tag", "-l", "--format=%(contents)", "1.1.0")
+	if err != nil {
+		t.Fatalf("Failed to read tag message: %v", err)
+	}
+	if !strings.Contains(message, "Changes since baseline:") {
+		t.Errorf("Expected tag message to list the changes since baseline, got: %s", message)
+	}
+	for _, subject := range []string{"After baseline", "Release work"} {
+		if !strings.Contains(message, subject) {
+			t.Errorf("Expected tag message to contain '%s', got: %s", subject, message)
+		}
+	}
+	if strings.Contains(message, "Before baseline") {
+		t.Errorf("Expected tag message to leave out commits up to the baseline, got: %s", message)
+	}
+}
+
+// TestFinishWithTagContributors tests listing the authors since the last tag in the tag message.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Finishes a release with a commit by Carol using --tag-annotate-with-contributors
+// 3. Verifies the tag message lists Carol, as there is no earlier tag
+// 4. Enables gitflow.release.finish.tagcontributors and finishes a release with commits by Alice and Bob
+// 5. Verifies the tag message lists Alice and Bob with their commit counts since the first tag, but not Carol
+func TestFinishWithTagContributors(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// commitAs commits a change to the file as the given author
+	commitAs := func(author string, file string) {
+		testutil.WriteFile(t, dir, file, author)
+		if _, err := testutil.RunGit(t, dir, "add", file); err != nil {
+			t.Fatalf("Failed to add file: %v", err)
+		}
+		if _, err := testutil.RunGit(t, dir, "commit", "-m", "Change "+file, "--author", author); err != nil {
+			t.Fatalf("Failed to commit file: %v", err)
+		}
+	}
+
+	// First release without an earlier tag
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	commitAs("Carol <carol@example.com>", "carol.txt")
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--tag-annotate-with-contributors")
+	if err != nil {
+		t.Fatalf("Failed to finish release: %v\nOutput: %s", err, output)
+	}
+	message, err := testutil.RunGit(t, dir, "tag", "-l", "--format=%(contents)", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to read tag message: %v", err)
+	}
+	if !strings.Contains(message, "Contributors:\n") || !strings.Contains(message, "Carol <carol@example.com> (1 commit)") {
+		t.Errorf("Expected tag message to list Carol, got: %s", message)
+	}
+
+	// Second release with several authors, enabled by configuration
+	_, err = testutil.RunGit(t, dir, "config", "gitflow.release.finish.tagcontributors", "true")
+	if err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.1.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	commitAs("Alice <alice@example.com>", "alice.txt")
+	commitAs("Bob <bob@example.com>", "bob.txt")
+	commitAs("Alice <alice@example.com>", "alice2.txt")
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.1.0")
+	if err != nil {
+		t.Fatalf("Failed to finish release: %v\nOutput: %s", err, output)
+	}
+
+	message, err = testutil.RunGit(t, dir, "tag", "-l", "--format=%(contents)", "1.1.0")
+	if err != nil {
+		t.Fatalf("Failed to read tag message: %v", err)
+	}
+	for _, expected := range []string{
+		"Contributors since 1.0.0:",
+		"Alice <alice@example.com> (2 commits)",
+		"Bob <bob@example.com> (1 commit)",
+	} {
+		if !strings.Contains(message, expected) {
+			t.Errorf("Expected tag message to contain '%s', got: %s", expected, message)
+		}
+	}
+	if strings.Contains(message, "Carol") {
+		t.Errorf("Expected tag message to leave out authors before the last tag, got: %s", message)
+	}
+}
+
+// TestFinishWithNoColor tests that --no-color and NO_COLOR keep ANSI escape codes out of the conflict output.
+// Steps:
+// 1. Sets up a feature branch that conflicts with develop
+// 2. Finishes it with color forced and verifies the conflict list is colored
+// 3. Aborts and finishes again with color forced and --no-color, verifying no ANSI codes are printed
+// 4. Aborts and finishes again with color forced and NO_COLOR set, verifying no ANSI codes are printed
+func TestFinishWithNoColor(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "colors")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "colors.txt", "feature content")
+	if _, err := testutil.RunGit(t, dir, "add", "colors.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add colors.txt in feature"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+		t.Fatalf("Failed to checkout develop: %v", err)
+	}
+	testutil.WriteFile(t, dir, "colors.txt", "develop content")
+	if _, err := testutil.RunGit(t, dir, "add", "colors.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add colors.txt in develop"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "checkout", "feature/colors"); err != nil {
+		t.Fatalf("Failed to checkout feature: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		env       []string
+		args      []string
+		wantColor bool
+	}{
+		{"forced color", []string{"CLICOLOR_FORCE=1"}, nil, true},
+		{"--no-color", []string{"CLICOLOR_FORCE=1"}, []string{"--no-color"}, false},
+		{"NO_COLOR", []string{"CLICOLOR_FORCE=1", "NO_COLOR=1"}, nil, false},
+	}
+	for _, tt := range tests {
+		args := append([]string{"feature", "finish", "colors"}, tt.args...)
+		output, err := testutil.RunGitFlowWithEnv(t, dir, tt.env, args...)
+		if err == nil {
+			t.Fatalf("%s: expected finish to stop on the conflict\nOutput: %s", tt.name, output)
+		}
+		if !strings.Contains(output, "colors.txt") {
+			t.Errorf("%s: expected the conflict list to name colors.txt, got: %s", tt.name, output)
+		}
+		if hasColor := strings.Contains(output, "\x1b["); hasColor != tt.wantColor {
+			t.Errorf("%s: expected ANSI codes %v, got output: %q", tt.name, tt.wantColor, output)
+		}
+
+		output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "--abort", "colors")
+		if err != nil {
+			t.Fatalf("%s: failed to abort finish: %v\nOutput: %s", tt.name, err, output)
+		}
+	}
+}
+
+// TestFinishWithMergeBaseOverride tests that --merge-base-override limits a squash to the changes after the given base.
+// Steps:
+// 1. Sets up a test repository with the squash strategy for features
+// 2. Creates a feature on top of another, unfinished feature
+// 3. Verifies a base that is not part of the branch is rejected
+// 4. Finishes the second feature with the first one as merge base override
+// 5. Verifies develop gets only the changes of the second feature in a single squash commit
+func TestFinishWithMergeBaseOverride(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch.feature.upstreamstrategy", "squash"); err != nil {
+		t.Fatalf("Failed to set squash strategy: %v", err)
+	}
+
+	// The first feature is not finished yet
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "first")
+	if err != nil {
+		t.Fatalf("Failed to create first feature: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "first.txt", "first content")
+	if _, err := testutil.RunGit(t, dir, "add", "first.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add first.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// The second feature was started from the first one
+	if _, err := testutil.RunGit(t, dir, "checkout", "-b", "feature/second"); err != nil {
+		t.Fatalf("Failed to create second feature: %v", err)
+	}
+	testutil.WriteFile(t, dir, "second.txt", "second content")
+	if _, err := testutil.RunGit(t, dir, "add", "second.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add second.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// A base outside the history of the branch is rejected
+	if _, err := testutil.RunGit(t, dir, "branch", "unrelated", "develop"); err != nil {
+		t.Fatalf("Failed to create branch: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "--allow-empty", "-m", "Move on"); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "checkout", "unrelated"); err != nil {
+		t.Fatalf("Failed to checkout branch: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "--allow-empty", "-m", "Unrelated"); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "checkout", "feature/second"); err != nil {
+		t.Fatalf("Failed to checkout second feature: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "second", "--merge-base-override", "unrelated")
+	if err == nil {
+		t.Fatalf("Expected finish to reject a base outside the branch\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "is not an ancestor of 'feature/second'") {
+		t.Errorf("Expected error about the base not being an ancestor, got: %s", output)
+	}
+
+	// Squash only the changes since the first feature
+	developBefore, _ := testutil.RunGit(t, dir, "rev-parse", "develop")
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "second", "--merge-base-override", "feature/first", "--force-delete")
+	if err != nil {
+		t.Fatalf("Failed to finish feature: %v\nOutput: %s", err, output)
+	}
+
+	if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+		t.Fatalf("Failed to checkout develop: %v", err)
+	}
+	if !testutil.FileExists(t, dir, "second.txt") {
+		t.Error("Expected second.txt to be squashed into develop")
+	}
+	if testutil.FileExists(t, dir, "first.txt") {
+		t.Error("Expected first.txt to stay out of develop")
+	}
+	count, err := testutil.RunGit(t, dir, "rev-list", "--count", strings.TrimSpace(developBefore)+"..develop")
+	if err != nil {
+		t.Fatalf("Failed to count commits: %v", err)
+	}
+	if strings.TrimSpace(count) != "1" {
+		t.Errorf("Expected a single squash commit on develop, got %s", strings.TrimSpace(count))
+	}
+}
+
+// TestFinishRefusedDuringGitRebase tests that finish, update and start refuse to run while a plain git rebase is stopped.
+// Steps:
+// 1. Sets up a feature branch and a scratch branch whose rebase onto develop conflicts
+// 2. Starts the rebase with git, leaving it stopped on the conflict
+// 3. Verifies finish, update and start refuse with a message to complete the rebase
+// 4. Aborts the rebase and verifies the finish succeeds
+func TestFinishRefusedDuringGitRebase(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "guarded")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "guarded.txt", "feature content")
+	if _, err := testutil.RunGit(t, dir, "add", "guarded.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add guarded.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// A scratch branch conflicts with develop
+	if _, err := testutil.RunGit(t, dir, "checkout", "-b", "scratch", "develop"); err != nil {
+		t.Fatalf("Failed to create scratch branch: %v", err)
+	}
+	testutil.WriteFile(t, dir, "scratch.txt", "scratch content")
+	if _, err := testutil.RunGit(t, dir, "add", "scratch.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add scratch.txt on scratch"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+		t.Fatalf("Failed to checkout develop: %v", err)
+	}
+	testutil.WriteFile(t, dir, "scratch.txt", "develop content")
+	if _, err := testutil.RunGit(t, dir, "add", "scratch.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add scratch.txt on develop"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Leave a rebase stopped on the conflict
+	if _, err := testutil.RunGit(t, dir, "checkout", "scratch"); err != nil {
+		t.Fatalf("Failed to checkout scratch: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "rebase", "develop"); err == nil {
+		t.Fatal("Expected the rebase to stop on a conflict")
+	}
+
+	// Every command refuses to stack on the rebase
+	commands := [][]string{
+		{"feature", "finish", "guarded"},
+		{"update", "feature/guarded"},
+		{"feature", "start", "another"},
+	}
+	for _, args := range commands {
+		output, err = testutil.RunGitFlow(t, dir, args...)
+		if err == nil {
+			t.Fatalf("Expected '%s' to fail during a rebase\nOutput: %s", strings.Join(args, " "), output)
+		}
+		if !strings.Contains(output, "a git rebase is in progress") || !strings.Contains(output, "git rebase --abort") {
+			t.Errorf("Expected '%s' to explain the rebase in progress, got: %s", strings.Join(args, " "), output)
+		}
+	}
+	if testutil.IsMergeInProgress(t, dir) {
+		t.Error("Expected no merge state to be saved")
+	}
+	if testutil.BranchExists(t, dir, "feature/another") {
+		t.Error("Expected no feature branch to be started")
+	}
+
+	// Once the rebase is aborted the finish succeeds
+	if _, err := testutil.RunGit(t, dir, "rebase", "--abort"); err != nil {
+		t.Fatalf("Failed to abort rebase: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "guarded")
+	if err != nil {
+		t.Fatalf("Failed to finish feature after the rebase: %v\nOutput: %s", err, output)
+	}
+}
+
+// TestFinishWithTagObject tests that --tag-object tags the given object instead of the tip of the target.
+// Steps:
+// 1. Sets up a test repository and starts a release with a commit
+// 2. Verifies an unknown object is rejected before anything is merged
+// 3. Finishes the release, tagging the initial commit instead of the new tip of main
+// 4. Verifies the tag points at the initial commit
+func TestFinishWithTagObject(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	initialCommit, err := testutil.RunGit(t, dir, "rev-parse", "main")
+	if err != nil {
+		t.Fatalf("Failed to resolve main: %v", err)
+	}
+	initialCommit = strings.TrimSpace(initialCommit)
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release content")
+	if _, err := testutil.RunGit(t, dir, "add", "release.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add release.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// An unknown object is rejected before anything is merged
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--tag-object", "0123456789abcdef0123456789abcdef01234567")
+	if err == nil {
+		t.Fatalf("Expected finish to fail with an unknown object\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "--tag-object") {
+		t.Errorf("Expected error to mention --tag-object, got: %s", output)
+	}
+	if mainCommit, _ := testutil.RunGit(t, dir, "rev-parse", "main"); strings.TrimSpace(mainCommit) != initialCommit {
+		t.Error("Expected main to be unchanged")
+	}
+
+	// Tag the initial commit instead of the merge
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--tag-object", initialCommit)
+	if err != nil {
+		t.Fatalf("Failed to finish release: %v\nOutput: %s", err, output)
+	}
+
+	taggedCommit, err := testutil.RunGit(t, dir, "rev-parse", "1.0.0^{}")
+	if err != nil {
+		t.Fatalf("Failed to resolve tag: %v", err)
+	}
+	if strings.TrimSpace(taggedCommit) != initialCommit {
+		t.Errorf("Expected tag to point at %s, got %s", initialCommit, strings.TrimSpace(taggedCommit))
+	}
+	mainCommit, _ := testutil.RunGit(t, dir, "rev-parse", "main")
+	if strings.TrimSpace(mainCommit) == initialCommit {
+		t.Error("Expected the release to be merged into main")
+	}
+}
+
+// TestFinishDereferencesAnnotatedTagTarget tests that the tag of a finish points at a commit rather than another tag.
+// Steps:
+// 1. Finishes release 1.0.0, whose annotated tag points at the tip of main
+// 2. Creates an annotated tag named 'main' at that tip, so the target name is ambiguous
+// 3. Finishes hotfix 1.0.1 and verifies its tag points at the new tip of main as a commit
+// 4. Finishes hotfix 1.0.2 tagging the annotated tag 1.0.0 and verifies the tag points at its commit
+// 5. Finishes hotfix 1.0.3 with --no-dereference-annotated-tag-target and verifies the tag objects are nested
+func TestFinishDereferencesAnnotatedTagTarget(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	start := func(branchType string, version string) {
+		output, err := testutil.RunGitFlow(t, dir, branchType, "start", version)
+		if err != nil {
+			t.Fatalf("Failed to create %s branch: %v\nOutput: %s", branchType, err, output)
+		}
+		testutil.WriteFile(t, dir, version+".txt", version)
+		if _, err := testutil.RunGit(t, dir, "add", version+".txt"); err != nil {
+			t.Fatalf("Failed to add file: %v", err)
+		}
+		if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add "+version+".txt"); err != nil {
+			t.Fatalf("Failed to commit file: %v", err)
+		}
+	}
+	finish := func(branchType string, version string, args ...string) {
+		output, err := testutil.RunGitFlow(t, dir, append([]string{branchType, "finish", version}, args...)...)
+		if err != nil {
+			t.Fatalf("Failed to finish %s branch: %v\nOutput: %s", branchType, err, output)
+		}
+	}
+	taggedObject := func(tag string) (string, string) {
+		object, err := testutil.RunGit(t, dir, "cat-file", "-p", "refs/tags/"+tag)
+		if err != nil {
+			t.Fatalf("Failed to read tag '%s': %v", tag, err)
+		}
+		lines := strings.Split(object, "\n")
+		if len(lines) < 2 {
+			t.Fatalf("Unexpected tag object '%s': %s", tag, object)
+		}
+		return strings.TrimPrefix(lines[0], "object "), strings.TrimPrefix(lines[1], "type ")
+	}
+
+	start("release", "1.0.0")
+	finish("release", "1.0.0")
+	releaseCommit, _ := testutil.RunGit(t, dir, "rev-parse", "refs/tags/1.0.0^{commit}")
+	releaseCommit = strings.TrimSpace(releaseCommit)
+
+	// A tag named like the target doesn't get tagged instead of the branch
+	start("hotfix", "1.0.1")
+	if _, err := testutil.RunGit(t, dir, "tag", "-a", "main", "-m", "Confusing tag", "refs/heads/main"); err != nil {
+		t.Fatalf("Failed to create tag: %v", err)
+	}
+	finish("hotfix", "1.0.1")
+	mainCommit, _ := testutil.RunGit(t, dir, "rev-parse", "refs/heads/main")
+	if object, objectType := taggedObject("1.0.1"); objectType != "commit" || object != strings.TrimSpace(mainCommit) {
+		t.Errorf("Expected tag 1.0.1 to point at commit %s, got %s %s", strings.TrimSpace(mainCommit), objectType, object)
+	}
+	if _, err := testutil.RunGit(t, dir, "tag", "-d", "main"); err != nil {
+		t.Fatalf("Failed to delete tag: %v", err)
+	}
+
+	// An annotated tag given as the object is dereferenced to its commit
+	start("hotfix", "1.0.2")
+	finish("hotfix", "1.0.2", "--tag-object", "1.0.0")
+	if object, objectType := taggedObject("1.0.2"); objectType != "commit" || object != releaseCommit {
+		t.Errorf("Expected tag 1.0.2 to point at commit %s, got %s %s", releaseCommit, objectType, object)
+	}
+
+	// Without dereferencing, the tag object itself is tagged
+	start("hotfix", "1.0.3")
+	finish("hotfix", "1.0.3", "--tag-object", "1.0.0", "--no-dereference-annotated-tag-target")
+	if _, objectType := taggedObject("1.0.3"); objectType != "tag" {
+		t.Errorf("Expected tag 1.0.3 to point at the tag 1.0.0, got %s", objectType)
+	}
+}
+
+func TestFinishValidateOnly(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "preflight")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "preflight.txt", "feature content")
+	if _, err := testutil.RunGit(t, dir, "add", "preflight.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add preflight.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// All checks pass while the target exists
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "preflight", "--validate-only")
+	if err != nil {
+		t.Fatalf("Expected validation to pass: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "All checks passed") {
+		t.Errorf("Expected output to report that all checks passed, got: %s", output)
+	}
+	if !testutil.BranchExists(t, dir, "feature/preflight") {
+		t.Error("Expected feature branch to still exist")
+	}
+
+	// Remove the target branch
+	if _, err := testutil.RunGit(t, dir, "branch", "-D", "develop"); err != nil {
+		t.Fatalf("Failed to delete develop: %v", err)
+	}
+
+	refsBefore, _ := testutil.RunGit(t, dir, "for-each-ref")
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "preflight", "--validate-only")
+	if err == nil {
+		t.Fatalf("Expected validation to fail for a missing target branch\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "FAIL  target branch 'develop' exists") {
+		t.Errorf("Expected output to report the missing target branch, got: %s", output)
+	}
+	if !strings.Contains(output, "ok    branch 'feature/preflight' exists") {
+		t.Errorf("Expected output to report the passing checks too, got: %s", output)
+	}
+
+	// Nothing was changed
+	refsAfter, _ := testutil.RunGit(t, dir, "for-each-ref")
+	if refsBefore != refsAfter {
+		t.Errorf("Expected no refs to change, before:\n%s\nafter:\n%s", refsBefore, refsAfter)
+	}
+	if branch := testutil.GetCurrentBranch(t, dir); branch != "feature/preflight" {
+		t.Errorf("Expected to stay on 'feature/preflight', got '%s'", branch)
+	}
+	if _, err := testutil.LoadMergeState(t, dir); err == nil {
+		t.Error("Expected no merge state to be saved")
+	}
+}
+
+func TestFinishWithEnvFile(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release content")
+	if _, err := testutil.RunGit(t, dir, "add", "release.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add release.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	envFile := filepath.Join(t.TempDir(), "finish.env")
+	content := "# Release settings\nTAGNAME=v1.0.0-env\nexport MESSAGE=\"Message from the env file\"\nkeep=true\n"
+	if err := os.WriteFile(envFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write env file: %v", err)
+	}
+
+	// An unknown key is rejected before anything happens
+	badEnvFile := filepath.Join(t.TempDir(), "bad.env")
+	if err := os.WriteFile(badEnvFile, []byte("NO_SUCH_OPTION=1\n"), 0644); err != nil {
+		t.Fatalf("Failed to write env file: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--env-file", badEnvFile)
+	if err == nil {
+		t.Fatalf("Expected finish to fail for an unknown env file key\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "unknown option 'NO_SUCH_OPTION'") {
+		t.Errorf("Expected error to name the unknown key, got: %s", output)
+	}
+
+	// The webhook secret is not an option, so it can't come from the env file either
+	secretEnvFile := filepath.Join(t.TempDir(), "secret.env")
+	if err := os.WriteFile(secretEnvFile, []byte("WEBHOOK_SECRET=s3cret\n"), 0644); err != nil {
+		t.Fatalf("Failed to write env file: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--env-file", secretEnvFile)
+	if err == nil {
+		t.Fatalf("Expected finish to fail for a webhook secret in the env file\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "unknown option 'WEBHOOK_SECRET'") {
+		t.Errorf("Expected error to name the webhook secret key, got: %s", output)
+	}
+
+	// Flags on the command line take precedence over the env file
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--env-file", envFile, "--message", "Message from the command line")
+	if err != nil {
+		t.Fatalf("Failed to finish release branch: %v\nOutput: %s", err, output)
+	}
+
+	message, err := testutil.RunGit(t, dir, "for-each-ref", "--format=%(contents)", "refs/tags/v1.0.0-env")
+	if err != nil || strings.TrimSpace(message) == "" {
+		t.Fatalf("Expected tag 'v1.0.0-env' from the env file to exist: %v", err)
+	}
+	if !strings.Contains(message, "Message from the command line") {
+		t.Errorf("Expected the command-line message to win, got: %s", message)
+	}
+	if !testutil.BranchExists(t, dir, "release/1.0.0") {
+		t.Error("Expected release branch to be kept by keep=true in the env file")
+	}
+}
+
+func TestFinishWithReserveTagThenFinalize(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release content")
+	if _, err := testutil.RunGit(t, dir, "add", "release.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add release.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Finishing with --reserve-tag only creates a lightweight placeholder
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--reserve-tag")
+	if err != nil {
+		t.Fatalf("Failed to finish release branch: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Reserved tag '1.0.0'") {
+		t.Errorf("Expected output to report the reservation, got: %s", output)
+	}
+	tagType, _ := testutil.RunGit(t, dir, "cat-file", "-t", "refs/tags/1.0.0")
+	if strings.TrimSpace(tagType) != "commit" {
+		t.Errorf("Expected a lightweight tag, got object type '%s'", strings.TrimSpace(tagType))
+	}
+	branch, err := testutil.RunGit(t, dir, "config", "gitflow.reservedtag.1.0.0.branch")
+	if err != nil || strings.TrimSpace(branch) != "main" {
+		t.Errorf("Expected the reservation to record 'main', got '%s' (%v)", strings.TrimSpace(branch), err)
+	}
+
+	// Finalizing an unreserved tag is an error
+	output, err = testutil.RunGitFlow(t, dir, "release", "finalize", "2.0.0")
+	if err == nil {
+		t.Fatalf("Expected finalize to fail for an unreserved tag\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "tag '2.0.0' is not reserved") {
+		t.Errorf("Expected error about the unreserved tag, got: %s", output)
+	}
+
+	// The artifact becomes ready with a later commit on main
+	if _, err := testutil.RunGit(t, dir, "checkout", "main"); err != nil {
+		t.Fatalf("Failed to checkout main: %v", err)
+	}
+	testutil.WriteFile(t, dir, "artifact.txt", "build artifact")
+	if _, err := testutil.RunGit(t, dir, "add", "artifact.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add artifact.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	mainHead, _ := testutil.RunGit(t, dir, "rev-parse", "main")
+
+	// Finalizing replaces the placeholder with an annotated tag at the final commit
+	output, err = testutil.RunGitFlow(t, dir, "release", "finalize", "1.0.0", "-m", "Release 1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to finalize tag: %v\nOutput: %s", err, output)
+	}
+	tagType, _ = testutil.RunGit(t, dir, "cat-file", "-t", "refs/tags/1.0.0")
+	if strings.TrimSpace(tagType) != "tag" {
+		t.Errorf("Expected an annotated tag, got object type '%s'", strings.TrimSpace(tagType))
+	}
+	tagCommit, _ := testutil.RunGit(t, dir, "rev-parse", "refs/tags/1.0.0^{}")
+	if strings.TrimSpace(tagCommit) != strings.TrimSpace(mainHead) {
+		t.Errorf("Expected the final tag at %s, got %s", strings.TrimSpace(mainHead), strings.TrimSpace(tagCommit))
+	}
+	message, _ := testutil.RunGit(t, dir, "for-each-ref", "--format=%(contents)", "refs/tags/1.0.0")
+	if !strings.Contains(message, "Release 1.0.0") {
+		t.Errorf("Expected the tag message 'Release 1.0.0', got: %s", message)
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.reservedtag.1.0.0.branch"); err == nil {
+		t.Error("Expected the reservation to be removed after finalizing")
+	}
+}
+
+func TestFinishInMinimalRepository(t *testing.T) {
+	// Setup: the test repository has a single commit and no tags
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	rootCommit, _ := testutil.RunGit(t, dir, "rev-list", "--max-parents=0", "HEAD")
+	rootCommit = strings.TrimSpace(rootCommit)
+
+	// A feature whose merge base is the only prior commit
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "first")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "first.txt", "first content")
+	if _, err := testutil.RunGit(t, dir, "add", "first.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add first.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "first")
+	if err != nil {
+		t.Fatalf("Failed to finish feature in a minimal repository: %v\nOutput: %s", err, output)
+	}
+	if testutil.BranchExists(t, dir, "feature/first") {
+		t.Error("Expected feature branch to be deleted")
+	}
+	if _, err := testutil.RunGit(t, dir, "merge-base", "--is-ancestor", rootCommit, "develop"); err != nil {
+		t.Error("Expected develop to still contain the initial commit")
+	}
+
+	// The first release has no prior tags; --since the initial commit covers the whole history
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "0.1.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "0.1.0", "--since", rootCommit)
+	if err != nil {
+		t.Fatalf("Failed to finish the first release: %v\nOutput: %s", err, output)
+	}
+	message, err := testutil.RunGit(t, dir, "for-each-ref", "--format=%(contents)", "refs/tags/0.1.0")
+	if err != nil {
+		t.Fatalf("Expected tag '0.1.0' to exist: %v", err)
+	}
+	if !strings.Contains(message, "Add first.txt") {
+		t.Errorf("Expected tag message to list the commits since the initial commit, got: %s", message)
+	}
+}
+
+func TestFinishWithMergeMessageEdit(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "edited")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "edited.txt", "feature content")
+	if _, err := testutil.RunGit(t, dir, "add", "edited.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add edited.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Without a terminal or an explicit editor, the finish refuses before merging
+	output, err = testutil.RunGitFlowWithEnv(t, dir, []string{"GIT_EDITOR="}, "feature", "finish", "edited", "--merge-message-edit")
+	if err == nil {
+		t.Fatalf("Expected finish to refuse editing without a terminal\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "no terminal to run the editor in") {
+		t.Errorf("Expected error about the missing terminal, got: %s", output)
+	}
+	if !testutil.BranchExists(t, dir, "feature/edited") || testutil.IsMergeInProgress(t, dir) {
+		t.Fatal("Expected nothing to change when editing is refused")
+	}
+
+	// An editor that saves the template it was given and replaces the message
+	templateFile := filepath.Join(t.TempDir(), "template.txt")
+	editor := "sh -c 'cp \"$1\" \"" + templateFile + "\"; printf \"Integrate the edited feature\\n\" > \"$1\"' editor"
+
+	// The config enables editing without the flag
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.feature.finish.editmergemessage", "true"); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+	output, err = testutil.RunGitFlowWithEnv(t, dir, []string{"GIT_EDITOR=" + editor}, "feature", "finish", "edited")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+
+	// The editor got the default subject and the integrated commits
+	template, err := os.ReadFile(templateFile)
+	if err != nil {
+		t.Fatalf("Expected the editor to be run: %v", err)
+	}
+	if !strings.HasPrefix(string(template), "Merge branch 'feature/edited' into develop") {
+		t.Errorf("Expected template to start with the default subject, got: %s", template)
+	}
+	if !strings.Contains(string(template), "Add edited.txt") {
+		t.Errorf("Expected template to list the integrated commits, got: %s", template)
+	}
+
+	// The merge commit uses the edited message
+	subject, _ := testutil.RunGit(t, dir, "log", "-1", "--format=%s", "develop")
+	if strings.TrimSpace(subject) != "Integrate the edited feature" {
+		t.Errorf("Expected the edited merge message, got '%s'", strings.TrimSpace(subject))
+	}
+	parents, _ := testutil.RunGit(t, dir, "log", "-1", "--format=%P", "develop")
+	if len(strings.Fields(parents)) != 2 {
+		t.Errorf("Expected a merge commit with two parents, got '%s'", strings.TrimSpace(parents))
+	}
+}
+
+func TestFinishBranchConfigRetention(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// startFeature creates a feature branch with a commit and a description setting
+	startFeature := func(name string) {
+		output, err := testutil.RunGitFlow(t, dir, "feature", "start", name)
+		if err != nil {
+			t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+		}
+		testutil.WriteFile(t, dir, name+".txt", "feature content")
+		if _, err := testutil.RunGit(t, dir, "add", name+".txt"); err != nil {
+			t.Fatalf("Failed to add file: %v", err)
+		}
+		if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add "+name+".txt"); err != nil {
+			t.Fatalf("Failed to commit file: %v", err)
+		}
+		if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch.feature/"+name+".description", "Archived metadata"); err != nil {
+			t.Fatalf("Failed to set description: %v", err)
+		}
+	}
+	hasConfig := func(name string) bool {
+		_, err := testutil.RunGit(t, dir, "config", "gitflow.branch.feature/"+name+".description")
+		return err == nil
+	}
+
+	// A kept branch keeps its settings
+	startFeature("kept")
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "kept", "--keep")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+	if !testutil.BranchExists(t, dir, "feature/kept") || !hasConfig("kept") {
+		t.Error("Expected the kept branch to keep its settings")
+	}
+
+	// A deleted branch loses its settings by default
+	startFeature("deleted")
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "deleted")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+	if testutil.BranchExists(t, dir, "feature/deleted") || hasConfig("deleted") {
+		t.Error("Expected the deleted branch and its settings to be removed")
+	}
+
+	// --keep-branch-config archives the settings of a deleted branch
+	startFeature("archived")
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "archived", "--keep-branch-config")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+	if testutil.BranchExists(t, dir, "feature/archived") || !hasConfig("archived") {
+		t.Error("Expected the branch to be deleted but its settings to be kept")
+	}
+
+	// The config enables keeping, and --no-keep-branch-config overrides it
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.feature.finish.keepbranchconfig", "true"); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+	startFeature("configured")
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "configured", "--no-keep-branch-config")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+	if hasConfig("configured") {
+		t.Error("Expected --no-keep-branch-config to remove the settings despite the config")
+	}
+}
+
+func TestFinishWithTargetRemoteTrackingUpdate(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	remoteDir, err := testutil.AddRemote(t, dir, "origin", true)
+	if err != nil {
+		t.Fatalf("Failed to add remote: %v", err)
+	}
+	defer os.RemoveAll(remoteDir)
+	if _, err := testutil.RunGit(t, dir, "fetch", "origin"); err != nil {
+		t.Fatalf("Failed to fetch: %v", err)
+	}
+
+	// A teammate pushes to develop in the meantime
+	cloneDir := t.TempDir()
+	if _, err := testutil.RunGit(t, cloneDir, "clone", "--branch", "develop", remoteDir, "."); err != nil {
+		t.Fatalf("Failed to clone remote: %v", err)
+	}
+	testutil.WriteFile(t, cloneDir, "teammate.txt", "teammate content")
+	if _, err := testutil.RunGit(t, cloneDir, "add", "teammate.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, cloneDir, "-c", "user.name=Teammate", "-c", "user.email=teammate@example.com", "commit", "-m", "Add teammate.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, cloneDir, "push", "origin", "develop"); err != nil {
+		t.Fatalf("Failed to push: %v", err)
+	}
+	remoteDevelop, _ := testutil.RunGit(t, remoteDir, "rev-parse", "develop")
+
+	// Finish a feature locally
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "tracking")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "tracking.txt", "feature content")
+	if _, err := testutil.RunGit(t, dir, "add", "tracking.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add tracking.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "tracking", "--target-remote-tracking-update")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+
+	// The report accounts for the teammate's commit instead of the stale remote-tracking branch
+	if !strings.Contains(output, "'develop' is 2 ahead and 1 behind 'origin/develop'") {
+		t.Errorf("Expected output to report develop as 2 ahead and 1 behind, got: %s", output)
+	}
+	trackingDevelop, _ := testutil.RunGit(t, dir, "rev-parse", "origin/develop")
+	if strings.TrimSpace(trackingDevelop) != strings.TrimSpace(remoteDevelop) {
+		t.Errorf("Expected 'origin/develop' at %s, got %s", strings.TrimSpace(remoteDevelop), strings.TrimSpace(trackingDevelop))
+	}
+
+	// Nothing was pushed
+	remoteAfter, _ := testutil.RunGit(t, remoteDir, "rev-parse", "develop")
+	if strings.TrimSpace(remoteAfter) != strings.TrimSpace(remoteDevelop) {
+		t.Error("Expected the remote develop branch to be unchanged")
+	}
+}
+
+// TestFinishWithIssueCommentCommand tests commenting on the branch's issue after a successful finish.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates a release branch with a commit and records issue 42 for it
+// 3. Configures gitflow.release.finish.issuecommentcommand writing "merged in %tag%" for %issue%
+// 4. Finishes the release and verifies the command ran with the issue id and tag substituted
+// 5. Finishes a feature without a recorded issue and verifies the command is skipped
+func TestFinishWithIssueCommentCommand(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create a release branch with a commit and record its issue
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release content")
+	_, err = testutil.RunGit(t, dir, "add", "release.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add release file")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "config", "gitflow.branch.release/1.0.0.issue", "42")
+	if err != nil {
+		t.Fatalf("Failed to record issue: %v", err)
+	}
+
+	// Configure the issue comment command
+	hook := `echo "%issue%: merged in %tag%" >> issue.out`
+	_, err = testutil.RunGit(t, dir, "config", "gitflow.release.finish.issuecommentcommand", hook)
+	if err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "config", "gitflow.feature.finish.issuecommentcommand", hook)
+	if err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+
+	// Finish the release
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to finish release branch: %v\nOutput: %s", err, output)
+	}
+
+	// Verify the command ran with the issue id and tag substituted
+	if !testutil.FileExists(t, dir, "issue.out") {
+		t.Fatalf("Expected issue comment command to create issue.out\nOutput: %s", output)
+	}
+	content := strings.TrimSpace(testutil.ReadFile(t, dir, "issue.out"))
+	if content != "42: merged in 1.0.0" {
+		t.Errorf("Expected issue comment '42: merged in 1.0.0', got '%s'", content)
+	}
+
+	// Finish a feature without a recorded issue
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "no-issue")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "no-issue")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+
+	// Verify the command was skipped
+	content = strings.TrimSpace(testutil.ReadFile(t, dir, "issue.out"))
+	if content != "42: merged in 1.0.0" {
+		t.Errorf("Expected no further issue comment, got '%s'", content)
+	}
+}
+
+// TestFinishIssueCommentCommandWithShellMetacharacters tests that ref names are not run by the issue comment command's shell.
+// Steps:
+// 1. Sets up a test repository and creates a feature branch whose name contains ; and $(...)
+// 2. Records an issue for it and configures an issue comment command writing %branch% and %target%
+// 3. Finishes the feature
+// 4. Verifies the command received the branch name as it is and none of its parts ran as a command
+func TestFinishIssueCommentCommandWithShellMetacharacters(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// A branch name git accepts but a shell would run
+	name := "fix;touch${IFS}pwned1;$(touch${IFS}pwned2)"
+	if _, err := testutil.RunGit(t, dir, "checkout", "-b", "feature/"+name, "develop"); err != nil {
+		t.Fatalf("Failed to create branch: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "--allow-empty", "-m", "Fix"); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch.feature/"+name+".issue", "7"); err != nil {
+		t.Fatalf("Failed to record issue: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.feature.finish.issuecommentcommand", `echo "%issue% %branch% into %target%" > issue.out`); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+
+	// Finish the feature
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", name)
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+
+	// Verify the name was passed as it is and nothing in it ran
+	content := strings.TrimSpace(testutil.ReadFile(t, dir, "issue.out"))
+	if content != "7 feature/"+name+" into develop" {
+		t.Errorf("Expected the branch name as it is in the issue comment, got '%s'", content)
+	}
+	for _, file := range []string{"pwned1", "pwned2"} {
+		if testutil.FileExists(t, dir, file) {
+			t.Errorf("Expected the branch name not to run as a command, but %s was created", file)
+		}
+	}
+}
+
+// TestFinishWithAlsoInto tests merging a hotfix into additional targets after the primary merge.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates support/1.x and support/2.x branches and a hotfix branch with a commit
+// 3. Finishes the hotfix with --also-into for both support branches
+// 4. Verifies main, develop and both support branches contain the hotfix and the branch is deleted
+func TestFinishWithAlsoInto(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create the support branches
+	for _, branch := range []string{"support/1.x", "support/2.x"} {
+		_, err = testutil.RunGit(t, dir, "branch", branch, "main")
+		if err != nil {
+			t.Fatalf("Failed to create %s: %v", branch, err)
+		}
+	}
+
+	// Create a hotfix branch with a commit
+	output, err = testutil.RunGitFlow(t, dir, "hotfix", "start", "1.0.1")
+	if err != nil {
+		t.Fatalf("Failed to create hotfix branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "fix.txt", "fix content")
+	_, err = testutil.RunGit(t, dir, "add", "fix.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add fix")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	hotfixCommit, err := testutil.RunGit(t, dir, "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatalf("Failed to resolve hotfix commit: %v", err)
+	}
+	hotfixCommit = strings.TrimSpace(hotfixCommit)
+
+	// Finish the hotfix into main and both support branches
+	output, err = testutil.RunGitFlow(t, dir, "hotfix", "finish", "1.0.1", "--also-into", "support/1.x", "--also-into", "support/2.x")
+	if err != nil {
+		t.Fatalf("Failed to finish hotfix branch: %v\nOutput: %s", err, output)
+	}
+
+	// Verify every branch contains the hotfix
+	for _, branch := range []string{"main", "develop", "support/1.x", "support/2.x"} {
+		if _, err := testutil.RunGit(t, dir, "merge-base", "--is-ancestor", hotfixCommit, branch); err != nil {
+			t.Errorf("Expected %s to contain the hotfix commit\nOutput: %s", branch, output)
+		}
+	}
+
+	// Verify the support branches did not receive anything else from main
+	log, err := testutil.RunGit(t, dir, "log", "--format=%s", "main..support/1.x")
+	if err != nil {
+		t.Fatalf("Failed to list commits: %v", err)
+	}
+	if !strings.Contains(log, "Merge branch 'hotfix/1.0.1'") {
+		t.Errorf("Expected a merge of the hotfix on support/1.x, got:\n%s", log)
+	}
+	if testutil.BranchExists(t, dir, "hotfix/1.0.1") {
+		t.Error("Expected hotfix branch to be deleted")
+	}
+	if testutil.IsMergeInProgress(t, dir) {
+		t.Error("Expected no merge state after the finish")
+	}
+}
+
+// TestFinishWithAlsoIntoConflict tests resuming and aborting a fan-out that conflicts on an additional target.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates support/1.x and a conflicting support/2.x, and a hotfix branch changing the same file
+// 3. Finishes the hotfix with --also-into for both and verifies it stops on support/2.x
+// 4. Aborts and verifies support/1.x is restored and the hotfix branch remains
+// 5. Finishes again, resolves the conflict, continues and verifies both support branches contain the hotfix
+func TestFinishWithAlsoIntoConflict(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create support/1.x and a support/2.x with a conflicting change
+	_, err = testutil.RunGit(t, dir, "branch", "support/1.x", "main")
+	if err != nil {
+		t.Fatalf("Failed to create support/1.x: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "checkout", "-b", "support/2.x", "main")
+	if err != nil {
+		t.Fatalf("Failed to create support/2.x: %v", err)
+	}
+	testutil.WriteFile(t, dir, "fix.txt", "support content")
+	_, err = testutil.RunGit(t, dir, "add", "fix.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Change fix.txt on support/2.x")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	support1Before, err := testutil.RunGit(t, dir, "rev-parse", "support/1.x")
+	if err != nil {
+		t.Fatalf("Failed to resolve support/1.x: %v", err)
+	}
+
+	// Create a hotfix branch changing the same file
+	output, err = testutil.RunGitFlow(t, dir, "hotfix", "start", "1.0.1")
+	if err != nil {
+		t.Fatalf("Failed to create hotfix branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "fix.txt", "fix content")
+	_, err = testutil.RunGit(t, dir, "add", "fix.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add fix")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	hotfixCommit, err := testutil.RunGit(t, dir, "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatalf("Failed to resolve hotfix commit: %v", err)
+	}
+	hotfixCommit = strings.TrimSpace(hotfixCommit)
+
+	// Finish, which stops on the conflict in support/2.x
+	output, err = testutil.RunGitFlow(t, dir, "hotfix", "finish", "1.0.1", "--also-into", "support/1.x", "--also-into", "support/2.x")
+	if err == nil {
+		t.Fatalf("Expected the finish to stop on a conflict\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "Merge conflicts detected while merging into 'support/2.x'") {
+		t.Errorf("Expected conflict message for support/2.x, got: %s", output)
+	}
+	state, err := testutil.LoadMergeState(t, dir)
+	if err != nil {
+		t.Fatalf("Failed to load merge state: %v", err)
+	}
+	if state.CurrentStep != "merge_also_into" {
+		t.Errorf("Expected step merge_also_into, got %s", state.CurrentStep)
+	}
+	if len(state.MergedInto) != 1 || state.MergedInto[0] != "support/1.x" {
+		t.Errorf("Expected support/1.x to be recorded as merged, got %v", state.MergedInto)
+	}
+
+	// Abort and verify support/1.x is restored
+	output, err = testutil.RunGitFlow(t, dir, "hotfix", "finish", "--abort", "1.0.1")
+	if err != nil {
+		t.Fatalf("Failed to abort finish: %v\nOutput: %s", err, output)
+	}
+	support1After, err := testutil.RunGit(t, dir, "rev-parse", "support/1.x")
+	if err != nil {
+		t.Fatalf("Failed to resolve support/1.x: %v", err)
+	}
+	if support1After != support1Before {
+		t.Errorf("Expected support/1.x to be restored to %s, got %s", support1Before, support1After)
+	}
+	if !testutil.BranchExists(t, dir, "hotfix/1.0.1") {
+		t.Error("Expected hotfix branch to still exist after abort")
+	}
+
+	// Finish again and resolve the conflict
+	_, err = testutil.RunGitFlow(t, dir, "hotfix", "finish", "1.0.1", "--also-into", "support/1.x", "--also-into", "support/2.x")
+	if err == nil {
+		t.Fatal("Expected the finish to stop on a conflict again")
+	}
+	testutil.WriteFile(t, dir, "fix.txt", "resolved content")
+	_, err = testutil.RunGit(t, dir, "add", "fix.txt")
+	if err != nil {
+		t.Fatalf("Failed to add resolved file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "--no-edit")
+	if err != nil {
+		t.Fatalf("Failed to commit resolution: %v", err)
+	}
+
+	// Continue and verify both support branches contain the hotfix
+	output, err = testutil.RunGitFlow(t, dir, "hotfix", "finish", "--continue", "1.0.1")
+	if err != nil {
+		t.Fatalf("Failed to continue finish: %v\nOutput: %s", err, output)
+	}
+	for _, branch := range []string{"support/1.x", "support/2.x"} {
+		if _, err := testutil.RunGit(t, dir, "merge-base", "--is-ancestor", hotfixCommit, branch); err != nil {
+			t.Errorf("Expected %s to contain the hotfix\nOutput: %s", branch, output)
+		}
+	}
+	if testutil.BranchExists(t, dir, "hotfix/1.0.1") {
+		t.Error("Expected hotfix branch to be deleted")
+	}
+}
+
+// TestFinishWithPrintTag tests printing the created tag in machine-parseable form.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates a release branch with a commit
+// 3. Finishes it with --print-tag
+// 4. Verifies a "tag: <name> <sha>" line with the commit the tag points to is printed
+// 5. Finishes a feature with --print-tag and verifies no tag line is printed
+func TestFinishWithPrintTag(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create a release branch with a commit
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release content")
+	_, err = testutil.RunGit(t, dir, "add", "release.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add release file")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Finish with --print-tag
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--print-tag")
+	if err != nil {
+		t.Fatalf("Failed to finish release branch: %v\nOutput: %s", err, output)
+	}
+
+	// Verify the tag line names the tag and the commit it points to
+	tagCommit, err := testutil.RunGit(t, dir, "rev-parse", "1.0.0^{commit}")
+	if err != nil {
+		t.Fatalf("Failed to resolve tag: %v", err)
+	}
+	expected := "tag: 1.0.0 " + strings.TrimSpace(tagCommit)
+	found := false
+	for _, line := range strings.Split(output, "\n") {
+		if line == expected {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected line '%s', got: %s", expected, output)
+	}
+
+	// A finish without a tag prints no tag line
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "untagged")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "untagged", "--print-tag")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+	if strings.Contains(output, "tag: ") {
+		t.Errorf("Expected no tag line without a tag, got: %s", output)
+	}
+}
+
+// TestFinishPreventDeleteRace tests that a branch that moved after its merge is not deleted.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates a hotfix branch and a conflicting change on develop
+// 3. Finishes the hotfix with --prevent-fast-forward-delete-race, which stops on the develop update
+// 4. Advances the hotfix branch as another process would, then resolves and continues
+// 5. Verifies the deletion is refused and the new commit is kept
+// 6. Continues with --force-delete and verifies the branch is deleted
+func TestFinishPreventDeleteRace(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Change fix.txt on develop so that the hotfix conflicts with it
+	testutil.WriteFile(t, dir, "fix.txt", "develop content")
+	_, err = testutil.RunGit(t, dir, "add", "fix.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Change fix.txt on develop")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Create a hotfix branch changing the same file
+	output, err = testutil.RunGitFlow(t, dir, "hotfix", "start", "1.0.1")
+	if err != nil {
+		t.Fatalf("Failed to create hotfix branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "fix.txt", "fix content")
+	_, err = testutil.RunGit(t, dir, "add", "fix.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add fix")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Finish, which stops on the conflict in develop after the merge into main
+	output, err = testutil.RunGitFlow(t, dir, "hotfix", "finish", "1.0.1", "--prevent-fast-forward-delete-race")
+	if err == nil {
+		t.Fatalf("Expected the finish to stop on a conflict\nOutput: %s", output)
+	}
+
+	// Advance the hotfix branch without touching the working tree
+	lateCommit, err := testutil.RunGit(t, dir, "commit-tree", "hotfix/1.0.1^{tree}", "-p", "hotfix/1.0.1", "-m", "Late commit")
+	if err != nil {
+		t.Fatalf("Failed to create late commit: %v", err)
+	}
+	lateCommit = strings.TrimSpace(lateCommit)
+	_, err = testutil.RunGit(t, dir, "update-ref", "refs/heads/hotfix/1.0.1", lateCommit)
+	if err != nil {
+		t.Fatalf("Failed to advance hotfix branch: %v", err)
+	}
+
+	// Resolve the conflict and continue
+	testutil.WriteFile(t, dir, "fix.txt", "resolved content")
+	_, err = testutil.RunGit(t, dir, "add", "fix.txt")
+	if err != nil {
+		t.Fatalf("Failed to add resolved file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "--no-edit")
+	if err != nil {
+		t.Fatalf("Failed to commit resolution: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "hotfix", "finish", "--continue", "1.0.1")
+	if err == nil {
+		t.Fatalf("Expected the deletion to be refused\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "branch 'hotfix/1.0.1' moved from") {
+		t.Errorf("Expected an error about the moved branch, got: %s", output)
+	}
+
+	// Verify the branch and its new commit are kept
+	head, err := testutil.RunGit(t, dir, "rev-parse", "hotfix/1.0.1")
+	if err != nil {
+		t.Fatalf("Expected hotfix branch to still exist: %v", err)
+	}
+	if strings.TrimSpace(head) != lateCommit {
+		t.Errorf("Expected hotfix branch at %s, got %s", lateCommit, head)
+	}
+	state, err := testutil.LoadMergeState(t, dir)
+	if err != nil {
+		t.Fatalf("Expected the finish to remain resumable: %v", err)
+	}
+	if state.CurrentStep != "delete_branch" {
+		t.Errorf("Expected step delete_branch, got %s", state.CurrentStep)
+	}
+
+	// Forcing the deletion deletes the branch anyway
+	output, err = testutil.RunGitFlow(t, dir, "hotfix", "finish", "--continue", "1.0.1", "--force-delete")
+	if err != nil {
+		t.Fatalf("Failed to continue finish with --force-delete: %v\nOutput: %s", err, output)
+	}
+	if testutil.BranchExists(t, dir, "hotfix/1.0.1") {
+		t.Error("Expected hotfix branch to be deleted")
+	}
+}
+
+// TestFinishWithAlternateIndexFile tests finishing with GIT_INDEX_FILE pointing to a separate index.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates a feature branch with a commit and copies the index to a separate file
+// 3. Finishes the feature with GIT_INDEX_FILE set to the copy
+// 4. Verifies the merge used the separate index and left the default index untouched
+func TestFinishWithAlternateIndexFile(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create a feature branch with a commit
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "alt-index")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "feature.txt", "feature content")
+	_, err = testutil.RunGit(t, dir, "add", "feature.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add feature file")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Copy the index to a separate file
+	defaultIndex, err := os.ReadFile(filepath.Join(dir, ".git", "index"))
+	if err != nil {
+		t.Fatalf("Failed to read index: %v", err)
+	}
+	altIndex := filepath.Join(dir, ".git", "alt-index")
+	if err := os.WriteFile(altIndex, defaultIndex, 0644); err != nil {
+		t.Fatalf("Failed to write alternate index: %v", err)
+	}
+
+	// Finish with the separate index
+	output, err = testutil.RunGitFlowWithEnv(t, dir, []string{"GIT_INDEX_FILE=" + altIndex}, "feature", "finish", "alt-index")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+
+	// Verify the feature was merged and the separate index matches the result
+	_, err = testutil.RunGit(t, dir, "cat-file", "-e", "develop:feature.txt")
+	if err != nil {
+		t.Errorf("Expected feature.txt on develop: %v", err)
+	}
+	cmd := exec.Command("git", "diff", "--cached", "--quiet", "develop")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_INDEX_FILE="+altIndex)
+	if err := cmd.Run(); err != nil {
+		t.Errorf("Expected the alternate index to match develop: %v", err)
+	}
+
+	// Verify the default index was left untouched
+	currentIndex, err := os.ReadFile(filepath.Join(dir, ".git", "index"))
+	if err != nil {
+		t.Fatalf("Failed to read index: %v", err)
+	}
+	if !bytes.Equal(currentIndex, defaultIndex) {
+		t.Error("Expected the default index to be left untouched")
+	}
+}
+
+// TestFinishWithSeparateGitDir tests finishing with GIT_DIR and GIT_WORK_TREE instead of a .git directory.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates a feature branch conflicting with develop
+// 3. Moves the git directory out of the work tree
+// 4. Finishes the feature with GIT_DIR and GIT_WORK_TREE set and verifies the state is saved in the git directory
+// 5. Aborts the finish and verifies the state is found and cleared
+func TestFinishWithSeparateGitDir(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create a feature branch with a change conflicting with develop
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "separate")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "conflict.txt", "feature content")
+	_, err = testutil.RunGit(t, dir, "add", "conflict.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add conflict file on feature")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "checkout", "develop")
+	if err != nil {
+		t.Fatalf("Failed to checkout develop: %v", err)
+	}
+	testutil.WriteFile(t, dir, "conflict.txt", "develop content")
+	_, err = testutil.RunGit(t, dir, "add", "conflict.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add conflict file on develop")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Move the git directory out of the work tree
+	gitDir := filepath.Join(t.TempDir(), "repo.git")
+	if err := os.Rename(filepath.Join(dir, ".git"), gitDir); err != nil {
+		t.Fatalf("Failed to move git directory: %v", err)
+	}
+	env := []string{"GIT_DIR=" + gitDir, "GIT_WORK_TREE=" + dir}
+
+	// Finish, which stops on the conflict
+	output, err = testutil.RunGitFlowWithEnv(t, dir, env, "feature", "finish", "separate")
+	if err == nil {
+		t.Fatalf("Expected the finish to stop on a conflict\nOutput: %s", output)
+	}
+	if _, err := os.Stat(filepath.Join(gitDir, "gitflow", "state", "merge.json")); err != nil {
+		t.Errorf("Expected the merge state in the git directory: %v\nOutput: %s", err, output)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git")); !os.IsNotExist(err) {
+		t.Error("Expected no .git directory to be created in the work tree")
+	}
+
+	// Abort and verify the state is found and cleared
+	output, err = testutil.RunGitFlowWithEnv(t, dir, env, "feature", "finish", "--abort", "separate")
+	if err != nil {
+		t.Fatalf("Failed to abort finish: %v\nOutput: %s", err, output)
+	}
+	if _, err := os.Stat(filepath.Join(gitDir, "gitflow", "state", "merge.json")); !os.IsNotExist(err) {
+		t.Error("Expected the merge state to be cleared")
+	}
+}
+
+// TestFinishIdempotentRerun tests re-running a completed finish with --idempotent.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates and finishes a release branch
+// 3. Re-runs the finish without --idempotent and verifies it fails
+// 4. Re-runs the finish with --idempotent and verifies it succeeds reporting it was already finished
+// 5. Re-runs a finished feature, which has no tag, with --idempotent and verifies it still fails
+func TestFinishIdempotentRerun(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create and finish a release branch
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release content")
+	_, err = testutil.RunGit(t, dir, "add", "release.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add release file")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to finish release branch: %v\nOutput: %s", err, output)
+	}
+
+	// Re-running without --idempotent fails
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0")
+	if err == nil {
+		t.Fatalf("Expected re-running the finish to fail\nOutput: %s", output)
+	}
+
+	// Re-running with --idempotent succeeds
+	mainBefore, err := testutil.RunGit(t, dir, "rev-parse", "main")
+	if err != nil {
+		t.Fatalf("Failed to resolve main: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--idempotent")
+	if err != nil {
+		t.Fatalf("Expected re-running with --idempotent to succeed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Branch 'release/1.0.0' already finished: tag '1.0.0' is part of 'main'") {
+		t.Errorf("Expected already finished message, got: %s", output)
+	}
+	mainAfter, err := testutil.RunGit(t, dir, "rev-parse", "main")
+	if err != nil {
+		t.Fatalf("Failed to resolve main: %v", err)
+	}
+	if mainAfter != mainBefore {
+		t.Error("Expected main to be unchanged")
+	}
+
+	// Without a tag there is no evidence of a completed finish
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "untagged")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "untagged")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "untagged", "--idempotent")
+	if err == nil {
+		t.Fatalf("Expected re-running a finish without a tag to fail\nOutput: %s", output)
+	}
+}
+
+// TestFinishChildrenStrategyReport tests reporting the strategy and outcome of each child update.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Adds a staging base branch under main with its own commit, updated by rebase
+// 3. Finishes a hotfix with --children-strategy-report
+// 4. Verifies develop is reported as merged and staging as rebased
+func TestFinishChildrenStrategyReport(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Add a staging base branch that is updated from main by rebase
+	for key, value := range map[string]string{
+		"gitflow.branch.staging.type":               "base",
+		"gitflow.branch.staging.parent":             "main",
+		"gitflow.branch.staging.downstreamstrategy": "rebase",
+		"gitflow.branch.staging.autoupdate":         "true",
+	} {
+		if _, err := testutil.RunGit(t, dir, "config", key, value); err != nil {
+			t.Fatalf("Failed to set %s: %v", key, err)
+		}
+	}
+	_, err = testutil.RunGit(t, dir, "checkout", "-b", "staging", "main")
+	if err != nil {
+		t.Fatalf("Failed to create staging: %v", err)
+	}
+	testutil.WriteFile(t, dir, "staging.txt", "staging content")
+	_, err = testutil.RunGit(t, dir, "add", "staging.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add staging file")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Create a hotfix branch with a commit
+	output, err = testutil.RunGitFlow(t, dir, "hotfix", "start", "1.0.1")
+	if err != nil {
+		t.Fatalf("Failed to create hotfix branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "fix.txt", "fix content")
+	_, err = testutil.RunGit(t, dir, "add", "fix.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add fix")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Finish with the report
+	output, err = testutil.RunGitFlow(t, dir, "hotfix", "finish", "1.0.1", "--children-strategy-report")
+	if err != nil {
+		t.Fatalf("Failed to finish hotfix branch: %v\nOutput: %s", err, output)
+	}
+
+	// Verify the report lists each child with its strategy and outcome
+	if !strings.Contains(output, "Child base branch updates:") {
+		t.Fatalf("Expected a child update report, got: %s", output)
+	}
+	reportLine := regexp.MustCompile(`(?m)^  (\S+)\s+(\S+)\s+(.+)$`)
+	report := map[string]string{}
+	for _, match := range reportLine.FindAllStringSubmatch(output[strings.Index(output, "Child base branch updates:"):], -1) {
+		report[match[1]] = match[2] + ": " + match[3]
+	}
+	if report["develop"] != "merge: created a merge commit" {
+		t.Errorf("Expected develop to be reported as 'merge: created a merge commit', got '%s'\nOutput: %s", report["develop"], output)
+	}
+	if report["staging"] != "rebase: rebased" {
+		t.Errorf("Expected staging to be reported as 'rebase: rebased', got '%s'\nOutput: %s", report["staging"], output)
+	}
+}
+
+// TestFinishWithoutNetworkOperations tests that a plain finish does not contact the remote.
+// Steps:
+// 1. Sets up a test repository with a remote and initializes git-flow
+// 2. Finishes a feature branch that was never pushed while tracing git commands
+// 3. Verifies no fetch, push or ls-remote was run
+// 4. Pushes a second feature branch and finishes it with --keepremote
+// 5. Verifies again that the remote was not contacted and the remote branch is kept
+// 6. Configures pushing and finishes pushed branches with --local-only and gitflow.feature.finish.localonly
+// 7. Verifies the remote was not contacted, the remote branches are kept and nothing was pushed
+// 8. Verifies --local-only is rejected together with --push or --no-keepremote
+func TestFinishWithoutNetworkOperations(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	remoteDir, err := testutil.AddRemote(t, dir, "origin", true)
+	if err != nil {
+		t.Fatalf("Failed to add remote: %v", err)
+	}
+	defer os.RemoveAll(remoteDir)
+
+	startFeature := func(name string) {
+		output, err := testutil.RunGitFlow(t, dir, "feature", "start", name)
+		if err != nil {
+			t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+		}
+		testutil.WriteFile(t, dir, name+".txt", "feature content")
+		if _, err := testutil.RunGit(t, dir, "add", name+".txt"); err != nil {
+			t.Fatalf("Failed to add file: %v", err)
+		}
+		if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add "+name+".txt"); err != nil {
+			t.Fatalf("Failed to commit file: %v", err)
+		}
+	}
+	finishTraced := func(args ...string) string {
+		traceFile := filepath.Join(t.TempDir(), "trace.log")
+		output, err := testutil.RunGitFlowWithEnv(t, dir, []string{"GIT_TRACE=" + traceFile}, append([]string{"feature", "finish"}, args...)...)
+		if err != nil {
+			t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+		}
+		trace, err := os.ReadFile(traceFile)
+		if err != nil {
+			t.Fatalf("Failed to read trace: %v", err)
+		}
+		return string(trace)
+	}
+	assertNoNetwork := func(trace string) {
+		for _, command := range []string{"git fetch", "git push", "git ls-remote", "git pull"} {
+			if strings.Contains(trace, command) {
+				t.Errorf("Expected finish not to run '%s', got trace:\n%s", command, trace)
+			}
+		}
+	}
+
+	// A branch that was never pushed
+	startFeature("local")
+	assertNoNetwork(finishTraced("local"))
+
+	// A pushed branch whose remote copy is kept
+	startFeature("pushed")
+	if _, err := testutil.RunGit(t, dir, "push", "origin", "feature/pushed"); err != nil {
+		t.Fatalf("Failed to push: %v", err)
+	}
+	assertNoNetwork(finishTraced("pushed", "--keepremote"))
+	if _, err := testutil.RunGit(t, remoteDir, "rev-parse", "--verify", "feature/pushed"); err != nil {
+		t.Error("Expected the remote feature branch to be kept")
+	}
+
+	// A local-only finish neither deletes the remote branch nor pushes, even if pushing is configured
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.feature.finish.push", "true"); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "push", "origin", "develop"); err != nil {
+		t.Fatalf("Failed to push develop: %v", err)
+	}
+	remoteDevelop, _ := testutil.RunGit(t, remoteDir, "rev-parse", "develop")
+	for _, args := range [][]string{{"local-flag", "--local-only"}, {"local-config"}} {
+		branch := "feature/" + args[0]
+		if len(args) == 1 {
+			if _, err := testutil.RunGit(t, dir, "config", "gitflow.feature.finish.localonly", "true"); err != nil {
+				t.Fatalf("Failed to set config: %v", err)
+			}
+		}
+		startFeature(args[0])
+		if _, err := testutil.RunGit(t, dir, "push", "origin", branch); err != nil {
+			t.Fatalf("Failed to push: %v", err)
+		}
+		assertNoNetwork(finishTraced(args...))
+		if _, err := testutil.RunGit(t, remoteDir, "rev-parse", "--verify", branch); err != nil {
+			t.Errorf("Expected the remote branch %s to be kept", branch)
+		}
+	}
+	remoteAfter, _ := testutil.RunGit(t, remoteDir, "rev-parse", "develop")
+	if strings.TrimSpace(remoteAfter) != strings.TrimSpace(remoteDevelop) {
+		t.Error("Expected a local-only finish not to push develop")
+	}
+
+	// Options that contact the remote contradict a local-only finish
+	startFeature("conflicting")
+	for _, option := range []string{"--push", "--no-keepremote"} {
+		output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "conflicting", "--local-only", option)
+		if err == nil {
+			t.Fatalf("Expected --local-only to be rejected with %s\nOutput: %s", option, output)
+		}
+		if !strings.Contains(output, option) || !strings.Contains(output, "local-only") {
+			t.Errorf("Expected the error to name %s and --local-only, got: %s", option, output)
+		}
+	}
+	if !testutil.BranchExists(t, dir, "feature/conflicting") {
+		t.Error("Expected the rejected finish to leave the branch in place")
+	}
+}
+
+// TestFinishWithPush tests pushing the base branches and the tag after finishing with --push.
+// Steps:
+// 1. Sets up a test repository with a remote and initializes git-flow
+// 2. Finishes a release branch with --push
+// 3. Verifies main, develop and the tag were pushed and the release branch was not
+// 4. Configures gitflow.feature.finish.push and verifies --no-push overrides it
+func TestFinishWithPush(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	remoteDir, err := testutil.AddRemote(t, dir, "origin", true)
+	if err != nil {
+		t.Fatalf("Failed to add remote: %v", err)
+	}
+	defer os.RemoveAll(remoteDir)
+
+	// Finish a release with --push
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release content")
+	if _, err := testutil.RunGit(t, dir, "add", "release.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add release.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--push")
+	if err != nil {
+		t.Fatalf("Failed to finish release branch: %v\nOutput: %s", err, output)
+	}
+
+	// The remote has the finished base branches and the tag
+	for _, ref := range []string{"main", "develop", "refs/tags/1.0.0"} {
+		local, _ := testutil.RunGit(t, dir, "rev-parse", ref)
+		remote, err := testutil.RunGit(t, remoteDir, "rev-parse", ref)
+		if err != nil {
+			t.Errorf("Expected '%s' to be pushed: %v", ref, err)
+			continue
+		}
+		if strings.TrimSpace(remote) != strings.TrimSpace(local) {
+			t.Errorf("Expected remote '%s' at %s, got %s", ref, strings.TrimSpace(local), strings.TrimSpace(remote))
+		}
+	}
+	if _, err := testutil.RunGit(t, remoteDir, "rev-parse", "--verify", "release/1.0.0"); err == nil {
+		t.Error("Expected the release branch not to be pushed")
+	}
+
+	// --no-push overrides the config
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.feature.finish.push", "true"); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+	remoteDevelop, _ := testutil.RunGit(t, remoteDir, "rev-parse", "develop")
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "unpushed")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "unpushed.txt", "feature content")
+	if _, err := testutil.RunGit(t, dir, "add", "unpushed.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add unpushed.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "unpushed", "--no-push")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+	remoteAfter, _ := testutil.RunGit(t, remoteDir, "rev-parse", "develop")
+	if strings.TrimSpace(remoteAfter) != strings.TrimSpace(remoteDevelop) {
+		t.Error("Expected --no-push to leave the remote develop branch unchanged")
+	}
+}
+
+// TestFinishWithFailedPush tests that a failed push keeps the merge state so the push can be retried.
+// Steps:
+// 1. Sets up a bare remote whose pre-receive hook rejects every push
+// 2. Finishes a release with --push and verifies it fails after merging and tagging locally
+// 3. Verifies the merge state is kept at the push step
+// 4. Removes the hook, continues the finish and verifies main, develop and the tag arrive
+func TestFinishWithFailedPush(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	remoteDir, err := testutil.AddRemote(t, dir, "origin", true)
+	if err != nil {
+		t.Fatalf("Failed to add remote: %v", err)
+	}
+	defer os.RemoveAll(remoteDir)
+	hook := filepath.Join(remoteDir, "hooks", "pre-receive")
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\necho rejected >&2\nexit 1\n"), 0755); err != nil {
+		t.Fatalf("Failed to write hook: %v", err)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release content")
+	if _, err := testutil.RunGit(t, dir, "add", "release.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add release.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// The push is rejected after the release is merged and tagged
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--push")
+	if err == nil {
+		t.Fatalf("Expected the finish to fail when the push is rejected\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "finish --continue 1.0.0") {
+		t.Errorf("Expected output to explain how to retry the push, got: %s", output)
+	}
+	if _, err := testutil.RunGit(t, dir, "rev-parse", "--verify", "refs/tags/1.0.0"); err != nil {
+		t.Error("Expected the tag to be created locally")
+	}
+	if testutil.BranchExists(t, dir, "release/1.0.0") {
+		t.Error("Expected the release branch to be deleted locally")
+	}
+	state, err := testutil.LoadMergeState(t, dir)
+	if err != nil {
+		t.Fatalf("Expected the merge state to be kept: %v", err)
+	}
+	if state.CurrentStep != "push" {
+		t.Errorf("Expected step push, got %s", state.CurrentStep)
+	}
+
+	// Continuing retries the push once the remote accepts it
+	if err := os.Remove(hook); err != nil {
+		t.Fatalf("Failed to remove hook: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "--continue", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to continue the finish: %v\nOutput: %s", err, output)
+	}
+	for _, ref := range []string{"main", "develop", "refs/tags/1.0.0"} {
+		local, _ := testutil.RunGit(t, dir, "rev-parse", ref)
+		remote, err := testutil.RunGit(t, remoteDir, "rev-parse", ref)
+		if err != nil {
+			t.Errorf("Expected '%s' to be pushed: %v", ref, err)
+			continue
+		}
+		if strings.TrimSpace(remote) != strings.TrimSpace(local) {
+			t.Errorf("Expected remote '%s' at %s, got %s", ref, strings.TrimSpace(local), strings.TrimSpace(remote))
+		}
+	}
+	if _, err := testutil.LoadMergeState(t, dir); err == nil {
+		t.Error("Expected the merge state to be cleared after the push")
+	}
+}
+
+// TestFinishResumesPartialPush tests that continuing a partly failed push only pushes the missing refs.
+// Steps:
+// 1. Sets up a bare remote whose pre-receive hook logs every ref and rejects develop
+// 2. Finishes a release with --push and verifies main is pushed before the push of develop fails
+// 3. Verifies the merge state records main as pushed
+// 4. Lets the remote accept develop, continues the finish and verifies only develop and the tag are pushed
+func TestFinishResumesPartialPush(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	remoteDir, err := testutil.AddRemote(t, dir, "origin", true)
+	if err != nil {
+		t.Fatalf("Failed to add remote: %v", err)
+	}
+	defer os.RemoveAll(remoteDir)
+	logFile := filepath.Join(remoteDir, "received.log")
+	rejectFile := filepath.Join(remoteDir, "reject-develop")
+	hook := "#!/bin/sh\n" +
+		"while read old new ref; do\n" +
+		"  echo \"$ref\" >> '" + logFile + "'\n" +
+		"  if [ -f '" + rejectFile + "' ] && [ \"$ref\" = refs/heads/develop ]; then exit 1; fi\n" +
+		"done\n"
+	if err := os.WriteFile(filepath.Join(remoteDir, "hooks", "pre-receive"), []byte(hook), 0755); err != nil {
+		t.Fatalf("Failed to write hook: %v", err)
+	}
+	if err := os.WriteFile(rejectFile, nil, 0644); err != nil {
+		t.Fatalf("Failed to write reject file: %v", err)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release content")
+	if _, err := testutil.RunGit(t, dir, "add", "release.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add release.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// main is pushed, develop is rejected
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--push")
+	if err == nil {
+		t.Fatalf("Expected the finish to fail when develop is rejected\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "Pushed 'main' to 'origin'") {
+		t.Errorf("Expected main to be pushed, got: %s", output)
+	}
+	state, err := testutil.LoadMergeState(t, dir)
+	if err != nil {
+		t.Fatalf("Expected the merge state to be kept: %v", err)
+	}
+	if len(state.PushedRefs) != 1 || state.PushedRefs[0] != "main" {
+		t.Errorf("Expected only main to be recorded as pushed, got %v", state.PushedRefs)
+	}
+
+	// Continuing only pushes what is missing
+	if err := os.Remove(rejectFile); err != nil {
+		t.Fatalf("Failed to remove reject file: %v", err)
+	}
+	if err := os.Remove(logFile); err != nil {
+		t.Fatalf("Failed to remove log file: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "--continue", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to continue the finish: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Already pushed 'main' to 'origin'") {
+		t.Errorf("Expected main to be skipped, got: %s", output)
+	}
+	received, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if string(received) != "refs/heads/develop\nrefs/tags/1.0.0\n" {
+		t.Errorf("Expected only develop and the tag to be pushed, got:\n%s", received)
+	}
+	for _, ref := range []string{"main", "develop", "refs/tags/1.0.0"} {
+		local, _ := testutil.RunGit(t, dir, "rev-parse", ref)
+		remote, _ := testutil.RunGit(t, remoteDir, "rev-parse", ref)
+		if strings.TrimSpace(remote) != strings.TrimSpace(local) {
+			t.Errorf("Expected remote '%s' at %s, got %s", ref, strings.TrimSpace(local), strings.TrimSpace(remote))
+		}
+	}
+}
+
+// TestFinishAbortPartialPush tests that aborting a partly failed push reports the refs that reached the remote.
+// Steps:
+// 1. Sets up a bare remote whose pre-receive hook rejects develop
+// 2. Finishes a release with --push and verifies main is pushed before the push of develop fails
+// 3. Aborts the finish and verifies it reports main as pushed rather than nothing
+// 4. Verifies the merge state is cleared
+func TestFinishAbortPartialPush(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	remoteDir, err := testutil.AddRemote(t, dir, "origin", true)
+	if err != nil {
+		t.Fatalf("Failed to add remote: %v", err)
+	}
+	defer os.RemoveAll(remoteDir)
+	hook := "#!/bin/sh\n" +
+		"while read old new ref; do\n" +
+		"  if [ \"$ref\" = refs/heads/develop ]; then exit 1; fi\n" +
+		"done\n"
+	if err := os.WriteFile(filepath.Join(remoteDir, "hooks", "pre-receive"), []byte(hook), 0755); err != nil {
+		t.Fatalf("Failed to write hook: %v", err)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release content")
+	if _, err := testutil.RunGit(t, dir, "add", "release.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add release.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// main is pushed, develop is rejected
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--push")
+	if err == nil {
+		t.Fatalf("Expected the finish to fail when develop is rejected\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "Pushed 'main' to 'origin'") {
+		t.Errorf("Expected main to be pushed, got: %s", output)
+	}
+
+	// The abort reports what already reached the remote
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "--abort", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to abort the finish: %v\nOutput: %s", err, output)
+	}
+	if strings.Contains(output, "nothing was pushed") {
+		t.Errorf("Expected the abort not to claim nothing was pushed, got: %s", output)
+	}
+	if !strings.Contains(output, "already pushed to 'origin': main") {
+		t.Errorf("Expected the abort to report main as pushed, got: %s", output)
+	}
+	if _, err := testutil.LoadMergeState(t, dir); err == nil {
+		t.Error("Expected the merge state to be cleared after the abort")
+	}
+}
+
+// TestFinishPushAfterConflict tests that a finish started with --push still pushes when it is
+// continued without flags after a conflict.
+// Steps:
+// 1. Starts a release and commits a conflicting change to develop
+// 2. Finishes the release with --push and verifies it stops on the conflict in develop
+// 3. Resolves the conflict and continues the finish without --push
+// 4. Verifies main, develop and the tag arrive on the remote
+func TestFinishPushAfterConflict(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	remoteDir, err := testutil.AddRemote(t, dir, "origin", true)
+	if err != nil {
+		t.Fatalf("Failed to add remote: %v", err)
+	}
+	defer os.RemoveAll(remoteDir)
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "version.txt", "1.0.0")
+	if _, err := testutil.RunGit(t, dir, "add", "version.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Set version"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+		t.Fatalf("Failed to checkout develop: %v", err)
+	}
+	testutil.WriteFile(t, dir, "version.txt", "dev-version")
+	if _, err := testutil.RunGit(t, dir, "add", "version.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Set dev version"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// The update of develop stops on the conflict
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--push")
+	if err == nil {
+		t.Fatalf("Expected the finish to stop on the conflict in develop\nOutput: %s", output)
+	}
+	if _, err := testutil.RunGit(t, remoteDir, "rev-parse", "--verify", "refs/tags/1.0.0"); err == nil {
+		t.Error("Expected nothing to be pushed before the conflict is resolved")
+	}
+
+	// Continuing without --push still pushes
+	testutil.WriteFile(t, dir, "version.txt", "1.0.0")
+	if _, err := testutil.RunGit(t, dir, "add", "version.txt"); err != nil {
+		t.Fatalf("Failed to add resolved file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Merge resolved"); err != nil {
+		t.Fatalf("Failed to commit merge resolution: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "--continue", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to continue the finish: %v\nOutput: %s", err, output)
+	}
+	for _, ref := range []string{"main", "develop", "refs/tags/1.0.0"} {
+		local, _ := testutil.RunGit(t, dir, "rev-parse", ref)
+		remote, err := testutil.RunGit(t, remoteDir, "rev-parse", ref)
+		if err != nil {
+			t.Errorf("Expected '%s' to be pushed: %v", ref, err)
+			continue
+		}
+		if strings.TrimSpace(remote) != strings.TrimSpace(local) {
+			t.Errorf("Expected remote '%s' at %s, got %s", ref, strings.TrimSpace(local), strings.TrimSpace(remote))
+		}
+	}
+}
+
+// TestFinishTagPushForceWithLease tests force-pushing a replaced tag only if the remote tag is unchanged.
+// Steps:
+// 1. Finishes a release with --push, publishing tag 1.0.0
+// 2. Finishes another release replacing tag 1.0.0 with gitflow.release.finish.tagpushsafe set
+// 3. Verifies the replaced tag is force-pushed
+// 4. Moves the remote tag from another clone
+// 5. Verifies replacing the tag again with --tag-push-force-with-lease is rejected and the remote tag kept
+func TestFinishTagPushForceWithLease(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	remoteDir, err := testutil.AddRemote(t, dir, "origin", true)
+	if err != nil {
+		t.Fatalf("Failed to add remote: %v", err)
+	}
+	defer os.RemoveAll(remoteDir)
+
+	finishRelease := func(version string, args ...string) (string, error) {
+		output, err := testutil.RunGitFlow(t, dir, "release", "start", version)
+		if err != nil {
+			t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+		}
+		testutil.WriteFile(t, dir, version+".txt", "release content")
+		if _, err := testutil.RunGit(t, dir, "add", version+".txt"); err != nil {
+			t.Fatalf("Failed to add file: %v", err)
+		}
+		if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add "+version+".txt"); err != nil {
+			t.Fatalf("Failed to commit file: %v", err)
+		}
+		return testutil.RunGitFlow(t, dir, append([]string{"release", "finish", version, "--push"}, args...)...)
+	}
+	remoteTag := func() string {
+		tag, err := testutil.RunGit(t, remoteDir, "rev-parse", "refs/tags/1.0.0")
+		if err != nil {
+			t.Fatalf("Expected tag 1.0.0 on the remote: %v", err)
+		}
+		return strings.TrimSpace(tag)
+	}
+
+	output, err = finishRelease("1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to finish release branch: %v\nOutput: %s", err, output)
+	}
+	published := remoteTag()
+
+	// The replaced tag is force-pushed because the remote still has the replaced one
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.release.finish.tagpushsafe", "true"); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+	output, err = finishRelease("1.0.1", "--tagname", "1.0.0", "--force-tag", "--force-remote-tag")
+	if err != nil {
+		t.Fatalf("Failed to finish release replacing the tag: %v\nOutput: %s", err, output)
+	}
+	local, _ := testutil.RunGit(t, dir, "rev-parse", "refs/tags/1.0.0")
+	replaced := remoteTag()
+	if replaced == published || replaced != strings.TrimSpace(local) {
+		t.Errorf("Expected the replaced tag %s on the remote, got %s", strings.TrimSpace(local), replaced)
+	}
+
+	// Someone else moves the tag on the remote
+	otherDir := t.TempDir()
+	if _, err := testutil.RunGit(t, dir, "clone", remoteDir, otherDir); err != nil {
+		t.Fatalf("Failed to clone remote: %v", err)
+	}
+	if _, err := testutil.RunGit(t, otherDir, "tag", "-f", "1.0.0", "origin/main~1"); err != nil {
+		t.Fatalf("Failed to move tag: %v", err)
+	}
+	if _, err := testutil.RunGit(t, otherDir, "push", "-f", "origin", "refs/tags/1.0.0"); err != nil {
+		t.Fatalf("Failed to push moved tag: %v", err)
+	}
+	moved := remoteTag()
+
+	// Replacing the tag again doesn't clobber the moved tag
+	if _, err := testutil.RunGit(t, dir, "config", "--unset", "gitflow.release.finish.tagpushsafe"); err != nil {
+		t.Fatalf("Failed to unset config: %v", err)
+	}
+	output, err = finishRelease("1.0.2", "--tagname", "1.0.0", "--force-tag", "--force-remote-tag", "--tag-push-force-with-lease")
+	if err == nil {
+		t.Fatalf("Expected the push of the replaced tag to be rejected\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "was moved") {
+		t.Errorf("Expected output to report the moved tag, got: %s", output)
+	}
+	if remoteTag() != moved {
+		t.Error("Expected the moved remote tag to be kept")
+	}
+}
+
+// TestFinishWithMergeMessageFilePerTarget tests merge message templates configured per target.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Configures a template for merges of hotfix branches into main and a generic one for the rest
+// 3. Finishes a hotfix branch, which merges into main and back-merges main into develop
+// 4. Verifies the merge into main uses the main template and the back-merge the generic one
+// 5. Adds a template for develop and verifies the next back-merge uses it
+func TestFinishWithMergeMessageFilePerTarget(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Configure the templates outside the working tree
+	templateDir := t.TempDir()
+	mainTemplate := filepath.Join(templateDir, "main.txt")
+	genericTemplate := filepath.Join(templateDir, "generic.txt")
+	developTemplate := filepath.Join(templateDir, "develop.txt")
+	if err := os.WriteFile(mainTemplate, []byte("release: %branch% into %target%\n"), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	if err := os.WriteFile(genericTemplate, []byte("chore: sync %branch% into %target%\n"), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	if err := os.WriteFile(developTemplate, []byte("chore(develop): back-merge %branch%\n"), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.hotfix.finish.mergemessagefile.main", mainTemplate); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.hotfix.finish.mergemessagefile", genericTemplate); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+
+	finishHotfix := func(version string) {
+		output, err := testutil.RunGitFlow(t, dir, "hotfix", "start", version)
+		if err != nil {
+			t.Fatalf("Failed to create hotfix branch: %v\nOutput: %s", err, output)
+		}
+		testutil.WriteFile(t, dir, "hotfix-"+version+".txt", "hotfix content")
+		if _, err := testutil.RunGit(t, dir, "add", "hotfix-"+version+".txt"); err != nil {
+			t.Fatalf("Failed to add file: %v", err)
+		}
+		if _, err := testutil.RunGit(t, dir, "commit", "-m", "Fix "+version); err != nil {
+			t.Fatalf("Failed to commit file: %v", err)
+		}
+		output, err = testutil.RunGitFlow(t, dir, "hotfix", "finish", version, "--notag")
+		if err != nil {
+			t.Fatalf("Failed to finish hotfix branch: %v\nOutput: %s", err, output)
+		}
+	}
+	subject := func(branch string) string {
+		output, err := testutil.RunGit(t, dir, "log", "-1", "--format=%s", branch)
+		if err != nil {
+			t.Fatalf("Failed to read log: %v", err)
+		}
+		return strings.TrimSpace(output)
+	}
+
+	// The merge into main uses its own template, the back-merge the generic one
+	finishHotfix("1.0.1")
+	if got := subject("main"); got != "release: hotfix/1.0.1 into main" {
+		t.Errorf("Expected main merge subject 'release: hotfix/1.0.1 into main', got '%s'", got)
+	}
+	if got := subject("develop"); got != "chore: sync main into develop" {
+		t.Errorf("Expected develop merge subject 'chore: sync main into develop', got '%s'", got)
+	}
+
+	// A template for develop takes precedence over the generic one
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.hotfix.finish.mergemessagefile.develop", developTemplate); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+	finishHotfix("1.0.2")
+	if got := subject("main"); got != "release: hotfix/1.0.2 into main" {
+		t.Errorf("Expected main merge subject 'release: hotfix/1.0.2 into main', got '%s'", got)
+	}
+	if got := subject("develop"); got != "chore(develop): back-merge main" {
+		t.Errorf("Expected develop merge subject 'chore(develop): back-merge main', got '%s'", got)
+	}
+}
+
+// TestFinishWithRecordMetrics tests appending a CSV row with the metrics of each finish.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Finishes a feature with two commits changing two files with --record-metrics
+// 3. Verifies a well-formed row with the counts and no conflict is appended
+// 4. Finishes a conflicting feature with --record-metrics, resolves it and continues
+// 5. Verifies a second row is appended that records the conflict
+func TestFinishWithRecordMetrics(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	metricsFile := filepath.Join(t.TempDir(), "metrics.csv")
+
+	commitFile := func(name string, content string) {
+		testutil.WriteFile(t, dir, name, content)
+		if _, err := testutil.RunGit(t, dir, "add", name); err != nil {
+			t.Fatalf("Failed to add file: %v", err)
+		}
+		if _, err := testutil.RunGit(t, dir, "commit", "-m", "Update "+name); err != nil {
+			t.Fatalf("Failed to commit file: %v", err)
+		}
+	}
+	readRows := func() [][]string {
+		file, err := os.Open(metricsFile)
+		if err != nil {
+			t.Fatalf("Failed to open metrics file: %v", err)
+		}
+		defer file.Close()
+		rows, err := csv.NewReader(file).ReadAll()
+		if err != nil {
+			t.Fatalf("Failed to parse metrics file: %v", err)
+		}
+		return rows
+	}
+	checkRow := func(row []string, branch string, commits string, files string, conflict string) {
+		if len(row) != 7 {
+			t.Fatalf("Expected 7 columns, got %d: %v", len(row), row)
+		}
+		if _, err := time.Parse(time.RFC3339, row[0]); err != nil {
+			t.Errorf("Expected an RFC 3339 timestamp, got '%s'", row[0])
+		}
+		expected := []string{"feature", branch, commits, files, conflict}
+		if !slices.Equal(row[1:6], expected) {
+			t.Errorf("Expected %v, got %v", expected, row[1:6])
+		}
+		if duration, err := strconv.ParseFloat(row[6], 64); err != nil || duration < 0 {
+			t.Errorf("Expected a duration in seconds, got '%s'", row[6])
+		}
+	}
+
+	// A clean finish
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "clean")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	commitFile("a.txt", "a")
+	commitFile("b.txt", "b")
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "clean", "--record-metrics", metricsFile)
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+	rows := readRows()
+	if len(rows) != 1 {
+		t.Fatalf("Expected 1 row, got %d: %v", len(rows), rows)
+	}
+	checkRow(rows[0], "feature/clean", "2", "2", "false")
+
+	// A finish that stops on a conflict and is continued
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "conflict")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	commitFile("a.txt", "feature change")
+	if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+		t.Fatalf("Failed to checkout develop: %v", err)
+	}
+	commitFile("a.txt", "develop change")
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "conflict", "--record-metrics", metricsFile)
+	if err == nil {
+		t.Fatalf("Expected the finish to stop on a conflict\nOutput: %s", output)
+	}
+	testutil.WriteFile(t, dir, "a.txt", "resolved")
+	if _, err := testutil.RunGit(t, dir, "add", "a.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Merge resolved"); err != nil {
+		t.Fatalf("Failed to commit merge resolution: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "--continue", "conflict")
+	if err != nil {
+		t.Fatalf("Failed to continue the finish: %v\nOutput: %s", err, output)
+	}
+	rows = readRows()
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 rows, got %d: %v", len(rows), rows)
+	}
+	checkRow(rows[1], "feature/conflict", "1", "1", "true")
+}
+
+// squashFeature sets up a repository with the squash strategy for features and a feature branch
+// with two commits changing file.txt
+func squashFeature(t *testing.T, name string) string {
+	dir := testutil.SetupTestRepo(t)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch.feature.upstreamstrategy", "squash"); err != nil {
+		t.Fatalf("Failed to set squash strategy: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", name)
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	for i, content := range []string{"feature content", "more feature content"} {
+		testutil.WriteFile(t, dir, "file.txt", content)
+		if _, err := testutil.RunGit(t, dir, "add", "file.txt"); err != nil {
+			t.Fatalf("Failed to add file: %v", err)
+		}
+		if _, err := testutil.RunGit(t, dir, "commit", "-m", fmt.Sprintf("Feature commit %d", i+1)); err != nil {
+			t.Fatalf("Failed to commit file: %v", err)
+		}
+	}
+	return dir
+}
+
+// TestFinishWithSquashStrategy tests that a squash finish commits the changes as one commit and deletes the branch.
+// Steps:
+// 1. Sets up a feature branch with two commits and the squash strategy for features
+// 2. Finishes the feature branch
+// 3. Verifies develop got a single commit with the default squash message and the changes
+// 4. Verifies the feature branch was deleted and no changes are left staged
+func TestFinishWithSquashStrategy(t *testing.T) {
+	// Setup
+	dir := squashFeature(t, "squashed")
+	defer testutil.CleanupTestRepo(t, dir)
+
+	developBefore, _ := testutil.RunGit(t, dir, "rev-parse", "develop")
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "squashed")
+	if err != nil {
+		t.Fatalf("Failed to finish feature: %v\nOutput: %s", err, output)
+	}
+
+	// Verify develop has one squash commit with the changes
+	count, err := testutil.RunGit(t, dir, "rev-list", "--count", strings.TrimSpace(developBefore)+"..develop")
+	if err != nil {
+		t.Fatalf("Failed to count commits: %v", err)
+	}
+	if strings.TrimSpace(count) != "1" {
+		t.Errorf("Expected a single squash commit on develop, got %s", strings.TrimSpace(count))
+	}
+	subject, _ := testutil.RunGit(t, dir, "log", "-1", "--format=%s", "develop")
+	if strings.TrimSpace(subject) != "Squashed feature 'squashed' into develop" {
+		t.Errorf("Expected the default squash message, got '%s'", strings.TrimSpace(subject))
+	}
+	content, _ := testutil.RunGit(t, dir, "show", "develop:file.txt")
+	if strings.TrimSpace(content) != "more feature content" {
+		t.Errorf("Expected the feature changes on develop, got '%s'", strings.TrimSpace(content))
+	}
+
+	// Verify the branch is gone and nothing is left staged
+	if testutil.BranchExists(t, dir, "feature/squashed") {
+		t.Error("Expected feature branch to be deleted")
+	}
+	status, _ := testutil.RunGit(t, dir, "status", "--porcelain")
+	if strings.TrimSpace(status) != "" {
+		t.Errorf("Expected a clean working tree, got: %s", status)
+	}
+}
+
+// TestFinishWithSquashConflictContinue tests that a squash finish that stops on a conflict commits the resolution on continue.
+// Steps:
+// 1. Sets up a feature branch with the squash strategy and a conflicting change on develop
+// 2. Finishes the feature branch with --squash-message and verifies it stops on the conflict
+// 3. Verifies the merge state records the squash strategy
+// 4. Resolves the conflict, stages it and runs finish --continue
+// 5. Verifies the resolution is committed with the squash message and the branch is deleted
+func TestFinishWithSquashConflictContinue(t *testing.T) {
+	// Setup
+	dir := squashFeature(t, "conflict")
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Change the same file on develop
+	if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+		t.Fatalf("Failed to checkout develop: %v", err)
+	}
+	testutil.WriteFile(t, dir, "file.txt", "develop content")
+	if _, err := testutil.RunGit(t, dir, "add", "file.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Develop commit"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// The squash stops on the conflict
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "conflict", "--squash-message", "Add the conflicting feature")
+	if err == nil {
+		t.Fatalf("Expected finish to stop on the conflict\nOutput: %s", output)
+	}
+	state, err := testutil.LoadMergeState(t, dir)
+	if err != nil {
+		t.Fatalf("Expected a merge state to be saved: %v", err)
+	}
+	if state.MergeStrategy != "squash" {
+		t.Errorf("Expected merge strategy 'squash' in the merge state, got '%s'", state.MergeStrategy)
+	}
+
+	// Resolve the conflict and continue
+	testutil.WriteFile(t, dir, "file.txt", "resolved content")
+	if _, err := testutil.RunGit(t, dir, "add", "file.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "--continue", "conflict")
+	if err != nil {
+		t.Fatalf("Failed to continue finish: %v\nOutput: %s", err, output)
+	}
+
+	// Verify the resolution was committed with the squash message
+	subject, _ := testutil.RunGit(t, dir, "log", "-1", "--format=%s", "develop")
+	if strings.TrimSpace(subject) != "Add the conflicting feature" {
+		t.Errorf("Expected the squash message, got '%s'", strings.TrimSpace(subject))
+	}
+	content, _ := testutil.RunGit(t, dir, "show", "develop:file.txt")
+	if strings.TrimSpace(content) != "resolved content" {
+		t.Errorf("Expected the resolution on develop, got '%s'", strings.TrimSpace(content))
+	}
+	status, _ := testutil.RunGit(t, dir, "status", "--porcelain")
+	if strings.TrimSpace(status) != "" {
+		t.Errorf("Expected a clean working tree, got: %s", status)
+	}
+	if testutil.BranchExists(t, dir, "feature/conflict") {
+		t.Error("Expected feature branch to be deleted")
+	}
+}
+
+// TestFinishSquashMessagePrecedence tests that the squash message comes from the flag, the file, the config and the default, in that order.
+// Steps:
+// 1. Sets up a feature branch with the squash strategy for each case
+// 2. Sets gitflow.feature.finish.squashmessage and writes a message file as the case requires
+// 3. Finishes the feature branch with --squash-message and --squash-message-file as the case requires
+// 4. Verifies the squash commit has the message of the option with the highest precedence
+func TestFinishSquashMessagePrecedence(t *testing.T) {
+	tests := []struct {
+		name     string
+		flag     bool
+		file     bool
+		config   bool
+		expected string
+	}{
+		{"flag", true, true, true, "Message from the flag"},
+		{"file", false, true, true, "Message from the file for feature/file"},
+		{"config", false, false, true, "Message from the config into develop"},
+		{"default", false, false, false, "Squashed feature 'default' into develop"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			dir := squashFeature(t, tt.name)
+			defer testutil.CleanupTestRepo(t, dir)
+
+			args := []string{"feature", "finish", tt.name}
+			if tt.config {
+				if _, err := testutil.RunGit(t, dir, "config", "gitflow.feature.finish.squashmessage", "Message from the config into %target%"); err != nil {
+					t.Fatalf("Failed to set squash message: %v", err)
+				}
+			}
+			if tt.file {
+				messageFile := filepath.Join(t.TempDir(), "message.txt")
+				if err := os.WriteFile(messageFile, []byte("Message from the file for %branch%\n"), 0644); err != nil {
+					t.Fatalf("Failed to write message file: %v", err)
+				}
+				args = append(args, "--squash-message-file", messageFile)
+			}
+			if tt.flag {
+				args = append(args, "--squash-message", "Message from the flag")
+			}
+
+			output, err := testutil.RunGitFlow(t, dir, args...)
+			if err != nil {
+				t.Fatalf("Failed to finish feature: %v\nOutput: %s", err, output)
+			}
+			subject, _ := testutil.RunGit(t, dir, "log", "-1", "--format=%s", "develop")
+			if strings.TrimSpace(subject) != tt.expected {
+				t.Errorf("Expected squash message '%s', got '%s'", tt.expected, strings.TrimSpace(subject))
+			}
+		})
+	}
+}
+
+// TestFinishHotfixWithTagTarget tests that the tag of a hotfix goes on the configured tag target instead of main.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Starts a hotfix branch and commits a fix
+// 3. Verifies finishing with a tag target that doesn't receive the hotfix is refused
+// 4. Sets gitflow.hotfix.finish.tagtarget to develop and finishes the hotfix
+// 5. Verifies the hotfix is merged into main and the tag points at the tip of develop
+func TestFinishHotfixWithTagTarget(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "hotfix", "start", "1.0.1")
+	if err != nil {
+		t.Fatalf("Failed to start hotfix branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "fix.txt", "fix")
+	if _, err := testutil.RunGit(t, dir, "add", "fix.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Fix"); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+
+	// A branch that doesn't receive the hotfix can't be tagged
+	if _, err := testutil.RunGit(t, dir, "branch", "unrelated", "main"); err != nil {
+		t.Fatalf("Failed to create branch: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "hotfix", "finish", "1.0.1", "--tag-on", "unrelated")
+	if err == nil {
+		t.Fatalf("Expected finish to refuse the tag target\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "does not receive 'hotfix/1.0.1' during the finish") {
+		t.Errorf("Expected tag target error, got: %s", output)
+	}
+	if !testutil.BranchExists(t, dir, "hotfix/1.0.1") {
+		t.Fatal("Expected the hotfix branch to be left alone")
+	}
+
+	// Tag develop, which gets the hotfix as a child base branch of main
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.hotfix.finish.tagtarget", "develop"); err != nil {
+		t.Fatalf("Failed to set tag target: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "hotfix", "finish", "1.0.1")
+	if err != nil {
+		t.Fatalf("Failed to finish hotfix: %v\nOutput: %s", err, output)
+	}
+
+	// Verify the merge went into main and the tag onto develop
+	if _, err := testutil.RunGit(t, dir, "merge-base", "--is-ancestor", "1.0.1", "main"); err == nil {
+		t.Error("Expected the tag not to be on main")
+	}
+	if _, err := testutil.RunGit(t, dir, "show", "main:fix.txt"); err != nil {
+		t.Errorf("Expected the hotfix to be merged into main: %v", err)
+	}
+	tagCommit, err := testutil.RunGit(t, dir, "rev-parse", "1.0.1^{commit}")
+	if err != nil {
+		t.Fatalf("Expected tag 1.0.1 to exist: %v", err)
+	}
+	developCommit, err := testutil.RunGit(t, dir, "rev-parse", "develop")
+	if err != nil {
+		t.Fatalf("Failed to resolve develop: %v", err)
+	}
+	if tagCommit != developCommit {
+		t.Errorf("Expected tag 1.0.1 at the tip of develop %s, got %s", strings.TrimSpace(developCommit), strings.TrimSpace(tagCommit))
+	}
+}
+
+// TestFinishWithTagOnAlsoIntoBranch tests that --tag-on tags an --also-into branch instead of the target.
+// Steps:
+// 1. Sets up a release branch and a support branch that also receives it
+// 2. Finishes the release with --also-into support and --tag-on support
+// 3. Verifies the tag points at the tip of the support branch rather than main
+func TestFinishWithTagOnAlsoIntoBranch(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "branch", "support", "main"); err != nil {
+		t.Fatalf("Failed to create support branch: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "2.0.0")
+	if err != nil {
+		t.Fatalf("Failed to start release branch: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "--allow-empty", "-m", "Release work"); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "2.0.0", "--also-into", "support", "--tag-on", "support")
+	if err != nil {
+		t.Fatalf("Failed to finish release: %v\nOutput: %s", err, output)
+	}
+
+	tagCommit, err := testutil.RunGit(t, dir, "rev-parse", "2.0.0^{commit}")
+	if err != nil {
+		t.Fatalf("Expected tag 2.0.0 to exist: %v", err)
+	}
+	supportCommit, _ := testutil.RunGit(t, dir, "rev-parse", "support")
+	mainCommit, _ := testutil.RunGit(t, dir, "rev-parse", "main")
+	if tagCommit != supportCommit {
+		t.Errorf("Expected tag 2.0.0 at the tip of support %s, got %s", strings.TrimSpace(supportCommit), strings.TrimSpace(tagCommit))
+	}
+	if tagCommit == mainCommit {
+		t.Error("Expected the tag not to be at the tip of main")
+	}
+}
+
+// TestSupportBranchLifecycle tests starting a support branch from a tag and finishing it without a merge.
+// Steps:
+// 1. Sets up a test repository, initializes git-flow and tags main as v1.2.0
+// 2. Moves main on and starts support/1.x from the tag
+// 3. Verifies the branch starts at the tag and commits a fix to it
+// 4. Verifies finishing with an option that needs a merge back is refused
+// 5. Finishes the support branch with a tag
+// 6. Verifies the branch is kept, nothing was merged into main and the tag is on the support branch
+func TestSupportBranchLifecycle(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "tag", "v1.2.0", "main"); err != nil {
+		t.Fatalf("Failed to create tag: %v", err)
+	}
+	tagCommit, _ := testutil.RunGit(t, dir, "rev-parse", "v1.2.0^{commit}")
+	if _, err := testutil.RunGit(t, dir, "checkout", "main"); err != nil {
+		t.Fatalf("Failed to checkout main: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "--allow-empty", "-m", "Work for 2.0"); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	mainBefore, _ := testutil.RunGit(t, dir, "rev-parse", "main")
+
+	// Start the support branch from the tag
+	output, err = testutil.RunGitFlow(t, dir, "support", "start", "1.x", "v1.2.0")
+	if err != nil {
+		t.Fatalf("Failed to start support branch: %v\nOutput: %s", err, output)
+	}
+	branchCommit, err := testutil.RunGit(t, dir, "rev-parse", "support/1.x")
+	if err != nil {
+		t.Fatalf("Expected support/1.x to exist: %v", err)
+	}
+	if branchCommit != tagCommit {
+		t.Errorf("Expected support/1.x to start at v1.2.0 %s, got %s", strings.TrimSpace(tagCommit), strings.TrimSpace(branchCommit))
+	}
+	testutil.WriteFile(t, dir, "fix.txt", "fix for 1.x")
+	if _, err := testutil.RunGit(t, dir, "add", "fix.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Fix for 1.x"); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	supportTip, _ := testutil.RunGit(t, dir, "rev-parse", "support/1.x")
+
+	// Asking for a merge back is an error
+	output, err = testutil.RunGitFlow(t, dir, "support", "finish", "1.x", "--no-keep")
+	if err == nil {
+		t.Fatalf("Expected finish with --no-keep to fail\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "not merged back or deleted") {
+		t.Errorf("Expected an error about support branches not being merged back, got: %s", output)
+	}
+
+	// Finish with a tag
+	output, err = testutil.RunGitFlow(t, dir, "support", "finish", "1.x", "--tag", "--tagname", "v1.2.1")
+	if err != nil {
+		t.Fatalf("Failed to finish support branch: %v\nOutput: %s", err, output)
+	}
+
+	// Verify the branch is kept and main is untouched
+	if !testutil.BranchExists(t, dir, "support/1.x") {
+		t.Error("Expected support/1.x to be kept")
+	}
+	if mainAfter, _ := testutil.RunGit(t, dir, "rev-parse", "main"); mainAfter != mainBefore {
+		t.Error("Expected main not to change")
+	}
+	if testutil.FileExists(t, dir, ".git/gitflow/state/merge.json") {
+		t.Error("Expected no merge state to be left behind")
+	}
+
+	// Verify the tag is on the support branch
+	newTagCommit, err := testutil.RunGit(t, dir, "rev-parse", "v1.2.1^{commit}")
+	if err != nil {
+		t.Fatalf("Expected tag v1.2.1 to exist: %v", err)
+	}
+	if newTagCommit != supportTip {
+		t.Errorf("Expected tag v1.2.1 at the tip of support/1.x %s, got %s", strings.TrimSpace(supportTip), strings.TrimSpace(newTagCommit))
+	}
+}
+
+// TestSupportBranchFinishRejectsTagOn tests that another branch to tag is rejected for a support branch,
+// whose tag always goes on the branch itself.
+// Steps:
+// 1. Sets up a test repository, initializes git-flow with defaults and starts support/1.x
+// 2. Finishes it with --tag-on main and verifies the finish fails without creating the tag
+// 3. Verifies --dry-run with --tag-on main fails as well
+// 4. Sets gitflow.support.finish.tagtarget and verifies the finish fails naming the config key
+func TestSupportBranchFinishRejectsTagOn(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "support", "start", "1.x", "main")
+	if err != nil {
+		t.Fatalf("Failed to start support branch: %v\nOutput: %s", err, output)
+	}
+
+	// --tag-on is rejected, in a finish and in its plan
+	for _, extra := range [][]string{nil, {"--dry-run"}} {
+		args := append([]string{"support", "finish", "1.x", "--tag", "--tagname", "s1", "--tag-on", "main"}, extra...)
+		output, err = testutil.RunGitFlow(t, dir, args...)
+		if err == nil {
+			t.Fatalf("Expected %v to fail\nOutput: %s", args, output)
+		}
+		if !strings.Contains(output, "--tag-on") || !strings.Contains(output, "not merged back") {
+			t.Errorf("Expected an error about --tag-on for a branch that is not merged back, got: %s", output)
+		}
+	}
+	if _, err := testutil.RunGit(t, dir, "rev-parse", "--verify", "refs/tags/s1"); err == nil {
+		t.Error("Expected tag s1 not to be created")
+	}
+
+	// So is the configured tag target
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.support.finish.tagtarget", "main"); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "support", "finish", "1.x", "--tag", "--tagname", "s1")
+	if err == nil {
+		t.Fatalf("Expected finish with gitflow.support.finish.tagtarget to fail\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "gitflow.support.finish.tagtarget") {
+		t.Errorf("Expected the error to name the config key, got: %s", output)
+	}
+	if _, err := testutil.RunGit(t, dir, "rev-parse", "--verify", "refs/tags/s1"); err == nil {
+		t.Error("Expected tag s1 not to be created")
+	}
+}
+
+// TestSupportBranchFinishDryRun tests that --dry-run lists only the tag and push steps for a support branch.
+// Steps:
+// 1. Sets up a test repository, initializes git-flow with defaults and starts support/1.x
+// 2. Lists the finish steps with a tag and a push
+// 3. Verifies only the tag and push steps are listed and the merge and deletion are skipped
+// 4. Verifies nothing in the repository changed
+func TestSupportBranchFinishDryRun(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "support", "start", "1.x")
+	if err != nil {
+		t.Fatalf("Failed to start support branch: %v\nOutput: %s", err, output)
+	}
+
+	// List the steps
+	refsBefore, _ := testutil.RunGit(t, dir, "for-each-ref")
+	output, err = testutil.RunGitFlow(t, dir, "support", "finish", "1.x", "--dry-run", "--tag", "--tagname", "v1.0.1", "--push")
+	if err != nil {
+		t.Fatalf("Failed to list finish steps: %v\nOutput: %s", err, output)
+	}
+
+	// Verify only the tag and push steps run
+	for _, expected := range []string{
+		"1. create_tag: Create tag 'v1.0.1'",
+		"2. push: Push 'support/1.x' to 'origin' with tag 'v1.0.1'",
+		"- merge (skipped: upstream strategy is 'none')",
+		"- delete_branch (skipped: the branch is kept)",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "3.") {
+		t.Errorf("Expected only two steps, got:\n%s", output)
+	}
+
+	// Verify nothing changed
+	refsAfter, _ := testutil.RunGit(t, dir, "for-each-ref")
+	if refsBefore != refsAfter {
+		t.Errorf("Expected no refs to change, before:\n%s\nafter:\n%s", refsBefore, refsAfter)
+	}
+}
+
+// TestSupportBranchFinishDryRunJSON tests that the JSON plan of a support branch finish has no merge or deletion.
+// Steps:
+// 1. Sets up a test repository, initializes git-flow with defaults and starts support/1.x
+// 2. Requests the JSON plan with a tag
+// 3. Verifies the branch is its own target with strategy none, nothing is deleted and only the tag step runs
+func TestSupportBranchFinishDryRunJSON(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "support", "start", "1.x")
+	if err != nil {
+		t.Fatalf("Failed to start support branch: %v\nOutput: %s", err, output)
+	}
+
+	// Request the plan
+	output, err = testutil.RunGitFlow(t, dir, "support", "finish", "1.x", "--dry-run", "--json", "--tag", "--tagname", "v1.0.1")
+	if err != nil {
+		t.Fatalf("Failed to get finish plan: %v\nOutput: %s", err, output)
+	}
+
+	// Verify the plan
+	var plan struct {
+		Target   string `json:"target"`
+		Strategy string `json:"strategy"`
+		Tag      struct {
+			Create bool   `json:"create"`
+			Name   string `json:"name"`
+		} `json:"tag"`
+		Children []json.RawMessage `json:"children"`
+		Delete   struct {
+			Local  bool `json:"local"`
+			Remote bool `json:"remote"`
+		} `json:"delete"`
+		Steps []string `json:"steps"`
+	}
+	if err := json.Unmarshal([]byte(output), &plan); err != nil {
+		t.Fatalf("Failed to decode plan: %v\nOutput: %s", err, output)
+	}
+	if plan.Target != "support/1.x" || plan.Strategy != "none" {
+		t.Errorf("Expected support/1.x to be its own target with strategy none, got target '%s' and strategy '%s'", plan.Target, plan.Strategy)
+	}
+	if !plan.Tag.Create || plan.Tag.Name != "v1.0.1" {
+		t.Errorf("Expected tag 'v1.0.1' to be created, got %+v", plan.Tag)
+	}
+	if len(plan.Children) != 0 || plan.Delete.Local || plan.Delete.Remote {
+		t.Errorf("Expected no child updates and no deletion, got %d children and %+v", len(plan.Children), plan.Delete)
+	}
+	if strings.Join(plan.Steps, ",") != "create_tag" {
+		t.Errorf("Expected only the create_tag step, got %v", plan.Steps)
+	}
+}
+
+// TestSupportBranchFinishValidateOnly tests --validate-only for a support branch.
+// Steps:
+// 1. Sets up a test repository, initializes git-flow with defaults and starts support/1.x
+// 2. Validates the finish and verifies all checks pass, including the merge strategy none
+// 3. Validates the finish with --no-keep and verifies it fails because the branch is not merged back
+func TestSupportBranchFinishValidateOnly(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "support", "start", "1.x")
+	if err != nil {
+		t.Fatalf("Failed to start support branch: %v\nOutput: %s", err, output)
+	}
+
+	// Validate the finish
+	output, err = testutil.RunGitFlow(t, dir, "support", "finish", "1.x", "--validate-only")
+	if err != nil {
+		t.Fatalf("Expected validation to pass: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "merge strategy is valid") || !strings.Contains(output, "All checks passed") {
+		t.Errorf("Expected all checks to pass, got:\n%s", output)
+	}
+	if strings.Contains(output, "target branch") {
+		t.Errorf("Expected no target checks for a branch that is not merged back, got:\n%s", output)
+	}
+
+	// An option that needs a merge back fails validation
+	output, err = testutil.RunGitFlow(t, dir, "support", "finish", "1.x", "--validate-only", "--no-keep")
+	if err == nil {
+		t.Fatalf("Expected validation with --no-keep to fail\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "FAIL") || !strings.Contains(output, "not merged back or deleted") {
+		t.Errorf("Expected a failed check about the branch not being merged back, got:\n%s", output)
+	}
+}
+
+// TestBugfixStartAndFinish tests the lifecycle of a bugfix branch with the default configuration.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Starts a bugfix branch and verifies it starts from develop
+// 3. Commits a fix and finishes the branch with the shorthand finish command
+// 4. Verifies the fix is merged into develop, main is untouched, no tag is created and the branch is deleted
+func TestBugfixStartAndFinish(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	mainBefore, _ := testutil.RunGit(t, dir, "rev-parse", "main")
+
+	// Start the bugfix branch from develop
+	output, err = testutil.RunGitFlow(t, dir, "bugfix", "start", "crash")
+	if err != nil {
+		t.Fatalf("Failed to start bugfix branch: %v\nOutput: %s", err, output)
+	}
+	if current := testutil.GetCurrentBranch(t, dir); current != "bugfix/crash" {
+		t.Errorf("Expected to be on bugfix/crash, got %s", current)
+	}
+	base, err := testutil.RunGit(t, dir, "config", "--get", "gitflow.branch.bugfix/crash.base")
+	if err != nil || strings.TrimSpace(base) != "develop" {
+		t.Errorf("Expected bugfix/crash to be based on develop, got '%s'", strings.TrimSpace(base))
+	}
+
+	testutil.WriteFile(t, dir, "fix.txt", "fixed")
+	if _, err := testutil.RunGit(t, dir, "add", "fix.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Fix crash"); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+
+	// The shorthand finish detects the bugfix branch
+	output, err = testutil.RunGitFlow(t, dir, "finish")
+	if err != nil {
+		t.Fatalf("Failed to finish bugfix branch: %v\nOutput: %s", err, output)
+	}
+
+	// Verify the fix went into develop only, without a tag
+	if _, err := testutil.RunGit(t, dir, "show", "develop:fix.txt"); err != nil {
+		t.Errorf("Expected the fix to be merged into develop: %v", err)
+	}
+	if mainAfter, _ := testutil.RunGit(t, dir, "rev-parse", "main"); mainAfter != mainBefore {
+		t.Error("Expected main not to change")
+	}
+	if tags, _ := testutil.RunGit(t, dir, "tag", "--list"); strings.TrimSpace(tags) != "" {
+		t.Errorf("Expected no tag for a bugfix branch, got: %s", tags)
+	}
+	if testutil.BranchExists(t, dir, "bugfix/crash") {
+		t.Error("Expected bugfix/crash to be deleted")
+	}
+}
+
+// TestFinishCommitCountGuard tests that a finish is refused when the branch has fewer or more commits
+// than the configured limits.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Sets gitflow.feature.finish.mincommits to 1 and maxcommits to 2
+// 3. Verifies that a feature without commits is refused and kept
+// 4. Verifies that features with exactly 1 and 2 commits are finished
+// 5. Verifies that a feature with 3 commits is refused
+// 6. Verifies that --max-commits and --no-commit-count-guard override the limits
+func TestFinishCommitCountGuard(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	for key, value := range map[string]string{"mincommits": "1", "maxcommits": "2"} {
+		if _, err := testutil.RunGit(t, dir, "config", "gitflow.feature.finish."+key, value); err != nil {
+			t.Fatalf("Failed to set %s: %v", key, err)
+		}
+	}
+
+	startFeature := func(name string, commits int) {
+		output, err := testutil.RunGitFlow(t, dir, "feature", "start", name)
+		if err != nil {
+			t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+		}
+		for i := 1; i <= commits; i++ {
+			file := fmt.Sprintf("%s-%d.txt", name, i)
+			testutil.WriteFile(t, dir, file, "feature content")
+			if _, err := testutil.RunGit(t, dir, "add", file); err != nil {
+				t.Fatalf("Failed to add file: %v", err)
+			}
+			if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add "+file); err != nil {
+				t.Fatalf("Failed to commit file: %v", err)
+			}
+		}
+	}
+	expectRefused := func(name string, expected string, args ...string) {
+		output, err := testutil.RunGitFlow(t, dir, append([]string{"feature", "finish", name}, args...)...)
+		if err == nil {
+			t.Fatalf("Expected finish of '%s' to be refused, got: %s", name, output)
+		}
+		if exitErr, ok := err.(*testutil.ExitError); !ok || exitErr.ExitCode != 2 {
+			t.Errorf("Expected exit code 2, got: %v", err)
+		}
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got: %s", expected, output)
+		}
+		if !testutil.BranchExists(t, dir, "feature/"+name) {
+			t.Errorf("Expected feature/%s to be kept", name)
+		}
+	}
+	expectFinished := func(name string, args ...string) {
+		output, err := testutil.RunGitFlow(t, dir, append([]string{"feature", "finish", name}, args...)...)
+		if err != nil {
+			t.Fatalf("Expected finish of '%s' to succeed: %v\nOutput: %s", name, err, output)
+		}
+		if testutil.BranchExists(t, dir, "feature/"+name) {
+			t.Errorf("Expected feature/%s to be deleted", name)
+		}
+	}
+
+	// No commits is below the minimum
+	startFeature("empty", 0)
+	expectRefused("empty", "has 0 commits not in 'develop', fewer than the minimum of 1 set in gitflow.feature.finish.mincommits")
+
+	// The limits themselves are allowed
+	startFeature("one", 1)
+	expectFinished("one")
+	startFeature("two", 2)
+	expectFinished("two")
+
+	// One commit above the maximum is refused
+	startFeature("three", 3)
+	expectRefused("three", "has 3 commits not in 'develop', more than the maximum of 2 set in gitflow.feature.finish.maxcommits")
+
+	// The flags override the configured limits
+	expectRefused("three", "more than the maximum of 2;", "--max-commits", "2")
+	expectFinished("three", "--max-commits", "3")
+	if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+		t.Fatalf("Failed to checkout develop: %v", err)
+	}
+	expectFinished("empty", "--no-commit-count-guard")
+}
+
+// TestFinishRecordsBaseSnapshot tests that the commits of the base branches before and after a finish are
+// logged to .git/gitflow/snapshots.log.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Finishes a release with --record-base-snapshot
+// 3. Verifies the record maps main and develop to their commits before and after the finish
+// 4. Finishes a feature with gitflow.finish.recordsnapshot set and verifies a second record for develop
+// 5. Finishes a feature with --no-record-base-snapshot and verifies no record is added
+func TestFinishRecordsBaseSnapshot(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	type branchSnapshot struct {
+		Before string `json:"before"`
+		After  string `json:"after"`
+	}
+	type snapshot struct {
+		Type     string                    `json:"type"`
+		Branch   string                    `json:"branch"`
+		Tag      string                    `json:"tag"`
+		Branches map[string]branchSnapshot `json:"branches"`
+	}
+	readSnapshots := func() []snapshot {
+		content, err := os.ReadFile(filepath.Join(dir, ".git", "gitflow", "snapshots.log"))
+		if err != nil {
+			t.Fatalf("Failed to read snapshot log: %v", err)
+		}
+		var snapshots []snapshot
+		for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+			var s snapshot
+			if err := json.Unmarshal([]byte(line), &s); err != nil {
+				t.Fatalf("Failed to parse snapshot record %q: %v", line, err)
+			}
+			snapshots = append(snapshots, s)
+		}
+		return snapshots
+	}
+	revParse := func(ref string) string {
+		sha, err := testutil.RunGit(t, dir, "rev-parse", ref)
+		if err != nil {
+			t.Fatalf("Failed to resolve '%s': %v", ref, err)
+		}
+		return strings.TrimSpace(sha)
+	}
+	finish := func(branchType string, name string, args ...string) {
+		output, err := testutil.RunGitFlow(t, dir, branchType, "start", name)
+		if err != nil {
+			t.Fatalf("Failed to create %s branch: %v\nOutput: %s", branchType, err, output)
+		}
+		testutil.WriteFile(t, dir, name+".txt", "content")
+		if _, err := testutil.RunGit(t, dir, "add", name+".txt"); err != nil {
+			t.Fatalf("Failed to add file: %v", err)
+		}
+		if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add "+name+".txt"); err != nil {
+			t.Fatalf("Failed to commit file: %v", err)
+		}
+		output, err = testutil.RunGitFlow(t, dir, append([]string{branchType, "finish", name}, args...)...)
+		if err != nil {
+			t.Fatalf("Failed to finish %s branch: %v\nOutput: %s", branchType, err, output)
+		}
+	}
+
+	// A release changes main and its child develop
+	mainBefore, developBefore := revParse("main"), revParse("develop")
+	finish("release", "1.0.0", "--record-base-snapshot")
+	snapshots := readSnapshots()
+	if len(snapshots) != 1 {
+		t.Fatalf("Expected 1 snapshot record, got %d", len(snapshots))
+	}
+	release := snapshots[0]
+	if release.Type != "release" || release.Branch != "release/1.0.0" || release.Tag != "1.0.0" {
+		t.Errorf("Expected a record of release/1.0.0 with tag 1.0.0, got %+v", release)
+	}
+	expected := map[string]branchSnapshot{
+		"main":    {Before: mainBefore, After: revParse("main")},
+		"develop": {Before: developBefore, After: revParse("develop")},
+	}
+	if len(release.Branches) != len(expected) {
+		t.Errorf("Expected snapshots of main and develop, got %v", release.Branches)
+	}
+	for branch, want := range expected {
+		if got := release.Branches[branch]; got != want {
+			t.Errorf("Expected snapshot of %s to be %+v, got %+v", branch, want, got)
+		}
+		if want.Before == want.After {
+			t.Errorf("Expected the finish to move %s", branch)
+		}
+	}
+
+	// The config enables the record
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.finish.recordsnapshot", "true"); err != nil {
+		t.Fatalf("Failed to set gitflow.finish.recordsnapshot: %v", err)
+	}
+	developBefore = revParse("develop")
+	finish("feature", "logged")
+	snapshots = readSnapshots()
+	if len(snapshots) != 2 {
+		t.Fatalf("Expected 2 snapshot records, got %d", len(snapshots))
+	}
+	want := map[string]branchSnapshot{"develop": {Before: developBefore, After: revParse("develop")}}
+	if got := snapshots[1].Branches; len(got) != 1 || got["develop"] != want["develop"] {
+		t.Errorf("Expected feature snapshot %v, got %v", want, got)
+	}
+
+	// The flag overrides the config
+	finish("feature", "unlogged", "--no-record-base-snapshot")
+	if snapshots := readSnapshots(); len(snapshots) != 2 {
+		t.Errorf("Expected no record with --no-record-base-snapshot, got %d records", len(snapshots))
+	}
+}
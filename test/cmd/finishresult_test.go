@@ -0,0 +1,187 @@
+package cmd_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	gitflow "github.com/gittower/git-flow-next/cmd"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExecuteFinishReturnsResult tests that ExecuteFinish returns the outcome of a completed finish.
+// Steps:
+// 1. Sets up a test repository and starts a release with a commit
+// 2. Calls ExecuteFinish with --dry-run and verifies no result is returned
+// 3. Calls ExecuteFinish for the release
+// 4. Verifies the result lists the target, strategy, tag, updated children and deleted branches
+// 5. Verifies the finish left the environment of the process unchanged
+func TestExecuteFinishReturnsResult(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release content")
+	if _, err := testutil.RunGit(t, dir, "add", "release.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add release.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// The finish runs in the current directory
+	oldDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(oldDir)
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change to test directory: %v", err)
+	}
+
+	// A dry run does not complete a finish
+	result, err := gitflow.ExecuteFinish("release", "1.0.0", false, false, false, &gitflow.TagOptions{}, &gitflow.BranchRetentionOptions{}, &gitflow.FinishOptions{DryRun: true})
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+
+	// Finish the release
+	result, err = gitflow.ExecuteFinish("release", "1.0.0", false, false, false, &gitflow.TagOptions{}, &gitflow.BranchRetentionOptions{}, &gitflow.FinishOptions{})
+	if err != nil {
+		t.Fatalf("Failed to finish release: %v", err)
+	}
+	if result == nil {
+		t.Fatal("Expected a result for the completed finish")
+	}
+
+	tagSHA, err := testutil.RunGit(t, dir, "rev-parse", "refs/tags/1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to resolve tag: %v", err)
+	}
+	assert.Equal(t, &gitflow.FinishResult{
+		Type:            "release",
+		Branch:          "release/1.0.0",
+		Target:          "main",
+		Strategy:        "merge",
+		Tag:             "1.0.0",
+		TagSHA:          strings.TrimSpace(tagSHA),
+		UpdatedChildren: []string{"develop"},
+		DeletedBranches: []string{"release/1.0.0"},
+	}, result)
+
+	// The reflog action is passed to the git commands, not set for the process
+	_, set := os.LookupEnv("GIT_REFLOG_ACTION")
+	assert.False(t, set)
+}
+
+// TestExecuteFinishCancelledReturnsTypedError tests that declining the finish prompt returns an OperationCancelledError.
+// Steps:
+// 1. Sets up a test repository with a branch that lacks the feature prefix
+// 2. Calls ExecuteFinish for it as a feature and answers the prompt with 'n'
+// 3. Verifies the error is an OperationCancelledError and the branch is kept
+func TestExecuteFinishCancelledReturnsTypedError(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "checkout", "-b", "unprefixed", "develop"); err != nil {
+		t.Fatalf("Failed to create branch: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "--allow-empty", "-m", "Unprefixed work"); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+
+	oldDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(oldDir)
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change to test directory: %v", err)
+	}
+
+	// Answer the prompt with 'n'
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	writer.WriteString("n\n")
+	writer.Close()
+	oldStdin := os.Stdin
+	os.Stdin = reader
+	defer func() { os.Stdin = oldStdin }()
+
+	result, err := gitflow.ExecuteFinish("feature", "unprefixed", false, false, false, &gitflow.TagOptions{}, &gitflow.BranchRetentionOptions{}, &gitflow.FinishOptions{})
+	assert.Nil(t, result)
+	assert.IsType(t, &errors.OperationCancelledError{}, err)
+	assert.Equal(t, errors.ExitCodeInvalidInput, err.(*errors.OperationCancelledError).ExitCode())
+	assert.True(t, testutil.BranchExists(t, dir, "unprefixed"))
+}
+
+// TestExecuteFinishSupportBranchReturnsResult tests that finishing a branch that is not merged back returns a result.
+// Steps:
+// 1. Sets up a test repository and starts support/1.x
+// 2. Calls ExecuteFinish for the support branch with a tag
+// 3. Verifies the result names the branch as its own target, the tag and no deleted branches
+func TestExecuteFinishSupportBranchReturnsResult(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "support", "start", "1.x")
+	if err != nil {
+		t.Fatalf("Failed to start support branch: %v\nOutput: %s", err, output)
+	}
+
+	oldDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(oldDir)
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change to test directory: %v", err)
+	}
+
+	// Finish the support branch with a tag
+	createTag := true
+	result, err := gitflow.ExecuteFinish("support", "1.x", false, false, false, &gitflow.TagOptions{ShouldTag: &createTag, TagName: "v1.0.1"}, &gitflow.BranchRetentionOptions{}, &gitflow.FinishOptions{})
+	if err != nil {
+		t.Fatalf("Failed to finish support branch: %v", err)
+	}
+	if result == nil {
+		t.Fatal("Expected a result for the completed finish")
+	}
+
+	tagSHA, err := testutil.RunGit(t, dir, "rev-parse", "refs/tags/v1.0.1")
+	if err != nil {
+		t.Fatalf("Failed to resolve tag: %v", err)
+	}
+	assert.Equal(t, &gitflow.FinishResult{
+		Type:            "support",
+		Branch:          "support/1.x",
+		Target:          "support/1.x",
+		Strategy:        "none",
+		Tag:             "v1.0.1",
+		TagSHA:          strings.TrimSpace(tagSHA),
+		UpdatedChildren: []string{},
+		DeletedBranches: []string{},
+	}, result)
+	assert.True(t, testutil.BranchExists(t, dir, "support/1.x"))
+}
@@ -620,3 +620,36 @@ func TestInitWithDefaultsAndOverrides(t *testing.T) {
 		t.Error("Expected 'hotfix' branch configuration to exist")
 	}
 }
+
+// TestInitWarnsAboutOverlappingPrefixes tests that init warns when topic branch prefixes overlap.
+// Steps:
+// 1. Initializes git-flow with a hotfix prefix equal to the feature prefix
+// 2. Verifies init succeeds and warns about feature and hotfix
+// 3. Initializes git-flow with a release prefix nested in the feature prefix
+// 4. Verifies the warning names feature and release
+func TestInitWarnsAboutOverlappingPrefixes(t *testing.T) {
+	// Setup
+	dir := setupTestRepo(t)
+	defer cleanupTestRepo(t, dir)
+
+	// Initialize git-flow with equal prefixes
+	output, err := runGitFlow(t, dir, "init", "--defaults", "--feature", "work/", "--hotfix", "work/")
+	if err != nil {
+		t.Fatalf("Expected init to succeed with overlapping prefixes: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Warning: prefixes of branch types 'feature' ('work/') and 'hotfix' ('work/') overlap") {
+		t.Errorf("Expected warning about feature and hotfix prefixes, got: %s", output)
+	}
+
+	// Re-initialize with a release prefix nested in the feature prefix
+	output, err = runGitFlow(t, dir, "init", "--defaults", "--feature", "work/", "--release", "work/release/")
+	if err != nil {
+		t.Fatalf("Expected init to succeed with nested prefixes: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Warning: prefixes of branch types 'feature' ('work/') and 'release' ('work/release/') overlap") {
+		t.Errorf("Expected warning about feature and release prefixes, got: %s", output)
+	}
+	if strings.Contains(output, "'hotfix'") {
+		t.Errorf("Expected no warning about hotfix after resetting its prefix, got: %s", output)
+	}
+}
@@ -3,6 +3,8 @@ package cmd_test
 import (
 	"strings"
 	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
 )
 
 // TestListFeatureBranches tests the listing of feature branches.
@@ -181,3 +183,116 @@ func TestListEmptyBranches(t *testing.T) {
 		t.Errorf("Expected output to contain 'No feature branches found', got: %s", output)
 	}
 }
+
+// TestListAllBranchTypes tests listing the topic branches of all types with git flow list.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates a release branch with a commit and two feature branches
+// 3. Lists all topic branches
+// 4. Verifies the branches are grouped by type, the current branch is marked and empty types are left out
+func TestListAllBranchTypes(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create a release branch with a commit, then the feature branches, ending on the second one
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create release branch: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "--allow-empty", "-m", "Prepare release"); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	for _, name := range []string{"first", "second"} {
+		output, err = testutil.RunGitFlow(t, dir, "feature", "start", name)
+		if err != nil {
+			t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+		}
+	}
+
+	// List all topic branches
+	output, err = testutil.RunGitFlow(t, dir, "list")
+	if err != nil {
+		t.Fatalf("Failed to list branches: %v\nOutput: %s", err, output)
+	}
+
+	// Verify the grouping and the marker of the current branch
+	expected := "Feature branches:\n" +
+		"  first (merged)\n" +
+		"* second (merged)\n" +
+		"\n" +
+		"Release branches:\n" +
+		"  1.0.0 (unmerged)\n"
+	if output != expected {
+		t.Errorf("Expected output:\n%s\ngot:\n%s", expected, output)
+	}
+}
+
+// TestListMergeStatusVerbose tests the merge status and the details printed by list --verbose.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates two feature branches with a commit each and merges one of them into develop
+// 3. Lists feature branches and verifies the merged and unmerged status
+// 4. Lists feature branches with --verbose and verifies the base, strategy and ahead/behind counts
+func TestListMergeStatusVerbose(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create two feature branches with a commit each
+	for _, name := range []string{"done", "wip"} {
+		output, err = testutil.RunGitFlow(t, dir, "feature", "start", name)
+		if err != nil {
+			t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+		}
+		if _, err := testutil.RunGit(t, dir, "commit", "--allow-empty", "-m", "Work on "+name); err != nil {
+			t.Fatalf("Failed to commit: %v", err)
+		}
+	}
+
+	// Merge the first one into develop without finishing it
+	if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+		t.Fatalf("Failed to checkout develop: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "merge", "--no-ff", "feature/done", "-m", "Merge done"); err != nil {
+		t.Fatalf("Failed to merge: %v", err)
+	}
+
+	// Verify the merge status
+	output, err = testutil.RunGitFlow(t, dir, "feature", "list")
+	if err != nil {
+		t.Fatalf("Failed to list feature branches: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "  done (merged)\n") || !strings.Contains(output, "  wip (unmerged)\n") {
+		t.Errorf("Expected done to be merged and wip to be unmerged, got: %s", output)
+	}
+	if strings.Contains(output, "base:") {
+		t.Errorf("Expected no details without --verbose, got: %s", output)
+	}
+
+	// Verify the details
+	output, err = testutil.RunGitFlow(t, dir, "feature", "list", "--verbose")
+	if err != nil {
+		t.Fatalf("Failed to list feature branches: %v\nOutput: %s", err, output)
+	}
+	expected := "Feature branches:\n" +
+		"  done (merged)\n" +
+		"    base: develop, upstream strategy: merge, 0 ahead of and 1 behind 'develop'\n" +
+		"  wip (unmerged)\n" +
+		"    base: develop, upstream strategy: merge, 1 ahead of and 2 behind 'develop'\n"
+	if output != expected {
+		t.Errorf("Expected output:\n%s\ngot:\n%s", expected, output)
+	}
+}
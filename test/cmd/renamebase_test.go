@@ -0,0 +1,174 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestRenameBaseUpdatesReferences tests renaming the develop branch with rename-base.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Starts a feature branch based on develop
+// 3. Runs git flow rename-base develop dev
+// 4. Verifies the branch is renamed and the develop configuration is moved
+// 5. Verifies parent and start point references and the feature base point to dev
+// 6. Verifies a new feature branch starts from dev
+func TestRenameBaseUpdatesReferences(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Start a feature branch based on develop
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "existing")
+	if err != nil {
+		t.Fatalf("Failed to start feature branch: %v\nOutput: %s", err, output)
+	}
+
+	// Rename develop
+	output, err = testutil.RunGitFlow(t, dir, "rename-base", "develop", "dev")
+	if err != nil {
+		t.Fatalf("Failed to rename base branch: %v\nOutput: %s", err, output)
+	}
+
+	// Verify the branch is renamed
+	if testutil.BranchExists(t, dir, "develop") {
+		t.Error("Expected develop branch to be renamed")
+	}
+	if !testutil.BranchExists(t, dir, "dev") {
+		t.Error("Expected dev branch to exist")
+	}
+
+	// Verify the configuration of the base branch is moved
+	if _, err := testutil.RunGit(t, dir, "config", "--get", "gitflow.branch.develop.type"); err == nil {
+		t.Error("Expected configuration of develop to be removed")
+	}
+	expected := map[string]string{
+		"gitflow.branch.dev.type":              "base",
+		"gitflow.branch.dev.parent":            "main",
+		"gitflow.branch.feature.parent":        "dev",
+		"gitflow.branch.feature.startpoint":    "dev",
+		"gitflow.branch.bugfix.parent":         "dev",
+		"gitflow.branch.bugfix.startpoint":     "dev",
+		"gitflow.branch.release.parent":        "main",
+		"gitflow.branch.release.startpoint":    "dev",
+		"gitflow.branch.hotfix.startpoint":     "main",
+		"gitflow.branch.feature/existing.base": "dev",
+	}
+	for key, value := range expected {
+		actual, err := testutil.RunGit(t, dir, "config", "--get", key)
+		if err != nil {
+			t.Errorf("Expected %s to be set: %v", key, err)
+			continue
+		}
+		if strings.TrimSpace(actual) != value {
+			t.Errorf("Expected %s to be '%s', got '%s'", key, value, strings.TrimSpace(actual))
+		}
+	}
+
+	// Verify new feature branches start from dev
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "new")
+	if err != nil {
+		t.Fatalf("Failed to start feature branch after rename: %v\nOutput: %s", err, output)
+	}
+	base, err := testutil.RunGit(t, dir, "config", "--get", "gitflow.branch.feature/new.base")
+	if err != nil || strings.TrimSpace(base) != "dev" {
+		t.Errorf("Expected new feature branch to be based on dev, got '%s'", strings.TrimSpace(base))
+	}
+}
+
+// TestRenameBaseWithRemote tests renaming a base branch on the remote with rename-base --remote.
+// Steps:
+// 1. Sets up a test repository with a remote and initializes git-flow
+// 2. Pushes develop to the remote
+// 3. Runs git flow rename-base develop dev --remote
+// 4. Verifies dev exists on the remote and develop was deleted there
+func TestRenameBaseWithRemote(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Add a remote with all branches
+	remoteDir, err := testutil.AddRemote(t, dir, "origin", true)
+	if err != nil {
+		t.Fatalf("Failed to add remote: %v", err)
+	}
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	// Rename develop locally and on the remote
+	output, err = testutil.RunGitFlow(t, dir, "rename-base", "develop", "dev", "--remote")
+	if err != nil {
+		t.Fatalf("Failed to rename base branch: %v\nOutput: %s", err, output)
+	}
+
+	// Verify the remote branches
+	remoteBranches, err := testutil.RunGit(t, remoteDir, "branch", "--list")
+	if err != nil {
+		t.Fatalf("Failed to list remote branches: %v", err)
+	}
+	if !strings.Contains(remoteBranches, "dev") {
+		t.Errorf("Expected dev on the remote, got: %s", remoteBranches)
+	}
+	if strings.Contains(remoteBranches, "develop") {
+		t.Errorf("Expected develop to be deleted on the remote, got: %s", remoteBranches)
+	}
+}
+
+// TestRenameBaseValidation tests that rename-base rejects invalid renames.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Verifies renaming a branch type is rejected
+// 3. Verifies renaming to an existing branch is rejected
+// 4. Verifies the configuration is unchanged
+func TestRenameBaseValidation(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// A topic branch type is not a base branch
+	output, err = testutil.RunGitFlow(t, dir, "rename-base", "feature", "feat")
+	if err == nil {
+		t.Fatalf("Expected renaming a branch type to fail\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "not a base branch") {
+		t.Errorf("Expected not a base branch error, got: %s", output)
+	}
+
+	// The new name must not be an existing branch
+	_, err = testutil.RunGit(t, dir, "branch", "dev")
+	if err != nil {
+		t.Fatalf("Failed to create branch: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "rename-base", "develop", "dev")
+	if err == nil {
+		t.Fatalf("Expected renaming to an existing branch to fail\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "branch 'dev' already exists") {
+		t.Errorf("Expected branch exists error, got: %s", output)
+	}
+
+	// Nothing was changed
+	parent, err := testutil.RunGit(t, dir, "config", "--get", "gitflow.branch.feature.parent")
+	if err != nil || strings.TrimSpace(parent) != "develop" {
+		t.Errorf("Expected feature parent to stay develop, got '%s'", strings.TrimSpace(parent))
+	}
+}
@@ -107,14 +107,53 @@ func TestAmbiguousBranchDetection(t *testing.T) {
 	assert.Contains(t, output, "operation cancelled")
 }
 
+// TestShorthandErrorExitCodes checks that branch detection errors exit with the invalid input code
+func TestShorthandErrorExitCodes(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	testutil.RunGitFlow(t, dir, "init", "--defaults", "--feature", "feat/", "--hotfix", "feat/") // Force overlap
+
+	// Not a topic branch
+	testutil.RunGit(t, dir, "checkout", "-b", "custom/branch")
+	output, err := testutil.RunGitFlow(t, dir, "finish")
+	assert.Contains(t, output, "branch 'custom/branch' is not a valid topic branch")
+	if exitErr, ok := err.(*testutil.ExitError); assert.True(t, ok) {
+		assert.Equal(t, 2, exitErr.ExitCode)
+	}
+
+	// Ambiguous branch, keeping the explicit command suggestion
+	testutil.RunGit(t, dir, "checkout", "-b", "feat/ambiguous")
+	output, err = testutil.RunGitFlowWithInput(t, dir, "y\n", "finish")
+	assert.Contains(t, output, "ambiguous branch 'feat/ambiguous' matches multiple types (feature, hotfix)")
+	if exitErr, ok := err.(*testutil.ExitError); assert.True(t, ok) {
+		assert.Equal(t, 2, exitErr.ExitCode)
+	}
+
+	// Cancelled at the ambiguity prompt
+	output, err = testutil.RunGitFlowWithInput(t, dir, "n\n", "finish")
+	assert.Contains(t, output, "operation cancelled by user")
+	if exitErr, ok := err.(*testutil.ExitError); assert.True(t, ok) {
+		assert.Equal(t, 2, exitErr.ExitCode)
+	}
+
+	// Detached HEAD, which is not reported as a branch named HEAD
+	testutil.RunGit(t, dir, "checkout", "--detach")
+	output, err = testutil.RunGitFlow(t, dir, "finish")
+	assert.Contains(t, output, "HEAD is detached")
+	assert.NotContains(t, output, "branch 'HEAD'")
+	if exitErr, ok := err.(*testutil.ExitError); assert.True(t, ok) {
+		assert.Equal(t, 3, exitErr.ExitCode)
+	}
+}
+
 // Command-Specific Tests
 func TestDeleteAlias(t *testing.T) {
 	dir := testutil.SetupTestRepo(t)
 	defer testutil.CleanupTestRepo(t, dir)
 	testutil.RunGitFlow(t, dir, "init", "--defaults")
 	testutil.RunGitFlow(t, dir, "feature", "start", "test-delete")
-	testutil.RunGit(t, dir, "checkout", "develop")                              // Switch off
-	output, err := testutil.RunGitFlow(t, dir, "delete", "feature/test-delete") // Use full prefixed name
+	testutil.RunGit(t, dir, "checkout", "develop")                                       // Switch off
+	output, err := testutil.RunGitFlow(t, dir, "delete", "feature/test-delete", "--yes") // Use full prefixed name
 	assert.NoError(t, err)
 	assert.Contains(t, output, "Deleted branch feature/test-delete")
 	assert.False(t, testutil.BranchExists(t, dir, "feature/test-delete"))
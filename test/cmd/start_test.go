@@ -2,6 +2,7 @@ package cmd_test
 
 import (
 	"fmt"
+	"os"
 	"strings"
 	"testing"
 
@@ -535,3 +536,430 @@ func TestStartWithCustomRemote(t *testing.T) {
 		t.Errorf("Expected fetch operation from custom remote '%s', but output doesn't indicate it: %s", customRemote, output)
 	}
 }
+
+// TestStartWithFallbackTo tests that --fallback-to starts from another branch when the start point is missing
+func TestStartWithFallbackTo(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Delete the develop branch to make it non-existent
+	if _, err := testutil.RunGit(t, dir, "checkout", "main"); err != nil {
+		t.Fatalf("Failed to switch to main branch: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "branch", "-D", "develop"); err != nil {
+		t.Fatalf("Failed to delete develop branch: %v", err)
+	}
+
+	// Create a feature branch, falling back to main
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "my-feature", "--fallback-to", "main")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch with fallback: %v\nOutput: %s", err, output)
+	}
+
+	// Verify the warning and the recorded base
+	if !strings.Contains(output, "Warning: start point branch 'develop' does not exist, starting from 'main' instead") {
+		t.Errorf("Expected fallback warning, got: %s", output)
+	}
+	if !testutil.BranchExists(t, dir, "feature/my-feature") {
+		t.Fatal("Expected feature branch to be created")
+	}
+	base, err := testutil.RunGit(t, dir, "config", "gitflow.branch.feature/my-feature.base")
+	if err != nil {
+		t.Fatalf("Failed to read base config: %v", err)
+	}
+	if strings.TrimSpace(base) != "main" {
+		t.Errorf("Expected base to be 'main', got '%s'", strings.TrimSpace(base))
+	}
+}
+
+// TestStartWithConfiguredFallback tests that gitflow.<type>.start.fallback is used when the start point is missing
+// and that --no-develop-fallback ignores it
+func TestStartWithConfiguredFallback(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and configure a fallback for features
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.feature.start.fallback", "main"); err != nil {
+		t.Fatalf("Failed to set fallback config: %v", err)
+	}
+
+	// Delete the develop branch to make it non-existent
+	if _, err := testutil.RunGit(t, dir, "checkout", "main"); err != nil {
+		t.Fatalf("Failed to switch to main branch: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "branch", "-D", "develop"); err != nil {
+		t.Fatalf("Failed to delete develop branch: %v", err)
+	}
+
+	// Strict mode fails even though a fallback is configured
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "strict", "--no-develop-fallback")
+	if err == nil {
+		t.Fatalf("Expected start to fail with --no-develop-fallback\nOutput: %s", output)
+	}
+	if exitErr, ok := err.(*testutil.ExitError); ok {
+		if exitErr.ExitCode != int(errors.ExitCodeBranchNotFound) {
+			t.Errorf("Expected exit code %d, got %d", errors.ExitCodeBranchNotFound, exitErr.ExitCode)
+		}
+	} else {
+		t.Error("Expected ExitError")
+	}
+	if testutil.BranchExists(t, dir, "feature/strict") {
+		t.Error("Expected no feature branch to be created in strict mode")
+	}
+
+	// Without the strict flag the configured fallback is used
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "relaxed")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch with configured fallback: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "starting from 'main' instead") {
+		t.Errorf("Expected fallback warning, got: %s", output)
+	}
+	if !testutil.BranchExists(t, dir, "feature/relaxed") {
+		t.Error("Expected feature branch to be created from the fallback")
+	}
+}
+
+// TestStartWithCopyConfigFrom tests that --copy-config-from copies per-branch settings to the new branch
+func TestStartWithCopyConfigFrom(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create a source feature with a description
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "template")
+	if err != nil {
+		t.Fatalf("Failed to create source feature branch: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch.feature/template.description", "Checkout redesign"); err != nil {
+		t.Fatalf("Failed to set description: %v", err)
+	}
+
+	// Create a new feature copying the settings by short name
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "copy", "--copy-config-from", "template")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Copied 1 settings from 'feature/template'") {
+		t.Errorf("Expected output to report the copied settings, got: %s", output)
+	}
+
+	// Verify the description was copied and the base was not overwritten
+	description, err := testutil.RunGit(t, dir, "config", "gitflow.branch.feature/copy.description")
+	if err != nil {
+		t.Fatalf("Expected description on the new branch: %v", err)
+	}
+	if strings.TrimSpace(description) != "Checkout redesign" {
+		t.Errorf("Expected description 'Checkout redesign', got '%s'", strings.TrimSpace(description))
+	}
+	base, err := testutil.RunGit(t, dir, "config", "gitflow.branch.feature/copy.base")
+	if err != nil {
+		t.Fatalf("Failed to read base config: %v", err)
+	}
+	if strings.TrimSpace(base) != "develop" {
+		t.Errorf("Expected base to be 'develop', got '%s'", strings.TrimSpace(base))
+	}
+
+	// A missing source branch is an error
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "orphan", "--copy-config-from", "missing")
+	if err == nil {
+		t.Fatalf("Expected start to fail for a missing source branch\nOutput: %s", output)
+	}
+	if testutil.BranchExists(t, dir, "feature/orphan") {
+		t.Error("Expected no branch to be created when the source branch is missing")
+	}
+}
+
+// TestStartRefusedDuringGitCherryPick tests that start refuses to run while a plain git cherry-pick is stopped
+func TestStartRefusedDuringGitCherryPick(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Commit conflicting changes on a scratch branch and on develop
+	if _, err := testutil.RunGit(t, dir, "checkout", "-b", "scratch"); err != nil {
+		t.Fatalf("Failed to create scratch branch: %v", err)
+	}
+	testutil.WriteFile(t, dir, "pick.txt", "scratch content")
+	if _, err := testutil.RunGit(t, dir, "add", "pick.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add pick.txt on scratch"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+		t.Fatalf("Failed to checkout develop: %v", err)
+	}
+	testutil.WriteFile(t, dir, "pick.txt", "develop content")
+	if _, err := testutil.RunGit(t, dir, "add", "pick.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add pick.txt on develop"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Leave a cherry-pick stopped on the conflict
+	if _, err := testutil.RunGit(t, dir, "cherry-pick", "scratch"); err == nil {
+		t.Fatal("Expected the cherry-pick to stop on a conflict")
+	}
+
+	// Start refuses to stack on the cherry-pick
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "picked")
+	if err == nil {
+		t.Fatalf("Expected start to fail during a cherry-pick\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "a git cherry-pick is in progress") {
+		t.Errorf("Expected error to explain the cherry-pick in progress, got: %s", output)
+	}
+	if testutil.BranchExists(t, dir, "feature/picked") {
+		t.Error("Expected no feature branch to be created")
+	}
+}
+
+// TestStartFromPullRequest tests starting a branch from a pull request ref published by the remote
+func TestStartFromPullRequest(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	remoteDir, err := testutil.AddRemote(t, dir, "origin", true)
+	if err != nil {
+		t.Fatalf("Failed to add remote: %v", err)
+	}
+	defer os.RemoveAll(remoteDir)
+
+	// Simulate a pull request by publishing a commit under refs/pull/7/head only
+	if _, err := testutil.RunGit(t, dir, "checkout", "-b", "contribution", "develop"); err != nil {
+		t.Fatalf("Failed to create contribution branch: %v", err)
+	}
+	testutil.WriteFile(t, dir, "contribution.txt", "contributed content")
+	if _, err := testutil.RunGit(t, dir, "add", "contribution.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add contribution.txt"); err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+	prHead, _ := testutil.RunGit(t, dir, "rev-parse", "HEAD")
+	if _, err := testutil.RunGit(t, dir, "push", "origin", "contribution:refs/pull/7/head"); err != nil {
+		t.Fatalf("Failed to publish pull request ref: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+		t.Fatalf("Failed to checkout develop: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "branch", "-D", "contribution"); err != nil {
+		t.Fatalf("Failed to delete contribution branch: %v", err)
+	}
+
+	// Without a name the branch is named after the pull request
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "--from-pr", "7")
+	if err != nil {
+		t.Fatalf("Failed to start from pull request: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Created branch 'feature/pr-7' from pull request #7") {
+		t.Errorf("Expected output to name the pull request, got: %s", output)
+	}
+	head, _ := testutil.RunGit(t, dir, "rev-parse", "feature/pr-7")
+	if strings.TrimSpace(head) != strings.TrimSpace(prHead) {
+		t.Errorf("Expected 'feature/pr-7' at the pull request head %s, got %s", strings.TrimSpace(prHead), strings.TrimSpace(head))
+	}
+	base, err := testutil.RunGit(t, dir, "config", "gitflow.branch.feature/pr-7.base")
+	if err != nil || strings.TrimSpace(base) != "develop" {
+		t.Errorf("Expected base to be 'develop', got '%s' (%v)", strings.TrimSpace(base), err)
+	}
+
+	// A given name is used as the slug, and the refspec is configurable
+	if _, err := testutil.RunGit(t, dir, "push", "origin", prHead[:7]+":refs/merge-requests/8/head"); err != nil {
+		t.Fatalf("Failed to publish merge request ref: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.start.prrefspec", "refs/merge-requests/%d/head"); err != nil {
+		t.Fatalf("Failed to set refspec: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "review-contribution", "--from-pr", "8")
+	if err != nil {
+		t.Fatalf("Failed to start from merge request: %v\nOutput: %s", err, output)
+	}
+	if !testutil.BranchExists(t, dir, "feature/review-contribution") {
+		t.Error("Expected 'feature/review-contribution' to be created")
+	}
+
+	// A pull request the remote doesn't have is an error
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "--from-pr", "99")
+	if err == nil {
+		t.Fatalf("Expected start to fail for a missing pull request\nOutput: %s", output)
+	}
+	if testutil.BranchExists(t, dir, "feature/pr-99") {
+		t.Error("Expected no branch to be created for a missing pull request")
+	}
+}
+
+// TestStartBare tests creating only the branch ref with --bare.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Checks out main and leaves a modified and an untracked file in the working tree
+// 3. Starts a feature branch with --bare
+// 4. Verifies the branch points at develop while HEAD, the index and the working tree are untouched
+// 5. Verifies --bare cannot be combined with --fetch
+func TestStartBare(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Leave changes on main that a checkout would carry along or refuse
+	_, err = testutil.RunGit(t, dir, "checkout", "main")
+	if err != nil {
+		t.Fatalf("Failed to checkout main: %v", err)
+	}
+	testutil.WriteFile(t, dir, "README.md", "modified")
+	testutil.WriteFile(t, dir, "untracked.txt", "untracked")
+	statusBefore, err := testutil.RunGit(t, dir, "status", "--porcelain")
+	if err != nil {
+		t.Fatalf("Failed to get status: %v", err)
+	}
+
+	// Start the branch as a bare ref
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "quick", "--bare")
+	if err != nil {
+		t.Fatalf("Failed to start bare feature branch: %v\nOutput: %s", err, output)
+	}
+
+	// Verify the ref points at the start point
+	branchCommit, err := testutil.RunGit(t, dir, "rev-parse", "feature/quick")
+	if err != nil {
+		t.Fatalf("Expected feature/quick to exist: %v", err)
+	}
+	developCommit, err := testutil.RunGit(t, dir, "rev-parse", "develop")
+	if err != nil {
+		t.Fatalf("Failed to resolve develop: %v", err)
+	}
+	if branchCommit != developCommit {
+		t.Errorf("Expected feature/quick at %s, got %s", developCommit, branchCommit)
+	}
+
+	// Verify HEAD and the working tree are untouched
+	if current := testutil.GetCurrentBranch(t, dir); current != "main" {
+		t.Errorf("Expected to stay on main, got %s", current)
+	}
+	statusAfter, err := testutil.RunGit(t, dir, "status", "--porcelain")
+	if err != nil {
+		t.Fatalf("Failed to get status: %v", err)
+	}
+	if statusAfter != statusBefore {
+		t.Errorf("Expected working tree status to be unchanged\nBefore: %s\nAfter: %s", statusBefore, statusAfter)
+	}
+
+	// --bare never fetches
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "fetched", "--bare", "--fetch")
+	if err == nil {
+		t.Fatalf("Expected --bare with --fetch to fail\nOutput: %s", output)
+	}
+	if testutil.BranchExists(t, dir, "feature/fetched") {
+		t.Error("Expected feature/fetched not to be created")
+	}
+}
+
+// TestStartAllowExisting tests switching to an existing branch with --allow-existing.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Starts a feature branch, commits on it and checks out develop
+// 3. Verifies starting it again without --allow-existing fails
+// 4. Starts it again with --allow-existing and verifies it is checked out with its commit
+// 5. Verifies --allow-existing refuses a base branch reached through an empty prefix
+func TestStartAllowExisting(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Create the branch with a commit of its own
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "existing")
+	if err != nil {
+		t.Fatalf("Failed to start feature branch: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "--allow-empty", "-m", "Feature work"); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	branchCommit, err := testutil.RunGit(t, dir, "rev-parse", "feature/existing")
+	if err != nil {
+		t.Fatalf("Failed to resolve feature branch: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+		t.Fatalf("Failed to checkout develop: %v", err)
+	}
+
+	// Without the flag the existing branch is an error
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "existing")
+	if err == nil {
+		t.Fatalf("Expected starting an existing branch to fail\nOutput: %s", output)
+	}
+
+	// With the flag it is checked out
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "existing", "--allow-existing")
+	if err != nil {
+		t.Fatalf("Failed to start existing feature branch: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "already exists, switched to it") {
+		t.Errorf("Expected a note about the existing branch, got: %s", output)
+	}
+	if current := testutil.GetCurrentBranch(t, dir); current != "feature/existing" {
+		t.Errorf("Expected to be on feature/existing, got %s", current)
+	}
+	headCommit, err := testutil.RunGit(t, dir, "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatalf("Failed to resolve HEAD: %v", err)
+	}
+	if headCommit != branchCommit {
+		t.Errorf("Expected the existing branch to be unchanged at %s, got %s", branchCommit, headCommit)
+	}
+
+	// A base branch is not a topic branch, even if the prefix matches it
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch.feature.prefix", ""); err != nil {
+		t.Fatalf("Failed to clear prefix: %v", err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "develop", "--allow-existing")
+	if err == nil {
+		t.Fatalf("Expected --allow-existing to refuse a base branch\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "is a base branch") {
+		t.Errorf("Expected base branch error, got: %s", output)
+	}
+}
@@ -0,0 +1,222 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestStatusShowsCurrentBranch tests the status of a repository without an operation in progress.
+// Steps:
+// 1. Sets up a test repository and verifies status reports git-flow as not initialized
+// 2. Initializes git-flow and starts a feature branch
+// 3. Verifies status shows the feature branch with its type and parent
+// 4. Verifies status --porcelain prints the same as key=value lines
+func TestStatusShowsCurrentBranch(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// An uninitialized repository is reported, not an error
+	output, err := testutil.RunGitFlow(t, dir, "status", "--porcelain")
+	if err != nil {
+		t.Fatalf("Failed to get status: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "initialized=false\n") {
+		t.Errorf("Expected initialized=false, got: %s", output)
+	}
+
+	// Initialize git-flow and start a feature branch
+	output, err = testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "status")
+	if err != nil {
+		t.Fatalf("Failed to start feature branch: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "status")
+	if err != nil {
+		t.Fatalf("Failed to get status: %v\nOutput: %s", err, output)
+	}
+	for _, expected := range []string{"git-flow is initialized", "On feature branch 'status' (parent 'develop')", "No operation in progress"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected status to contain '%s', got: %s", expected, output)
+		}
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "status", "--porcelain")
+	if err != nil {
+		t.Fatalf("Failed to get status: %v\nOutput: %s", err, output)
+	}
+	expected := "initialized=true\nbranch=feature/status\nbranch.type=feature\nbranch.name=status\nbranch.parent=develop\noperation=none\n"
+	if output != expected {
+		t.Errorf("Expected porcelain status:\n%s\ngot:\n%s", expected, output)
+	}
+}
+
+// TestStatusShowsFinishInProgress tests the status while a finish is stopped on a conflict.
+// Steps:
+// 1. Sets up a feature branch and a conflicting change on develop
+// 2. Finishes the feature branch, which stops on the conflict
+// 3. Verifies status shows the finish with its step and the commands to continue and abort
+// 4. Verifies status --porcelain reports the operation as key=value lines
+func TestStatusShowsFinishInProgress(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "conflict")
+	if err != nil {
+		t.Fatalf("Failed to start feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "file.txt", "feature content")
+	if _, err := testutil.RunGit(t, dir, "add", "file.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Feature change"); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+		t.Fatalf("Failed to checkout develop: %v", err)
+	}
+	testutil.WriteFile(t, dir, "file.txt", "develop content")
+	if _, err := testutil.RunGit(t, dir, "add", "file.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Develop change"); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+
+	// The finish stops on the conflict
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "conflict")
+	if err == nil {
+		t.Fatalf("Expected finish to stop on the conflict\nOutput: %s", output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "status")
+	if err != nil {
+		t.Fatalf("Failed to get status: %v\nOutput: %s", err, output)
+	}
+	for _, expected := range []string{
+		"Finish of feature branch 'conflict' into 'develop' in progress",
+		"Step: merge",
+		"There are unresolved conflicts",
+		"git flow feature finish --continue conflict",
+		"git flow feature finish --abort conflict",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected status to contain '%s', got: %s", expected, output)
+		}
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "status", "--porcelain")
+	if err != nil {
+		t.Fatalf("Failed to get status: %v\nOutput: %s", err, output)
+	}
+	for _, expected := range []string{
+		"operation=finish\n",
+		"operation.type=feature\n",
+		"operation.branch=feature/conflict\n",
+		"operation.parent=develop\n",
+		"operation.step=merge\n",
+		"operation.conflicts=true\n",
+		"operation.continue=git flow feature finish --continue conflict\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected porcelain status to contain '%s', got: %s", expected, output)
+		}
+	}
+}
+
+// TestStatusShowsStaleUpdate tests the status after an update that stopped on a conflict was completed with git.
+// Steps:
+// 1. Sets up a feature branch and a conflicting change on develop
+// 2. Updates the feature branch with --rebase, which stops on the conflict
+// 3. Verifies status shows the update in progress with the rebase commands
+// 4. Resolves the conflict and runs git rebase --continue
+// 5. Verifies status reports the state as stale with the file to remove instead of rebase commands
+func TestStatusShowsStaleUpdate(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "stale")
+	if err != nil {
+		t.Fatalf("Failed to start feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "file.txt", "feature content")
+	if _, err := testutil.RunGit(t, dir, "add", "file.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Feature change"); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+		t.Fatalf("Failed to checkout develop: %v", err)
+	}
+	testutil.WriteFile(t, dir, "file.txt", "develop content")
+	if _, err := testutil.RunGit(t, dir, "add", "file.txt"); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Develop change"); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+
+	// The update stops on the conflict
+	output, err = testutil.RunGitFlow(t, dir, "feature", "update", "stale", "--rebase")
+	if err == nil {
+		t.Fatalf("Expected update to stop on the conflict\nOutput: %s", output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "status")
+	if err != nil {
+		t.Fatalf("Failed to get status: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Update of 'feature/stale' from 'develop' in progress") || !strings.Contains(output, "git rebase --continue") {
+		t.Errorf("Expected status to show the update in progress, got: %s", output)
+	}
+
+	// Complete the rebase with git, as status advised
+	testutil.WriteFile(t, dir, "file.txt", "resolved content")
+	if _, err := testutil.RunGit(t, dir, "add", "file.txt"); err != nil {
+		t.Fatalf("Failed to add resolved file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "-c", "core.editor=true", "rebase", "--continue"); err != nil {
+		t.Fatalf("Failed to continue rebase: %v", err)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "status")
+	if err != nil {
+		t.Fatalf("Failed to get status: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Update of 'feature/stale' from 'develop' is no longer in progress") {
+		t.Errorf("Expected status to report the update as stale, got: %s", output)
+	}
+	if !strings.Contains(output, "To clear it, remove '") {
+		t.Errorf("Expected status to say how to clear the state, got: %s", output)
+	}
+	if strings.Contains(output, "git rebase --continue") {
+		t.Errorf("Expected status not to suggest continuing a rebase that no longer exists, got: %s", output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "status", "--porcelain")
+	if err != nil {
+		t.Fatalf("Failed to get status: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "operation=stale\n") || !strings.Contains(output, "operation.statefile=") {
+		t.Errorf("Expected porcelain status to report a stale operation, got: %s", output)
+	}
+	if strings.Contains(output, "operation.continue=") {
+		t.Errorf("Expected no continue command for a stale operation, got: %s", output)
+	}
+}
@@ -2,6 +2,8 @@ package cmd_test
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/gittower/git-flow-next/internal/config"
@@ -749,3 +751,355 @@ func TestUpdateWithRebaseFlagOnBaseBranch(t *testing.T) {
 	assert.True(t, testutil.FileExists(t, dir, "main-change.txt"))
 	assert.True(t, testutil.FileExists(t, dir, "develop-change.txt"))
 }
+
+// TestUpdateWithStrategyFlag tests overriding the downstream strategy with --strategy.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Configures a downstream strategy different from the forced one
+// 3. Creates diverging commits on develop and a feature branch
+// 4. Updates the feature branch with --strategy merge, rebase or squash
+// 5. Verifies the resulting history has the shape of the forced strategy
+func TestUpdateWithStrategyFlag(t *testing.T) {
+	tests := []struct {
+		strategy   string
+		configured string
+	}{
+		{"merge", "rebase"},
+		{"rebase", "merge"},
+		{"squash", "merge"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.strategy, func(t *testing.T) {
+			dir := testutil.SetupTestRepo(t)
+			defer testutil.CleanupTestRepo(t, dir)
+
+			if _, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch.feature.downstreamStrategy", tc.configured); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := testutil.RunGitFlow(t, dir, "feature", "start", "forced"); err != nil {
+				t.Fatal(err)
+			}
+
+			// Diverge develop and the feature branch
+			if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+				t.Fatal(err)
+			}
+			if err := testutil.WriteFile(t, dir, "develop-change.txt", "develop change"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := testutil.RunGit(t, dir, "add", "develop-change.txt"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add develop change"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := testutil.RunGit(t, dir, "checkout", "feature/forced"); err != nil {
+				t.Fatal(err)
+			}
+			if err := testutil.WriteFile(t, dir, "feature-change.txt", "feature change"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := testutil.RunGit(t, dir, "add", "feature-change.txt"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add feature change"); err != nil {
+				t.Fatal(err)
+			}
+			featureTip, err := testutil.RunGit(t, dir, "rev-parse", "HEAD")
+			assert.NoError(t, err)
+			developTip, err := testutil.RunGit(t, dir, "rev-parse", "develop")
+			assert.NoError(t, err)
+
+			output, err := testutil.RunGitFlow(t, dir, "feature", "update", "--strategy", tc.strategy, "forced")
+			assert.NoError(t, err, output)
+			assert.Contains(t, output, "Using "+tc.strategy+" strategy for 'feature/forced'")
+
+			assert.True(t, testutil.FileExists(t, dir, "develop-change.txt"))
+			assert.True(t, testutil.FileExists(t, dir, "feature-change.txt"))
+
+			// Verify the history shape
+			parents, err := testutil.RunGit(t, dir, "log", "-1", "--format=%P")
+			assert.NoError(t, err)
+			parentList := strings.Fields(parents)
+			switch tc.strategy {
+			case "merge":
+				assert.Equal(t, []string{strings.TrimSpace(featureTip), strings.TrimSpace(developTip)}, parentList)
+			case "rebase":
+				assert.Equal(t, []string{strings.TrimSpace(developTip)}, parentList)
+				subject, err := testutil.RunGit(t, dir, "log", "-1", "--format=%s")
+				assert.NoError(t, err)
+				assert.Equal(t, "Add feature change", strings.TrimSpace(subject))
+			case "squash":
+				assert.Equal(t, []string{strings.TrimSpace(featureTip)}, parentList)
+				subject, err := testutil.RunGit(t, dir, "log", "-1", "--format=%s")
+				assert.NoError(t, err)
+				assert.Contains(t, subject, "Squashed commit of branch 'develop'")
+			}
+		})
+	}
+}
+
+// TestUpdateWithInvalidStrategyFlag tests that unknown or conflicting strategy overrides are rejected.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates a feature branch
+// 3. Verifies --strategy with an unknown value fails
+// 4. Verifies --rebase combined with --strategy merge fails
+func TestUpdateWithInvalidStrategyFlag(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if _, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := testutil.RunGitFlow(t, dir, "feature", "start", "invalid-strategy"); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "update", "--strategy", "octopus", "invalid-strategy")
+	assert.Error(t, err)
+	assert.Contains(t, output, "unknown strategy 'octopus'")
+
+	output, err = testutil.RunGitFlow(t, dir, "update", "--rebase", "--strategy", "merge", "feature/invalid-strategy")
+	assert.Error(t, err)
+	assert.Contains(t, output, "cannot be combined with --strategy merge")
+}
+
+// TestUpdateWithFetch tests fetching the parent branch before updating.
+// Steps:
+// 1. Sets up a test repository with a remote and initializes git-flow
+// 2. Creates a feature branch and pushes a develop commit that the local develop lacks
+// 3. Updates with --no-fetch and verifies the remote commit is not picked up
+// 4. Updates with --fetch and verifies develop was fast-forwarded and merged into the feature
+// 5. Verifies gitflow.feature.update.fetch enables fetching by default
+func TestUpdateWithFetch(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and add a remote
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	bareDir, err := testutil.AddRemote(t, dir, "origin", true)
+	if err != nil {
+		t.Fatalf("Failed to add remote: %v", err)
+	}
+	defer testutil.CleanupTestRepo(t, bareDir)
+
+	// pushRemoteDevelopCommit adds a commit to develop on the remote only
+	pushRemoteDevelopCommit := func(file string) {
+		_, err := testutil.RunGit(t, dir, "checkout", "-b", "remote-work", "develop")
+		if err != nil {
+			t.Fatalf("Failed to create branch: %v", err)
+		}
+		testutil.WriteFile(t, dir, file, "remote content")
+		_, err = testutil.RunGit(t, dir, "add", file)
+		if err != nil {
+			t.Fatalf("Failed to add file: %v", err)
+		}
+		_, err = testutil.RunGit(t, dir, "commit", "-m", "Add "+file)
+		if err != nil {
+			t.Fatalf("Failed to commit file: %v", err)
+		}
+		_, err = testutil.RunGit(t, dir, "push", "origin", "remote-work:develop")
+		if err != nil {
+			t.Fatalf("Failed to push: %v", err)
+		}
+		_, err = testutil.RunGit(t, dir, "checkout", "feature/fetch")
+		if err != nil {
+			t.Fatalf("Failed to checkout feature branch: %v", err)
+		}
+		_, err = testutil.RunGit(t, dir, "branch", "-D", "remote-work")
+		if err != nil {
+			t.Fatalf("Failed to delete branch: %v", err)
+		}
+	}
+
+	// Create a feature branch; develop then moves on the remote
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "fetch")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	pushRemoteDevelopCommit("remote1.txt")
+
+	// Without fetching the stale local develop is used
+	output, err = testutil.RunGitFlow(t, dir, "feature", "update", "--no-fetch")
+	if err != nil {
+		t.Fatalf("Failed to update feature branch: %v\nOutput: %s", err, output)
+	}
+	if strings.Contains(output, "Fetching from origin") {
+		t.Errorf("Expected no fetch with --no-fetch, got: %s", output)
+	}
+	if testutil.FileExists(t, dir, "remote1.txt") {
+		t.Error("Expected remote commit not to be merged without fetching")
+	}
+
+	// With --fetch develop is fast-forwarded first
+	output, err = testutil.RunGitFlow(t, dir, "feature", "update", "--fetch")
+	if err != nil {
+		t.Fatalf("Failed to update feature branch: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Fetching from origin...") {
+		t.Errorf("Expected fetch message, got: %s", output)
+	}
+	if !testutil.FileExists(t, dir, "remote1.txt") {
+		t.Error("Expected remote commit to be merged after fetching")
+	}
+	localDevelop, _ := testutil.RunGit(t, dir, "rev-parse", "develop")
+	remoteDevelop, _ := testutil.RunGit(t, dir, "rev-parse", "origin/develop")
+	if localDevelop != remoteDevelop {
+		t.Error("Expected local develop to be fast-forwarded to origin/develop")
+	}
+
+	// The config enables fetching by default
+	_, err = testutil.RunGit(t, dir, "config", "gitflow.feature.update.fetch", "true")
+	if err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+	pushRemoteDevelopCommit("remote2.txt")
+	output, err = testutil.RunGitFlow(t, dir, "feature", "update")
+	if err != nil {
+		t.Fatalf("Failed to update feature branch: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Fetching from origin...") || !testutil.FileExists(t, dir, "remote2.txt") {
+		t.Errorf("Expected config to enable fetching, got: %s", output)
+	}
+}
+
+// TestUpdateReportsPulledInChanges tests that update summarizes the commits and files pulled in from the parent.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates a feature branch
+// 3. Commits two changes to different files in develop
+// 4. Updates the feature branch
+// 5. Verifies the summary reports two commits and two files
+// 6. Updates with --verbose after another develop commit and verifies the commit is listed
+func TestUpdateReportsPulledInChanges(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with branch creation
+	if _, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Create a feature branch
+	if _, err := testutil.RunGitFlow(t, dir, "feature", "start", "summary"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Make two commits in develop
+	if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"first.txt", "second.txt"} {
+		if err := testutil.WriteFile(t, dir, name, name); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := testutil.RunGit(t, dir, "add", name); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add "+name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Update the feature branch
+	output, err := testutil.RunGitFlow(t, dir, "update", "feature/summary")
+	if err != nil {
+		t.Fatalf("Failed to update feature branch: %v\nOutput: %s", err, output)
+	}
+	assert.Contains(t, output, "Pulled in 2 commit(s) from 'develop' changing 2 file(s)")
+	assert.NotContains(t, output, "Add first.txt", "commits should only be listed with --verbose")
+
+	// Another develop commit, updated with verbose output
+	if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+		t.Fatal(err)
+	}
+	if err := testutil.WriteFile(t, dir, "third.txt", "third.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := testutil.RunGit(t, dir, "add", "third.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add third.txt"); err != nil {
+		t.Fatal(err)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "update", "feature/summary", "--verbose")
+	if err != nil {
+		t.Fatalf("Failed to update feature branch: %v\nOutput: %s", err, output)
+	}
+	assert.Contains(t, output, "Pulled in 1 commit(s) from 'develop' changing 1 file(s)")
+	assert.Contains(t, output, "Add third.txt")
+	assert.Contains(t, output, "third.txt | 1 +")
+}
+
+// TestUpdateInterrupted tests the report left behind when an update is interrupted.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates a feature branch and a commit on each of feature and develop
+// 3. Installs a prepare-commit-msg hook that interrupts git-flow while the merge commit is prepared
+// 4. Verifies the update exits with the interrupted code, keeps the merge state and explains how to resume
+func TestUpdateInterrupted(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "interrupted")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	for _, branch := range []string{"feature/interrupted", "develop"} {
+		if _, err := testutil.RunGit(t, dir, "checkout", branch); err != nil {
+			t.Fatalf("Failed to checkout %s: %v", branch, err)
+		}
+		file := strings.ReplaceAll(branch, "/", "-") + ".txt"
+		testutil.WriteFile(t, dir, file, "content")
+		if _, err := testutil.RunGit(t, dir, "add", file); err != nil {
+			t.Fatalf("Failed to add file: %v", err)
+		}
+		if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add "+file); err != nil {
+			t.Fatalf("Failed to commit file: %v", err)
+		}
+	}
+
+	// Interrupt git-flow (the parent of git) and stop the merge before it is committed
+	hook := "#!/bin/sh\n" +
+		"kill -INT $(ps -o ppid= -p $PPID)\n" +
+		"sleep 1\n" +
+		"exit 1\n"
+	if err := os.WriteFile(filepath.Join(dir, ".git", "hooks", "prepare-commit-msg"), []byte(hook), 0755); err != nil {
+		t.Fatalf("Failed to write hook: %v", err)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "update", "feature/interrupted", "--strategy", "merge")
+	if err == nil {
+		t.Fatalf("Expected update to be interrupted, got: %s", output)
+	}
+	if exitErr, ok := err.(*testutil.ExitError); !ok || exitErr.ExitCode != 130 {
+		t.Errorf("Expected exit code 130, got: %v", err)
+	}
+	if !strings.Contains(output, "Interrupted while updating 'feature/interrupted' from 'develop'") {
+		t.Errorf("Expected interrupted update in output, got: %s", output)
+	}
+	if !strings.Contains(output, "Run 'git commit' to complete the update or 'git merge --abort' to abort it") {
+		t.Errorf("Expected resume instructions in output, got: %s", output)
+	}
+
+	state, err := testutil.LoadMergeState(t, dir)
+	if err != nil {
+		t.Fatalf("Expected merge state to be kept: %v", err)
+	}
+	if state.Action != "update" || state.FullBranchName != "feature/interrupted" {
+		t.Errorf("Expected the update of feature/interrupted to be saved, got %+v", state)
+	}
+}
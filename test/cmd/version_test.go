@@ -0,0 +1,102 @@
+package cmd_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestVersion tests that the version command and the --version flag report the version of the binary
+// and of the repository's configuration.
+// Steps:
+// 1. Sets up a test repository without git-flow
+// 2. Verifies version prints only the version of the binary
+// 3. Initializes git-flow with defaults
+// 4. Verifies version and --version print the binary version and config version 1.0
+func TestVersion(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	versionLine := regexp.MustCompile(`(?m)^git-flow-next version \d+\.\d+\.\d+\S*( \(built .+ from \S+\))?$`)
+
+	// Without git-flow, there is no config version
+	output, err := testutil.RunGitFlow(t, dir, "version")
+	if err != nil {
+		t.Fatalf("Failed to run version: %v\nOutput: %s", err, output)
+	}
+	if !versionLine.MatchString(output) {
+		t.Errorf("Expected output to contain the binary version, got: %s", output)
+	}
+	if strings.Contains(output, "Config version") {
+		t.Errorf("Expected no config version before init, got: %s", output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// The command and the flag report the same
+	for _, args := range [][]string{{"version"}, {"--version"}} {
+		output, err := testutil.RunGitFlow(t, dir, args...)
+		if err != nil {
+			t.Fatalf("Failed to run %v: %v\nOutput: %s", args, err, output)
+		}
+		if !versionLine.MatchString(output) {
+			t.Errorf("Expected %v to print the binary version, got: %s", args, output)
+		}
+		if !strings.Contains(output, "Config version: 1.0\n") {
+			t.Errorf("Expected %v to print config version 1.0, got: %s", args, output)
+		}
+		if strings.Contains(output, "upgrade") {
+			t.Errorf("Expected no upgrade hint for a current configuration, got: %s", output)
+		}
+	}
+}
+
+// TestVersionHintsAtOutdatedConfig tests that version suggests an upgrade for a configuration older than
+// the schema of the binary, and that config doctor --fix performs it.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Sets gitflow.version to 0.9
+// 3. Verifies version reports 0.9 with a hint to run config doctor --fix
+// 4. Runs config doctor --fix --yes and verifies gitflow.version is 1.0
+func TestVersionHintsAtOutdatedConfig(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.version", "0.9"); err != nil {
+		t.Fatalf("Failed to set gitflow.version: %v", err)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "version")
+	if err != nil {
+		t.Fatalf("Failed to run version: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Config version: 0.9\n") {
+		t.Errorf("Expected config version 0.9, got: %s", output)
+	}
+	if !strings.Contains(output, "run 'git flow config doctor --fix' to upgrade it") {
+		t.Errorf("Expected an upgrade hint, got: %s", output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "config", "doctor", "--fix", "--yes")
+	if err != nil {
+		t.Fatalf("Failed to run config doctor: %v\nOutput: %s", err, output)
+	}
+	version, err := testutil.RunGit(t, dir, "config", "gitflow.version")
+	if err != nil {
+		t.Fatalf("Failed to read gitflow.version: %v", err)
+	}
+	if strings.TrimSpace(version) != "1.0" {
+		t.Errorf("Expected gitflow.version to be upgraded to 1.0, got: %s", version)
+	}
+}
@@ -270,6 +270,31 @@ func TestApplyOverrides_CustomBranchNames(t *testing.T) {
 	assert.False(t, exists)
 }
 
+func TestRenameBaseBranch(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg = config.RenameBaseBranch(cfg, "develop", "dev")
+
+	// Check the renamed base branch keeps its configuration
+	devConfig, exists := cfg.Branches["dev"]
+	assert.True(t, exists)
+	assert.Equal(t, string(config.BranchTypeBase), devConfig.Type)
+	assert.Equal(t, "main", devConfig.Parent)
+	_, exists = cfg.Branches["develop"]
+	assert.False(t, exists)
+
+	// Check references to develop are updated
+	assert.Equal(t, "dev", cfg.Branches["feature"].Parent)
+	assert.Equal(t, "dev", cfg.Branches["feature"].StartPoint)
+	assert.Equal(t, "main", cfg.Branches["release"].Parent)
+	assert.Equal(t, "dev", cfg.Branches["release"].StartPoint)
+	assert.Equal(t, "main", cfg.Branches["hotfix"].StartPoint)
+	assert.Empty(t, config.FindOrphanedBranches(cfg))
+
+	// Removing a base branch leaves its children orphaned
+	delete(cfg.Branches, "dev")
+	assert.Equal(t, []string{"bugfix", "feature", "release"}, config.FindOrphanedBranches(cfg))
+}
+
 func TestApplyOverrides_CustomPrefixes(t *testing.T) {
 	cfg := config.DefaultConfig()
 	cfg = config.ApplyOverrides(cfg, config.ConfigOverrides{
@@ -301,6 +326,24 @@ func TestApplyOverrides_CustomPrefixes(t *testing.T) {
 	assert.Equal(t, "main", supportConfig.StartPoint)
 }
 
+func TestFindPrefixConflicts(t *testing.T) {
+	// Default prefixes do not overlap
+	assert.Empty(t, config.FindPrefixConflicts(config.DefaultConfig()))
+
+	// Equal prefixes and nested prefixes are both reported
+	cfg := config.ApplyOverrides(config.DefaultConfig(), config.ConfigOverrides{
+		FeaturePrefix: "work/",
+		HotfixPrefix:  "work/",
+		ReleasePrefix: "work/release/",
+	})
+	conflicts := config.FindPrefixConflicts(cfg)
+	assert.Equal(t, []config.PrefixConflict{
+		{TypeA: "feature", PrefixA: "work/", TypeB: "hotfix", PrefixB: "work/"},
+		{TypeA: "feature", PrefixA: "work/", TypeB: "release", PrefixB: "work/release/"},
+		{TypeA: "hotfix", PrefixA: "work/", TypeB: "release", PrefixB: "work/release/"},
+	}, conflicts)
+}
+
 func TestApplyOverrides_CustomTagPrefix(t *testing.T) {
 	cfg := config.DefaultConfig()
 	cfg = config.ApplyOverrides(cfg, config.ConfigOverrides{
@@ -0,0 +1,97 @@
+package mergestate_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/mergestate"
+	"github.com/gittower/git-flow-next/test/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// withStateDir changes to the provided repository directory, runs the testFunc, and changes back afterwards
+func withStateDir(t *testing.T, dir string, testFunc func()) {
+	oldDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change to test directory: %v", err)
+	}
+	defer func() {
+		if err := os.Chdir(oldDir); err != nil {
+			t.Fatalf("Failed to change back to original directory: %v", err)
+		}
+	}()
+
+	testFunc()
+}
+
+func TestMergeStateKeepsChildBranchHeads(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	withStateDir(t, dir, func() {
+		state := &mergestate.MergeState{
+			Action:          "finish",
+			BranchType:      "feature",
+			BranchName:      "heads",
+			CurrentStep:     "update_children",
+			ParentBranch:    "develop",
+			FullBranchName:  "feature/heads",
+			ChildBranches:   []string{"alpha", "beta"},
+			UpdatedBranches: []string{"alpha"},
+			ChildBranchHeads: map[string]string{
+				"alpha": "1111111111111111111111111111111111111111",
+				"beta":  "2222222222222222222222222222222222222222",
+			},
+		}
+		assert.NoError(t, mergestate.SaveMergeState(state))
+
+		loaded, err := mergestate.LoadMergeState()
+		assert.NoError(t, err)
+		assert.Equal(t, state, loaded)
+	})
+}
+
+func TestMergeStateWithoutChildBranchHeads(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	withStateDir(t, dir, func() {
+		// States written before the heads were recorded have no childBranchHeads key
+		assert.NoError(t, os.MkdirAll(".git/gitflow/state", 0755))
+		data := `{"action":"finish","branchType":"feature","branchName":"old","currentStep":"update_children","parentBranch":"develop","mergeStrategy":"merge","fullBranchName":"feature/old","childBranches":["alpha"],"updatedBranches":[]}`
+		assert.NoError(t, os.WriteFile(".git/gitflow/state/merge.json", []byte(data), 0644))
+
+		loaded, err := mergestate.LoadMergeState()
+		assert.NoError(t, err)
+		assert.Nil(t, loaded.ChildBranchHeads)
+		assert.Equal(t, []string{"alpha"}, loaded.ChildBranches)
+	})
+}
+
+func TestMergeStateCloneSharesNothing(t *testing.T) {
+	state := &mergestate.MergeState{
+		Action:           "finish",
+		BranchType:       "release",
+		BranchName:       "1.0.0",
+		CurrentStep:      "update_children",
+		ParentBranch:     "main",
+		FullBranchName:   "release/1.0.0",
+		ChildBranches:    []string{"develop"},
+		UpdatedBranches:  []string{},
+		ChildBranchHeads: map[string]string{"develop": "1111111111111111111111111111111111111111"},
+	}
+
+	clone, err := state.Clone()
+	assert.NoError(t, err)
+	assert.Equal(t, state, clone)
+
+	state.UpdatedBranches = append(state.UpdatedBranches, "develop")
+	state.ChildBranches[0] = "staging"
+	state.ChildBranchHeads["develop"] = "2222222222222222222222222222222222222222"
+	assert.Empty(t, clone.UpdatedBranches)
+	assert.Equal(t, []string{"develop"}, clone.ChildBranches)
+	assert.Equal(t, "1111111111111111111111111111111111111111", clone.ChildBranchHeads["develop"])
+}
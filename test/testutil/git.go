@@ -76,6 +76,24 @@ func RunGitFlowWithInput(t *testing.T, dir string, input string, args ...string)
 	return string(output), nil
 }
 
+// RunGitFlowWithEnv runs a git-flow command with additional environment variables and returns its output
+func RunGitFlowWithEnv(t *testing.T, dir string, env []string, args ...string) (string, error) {
+	cmd := exec.Command(gitFlowPath, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return string(output), &ExitError{
+				ExitCode: exitErr.ExitCode(),
+				Err:      fmt.Errorf("%s", output),
+			}
+		}
+		return string(output), err
+	}
+	return string(output), nil
+}
+
 // SetupTestRepo creates a temporary Git repository for testing
 func SetupTestRepo(t *testing.T) string {
 	// Create temporary directory
@@ -36,6 +36,19 @@ func LoadMergeState(t *testing.T, dir string) (*mergestate.MergeState, error) {
 	return &state, nil
 }
 
+// SaveMergeState overwrites the merge state in the test repository
+func SaveMergeState(t *testing.T, dir string, state *mergestate.MergeState) {
+	t.Helper()
+	data, err := json.Marshal(state)
+	if err != nil {
+		t.Fatalf("Failed to marshal merge state: %v", err)
+	}
+	stateFile := filepath.Join(dir, ".git", "gitflow", "state", "merge.json")
+	if err := os.WriteFile(stateFile, data, 0644); err != nil {
+		t.Fatalf("Failed to write merge state file: %v", err)
+	}
+}
+
 // IsMergeInProgress checks if a merge is in progress in the test repository
 func IsMergeInProgress(t *testing.T, dir string) bool {
 	// Check for .git/MERGE_HEAD which indicates a merge in progress
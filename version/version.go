@@ -1,7 +1,7 @@
 package version
 
-// Version information
-const (
+// Version information, injected during build with -ldflags "-X github.com/gittower/git-flow-next/version.<name>=<value>"
+var (
 	// Version is the current version of git-flow-next
 	Version = "0.1.0-alpha.1"
 